@@ -23,12 +23,11 @@ package api
 // This covers initializing the following parts:
 //   - database api
 //   - entity management
+//
+// The database schema is not touched. Use the Migrator of the
+// EntityManager to apply pending migrations.
 func Init(em EntityManager) error {
 	entityManager = em
-	err := entityManager.RegisterDefaultEntities()
-	if err != nil {
-		return err
-	}
 
 	return nil
 }
@@ -19,12 +19,9 @@
 package api
 
 import (
-	"fmt"
 	"github.com/lazybytez/jojo-discord-bot/test/dbmock"
 	"github.com/lazybytez/jojo-discord-bot/test/logmock"
-	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
-	"reflect"
 	"testing"
 )
 
@@ -33,46 +30,25 @@ type APITestSuite struct {
 }
 
 func (suite *APITestSuite) TestInit() {
-	databaseMock := &dbmock.DatabaseAccessMock{}
-	loggerMock := &logmock.LoggerMock{}
+	previousEntityManager := entityManager
+	defer func() {
+		entityManager = previousEntityManager
+	}()
 
-	testEntityManager := EntityManager{
-		database: databaseMock,
-		logger:   loggerMock,
-	}
-
-	databaseMock.On("RegisterEntity", mock.Anything).Return(nil).Times(len(defaultEntities))
-	loggerMock.On("Info",
-		mock.AnythingOfType("string"),
-		mock.AnythingOfType(reflect.TypeOf([]interface{}{}).Name()))
-
-	err := Init(testEntityManager)
-
-	databaseMock.AssertExpectations(suite.T())
-	suite.NoError(err)
-}
-
-func (suite *APITestSuite) TestInitWithFailure() {
 	databaseMock := &dbmock.DatabaseAccessMock{}
 	loggerMock := &logmock.LoggerMock{}
 
-	testError := fmt.Errorf("something really bad happened")
-
 	testEntityManager := EntityManager{
 		database: databaseMock,
 		logger:   loggerMock,
 	}
 
-	databaseMock.On("RegisterEntity", mock.Anything).Return(testError).Once()
-	loggerMock.On("Err",
-		testError,
-		mock.AnythingOfType("string"),
-		mock.AnythingOfType(reflect.TypeOf([]interface{}{}).Name()))
-
 	err := Init(testEntityManager)
 
+	// Init must not touch the database schema
 	databaseMock.AssertExpectations(suite.T())
-	suite.Error(err)
+	suite.NoError(err)
+	suite.Equal(databaseMock, GetEntityManager().DB())
 }
 
 func TestApi(t *testing.T) {
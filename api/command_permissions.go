@@ -0,0 +1,212 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"strconv"
+)
+
+// Guilds can override who is allowed to use a command using entities.CommandPermissionOverride.
+// The overrides of a command are evaluated in the following order:
+//  1. members with the administrator permission are never restricted
+//  2. the command is denied in channels that are denied and, if there are
+//     channels that are allowed, in all channels that are not allowed
+//  3. a rule for the member itself allows or denies the command
+//  4. a rule for one of the roles of the member allows or denies the command,
+//     where allowing rules take precedence over denying ones. The @everyone role,
+//     whose id equals the id of the guild, applies to every member
+//  5. if no rule applies, the Permissions of the Command are checked
+
+// CommandPermissionOverrideMiddleware enforces the permission overrides configured
+// for the command on the guild the command is executed on.
+// See IsCommandAllowedForMember for details.
+func CommandPermissionOverrideMiddleware(command *Command, next CommandHandlerFunc) CommandHandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		if isCommandAllowedForInteraction(command, i) {
+			next(s, i)
+
+			return
+		}
+
+		respondWithStopMessage(s, i, command.c, fmt.Sprintf("You are not allowed to use "+
+			"the command `/%s` here!", command.Cmd.Name))
+
+		user := getInteractionUser(i)
+		command.c.Logger().Info("The user \"%s#%s\" with id \"%s\" has been denied to execute the "+
+			"command \"%s\" %s",
+			user.Username,
+			user.Discriminator,
+			user.ID,
+			command.Cmd.Name,
+			getGuildOrGlobalLogPart(i.GuildID, "on"))
+	}
+}
+
+// isCommandAllowedForInteraction checks whether the member that triggered the passed
+// interaction is allowed to use the passed command.
+//
+// Interactions outside of guilds are always allowed, guild interactions
+// without a member are always denied.
+func isCommandAllowedForInteraction(command *Command, i *discordgo.InteractionCreate) bool {
+	if "" == i.GuildID {
+		return true
+	}
+
+	if nil == i.Member {
+		return false
+	}
+
+	overrides := getCommandPermissionOverrides(command, i.GuildID)
+
+	return IsCommandAllowedForMember(command, overrides, i.Member, i.GuildID, i.ChannelID)
+}
+
+// IsCommandAllowedForMember checks whether the passed member is allowed to use the passed command
+// in the channel with the passed id of the guild with the passed id,
+// taking the passed overrides in account.
+func IsCommandAllowedForMember(
+	command *Command,
+	overrides []entities.CommandPermissionOverride,
+	member *discordgo.Member,
+	guildId string,
+	channelId string,
+) bool {
+	if member.Permissions&discordgo.PermissionAdministrator == discordgo.PermissionAdministrator {
+		return true
+	}
+
+	allowedInChannel, ok := evaluateChannelOverrides(overrides, channelId)
+	if ok && !allowedInChannel {
+		return false
+	}
+
+	userId := ""
+	if nil != member.User {
+		userId = member.User.ID
+	}
+
+	roleAllowed := false
+	roleDenied := false
+	for _, override := range overrides {
+		targetId := strconv.FormatUint(override.TargetID, 10)
+
+		switch override.TargetType {
+		case entities.CommandPermissionOverrideTargetUser:
+			if targetId == userId {
+				return override.Allow
+			}
+		case entities.CommandPermissionOverrideTargetRole:
+			if !hasRole(member, guildId, targetId) {
+				continue
+			}
+
+			roleAllowed = roleAllowed || override.Allow
+			roleDenied = roleDenied || !override.Allow
+		}
+	}
+
+	if roleAllowed {
+		return true
+	}
+
+	if roleDenied {
+		return false
+	}
+
+	return member.Permissions&command.Permissions == command.Permissions
+}
+
+// evaluateChannelOverrides checks the channel overrides in the passed overrides.
+// The second return value is false, if there are no channel overrides.
+func evaluateChannelOverrides(overrides []entities.CommandPermissionOverride, channelId string) (bool, bool) {
+	hasChannelOverrides := false
+	hasAllowedChannels := false
+	allowed := false
+
+	for _, override := range overrides {
+		if entities.CommandPermissionOverrideTargetChannel != override.TargetType {
+			continue
+		}
+		hasChannelOverrides = true
+
+		isChannel := strconv.FormatUint(override.TargetID, 10) == channelId
+		if isChannel && !override.Allow {
+			return false, true
+		}
+
+		if override.Allow {
+			hasAllowedChannels = true
+			allowed = allowed || isChannel
+		}
+	}
+
+	if !hasChannelOverrides {
+		return true, false
+	}
+
+	return !hasAllowedChannels || allowed, true
+}
+
+// hasRole checks whether the passed member of the guild with the passed id
+// has the role with the passed id.
+//
+// Discord does not list the @everyone role in the roles of a member,
+// so the role with the id of the guild is treated as assigned to every member.
+func hasRole(member *discordgo.Member, guildId string, roleId string) bool {
+	if guildId == roleId {
+		return true
+	}
+
+	for _, memberRoleId := range member.Roles {
+		if memberRoleId == roleId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getCommandPermissionOverrides returns the permission overrides of the passed command
+// on the guild with the passed id. When the overrides cannot be loaded,
+// no overrides are returned.
+func getCommandPermissionOverrides(command *Command, guildId string) []entities.CommandPermissionOverride {
+	em := command.c.EntityManager()
+	commandKey := getCommandKey(command.Cmd.Type, command.Cmd.Name)
+
+	guild, err := em.Guilds().Get(guildId)
+	if nil != err {
+		command.c.Logger().Err(err, "Failed to load guild \"%s\" to check the permission "+
+			"overrides of the command \"%s\"!", guildId, commandKey)
+
+		return nil
+	}
+
+	overrides, err := em.CommandPermissionOverride().GetByGuildAndCommand(guild.ID, commandKey)
+	if nil != err {
+		command.c.Logger().Err(err, "Failed to load the permission overrides of the "+
+			"command \"%s\" on guild \"%s\"!", commandKey, guildId)
+
+		return nil
+	}
+
+	return overrides
+}
@@ -0,0 +1,171 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"github.com/lazybytez/jojo-discord-bot/test/discordgo_mock"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type CommandPermissionsTestSuite struct {
+	suite.Suite
+	command *Command
+}
+
+func (suite *CommandPermissionsTestSuite) SetupTest() {
+	suite.command = &Command{
+		Cmd:         &discordgo.ApplicationCommand{Name: "test"},
+		Permissions: discordgo.PermissionManageServer,
+	}
+}
+
+func (suite *CommandPermissionsTestSuite) TestIsCommandAllowedForMember() {
+	roleAllow := entities.CommandPermissionOverride{
+		TargetType: entities.CommandPermissionOverrideTargetRole,
+		TargetID:   10,
+		Allow:      true,
+	}
+	roleDeny := entities.CommandPermissionOverride{
+		TargetType: entities.CommandPermissionOverrideTargetRole,
+		TargetID:   11,
+		Allow:      false,
+	}
+	everyoneDeny := entities.CommandPermissionOverride{
+		TargetType: entities.CommandPermissionOverrideTargetRole,
+		TargetID:   1,
+		Allow:      false,
+	}
+	userAllow := entities.CommandPermissionOverride{
+		TargetType: entities.CommandPermissionOverrideTargetUser,
+		TargetID:   42,
+		Allow:      true,
+	}
+	userDeny := entities.CommandPermissionOverride{
+		TargetType: entities.CommandPermissionOverrideTargetUser,
+		TargetID:   42,
+		Allow:      false,
+	}
+	channelAllow := entities.CommandPermissionOverride{
+		TargetType: entities.CommandPermissionOverrideTargetChannel,
+		TargetID:   100,
+		Allow:      true,
+	}
+	channelDeny := entities.CommandPermissionOverride{
+		TargetType: entities.CommandPermissionOverrideTargetChannel,
+		TargetID:   101,
+		Allow:      false,
+	}
+
+	tables := []struct {
+		name        string
+		overrides   []entities.CommandPermissionOverride
+		roles       []string
+		permissions int64
+		channelId   string
+		expected    bool
+	}{
+		{"no overrides without permissions", nil, nil, 0, "100", false},
+		{"no overrides with permissions", nil, nil, discordgo.PermissionManageServer, "100", true},
+		{"administrator", []entities.CommandPermissionOverride{userDeny, channelDeny}, nil,
+			discordgo.PermissionAdministrator, "101", true},
+		{"allowed role", []entities.CommandPermissionOverride{roleAllow}, []string{"10"}, 0, "100", true},
+		{"denied role", []entities.CommandPermissionOverride{roleDeny}, []string{"11"},
+			discordgo.PermissionManageServer, "100", false},
+		{"allowed role wins", []entities.CommandPermissionOverride{roleDeny, roleAllow}, []string{"10", "11"},
+			0, "100", true},
+		{"foreign role", []entities.CommandPermissionOverride{roleAllow}, []string{"12"}, 0, "100", false},
+		{"denied everyone", []entities.CommandPermissionOverride{everyoneDeny}, nil,
+			discordgo.PermissionManageServer, "100", false},
+		{"denied everyone with allowed role", []entities.CommandPermissionOverride{everyoneDeny, roleAllow},
+			[]string{"10"}, 0, "100", true},
+		{"denied everyone with foreign role", []entities.CommandPermissionOverride{everyoneDeny, roleAllow},
+			[]string{"12"}, discordgo.PermissionManageServer, "100", false},
+		{"allowed user", []entities.CommandPermissionOverride{userAllow, roleDeny}, []string{"11"}, 0, "100", true},
+		{"denied user", []entities.CommandPermissionOverride{userDeny, roleAllow}, []string{"10"}, 0, "100", false},
+		{"denied channel", []entities.CommandPermissionOverride{channelDeny, userAllow}, nil, 0, "101", false},
+		{"allowed channel", []entities.CommandPermissionOverride{channelAllow, userAllow}, nil, 0, "100", true},
+		{"not allowed channel", []entities.CommandPermissionOverride{channelAllow, userAllow}, nil, 0, "102", false},
+		{"other channel denied", []entities.CommandPermissionOverride{channelDeny, userAllow}, nil, 0, "102", true},
+	}
+
+	for _, table := range tables {
+		member := &discordgo.Member{
+			User:        &discordgo.User{ID: "42"},
+			Roles:       table.roles,
+			Permissions: table.permissions,
+		}
+
+		result := IsCommandAllowedForMember(suite.command, table.overrides, member, "1", table.channelId)
+
+		suite.Equal(table.expected, result, table.name)
+	}
+}
+
+func (suite *CommandPermissionsTestSuite) TestCommandPermissionOverrideMiddlewareWithoutGuild() {
+	nextCalled := false
+	suite.command.c = &Component{Name: "Test"}
+
+	handler := CommandPermissionOverrideMiddleware(suite.command, func(_ *discordgo.Session, _ *discordgo.InteractionCreate) {
+		nextCalled = true
+	})
+
+	handler(nil, &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			User: &discordgo.User{ID: "42"},
+		},
+	})
+
+	suite.True(nextCalled)
+}
+
+func (suite *CommandPermissionsTestSuite) TestCommandPermissionOverrideMiddlewareWithoutMember() {
+	nextCalled := false
+	suite.command.c = &Component{Name: "Test"}
+
+	s, r := discordgo_mock.MockSession()
+	interactionResponse := &discordgo.InteractionResponse{}
+	r.OnRequestCaptureResult(http.MethodPost, interactionResponse).Once().Return(
+		&http.Response{
+			StatusCode: http.StatusNoContent,
+		}, nil)
+
+	handler := CommandPermissionOverrideMiddleware(suite.command, func(_ *discordgo.Session, _ *discordgo.InteractionCreate) {
+		nextCalled = true
+	})
+
+	handler(s, &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:      "interaction.ID",
+			Token:   "interaction.Token",
+			GuildID: "1",
+		},
+	})
+
+	suite.False(nextCalled)
+	r.AssertExpectations(suite.T())
+	suite.Equal(discordgo.MessageFlagsEphemeral, interactionResponse.Data.Flags)
+}
+
+func TestCommandPermissions(t *testing.T) {
+	suite.Run(t, new(CommandPermissionsTestSuite))
+}
@@ -19,10 +19,12 @@
 package api
 
 import (
+	"fmt"
 	"github.com/bwmarrin/discordgo"
 	"github.com/lazybytez/jojo-discord-bot/api/entities"
 	"github.com/lazybytez/jojo-discord-bot/api/util"
 	"github.com/lazybytez/jojo-discord-bot/services"
+	"github.com/lazybytez/jojo-discord-bot/webapi"
 	"sort"
 	"strings"
 )
@@ -56,15 +58,36 @@ type Component struct {
 	// Categories are filled with the categories of the commands
 	// that are owned by the component. Manually added categories will
 	// be still merged for components that do not have any command.
-	Categories   Categories
-	Description  string
-	LoadPriority int
+	Categories  Categories
+	Description string
+	// DependsOn holds the codes of the components that must be loaded
+	// and enabled for this component to work properly.
+	// Components are loaded after the components they depend on.
+	DependsOn []entities.ComponentCode
+	// ConfigSchema declares the options that can be configured
+	// per guild for the component.
+	// See ComponentConfigManager on how to read and write the configured values.
+	ConfigSchema ConfigSchema
+	// Intents declares the gateway intents required by the component.
+	// Components that require privileged intents, which are not enabled,
+	// cannot be loaded. See PrivilegedIntents.
+	Intents discordgo.Intent
+	// Migrations declares the versioned schema migrations of the entities
+	// owned by the component. They are applied after the core migrations,
+	// in the order the components are loaded.
+	Migrations Migrations
+	// Entities declares the entities owned by the component in the order
+	// they have to be created to satisfy their foreign keys.
+	// They are copied together with the core entities, when the database
+	// is moved using the DatabaseCopier.
+	Entities []interface{}
 
 	// State
 	State *State
 
 	// Lifecycle hooks
-	loadComponentFunction func(_ *discordgo.Session) error
+	loadComponentFunction   func(_ *discordgo.Session) error
+	unloadComponentFunction func(_ *discordgo.Session) error
 
 	// Utilities
 	// These are private and only managed by the API system.
@@ -77,13 +100,30 @@ type Component struct {
 	slashCommandManager *SlashCommandManager
 	discord             *discordgo.Session
 	botAuditLogger      *BotAuditLogger
+	lifecycleManager    ComponentLifecycleManager
+	configManager       ComponentConfigManager
+	interactionManager  ComponentInteractionManager
+	modalManager        ModalManager
+	scheduler           Scheduler
+	eventBus            EventBus
+	storage             ComponentStorage
 }
 
 // RegistrableComponent is the interface that allows a component to be
-// initialized and registered.
+// initialized, registered and torn down again.
+//
+// A component that has been unloaded must not leave anything behind.
+// This includes event handlers, slash commands, web api routes and status.
+// Afterwards, it must be possible to load the component again.
 type RegistrableComponent interface {
+	// LoadComponent loads the component.
+	// Loading an already loaded component results in an error.
 	LoadComponent(discord *discordgo.Session) error
+	// UnloadComponent unloads the component and removes everything
+	// that has been registered by it.
 	UnloadComponent(discord *discordgo.Session) error
+	// ReloadComponent unloads the component and loads it again.
+	ReloadComponent(discord *discordgo.Session) error
 }
 
 // ServiceManager is a simple interface that defines the methods
@@ -138,18 +178,72 @@ type ServiceManager interface {
 	DiscordApi() DiscordApiWrapper
 	// BotStatusManager returns the current StatusManager which
 	// allows to add additional status to the bot.
+	//
+	// Status added through the StatusManager are owned by the component
+	// and removed when the component is unloaded.
 	BotStatusManager() StatusManager
+	// WebApiRouter returns a webapi.ComponentRouter that allows to register
+	// web api routes owned by the component.
+	//
+	// Routes registered through the router are removed when the component is unloaded.
+	WebApiRouter() webapi.ComponentRouter
+	// ComponentLifecycleManager returns the ComponentLifecycleManager that allows to
+	// load, unload and reload other components at runtime.
+	ComponentLifecycleManager() ComponentLifecycleManager
+	// ComponentConfig returns the ComponentConfigManager of the component,
+	// which allows to read and write the per-guild configuration of the component.
+	ComponentConfig() ComponentConfigManager
+	// ComponentInteractionManager returns the ComponentInteractionManager of the component,
+	// which allows to handle interactions with message components like buttons and select menus.
+	//
+	// Handlers registered through the manager are removed when the component is unloaded.
+	ComponentInteractionManager() ComponentInteractionManager
+	// ModalManager returns the ModalManager of the component,
+	// which allows to handle the submission of modals.
+	//
+	// Handlers registered through the manager are removed when the component is unloaded.
+	ModalManager() ModalManager
+	// Scheduler returns the Scheduler of the component,
+	// which allows to schedule periodic and one-off jobs.
+	//
+	// Jobs scheduled through the Scheduler are cancelled when the component is unloaded.
+	Scheduler() Scheduler
+	// EventBus returns the EventBus of the component,
+	// which allows to publish events and subscribe to events of other components.
+	//
+	// Subscriptions registered through the EventBus are removed when the component is unloaded.
+	EventBus() EventBus
+	// ComponentStorage returns the ComponentStorage of the component,
+	// which allows to store small amounts of persistent state as JSON values
+	// scoped by the component and optionally a guild, channel or user.
+	ComponentStorage() ComponentStorage
 }
 
 // LoadComponent is used by the component registration system that
 // automatically calls the LoadComponent method for all Component instances in
 // the components.Components array.
 func (c *Component) LoadComponent(discord *discordgo.Session) error {
+	if c.State.Loaded {
+		return fmt.Errorf("the component \"%s\" is already loaded", c.Code)
+	}
+
+	if missingIntents := c.GetMissingIntents(); 0 != missingIntents {
+		return fmt.Errorf("the component \"%s\" requires the intents \"%d\" (privileged: %s), "+
+			"which are not available",
+			c.Code,
+			missingIntents,
+			GetPrivilegedIntentNames(missingIntents))
+	}
+
 	c.discord = discord
 
 	err := c.loadComponentFunction(discord)
 
 	if err != nil {
+		// Release everything the component registered before it failed,
+		// so that loading it again does not conflict with leftovers.
+		c.releaseResources()
+
 		return err
 	}
 
@@ -188,12 +282,54 @@ func (c *Component) computeCategories() {
 // The function takes care of tasks like unregistering slash-commands and so on.
 //
 // It is used to give components the ability to gracefully shutdown.
-func (c *Component) UnloadComponent(*discordgo.Session) error {
-	c.HandlerManager().UnregisterAll()
+func (c *Component) UnloadComponent(discord *discordgo.Session) error {
+	var err error
+	if nil != c.unloadComponentFunction {
+		err = c.unloadComponentFunction(discord)
+	}
+
+	c.releaseResources()
 
 	c.State.Loaded = false
 
-	return nil
+	return err
+}
+
+// releaseResources releases all resources the component registered through the API,
+// like handlers, commands, scheduled jobs, subscriptions and web api routes.
+func (c *Component) releaseResources() {
+	c.HandlerManager().UnregisterAll()
+	c.SlashCommandManager().UnregisterAll()
+	removeComponentCommandMiddlewares(c.Code)
+	c.ComponentInteractionManager().UnregisterAll()
+	c.ModalManager().UnregisterAll()
+	c.Scheduler().CancelAll()
+	c.EventBus().UnsubscribeAll()
+	webapi.UnregisterRoutes(string(c.Code))
+	botStatusManager.removeStatusOfComponent(c.Code)
+}
+
+// ReloadComponent unloads the component and loads it again afterwards.
+//
+// The component is loaded again, even if the unload hook of the component
+// returned an error, as the resources managed by the API are released anyway.
+func (c *Component) ReloadComponent(discord *discordgo.Session) error {
+	if c.State.Loaded {
+		err := c.UnloadComponent(discord)
+		if nil != err {
+			c.Logger().Warn("The unload hook of the component failed during reload: %v", err.Error())
+		}
+	}
+
+	return c.LoadComponent(discord)
+}
+
+// WebApiRouter returns a webapi.ComponentRouter that allows to register
+// web api routes owned by the component.
+//
+// Routes registered through the router are removed when the component is unloaded.
+func (c *Component) WebApiRouter() webapi.ComponentRouter {
+	return webapi.RouterFor(string(c.Code))
 }
 
 // IsCoreComponent checks whether the passed component is a core
@@ -0,0 +1,371 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ConfigOptionType defines the type of the value of a ConfigOption.
+type ConfigOptionType string
+
+// These are the available types of configuration options.
+const (
+	ConfigOptionTypeString  ConfigOptionType = "string"
+	ConfigOptionTypeInteger ConfigOptionType = "integer"
+	ConfigOptionTypeBoolean ConfigOptionType = "boolean"
+)
+
+// ErrUnknownConfigOption is returned, when a configuration option
+// is requested that is not part of the ConfigSchema of a component.
+var ErrUnknownConfigOption = errors.New("the requested configuration option does not exist")
+
+// ConfigValueError is returned, when a value that should be stored for
+// a configuration option cannot be parsed or fails the validation of the option.
+type ConfigValueError struct {
+	Key string
+	Err error
+}
+
+// Error returns the message of the ConfigValueError.
+func (cve *ConfigValueError) Error() string {
+	return fmt.Sprintf("invalid value for configuration option \"%s\": %s", cve.Key, cve.Err.Error())
+}
+
+// Unwrap returns the error that caused the ConfigValueError.
+func (cve *ConfigValueError) Unwrap() error {
+	return cve.Err
+}
+
+// ConfigOption describes a single configuration option of a component.
+//
+// The Default must match the Type of the option:
+//   - ConfigOptionTypeString expects a string
+//   - ConfigOptionTypeInteger expects an int64
+//   - ConfigOptionTypeBoolean expects a bool
+//
+// The optional Validate function is called with the parsed value
+// before a value is stored.
+type ConfigOption struct {
+	Key         string
+	Description string
+	Type        ConfigOptionType
+	Default     interface{}
+	Validate    func(value interface{}) error
+}
+
+// ConfigSchema holds all configuration options of a component.
+type ConfigSchema []*ConfigOption
+
+// ComponentConfigContainer is the default implementation of the ComponentConfigManager.
+type ComponentConfigContainer struct {
+	owner *Component
+}
+
+// ComponentConfigManager allows to read and write the per-guild
+// configuration of a component.
+//
+// The configuration options are declared using the ConfigSchema of the component.
+// When no value has been configured for a guild, the default value of the
+// option is returned. An empty guild id always results in the default value.
+type ComponentConfigManager interface {
+	// Schema returns the ConfigSchema of the component.
+	Schema() ConfigSchema
+	// GetOption returns the ConfigOption with the passed key.
+	GetOption(key string) (*ConfigOption, error)
+	// Get returns the value of the option with the passed key on the passed guild.
+	// The type of the returned value depends on the type of the option.
+	Get(guildId string, key string) (interface{}, error)
+	// GetString returns the value of the string option with the passed key on the passed guild.
+	GetString(guildId string, key string) (string, error)
+	// GetInteger returns the value of the integer option with the passed key on the passed guild.
+	GetInteger(guildId string, key string) (int64, error)
+	// GetBoolean returns the value of the boolean option with the passed key on the passed guild.
+	GetBoolean(guildId string, key string) (bool, error)
+	// Set parses, validates and stores the passed raw value for the
+	// option with the passed key on the passed guild.
+	// Values that cannot be parsed or are invalid result in a ConfigValueError.
+	Set(guildId string, key string, rawValue string) error
+	// Reset removes the configured value of the option with the passed key on the passed guild,
+	// which results in the default value being used again.
+	Reset(guildId string, key string) error
+}
+
+// ComponentConfig returns the ComponentConfigManager of the component,
+// which allows to read and write the per-guild configuration of the component.
+func (c *Component) ComponentConfig() ComponentConfigManager {
+	if nil == c.configManager {
+		c.configManager = &ComponentConfigContainer{owner: c}
+	}
+
+	return c.configManager
+}
+
+// Schema returns the ConfigSchema of the component.
+func (ccc *ComponentConfigContainer) Schema() ConfigSchema {
+	return ccc.owner.ConfigSchema
+}
+
+// GetOption returns the ConfigOption with the passed key.
+func (ccc *ComponentConfigContainer) GetOption(key string) (*ConfigOption, error) {
+	for _, option := range ccc.owner.ConfigSchema {
+		if option.Key == key {
+			return option, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: \"%s\" of component \"%s\"", ErrUnknownConfigOption, key, ccc.owner.Code)
+}
+
+// Get returns the value of the option with the passed key on the passed guild.
+// The type of the returned value depends on the type of the option.
+func (ccc *ComponentConfigContainer) Get(guildId string, key string) (interface{}, error) {
+	option, err := ccc.GetOption(key)
+	if nil != err {
+		return nil, err
+	}
+
+	if "" == guildId {
+		return option.Default, nil
+	}
+
+	guild, regComp, err := ccc.getGuildAndComponent(guildId)
+	if nil != err {
+		return option.Default, err
+	}
+
+	config, err := ccc.owner.EntityManager().GuildComponentConfig().Get(guild.ID, regComp.ID, key)
+	if nil != err {
+		// No entities entry = default value
+		return option.Default, nil
+	}
+
+	value, err := option.Parse(config.Value)
+	if nil != err {
+		ccc.owner.Logger().Warn("Stored value of configuration option \"%s\" on guild \"%s\" is invalid, "+
+			"falling back to default value: %v",
+			key,
+			guildId,
+			err.Error())
+
+		return option.Default, nil
+	}
+
+	return value, nil
+}
+
+// GetString returns the value of the string option with the passed key on the passed guild.
+func (ccc *ComponentConfigContainer) GetString(guildId string, key string) (string, error) {
+	value, err := ccc.Get(guildId, key)
+
+	stringValue, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("the configuration option \"%s\" is not of type string", key)
+	}
+
+	return stringValue, err
+}
+
+// GetInteger returns the value of the integer option with the passed key on the passed guild.
+func (ccc *ComponentConfigContainer) GetInteger(guildId string, key string) (int64, error) {
+	value, err := ccc.Get(guildId, key)
+
+	integerValue, ok := value.(int64)
+	if !ok {
+		return 0, fmt.Errorf("the configuration option \"%s\" is not of type integer", key)
+	}
+
+	return integerValue, err
+}
+
+// GetBoolean returns the value of the boolean option with the passed key on the passed guild.
+func (ccc *ComponentConfigContainer) GetBoolean(guildId string, key string) (bool, error) {
+	value, err := ccc.Get(guildId, key)
+
+	booleanValue, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("the configuration option \"%s\" is not of type boolean", key)
+	}
+
+	return booleanValue, err
+}
+
+// Set parses, validates and stores the passed raw value for the
+// option with the passed key on the passed guild.
+// Values that cannot be parsed or are invalid result in a ConfigValueError.
+func (ccc *ComponentConfigContainer) Set(guildId string, key string, rawValue string) error {
+	option, err := ccc.GetOption(key)
+	if nil != err {
+		return err
+	}
+
+	value, err := option.Parse(rawValue)
+	if nil != err {
+		return &ConfigValueError{Key: key, Err: err}
+	}
+
+	guild, regComp, err := ccc.getGuildAndComponent(guildId)
+	if nil != err {
+		return err
+	}
+
+	return ccc.owner.EntityManager().GuildComponentConfig().Upsert(&entities.GuildComponentConfig{
+		GuildID:     guild.ID,
+		ComponentID: regComp.ID,
+		Key:         key,
+		Value:       option.Format(value),
+	})
+}
+
+// Reset removes the configured value of the option with the passed key on the passed guild,
+// which results in the default value being used again.
+func (ccc *ComponentConfigContainer) Reset(guildId string, key string) error {
+	_, err := ccc.GetOption(key)
+	if nil != err {
+		return err
+	}
+
+	guild, regComp, err := ccc.getGuildAndComponent(guildId)
+	if nil != err {
+		return err
+	}
+
+	em := ccc.owner.EntityManager().GuildComponentConfig()
+	config, err := em.Get(guild.ID, regComp.ID, key)
+	if nil != err {
+		// Nothing configured, nothing to reset
+		return nil
+	}
+
+	return em.Delete(config)
+}
+
+// getGuildAndComponent returns the database entities of the guild with the passed id and
+// the component that owns the ComponentConfigContainer.
+func (ccc *ComponentConfigContainer) getGuildAndComponent(
+	guildId string,
+) (*entities.Guild, *entities.RegisteredComponent, error) {
+	em := ccc.owner.EntityManager()
+
+	guild, err := em.Guilds().Get(guildId)
+	if nil != err {
+		return nil, nil, fmt.Errorf("missing guild with id \"%s\" in database: %w", guildId, err)
+	}
+
+	regComp, err := em.RegisteredComponent().Get(ccc.owner.Code)
+	if nil != err {
+		return nil, nil, fmt.Errorf("missing component with code \"%s\" in database: %w", ccc.owner.Code, err)
+	}
+
+	return guild, regComp, nil
+}
+
+// Parse converts the passed raw value into the type of the option
+// and validates it.
+func (co *ConfigOption) Parse(rawValue string) (interface{}, error) {
+	var value interface{}
+	var err error
+
+	rawValue = strings.TrimSpace(rawValue)
+	switch co.Type {
+	case ConfigOptionTypeString:
+		value = rawValue
+	case ConfigOptionTypeInteger:
+		value, err = strconv.ParseInt(rawValue, 10, 64)
+		if nil != err {
+			return nil, fmt.Errorf("the value \"%s\" is not a valid integer", rawValue)
+		}
+	case ConfigOptionTypeBoolean:
+		value, err = strconv.ParseBool(rawValue)
+		if nil != err {
+			return nil, fmt.Errorf("the value \"%s\" is not a valid boolean", rawValue)
+		}
+	default:
+		return nil, fmt.Errorf("the configuration option \"%s\" has the unsupported type \"%s\"", co.Key, co.Type)
+	}
+
+	if nil != co.Validate {
+		err = co.Validate(value)
+		if nil != err {
+			return nil, err
+		}
+	}
+
+	return value, nil
+}
+
+// Format converts the passed value into its string representation,
+// which can be stored in the database or displayed to users.
+func (co *ConfigOption) Format(value interface{}) string {
+	return fmt.Sprintf("%v", value)
+}
+
+// IntegerRangeValidator creates a validation function for integer
+// configuration options that ensures values are in the passed range.
+func IntegerRangeValidator(min int64, max int64) func(value interface{}) error {
+	return func(value interface{}) error {
+		integerValue, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("the value \"%v\" is not a valid integer", value)
+		}
+
+		if integerValue < min || integerValue > max {
+			return fmt.Errorf("the value must be between %d and %d", min, max)
+		}
+
+		return nil
+	}
+}
+
+// IntegerOptionRangeValidator creates a validation function for integer configuration
+// options that ensures values are in the range of the slash-command option with the passed name.
+//
+// This allows configuration options that provide the default of a command option
+// to share the limits declared on the command option.
+// Panics if there is no option with the passed name.
+func IntegerOptionRangeValidator(
+	options []*discordgo.ApplicationCommandOption,
+	name string,
+) func(value interface{}) error {
+	for _, option := range options {
+		if option.Name != name {
+			continue
+		}
+
+		min := int64(math.MinInt64)
+		if nil != option.MinValue {
+			min = int64(*option.MinValue)
+		}
+
+		max := int64(math.MaxInt64)
+		if 0 != option.MaxValue {
+			max = int64(option.MaxValue)
+		}
+
+		return IntegerRangeValidator(min, max)
+	}
+
+	panic(fmt.Sprintf("there is no command option with name \"%s\"", name))
+}
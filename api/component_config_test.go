@@ -0,0 +1,195 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"errors"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type ComponentConfigTestSuite struct {
+	suite.Suite
+	component *Component
+}
+
+func (suite *ComponentConfigTestSuite) SetupTest() {
+	suite.component = &Component{
+		Code: "test_component",
+		ConfigSchema: ConfigSchema{
+			{
+				Key:         "greeting",
+				Description: "The greeting",
+				Type:        ConfigOptionTypeString,
+				Default:     "Hello",
+			},
+			{
+				Key:         "amount",
+				Description: "The amount",
+				Type:        ConfigOptionTypeInteger,
+				Default:     int64(3),
+				Validate:    IntegerRangeValidator(1, 10),
+			},
+			{
+				Key:         "enabled",
+				Description: "Whether it is enabled",
+				Type:        ConfigOptionTypeBoolean,
+				Default:     true,
+			},
+		},
+	}
+}
+
+func (suite *ComponentConfigTestSuite) TestSchema() {
+	suite.Equal(suite.component.ConfigSchema, suite.component.ComponentConfig().Schema())
+}
+
+func (suite *ComponentConfigTestSuite) TestGetOption() {
+	option, err := suite.component.ComponentConfig().GetOption("amount")
+
+	suite.NoError(err)
+	suite.Equal(suite.component.ConfigSchema[1], option)
+}
+
+func (suite *ComponentConfigTestSuite) TestGetOptionWithUnknownKey() {
+	option, err := suite.component.ComponentConfig().GetOption("unknown")
+
+	suite.Nil(option)
+	suite.True(errors.Is(err, ErrUnknownConfigOption))
+}
+
+func (suite *ComponentConfigTestSuite) TestGetDefaultsWithoutGuild() {
+	config := suite.component.ComponentConfig()
+
+	greeting, err := config.GetString("", "greeting")
+	suite.NoError(err)
+	suite.Equal("Hello", greeting)
+
+	amount, err := config.GetInteger("", "amount")
+	suite.NoError(err)
+	suite.Equal(int64(3), amount)
+
+	enabled, err := config.GetBoolean("", "enabled")
+	suite.NoError(err)
+	suite.True(enabled)
+}
+
+func (suite *ComponentConfigTestSuite) TestGetWithWrongType() {
+	config := suite.component.ComponentConfig()
+
+	_, err := config.GetInteger("", "greeting")
+	suite.Error(err)
+
+	_, err = config.GetBoolean("", "amount")
+	suite.Error(err)
+
+	_, err = config.GetString("", "enabled")
+	suite.Error(err)
+}
+
+func (suite *ComponentConfigTestSuite) TestSetAndResetWithUnknownKey() {
+	config := suite.component.ComponentConfig()
+
+	suite.True(errors.Is(config.Set("123", "unknown", "value"), ErrUnknownConfigOption))
+	suite.True(errors.Is(config.Reset("123", "unknown"), ErrUnknownConfigOption))
+}
+
+func (suite *ComponentConfigTestSuite) TestSetWithInvalidValue() {
+	err := suite.component.ComponentConfig().Set("123", "amount", "42")
+
+	var valueErr *ConfigValueError
+	suite.True(errors.As(err, &valueErr))
+	suite.Equal("amount", valueErr.Key)
+	suite.EqualError(valueErr.Err, "the value must be between 1 and 10")
+}
+
+func (suite *ComponentConfigTestSuite) TestParse() {
+	tables := []struct {
+		option   *ConfigOption
+		rawValue string
+		expected interface{}
+		isValid  bool
+	}{
+		{suite.component.ConfigSchema[0], " Hi ", "Hi", true},
+		{suite.component.ConfigSchema[1], "5", int64(5), true},
+		{suite.component.ConfigSchema[1], "11", nil, false},
+		{suite.component.ConfigSchema[1], "0", nil, false},
+		{suite.component.ConfigSchema[1], "five", nil, false},
+		{suite.component.ConfigSchema[2], "false", false, true},
+		{suite.component.ConfigSchema[2], "maybe", nil, false},
+		{&ConfigOption{Key: "invalid", Type: "float"}, "1.0", nil, false},
+	}
+
+	for _, table := range tables {
+		value, err := table.option.Parse(table.rawValue)
+
+		if !table.isValid {
+			suite.Error(err)
+			suite.Nil(value)
+
+			continue
+		}
+
+		suite.NoError(err)
+		suite.Equal(table.expected, value)
+	}
+}
+
+func (suite *ComponentConfigTestSuite) TestFormat() {
+	suite.Equal("Hi", suite.component.ConfigSchema[0].Format("Hi"))
+	suite.Equal("5", suite.component.ConfigSchema[1].Format(int64(5)))
+	suite.Equal("true", suite.component.ConfigSchema[2].Format(true))
+}
+
+func (suite *ComponentConfigTestSuite) TestIntegerRangeValidator() {
+	validator := IntegerRangeValidator(1, 3)
+
+	suite.NoError(validator(int64(1)))
+	suite.NoError(validator(int64(3)))
+	suite.Error(validator(int64(0)))
+	suite.Error(validator(int64(4)))
+	suite.Error(validator("2"))
+}
+
+func (suite *ComponentConfigTestSuite) TestIntegerOptionRangeValidator() {
+	minValue := float64(2)
+	options := []*discordgo.ApplicationCommandOption{
+		{Name: "other"},
+		{Name: "sites", MinValue: &minValue, MaxValue: 1000},
+	}
+
+	validate := IntegerOptionRangeValidator(options, "sites")
+
+	suite.Error(validate(int64(1)))
+	suite.NoError(validate(int64(2)))
+	suite.NoError(validate(int64(1000)))
+	suite.Error(validate(int64(1001)))
+
+	unbounded := IntegerOptionRangeValidator(options, "other")
+	suite.NoError(unbounded(int64(-5000)))
+
+	suite.Panics(func() {
+		IntegerOptionRangeValidator(options, "unknown")
+	})
+}
+
+func TestComponentConfig(t *testing.T) {
+	suite.Run(t, new(ComponentConfigTestSuite))
+}
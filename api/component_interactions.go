@@ -0,0 +1,244 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"strings"
+	"sync"
+)
+
+// customIdSeparator separates the component code, the handler name
+// and the payload in the custom id of message components.
+const customIdSeparator = ":"
+
+// MaxCustomIdLength is the maximum length of a custom id accepted by Discord.
+const MaxCustomIdLength = 100
+
+// ComponentInteractionHandler is a function that handles an interaction
+// with a message component (e.g. a button or a select menu).
+//
+// The payload holds the state that has been encoded in the custom id
+// of the message component when it was created. It is empty, if there is no payload.
+type ComponentInteractionHandler func(s *discordgo.Session, i *discordgo.InteractionCreate, payload string)
+
+// componentInteraction holds a registered ComponentInteractionHandler
+// together with the component that owns it.
+type componentInteraction struct {
+	name    string
+	handler ComponentInteractionHandler
+	c       *Component
+}
+
+var (
+	// componentInteractionMap holds all registered componentInteraction
+	// with the namespaced name (<component code>:<name>) as key.
+	componentInteractionMap = make(map[string]*componentInteraction)
+
+	// componentInteractionMapMu is used to synchronize access to the componentInteractionMap,
+	// as handlers can be registered and unregistered at runtime.
+	componentInteractionMapMu sync.RWMutex
+)
+
+// ComponentInteractionContainer is the default implementation
+// of the ComponentInteractionManager.
+type ComponentInteractionContainer struct {
+	owner *Component
+}
+
+// ComponentInteractionManager allows components to handle interactions
+// with message components like buttons and select menus.
+//
+// The custom ids of message components are namespaced using the code of the
+// component that owns the handler. Always use CustomId to create the custom id
+// of a message component, that should be handled by a registered handler.
+type ComponentInteractionManager interface {
+	// Register registers the passed handler with the passed name.
+	// The name must be unique within the component and must not contain a colon.
+	Register(name string, handler ComponentInteractionHandler) error
+	// Unregister removes the handler with the passed name.
+	Unregister(name string) error
+	// UnregisterAll removes all handlers owned by the component.
+	UnregisterAll()
+	// CustomId creates the custom id for a message component that should be
+	// handled by the handler with the passed name.
+	// The optional payload is passed to the handler, when the message component is used.
+	CustomId(name string, payload string) (string, error)
+}
+
+// ComponentInteractionManager returns the ComponentInteractionManager of the component,
+// which allows to handle interactions with message components like buttons and select menus.
+func (c *Component) ComponentInteractionManager() ComponentInteractionManager {
+	if nil == c.interactionManager {
+		c.interactionManager = &ComponentInteractionContainer{owner: c}
+	}
+
+	return c.interactionManager
+}
+
+// Register registers the passed handler with the passed name.
+// The name must be unique within the component and must not contain a colon.
+func (cic *ComponentInteractionContainer) Register(name string, handler ComponentInteractionHandler) error {
+	if "" == name || strings.Contains(name, customIdSeparator) {
+		return fmt.Errorf("the name \"%s\" of the component interaction handler is invalid", name)
+	}
+
+	key := getComponentInteractionKey(cic.owner.Code, name)
+
+	componentInteractionMapMu.Lock()
+	defer componentInteractionMapMu.Unlock()
+
+	if _, ok := componentInteractionMap[key]; ok {
+		return fmt.Errorf("there is already a component interaction handler with the name \"%s\"", key)
+	}
+
+	componentInteractionMap[key] = &componentInteraction{
+		name:    name,
+		handler: handler,
+		c:       cic.owner,
+	}
+
+	cic.owner.Logger().Info("Registered component interaction handler \"%s\"!", key)
+
+	return nil
+}
+
+// Unregister removes the handler with the passed name.
+func (cic *ComponentInteractionContainer) Unregister(name string) error {
+	key := getComponentInteractionKey(cic.owner.Code, name)
+
+	componentInteractionMapMu.Lock()
+	defer componentInteractionMapMu.Unlock()
+
+	if _, ok := componentInteractionMap[key]; !ok {
+		return fmt.Errorf("there is no component interaction handler with the name \"%s\"", key)
+	}
+
+	delete(componentInteractionMap, key)
+
+	cic.owner.Logger().Info("Unregistered component interaction handler \"%s\"!", key)
+
+	return nil
+}
+
+// UnregisterAll removes all handlers owned by the component.
+func (cic *ComponentInteractionContainer) UnregisterAll() {
+	componentInteractionMapMu.Lock()
+	defer componentInteractionMapMu.Unlock()
+
+	for key, interaction := range componentInteractionMap {
+		if interaction.c.Code == cic.owner.Code {
+			delete(componentInteractionMap, key)
+		}
+	}
+}
+
+// CustomId creates the custom id for a message component that should be
+// handled by the handler with the passed name.
+// The optional payload is passed to the handler, when the message component is used.
+//
+// The resulting custom id has the format <component code>:<name>[:<payload>]
+// and must not exceed the maximum length of custom ids allowed by Discord.
+func (cic *ComponentInteractionContainer) CustomId(name string, payload string) (string, error) {
+	customId := getComponentInteractionKey(cic.owner.Code, name)
+	if "" != payload {
+		customId = customId + customIdSeparator + payload
+	}
+
+	if len(customId) > MaxCustomIdLength {
+		return "", fmt.Errorf("the custom id \"%s\" exceeds the maximum length of %d characters",
+			customId,
+			MaxCustomIdLength)
+	}
+
+	return customId, nil
+}
+
+// ParseCustomId splits the passed custom id into the component code,
+// the handler name and the payload.
+//
+// Returns false if the custom id has not been created using CustomId.
+func ParseCustomId(customId string) (entities.ComponentCode, string, string, bool) {
+	parts := strings.SplitN(customId, customIdSeparator, 3)
+	if len(parts) < 2 || "" == parts[0] || "" == parts[1] {
+		return "", "", "", false
+	}
+
+	payload := ""
+	if len(parts) == 3 {
+		payload = parts[2]
+	}
+
+	return entities.ComponentCode(parts[0]), parts[1], payload, true
+}
+
+// getComponentInteractionKey returns the namespaced name of a component interaction handler.
+func getComponentInteractionKey(code entities.ComponentCode, name string) string {
+	return string(code) + customIdSeparator + name
+}
+
+// getComponentInteraction returns the componentInteraction registered with the passed key.
+func getComponentInteraction(key string) (*componentInteraction, bool) {
+	componentInteractionMapMu.RLock()
+	defer componentInteractionMapMu.RUnlock()
+
+	interaction, ok := componentInteractionMap[key]
+
+	return interaction, ok
+}
+
+// handleComponentInteractionDispatch handles the processing of an interaction
+// with a message component. The interaction is delegated to the handler that has
+// been encoded in the custom id of the message component.
+//
+// Like slash commands, interactions with message components of disabled
+// components are rejected.
+func handleComponentInteractionDispatch(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	code, name, payload, ok := ParseCustomId(i.MessageComponentData().CustomID)
+	if !ok {
+		return
+	}
+
+	interaction, ok := getComponentInteraction(getComponentInteractionKey(code, name))
+	if !ok {
+		return
+	}
+
+	defer recoverFromInteractionPanic(s, i, interaction.c, fmt.Sprintf("component interaction \"%s\" on guild \"%s\"",
+		name,
+		i.GuildID))
+
+	if !IsComponentEnabled(interaction.c, i.GuildID) {
+		respondWithComponentDisabled(s, i, interaction.c)
+
+		return
+	}
+
+	interaction.handler(s, i, payload)
+}
+
+// respondWithComponentDisabled responds to the passed interaction with a message
+// telling the user that the component that should handle the interaction is disabled.
+func respondWithComponentDisabled(s *discordgo.Session, i *discordgo.InteractionCreate, c *Component) {
+	respondWithStopMessage(s, i, c, fmt.Sprintf("The `%s` module is currently disabled, "+
+		"so this interaction cannot be processed!",
+		c.Name))
+}
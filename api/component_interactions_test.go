@@ -0,0 +1,171 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+type ComponentInteractionManagerTestSuite struct {
+	suite.Suite
+	owningComponent *Component
+}
+
+func (suite *ComponentInteractionManagerTestSuite) SetupTest() {
+	suite.owningComponent = &Component{
+		Code: "bot_test_component",
+		Name: "Test Component",
+	}
+	componentInteractionMap = make(map[string]*componentInteraction)
+}
+
+func (suite *ComponentInteractionManagerTestSuite) TestRegister() {
+	err := suite.owningComponent.ComponentInteractionManager().Register("confirm", nil)
+
+	suite.NoError(err)
+	suite.Contains(componentInteractionMap, "bot_test_component:confirm")
+}
+
+func (suite *ComponentInteractionManagerTestSuite) TestRegisterTwice() {
+	manager := suite.owningComponent.ComponentInteractionManager()
+
+	suite.NoError(manager.Register("confirm", nil))
+	suite.Error(manager.Register("confirm", nil))
+}
+
+func (suite *ComponentInteractionManagerTestSuite) TestRegisterWithInvalidName() {
+	manager := suite.owningComponent.ComponentInteractionManager()
+
+	suite.Error(manager.Register("", nil))
+	suite.Error(manager.Register("con:firm", nil))
+	suite.Len(componentInteractionMap, 0)
+}
+
+func (suite *ComponentInteractionManagerTestSuite) TestUnregister() {
+	manager := suite.owningComponent.ComponentInteractionManager()
+	suite.NoError(manager.Register("confirm", nil))
+
+	suite.NoError(manager.Unregister("confirm"))
+	suite.Len(componentInteractionMap, 0)
+	suite.Error(manager.Unregister("confirm"))
+}
+
+func (suite *ComponentInteractionManagerTestSuite) TestUnregisterAll() {
+	foreignComponent := &Component{Code: "foreign_component"}
+
+	suite.NoError(suite.owningComponent.ComponentInteractionManager().Register("confirm", nil))
+	suite.NoError(suite.owningComponent.ComponentInteractionManager().Register("cancel", nil))
+	suite.NoError(foreignComponent.ComponentInteractionManager().Register("confirm", nil))
+
+	suite.owningComponent.ComponentInteractionManager().UnregisterAll()
+
+	suite.Len(componentInteractionMap, 1)
+	suite.Contains(componentInteractionMap, "foreign_component:confirm")
+}
+
+func (suite *ComponentInteractionManagerTestSuite) TestCustomId() {
+	manager := suite.owningComponent.ComponentInteractionManager()
+
+	customId, err := manager.CustomId("confirm", "")
+	suite.NoError(err)
+	suite.Equal("bot_test_component:confirm", customId)
+
+	customId, err = manager.CustomId("confirm", "42:abc")
+	suite.NoError(err)
+	suite.Equal("bot_test_component:confirm:42:abc", customId)
+
+	customId, err = manager.CustomId("confirm", strings.Repeat("a", MaxCustomIdLength))
+	suite.Error(err)
+	suite.Equal("", customId)
+}
+
+func (suite *ComponentInteractionManagerTestSuite) TestParseCustomId() {
+	tables := []struct {
+		customId        string
+		expectedCode    entities.ComponentCode
+		expectedName    string
+		expectedPayload string
+		expectedOk      bool
+	}{
+		{"bot_test_component:confirm", "bot_test_component", "confirm", "", true},
+		{"bot_test_component:confirm:42:abc", "bot_test_component", "confirm", "42:abc", true},
+		{"bot_test_component", "", "", "", false},
+		{"bot_test_component:", "", "", "", false},
+		{":confirm", "", "", "", false},
+		{"", "", "", "", false},
+	}
+
+	for _, table := range tables {
+		code, name, payload, ok := ParseCustomId(table.customId)
+
+		suite.Equal(table.expectedCode, code)
+		suite.Equal(table.expectedName, name)
+		suite.Equal(table.expectedPayload, payload)
+		suite.Equal(table.expectedOk, ok)
+	}
+}
+
+func (suite *ComponentInteractionManagerTestSuite) TestHandleComponentInteractionDispatch() {
+	receivedPayload := ""
+	handlerCalled := false
+
+	err := suite.owningComponent.ComponentInteractionManager().Register("confirm",
+		func(_ *discordgo.Session, _ *discordgo.InteractionCreate, payload string) {
+			handlerCalled = true
+			receivedPayload = payload
+		})
+	suite.NoError(err)
+
+	handleCommandDispatch(nil, &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type: discordgo.InteractionMessageComponent,
+			Data: discordgo.MessageComponentInteractionData{CustomID: "bot_test_component:confirm:42"},
+		},
+	})
+
+	suite.True(handlerCalled)
+	suite.Equal("42", receivedPayload)
+}
+
+func (suite *ComponentInteractionManagerTestSuite) TestHandleComponentInteractionDispatchWithUnknownHandler() {
+	handlerCalled := false
+
+	err := suite.owningComponent.ComponentInteractionManager().Register("confirm",
+		func(_ *discordgo.Session, _ *discordgo.InteractionCreate, _ string) {
+			handlerCalled = true
+		})
+	suite.NoError(err)
+
+	handleCommandDispatch(nil, &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type: discordgo.InteractionMessageComponent,
+			Data: discordgo.MessageComponentInteractionData{CustomID: "bot_test_component:cancel"},
+		},
+	})
+
+	suite.False(handlerCalled)
+}
+
+func TestComponentInteractionManager(t *testing.T) {
+	suite.Run(t, new(ComponentInteractionManagerTestSuite))
+}
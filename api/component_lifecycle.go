@@ -0,0 +1,185 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"fmt"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"sync"
+)
+
+// componentLifecycleMu ensures that only one component
+// is loaded, unloaded or reloaded at the same time.
+var componentLifecycleMu sync.Mutex
+
+// ComponentLifecycleContainer is the default implementation
+// of the ComponentLifecycleManager.
+type ComponentLifecycleContainer struct {
+	owner *Component
+}
+
+// ComponentLifecycleManager allows to load, unload and reload
+// components while the bot is running.
+//
+// Core components cannot be managed at runtime, as the bot
+// would not work properly without them.
+//
+// After every operation, the slash commands are synced globally
+// and for all guilds the bot is currently on.
+type ComponentLifecycleManager interface {
+	// Load loads the component with the passed code.
+	Load(code entities.ComponentCode) error
+	// Unload unloads the component with the passed code.
+	Unload(code entities.ComponentCode) error
+	// Reload unloads the component with the passed code
+	// and loads it again.
+	Reload(code entities.ComponentCode) error
+}
+
+// ComponentLifecycleManager returns the ComponentLifecycleManager that allows to
+// load, unload and reload other components at runtime.
+func (c *Component) ComponentLifecycleManager() ComponentLifecycleManager {
+	if nil == c.lifecycleManager {
+		c.lifecycleManager = &ComponentLifecycleContainer{owner: c}
+	}
+
+	return c.lifecycleManager
+}
+
+// Load loads the component with the passed code.
+func (clc *ComponentLifecycleContainer) Load(code entities.ComponentCode) error {
+	comp, err := clc.findManageableComponent(code)
+	if nil != err {
+		return err
+	}
+
+	componentLifecycleMu.Lock()
+	defer componentLifecycleMu.Unlock()
+
+	if comp.State.Loaded {
+		return fmt.Errorf("the component \"%s\" is already loaded", code)
+	}
+
+	for _, dependency := range GetComponentDependencies(code) {
+		if !dependency.State.Loaded {
+			return fmt.Errorf("the component \"%s\" depends on \"%s\", which is not loaded", code, dependency.Code)
+		}
+	}
+
+	err = comp.LoadComponent(clc.owner.discord)
+	if nil != err {
+		return err
+	}
+
+	clc.owner.Logger().Info("Component \"%s\" has been loaded at runtime", code)
+	clc.syncCommands()
+
+	return nil
+}
+
+// Unload unloads the component with the passed code.
+func (clc *ComponentLifecycleContainer) Unload(code entities.ComponentCode) error {
+	comp, err := clc.findManageableComponent(code)
+	if nil != err {
+		return err
+	}
+
+	componentLifecycleMu.Lock()
+	defer componentLifecycleMu.Unlock()
+
+	if !comp.State.Loaded {
+		return fmt.Errorf("the component \"%s\" is not loaded", code)
+	}
+
+	for _, dependent := range GetDependentComponents(code) {
+		if dependent.State.Loaded {
+			return fmt.Errorf("the component \"%s\" is required by \"%s\", which must be unloaded first",
+				code,
+				dependent.Code)
+		}
+	}
+
+	err = comp.UnloadComponent(clc.owner.discord)
+	if nil != err {
+		clc.owner.Logger().Err(err, "The unload hook of component \"%s\" failed at runtime", code)
+	} else {
+		clc.owner.Logger().Info("Component \"%s\" has been unloaded at runtime", code)
+	}
+	clc.syncCommands()
+
+	return err
+}
+
+// Reload unloads the component with the passed code
+// and loads it again.
+//
+// Like Unload, components that are required by loaded components cannot be reloaded,
+// as their dependents would run against the torn down component while it is reloaded.
+func (clc *ComponentLifecycleContainer) Reload(code entities.ComponentCode) error {
+	comp, err := clc.findManageableComponent(code)
+	if nil != err {
+		return err
+	}
+
+	componentLifecycleMu.Lock()
+	defer componentLifecycleMu.Unlock()
+
+	for _, dependent := range GetDependentComponents(code) {
+		if dependent.State.Loaded {
+			return fmt.Errorf("the component \"%s\" is required by \"%s\", which must be unloaded first",
+				code,
+				dependent.Code)
+		}
+	}
+
+	err = comp.ReloadComponent(clc.owner.discord)
+	if nil != err {
+		clc.owner.Logger().Err(err, "Failed to reload component \"%s\" at runtime", code)
+	} else {
+		clc.owner.Logger().Info("Component \"%s\" has been reloaded at runtime", code)
+	}
+	clc.syncCommands()
+
+	return err
+}
+
+// findManageableComponent returns the component with the passed code,
+// if it exists and is no core component.
+func (clc *ComponentLifecycleContainer) findManageableComponent(code entities.ComponentCode) (*Component, error) {
+	comp, ok := FindComponentByCode(code)
+	if !ok {
+		return nil, fmt.Errorf("there is no component with code \"%s\"", code)
+	}
+
+	if IsCoreComponent(comp) {
+		return nil, fmt.Errorf("the core component \"%s\" cannot be managed at runtime", code)
+	}
+
+	if nil == clc.owner.discord {
+		return nil, fmt.Errorf("cannot manage component \"%s\" without an active discord session", code)
+	}
+
+	return comp, nil
+}
+
+// syncCommands syncs the slash commands globally and for all
+// guilds the bot is currently on, across all shards.
+func (clc *ComponentLifecycleContainer) syncCommands() {
+	syncCommandsOfAllGuilds(clc.owner.discord, clc.owner.SlashCommandManager())
+}
@@ -0,0 +1,74 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"github.com/lazybytez/jojo-discord-bot/test/discordgo_mock"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type ComponentLifecycleTestSuite struct {
+	suite.Suite
+	previousComponents []*Component
+	owningComponent    *Component
+	dependency         *Component
+	dependent          *Component
+}
+
+func (suite *ComponentLifecycleTestSuite) SetupTest() {
+	session, _ := discordgo_mock.MockSession()
+
+	suite.owningComponent = &Component{Code: "bot_test_component", discord: session}
+	suite.dependency = &Component{
+		Code:  "dependency",
+		State: &State{Loaded: true},
+	}
+	suite.dependent = &Component{
+		Code:      "dependent",
+		DependsOn: []entities.ComponentCode{"dependency"},
+		State:     &State{Loaded: true},
+	}
+
+	suite.previousComponents = Components
+	Components = []*Component{suite.dependency, suite.dependent}
+}
+
+func (suite *ComponentLifecycleTestSuite) TearDownTest() {
+	Components = suite.previousComponents
+}
+
+func (suite *ComponentLifecycleTestSuite) TestUnloadWithLoadedDependent() {
+	err := suite.owningComponent.ComponentLifecycleManager().Unload("dependency")
+
+	suite.Error(err)
+	suite.True(suite.dependency.State.Loaded)
+}
+
+func (suite *ComponentLifecycleTestSuite) TestReloadWithLoadedDependent() {
+	err := suite.owningComponent.ComponentLifecycleManager().Reload("dependency")
+
+	suite.Error(err)
+	suite.True(suite.dependency.State.Loaded)
+}
+
+func TestComponentLifecycle(t *testing.T) {
+	suite.Run(t, new(ComponentLifecycleTestSuite))
+}
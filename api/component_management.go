@@ -19,9 +19,11 @@
 package api
 
 import (
+	"fmt"
 	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
 	"reflect"
-	"sort"
+	"strings"
 )
 
 // Components holds all available components that
@@ -39,61 +41,201 @@ var Components = make([]*Component, 0)
 // components.
 // To get the application to automatically call this function, add an import
 // to the <repo-root>/components/component_registry.go.
+//
+// Note that the registered components are not ordered by their dependencies
+// until ResolveComponentDependencies has been called.
 func RegisterComponent(component *Component, loadComponentFunction func(session *discordgo.Session) error) {
 	component.loadComponentFunction = loadComponentFunction
 	Components = append(Components, component)
+}
+
+// RegisterComponentUnloadHook sets the function that is called when the passed
+// Component is unloaded.
+//
+// The hook should be used to free resources that are not managed by the API,
+// like tickers or goroutines started by the component. Handlers, slash commands,
+// web api routes and status are removed automatically.
+func RegisterComponentUnloadHook(component *Component, unloadComponentFunction func(session *discordgo.Session) error) {
+	component.unloadComponentFunction = unloadComponentFunction
+}
+
+// FindComponentByCode returns the registered Component with the passed code.
+// The second return value is false, if there is no such component.
+func FindComponentByCode(code entities.ComponentCode) (*Component, bool) {
+	for _, comp := range Components {
+		if comp.Code == code {
+			return comp, true
+		}
+	}
+
+	return nil, false
+}
 
-	// This is run once when starting the application.
-	// It is not expected to have more than 100 components,
-	// therefore just sort after every mutation.
-	sortComponents()
+// GetDependentComponents returns all registered components that depend on the
+// component with the passed code, either directly or transitively.
+//
+// The returned components are ordered like the Components slice.
+func GetDependentComponents(code entities.ComponentCode) []*Component {
+	dependents := make([]*Component, 0)
+	dependentCodes := map[entities.ComponentCode]bool{code: true}
+
+	// Components are sorted by their dependencies, therefore
+	// a single pass is enough to find transitive dependents.
+	for _, comp := range Components {
+		for _, dependency := range comp.DependsOn {
+			if dependentCodes[dependency] {
+				dependentCodes[comp.Code] = true
+				dependents = append(dependents, comp)
+
+				break
+			}
+		}
+	}
+
+	return dependents
 }
 
-// sortComponents sorts the components contained in
-// the Components slice.
+// GetComponentDependencies returns all registered components the component
+// with the passed code depends on, either directly or transitively.
+//
+// The returned components are ordered like the Components slice.
+func GetComponentDependencies(code entities.ComponentCode) []*Component {
+	dependencyCodes := make(map[entities.ComponentCode]bool)
+
+	var collect func(code entities.ComponentCode)
+	collect = func(code entities.ComponentCode) {
+		comp, ok := FindComponentByCode(code)
+		if !ok {
+			return
+		}
+
+		for _, dependency := range comp.DependsOn {
+			if dependencyCodes[dependency] {
+				continue
+			}
+
+			dependencyCodes[dependency] = true
+			collect(dependency)
+		}
+	}
+	collect(code)
+
+	dependencies := make([]*Component, 0)
+	for _, comp := range Components {
+		if dependencyCodes[comp.Code] {
+			dependencies = append(dependencies, comp)
+		}
+	}
+
+	return dependencies
+}
+
+// ResolveComponentDependencies sorts the components contained in
+// the Components slice, so that every component is placed after
+// the components it depends on.
 //
 // The following logic is applied:
-//   - First split components with a code starting with "bot_" out
-//   - Sort components with code prefix "bot_" after their priority
-//   - Sort other components after their priority
-//   - Append sorted normal components to the "bot_" Components
+//   - A component can only be placed when all of its dependencies have been placed
+//   - Of all components that can be placed, components with a code starting
+//     with "bot_" are placed first
+//   - Components that are equal in the points above keep their registration order
 //
-// The slice is now sorted after priority, with "bot_" components being always first
-func sortComponents() {
-	coreComponents := make([]*Component, 0)
-	featureComponents := make([]*Component, 0)
+// An error is returned, when a component depends on a component that has not been
+// registered or when the dependencies of components form a cycle.
+// In this case, the Components slice is left untouched.
+func ResolveComponentDependencies() error {
+	for _, comp := range Components {
+		for _, dependency := range comp.DependsOn {
+			if _, ok := FindComponentByCode(dependency); !ok {
+				return fmt.Errorf(
+					"the component \"%s\" depends on the component \"%s\", which has not been registered",
+					comp.Code,
+					dependency)
+			}
+		}
+	}
 
+	pending := make([]*Component, 0, len(Components))
 	for _, comp := range Components {
 		if IsCoreComponent(comp) {
-			coreComponents = append(coreComponents, comp)
+			pending = append(pending, comp)
+		}
+	}
+	for _, comp := range Components {
+		if !IsCoreComponent(comp) {
+			pending = append(pending, comp)
+		}
+	}
+
+	placed := make(map[entities.ComponentCode]bool)
+	sortedComponents := make([]*Component, 0, len(Components))
+	for len(pending) > 0 {
+		nextKey := -1
+		for key, comp := range pending {
+			if areDependenciesPlaced(comp, placed) {
+				nextKey = key
+
+				break
+			}
+		}
 
-			continue
+		if -1 == nextKey {
+			return fmt.Errorf(
+				"the dependencies of the following components form a cycle: %s",
+				joinComponentCodes(pending))
 		}
 
-		featureComponents = append(featureComponents, comp)
+		next := pending[nextKey]
+		placed[next.Code] = true
+		sortedComponents = append(sortedComponents, next)
+		pending = append(pending[:nextKey], pending[nextKey+1:]...)
 	}
 
-	sort.SliceStable(coreComponents, func(i, j int) bool {
-		return coreComponents[i].LoadPriority > coreComponents[j].LoadPriority
-	})
+	Components = sortedComponents
 
-	sort.SliceStable(featureComponents, func(i, j int) bool {
-		return featureComponents[i].LoadPriority > featureComponents[j].LoadPriority
-	})
+	return nil
+}
+
+// areDependenciesPlaced checks whether all dependencies of the
+// passed component are contained in the passed map of placed components.
+func areDependenciesPlaced(comp *Component, placed map[entities.ComponentCode]bool) bool {
+	for _, dependency := range comp.DependsOn {
+		if !placed[dependency] {
+			return false
+		}
+	}
 
-	Components = []*Component{}
-	Components = append(Components, coreComponents...)
-	Components = append(Components, featureComponents...)
+	return true
+}
+
+// joinComponentCodes creates a comma separated list of the codes
+// of the passed components.
+func joinComponentCodes(components []*Component) string {
+	codes := make([]string, len(components))
+	for key, comp := range components {
+		codes[key] = fmt.Sprintf("\"%s\"", comp.Code)
+	}
+
+	return strings.Join(codes, ", ")
 }
 
 // IsComponentEnabled checks if a specific component is currently enabled
 // for a specific guild.
 // If the guild id is empty, the function will return the global status of the component.
+//
+// A component is only considered enabled, when all of its dependencies are enabled too.
 func IsComponentEnabled(comp *Component, guildId string) bool {
 	if IsCoreComponent(comp) {
 		return true
 	}
 
+	for _, dependency := range comp.DependsOn {
+		dependencyComp, ok := FindComponentByCode(dependency)
+		if !ok || !IsComponentEnabled(dependencyComp, guildId) {
+			return false
+		}
+	}
+
 	em := comp.EntityManager()
 	regComp, err := em.RegisteredComponent().Get(comp.Code)
 	if nil != err {
@@ -119,6 +261,60 @@ func IsComponentEnabled(comp *Component, guildId string) bool {
 	return guildStatus.Enabled
 }
 
+// IsCommandEnabled checks if a specific command is currently enabled
+// for a specific guild. A command is enabled when its component is enabled
+// and the command has not been disabled on the guild.
+// If the guild id is empty, the function will return the global status of the component.
+func IsCommandEnabled(command *Command, guildId string) bool {
+	if !IsComponentEnabled(command.c, guildId) {
+		return false
+	}
+
+	return IsCommandEnabledOnGuild(command, guildId)
+}
+
+// IsCommandEnabledOnGuild checks if a specific command has not been disabled on the passed guild.
+// In contrast to IsCommandEnabled, the status of the owning component is not checked.
+//
+// Commands of core components and commands without a stored status are always enabled.
+func IsCommandEnabledOnGuild(command *Command, guildId string) bool {
+	if "" == guildId || IsCoreComponent(command.c) {
+		return true
+	}
+
+	em := command.c.EntityManager()
+	regComp, err := em.RegisteredComponent().Get(command.c.Code)
+	if nil != err {
+		command.c.Logger().Warn("Missing component with name \"%v\" in database!", command.c.Name)
+
+		return true
+	}
+
+	guild, err := em.Guilds().Get(guildId)
+	if nil != err {
+		command.c.Logger().Warn("Missing guild with ID \"%v\" in database!", guildId)
+
+		return true
+	}
+
+	statuses, err := em.GuildCommandStatus().GetByGuildAndComponent(guild.ID, regComp.ID)
+	if nil != err {
+		command.c.Logger().Err(err, "Failed to load the command statuses of component \"%v\" "+
+			"on guild \"%v\"!", command.c.Name, guildId)
+
+		return true
+	}
+
+	commandKey := getCommandKey(command.Cmd.Type, command.Cmd.Name)
+	for _, status := range statuses {
+		if status.Command == commandKey {
+			return status.Enabled
+		}
+	}
+
+	return true
+}
+
 // getGuildIdFromEventInterface returns the guild id of an event.
 // It first tries to get the value of a GuildID field, if this doesn't work,
 // the ID field is used (event should be guild event in this case).
@@ -20,12 +20,18 @@ package api
 
 import (
 	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"github.com/lazybytez/jojo-discord-bot/services/cache"
+	"github.com/lazybytez/jojo-discord-bot/test/logmock"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
 	"testing"
+	"time"
 )
 
-// RegisterComponentTestSuite tests RegisterComponent explicitly
-// and sortComponents implicitly!
+// RegisterComponentTestSuite tests RegisterComponent
+// and the resolution of component dependencies!
 type RegisterComponentTestSuite struct {
 	suite.Suite
 }
@@ -123,614 +129,187 @@ func (suite *RegisterComponentTestSuite) TestRegisterComponentWithMultipleUnsort
 	suite.True(hasCalled3)
 }
 
-func (suite *RegisterComponentTestSuite) TestRegisterComponentWithMultipleSortedFeatureComponents() {
-	testComponent1 := Component{
-		// Metadata
-		Code:         "component1",
-		LoadPriority: -1000,
-	}
-	testComponent2 := Component{
-		// Metadata
-		Code: "component2",
-	}
-	testComponent3 := Component{
-		// Metadata
-		Code:         "component3",
-		LoadPriority: 1000,
-	}
-	testComponent4 := Component{
-		// Metadata
-		Code: "component4",
-	}
-
-	hasCalled1 := false
-	loadFunc1 := func(session *discordgo.Session) error {
-		hasCalled1 = true
-		return nil
-	}
-	hasCalled2 := false
-	loadFunc2 := func(session *discordgo.Session) error {
-		hasCalled2 = true
-		return nil
-	}
-	hasCalled3 := false
-	loadFunc3 := func(session *discordgo.Session) error {
-		hasCalled3 = true
-		return nil
-	}
-	hasCalled4 := false
-	loadFunc4 := func(session *discordgo.Session) error {
-		hasCalled4 = true
-		return nil
-	}
+func (suite *RegisterComponentTestSuite) TestResolveComponentDependenciesWithoutDependencies() {
+	testComponent1 := Component{Code: "component1"}
+	testComponent2 := Component{Code: "component2"}
+	testComponent3 := Component{Code: "component3"}
 
-	RegisterComponent(&testComponent1, loadFunc1)
-	RegisterComponent(&testComponent2, loadFunc2)
-	RegisterComponent(&testComponent3, loadFunc3)
-	RegisterComponent(&testComponent4, loadFunc4)
+	RegisterComponent(&testComponent1, nil)
+	RegisterComponent(&testComponent2, nil)
+	RegisterComponent(&testComponent3, nil)
 
-	suite.Len(Components, 4)
+	err := ResolveComponentDependencies()
 
-	// Fist component -> Third component
-	resultComp1 := Components[3]
-	suite.NotNil(resultComp1)
-	suite.Equal(&testComponent1, resultComp1)
-	err1 := resultComp1.loadComponentFunction(nil)
-	suite.NoError(err1)
-	suite.True(hasCalled1)
-
-	// Second component
-	resultComp2 := Components[1]
-	suite.NotNil(resultComp2)
-	suite.Equal(&testComponent2, resultComp2)
-	err2 := resultComp2.loadComponentFunction(nil)
-	suite.NoError(err2)
-	suite.True(hasCalled2)
-
-	// Third component -> First component
-	resultComp3 := Components[0]
-	suite.NotNil(resultComp3)
-	suite.Equal(&testComponent3, resultComp3)
-	err3 := resultComp3.loadComponentFunction(nil)
-	suite.NoError(err3)
-	suite.True(hasCalled3)
-
-	// Fourth component
-	resultComp4 := Components[2]
-	suite.NotNil(resultComp4)
-	suite.Equal(&testComponent4, resultComp4)
-	err4 := resultComp4.loadComponentFunction(nil)
-	suite.NoError(err4)
-	suite.True(hasCalled4)
-}
-
-func (suite *RegisterComponentTestSuite) TestRegisterComponentWithSingleCoreComponent() {
-	testComponent := Component{
-		// Metadata
-		Code: "bot_component1",
-	}
-
-	hasCalled := false
-	loadFunc := func(session *discordgo.Session) error {
-		hasCalled = true
-
-		return nil
-	}
-
-	RegisterComponent(&testComponent, loadFunc)
-
-	suite.Len(Components, 1)
-
-	resultComp := Components[0]
-
-	suite.NotNil(resultComp)
-	suite.Equal(&testComponent, resultComp)
-
-	err := resultComp.loadComponentFunction(nil)
 	suite.NoError(err)
-	suite.True(hasCalled)
+	suite.Equal([]*Component{&testComponent1, &testComponent2, &testComponent3}, Components)
 }
 
-func (suite *RegisterComponentTestSuite) TestRegisterComponentWithMultipleUnsortedCoreComponents() {
-	testComponent1 := Component{
-		// Metadata
-		Code: "bot_component1",
-	}
-	testComponent2 := Component{
-		// Metadata
-		Code: "bot_component2",
-	}
-	testComponent3 := Component{
-		// Metadata
-		Code: "bot_component3",
-	}
+func (suite *RegisterComponentTestSuite) TestResolveComponentDependenciesWithMixedComponents() {
+	testComponent1 := Component{Code: "component1"}
+	testComponent2 := Component{Code: "bot_component2"}
+	testComponent3 := Component{Code: "component3"}
+	testComponent4 := Component{Code: "bot_component4"}
 
-	hasCalled1 := false
-	loadFunc1 := func(session *discordgo.Session) error {
-		hasCalled1 = true
-		return nil
-	}
-	hasCalled2 := false
-	loadFunc2 := func(session *discordgo.Session) error {
-		hasCalled2 = true
-		return nil
-	}
-	hasCalled3 := false
-	loadFunc3 := func(session *discordgo.Session) error {
-		hasCalled3 = true
-		return nil
-	}
+	RegisterComponent(&testComponent1, nil)
+	RegisterComponent(&testComponent2, nil)
+	RegisterComponent(&testComponent3, nil)
+	RegisterComponent(&testComponent4, nil)
 
-	RegisterComponent(&testComponent1, loadFunc1)
-	RegisterComponent(&testComponent2, loadFunc2)
-	RegisterComponent(&testComponent3, loadFunc3)
+	err := ResolveComponentDependencies()
 
-	suite.Len(Components, 3)
-
-	// Fist component
-	resultComp1 := Components[0]
-	suite.NotNil(resultComp1)
-	suite.Equal(&testComponent1, resultComp1)
-	err1 := resultComp1.loadComponentFunction(nil)
-	suite.NoError(err1)
-	suite.True(hasCalled1)
-
-	// Second component
-	resultComp2 := Components[1]
-	suite.NotNil(resultComp2)
-	suite.Equal(&testComponent2, resultComp2)
-	err2 := resultComp2.loadComponentFunction(nil)
-	suite.NoError(err2)
-	suite.True(hasCalled2)
-
-	// Third component
-	resultComp3 := Components[2]
-	suite.NotNil(resultComp3)
-	suite.Equal(&testComponent3, resultComp3)
-	err3 := resultComp3.loadComponentFunction(nil)
-	suite.NoError(err3)
-	suite.True(hasCalled3)
+	suite.NoError(err)
+	suite.Equal([]*Component{&testComponent2, &testComponent4, &testComponent1, &testComponent3}, Components)
 }
 
-func (suite *RegisterComponentTestSuite) TestRegisterComponentWithMultipleSortedCoreComponents() {
+func (suite *RegisterComponentTestSuite) TestResolveComponentDependenciesWithDependencies() {
 	testComponent1 := Component{
-		// Metadata
-		Code:         "bot_component1",
-		LoadPriority: -1000,
+		Code:      "component1",
+		DependsOn: []entities.ComponentCode{"component3"},
 	}
 	testComponent2 := Component{
-		// Metadata
-		Code: "bot_component2",
+		Code:      "bot_component2",
+		DependsOn: []entities.ComponentCode{"bot_component4"},
 	}
 	testComponent3 := Component{
-		// Metadata
-		Code:         "bot_component3",
-		LoadPriority: 1000,
+		Code:      "component3",
+		DependsOn: []entities.ComponentCode{"bot_component2", "component5"},
 	}
 	testComponent4 := Component{
-		// Metadata
 		Code: "bot_component4",
 	}
-
-	hasCalled1 := false
-	loadFunc1 := func(session *discordgo.Session) error {
-		hasCalled1 = true
-		return nil
-	}
-	hasCalled2 := false
-	loadFunc2 := func(session *discordgo.Session) error {
-		hasCalled2 = true
-		return nil
-	}
-	hasCalled3 := false
-	loadFunc3 := func(session *discordgo.Session) error {
-		hasCalled3 = true
-		return nil
-	}
-	hasCalled4 := false
-	loadFunc4 := func(session *discordgo.Session) error {
-		hasCalled4 = true
-		return nil
+	testComponent5 := Component{
+		Code: "component5",
 	}
 
-	RegisterComponent(&testComponent1, loadFunc1)
-	RegisterComponent(&testComponent2, loadFunc2)
-	RegisterComponent(&testComponent3, loadFunc3)
-	RegisterComponent(&testComponent4, loadFunc4)
+	RegisterComponent(&testComponent1, nil)
+	RegisterComponent(&testComponent2, nil)
+	RegisterComponent(&testComponent3, nil)
+	RegisterComponent(&testComponent4, nil)
+	RegisterComponent(&testComponent5, nil)
 
-	suite.Len(Components, 4)
+	err := ResolveComponentDependencies()
 
-	// Fist component -> Fourth component
-	resultComp1 := Components[3]
-	suite.NotNil(resultComp1)
-	suite.Equal(&testComponent1, resultComp1)
-	err1 := resultComp1.loadComponentFunction(nil)
-	suite.NoError(err1)
-	suite.True(hasCalled1)
-
-	// Second component -> Second component
-	resultComp2 := Components[1]
-	suite.NotNil(resultComp2)
-	suite.Equal(&testComponent2, resultComp2)
-	err2 := resultComp2.loadComponentFunction(nil)
-	suite.NoError(err2)
-	suite.True(hasCalled2)
-
-	// Third component -> First component
-	resultComp3 := Components[0]
-	suite.NotNil(resultComp3)
-	suite.Equal(&testComponent3, resultComp3)
-	err3 := resultComp3.loadComponentFunction(nil)
-	suite.NoError(err3)
-	suite.True(hasCalled3)
-
-	// Fourth component -> Third component
-	resultComp4 := Components[2]
-	suite.NotNil(resultComp4)
-	suite.Equal(&testComponent4, resultComp4)
-	err4 := resultComp4.loadComponentFunction(nil)
-	suite.NoError(err4)
-	suite.True(hasCalled4)
+	suite.NoError(err)
+	suite.Equal([]*Component{
+		&testComponent4,
+		&testComponent2,
+		&testComponent5,
+		&testComponent3,
+		&testComponent1,
+	}, Components)
 }
 
-func (suite *RegisterComponentTestSuite) TestRegisterComponentWithMultipleUnsortedMixedComponents() {
+func (suite *RegisterComponentTestSuite) TestResolveComponentDependenciesWithMissingDependency() {
 	testComponent1 := Component{
-		// Metadata
-		Code: "component1",
-	}
-	testComponent2 := Component{
-		// Metadata
-		Code: "bot_component2",
-	}
-	testComponent3 := Component{
-		// Metadata
-		Code: "component3",
-	}
-	testComponent4 := Component{
-		// Metadata
-		Code: "bot_component4",
-	}
-
-	hasCalled1 := false
-	loadFunc1 := func(session *discordgo.Session) error {
-		hasCalled1 = true
-		return nil
-	}
-	hasCalled2 := false
-	loadFunc2 := func(session *discordgo.Session) error {
-		hasCalled2 = true
-		return nil
-	}
-	hasCalled3 := false
-	loadFunc3 := func(session *discordgo.Session) error {
-		hasCalled3 = true
-		return nil
-	}
-	hasCalled4 := false
-	loadFunc4 := func(session *discordgo.Session) error {
-		hasCalled4 = true
-		return nil
+		Code:      "component1",
+		DependsOn: []entities.ComponentCode{"missing_component"},
 	}
+	testComponent2 := Component{Code: "component2"}
 
-	RegisterComponent(&testComponent1, loadFunc1)
-	RegisterComponent(&testComponent2, loadFunc2)
-	RegisterComponent(&testComponent3, loadFunc3)
-	RegisterComponent(&testComponent4, loadFunc4)
-
-	suite.Len(Components, 4)
-
-	// Fist component -> Third component
-	resultComp1 := Components[2]
-	suite.NotNil(resultComp1)
-	suite.Equal(&testComponent1, resultComp1)
-	err1 := resultComp1.loadComponentFunction(nil)
-	suite.NoError(err1)
-	suite.True(hasCalled1)
-
-	// Second component -> First component
-	resultComp2 := Components[0]
-	suite.NotNil(resultComp2)
-	suite.Equal(&testComponent2, resultComp2)
-	err2 := resultComp2.loadComponentFunction(nil)
-	suite.NoError(err2)
-	suite.True(hasCalled2)
+	RegisterComponent(&testComponent1, nil)
+	RegisterComponent(&testComponent2, nil)
 
-	// Third component -> Fourth component
-	resultComp3 := Components[3]
-	suite.NotNil(resultComp3)
-	suite.Equal(&testComponent3, resultComp3)
-	err3 := resultComp3.loadComponentFunction(nil)
-	suite.NoError(err3)
-	suite.True(hasCalled3)
+	err := ResolveComponentDependencies()
 
-	// Fourth component -> Second component
-	resultComp4 := Components[1]
-	suite.NotNil(resultComp4)
-	suite.Equal(&testComponent4, resultComp4)
-	err4 := resultComp4.loadComponentFunction(nil)
-	suite.NoError(err4)
-	suite.True(hasCalled4)
+	suite.Error(err)
+	suite.Contains(err.Error(), "missing_component")
+	suite.Equal([]*Component{&testComponent1, &testComponent2}, Components)
 }
 
-func (suite *RegisterComponentTestSuite) TestRegisterComponentWithMultipleSortedBotMixedComponents() {
+func (suite *RegisterComponentTestSuite) TestResolveComponentDependenciesWithCycle() {
 	testComponent1 := Component{
-		// Metadata
-		Code: "bot_component1",
+		Code:      "component1",
+		DependsOn: []entities.ComponentCode{"component3"},
 	}
 	testComponent2 := Component{
-		// Metadata
-		Code:         "bot_component2",
-		LoadPriority: -999,
+		Code: "component2",
 	}
 	testComponent3 := Component{
-		// Metadata
-		Code: "component3",
+		Code:      "component3",
+		DependsOn: []entities.ComponentCode{"component4"},
 	}
 	testComponent4 := Component{
-		// Metadata
-		Code:         "bot_component4",
-		LoadPriority: 9999,
+		Code:      "component4",
+		DependsOn: []entities.ComponentCode{"component1"},
 	}
 
-	hasCalled1 := false
-	loadFunc1 := func(session *discordgo.Session) error {
-		hasCalled1 = true
-		return nil
-	}
-	hasCalled2 := false
-	loadFunc2 := func(session *discordgo.Session) error {
-		hasCalled2 = true
-		return nil
-	}
-	hasCalled3 := false
-	loadFunc3 := func(session *discordgo.Session) error {
-		hasCalled3 = true
-		return nil
-	}
-	hasCalled4 := false
-	loadFunc4 := func(session *discordgo.Session) error {
-		hasCalled4 = true
-		return nil
-	}
+	RegisterComponent(&testComponent1, nil)
+	RegisterComponent(&testComponent2, nil)
+	RegisterComponent(&testComponent3, nil)
+	RegisterComponent(&testComponent4, nil)
 
-	RegisterComponent(&testComponent1, loadFunc1)
-	RegisterComponent(&testComponent2, loadFunc2)
-	RegisterComponent(&testComponent3, loadFunc3)
-	RegisterComponent(&testComponent4, loadFunc4)
+	err := ResolveComponentDependencies()
 
-	suite.Len(Components, 4)
-
-	// Fist component -> Second component
-	resultComp1 := Components[1]
-	suite.NotNil(resultComp1)
-	suite.Equal(&testComponent1, resultComp1)
-	err1 := resultComp1.loadComponentFunction(nil)
-	suite.NoError(err1)
-	suite.True(hasCalled1)
-
-	// Second component -> Fourth component
-	resultComp2 := Components[2]
-	suite.NotNil(resultComp2)
-	suite.Equal(&testComponent2, resultComp2)
-	err2 := resultComp2.loadComponentFunction(nil)
-	suite.NoError(err2)
-	suite.True(hasCalled2)
-
-	// Third component -> Third component
-	resultComp3 := Components[3]
-	suite.NotNil(resultComp3)
-	suite.Equal(&testComponent3, resultComp3)
-	err3 := resultComp3.loadComponentFunction(nil)
-	suite.NoError(err3)
-	suite.True(hasCalled3)
-
-	// Fourth component -> First component
-	resultComp4 := Components[0]
-	suite.NotNil(resultComp4)
-	suite.Equal(&testComponent4, resultComp4)
-	err4 := resultComp4.loadComponentFunction(nil)
-	suite.NoError(err4)
-	suite.True(hasCalled4)
+	suite.Error(err)
+	suite.Contains(err.Error(), "component1")
+	suite.Contains(err.Error(), "component3")
+	suite.Contains(err.Error(), "component4")
+	suite.NotContains(err.Error(), "component2")
+	suite.Equal([]*Component{&testComponent1, &testComponent2, &testComponent3, &testComponent4}, Components)
 }
 
-func (suite *RegisterComponentTestSuite) TestRegisterComponentWithMultipleSortedFeatureMixedComponents() {
-	testComponent1 := Component{
-		// Metadata
-		Code: "component1",
-	}
+func (suite *RegisterComponentTestSuite) TestGetDependentComponents() {
+	testComponent1 := Component{Code: "component1"}
 	testComponent2 := Component{
-		// Metadata
-		Code:         "component2",
-		LoadPriority: -999,
+		Code:      "component2",
+		DependsOn: []entities.ComponentCode{"component1"},
 	}
 	testComponent3 := Component{
-		// Metadata
-		Code: "bot_component3",
-	}
-	testComponent4 := Component{
-		// Metadata
-		Code:         "component4",
-		LoadPriority: 9999,
+		Code:      "component3",
+		DependsOn: []entities.ComponentCode{"component2"},
 	}
+	testComponent4 := Component{Code: "component4"}
 
-	hasCalled1 := false
-	loadFunc1 := func(session *discordgo.Session) error {
-		hasCalled1 = true
-		return nil
-	}
-	hasCalled2 := false
-	loadFunc2 := func(session *discordgo.Session) error {
-		hasCalled2 = true
-		return nil
-	}
-	hasCalled3 := false
-	loadFunc3 := func(session *discordgo.Session) error {
-		hasCalled3 = true
-		return nil
-	}
-	hasCalled4 := false
-	loadFunc4 := func(session *discordgo.Session) error {
-		hasCalled4 = true
-		return nil
-	}
-
-	RegisterComponent(&testComponent1, loadFunc1)
-	RegisterComponent(&testComponent2, loadFunc2)
-	RegisterComponent(&testComponent3, loadFunc3)
-	RegisterComponent(&testComponent4, loadFunc4)
+	RegisterComponent(&testComponent1, nil)
+	RegisterComponent(&testComponent2, nil)
+	RegisterComponent(&testComponent3, nil)
+	RegisterComponent(&testComponent4, nil)
 
-	suite.Len(Components, 4)
-
-	// Fist component -> Second component
-	resultComp1 := Components[2]
-	suite.NotNil(resultComp1)
-	suite.Equal(&testComponent1, resultComp1)
-	err1 := resultComp1.loadComponentFunction(nil)
-	suite.NoError(err1)
-	suite.True(hasCalled1)
-
-	// Second component -> Fourth component
-	resultComp2 := Components[3]
-	suite.NotNil(resultComp2)
-	suite.Equal(&testComponent2, resultComp2)
-	err2 := resultComp2.loadComponentFunction(nil)
-	suite.NoError(err2)
-	suite.True(hasCalled2)
-
-	// Third component -> Third component
-	resultComp3 := Components[0]
-	suite.NotNil(resultComp3)
-	suite.Equal(&testComponent3, resultComp3)
-	err3 := resultComp3.loadComponentFunction(nil)
-	suite.NoError(err3)
-	suite.True(hasCalled3)
+	suite.NoError(ResolveComponentDependencies())
 
-	// Fourth component -> First component
-	resultComp4 := Components[1]
-	suite.NotNil(resultComp4)
-	suite.Equal(&testComponent4, resultComp4)
-	err4 := resultComp4.loadComponentFunction(nil)
-	suite.NoError(err4)
-	suite.True(hasCalled4)
+	suite.Equal([]*Component{&testComponent2, &testComponent3}, GetDependentComponents("component1"))
+	suite.Equal([]*Component{&testComponent3}, GetDependentComponents("component2"))
+	suite.Empty(GetDependentComponents("component4"))
 }
 
-func (suite *RegisterComponentTestSuite) TestRegisterComponentWithMultipleSortedMixedComponents() {
-	testComponent1 := Component{
-		// Metadata
-		Code:         "bot_component1",
-		LoadPriority: -99,
-	}
+func (suite *RegisterComponentTestSuite) TestGetComponentDependencies() {
+	testComponent1 := Component{Code: "component1"}
 	testComponent2 := Component{
-		// Metadata
-		Code:         "component2",
-		LoadPriority: -999,
+		Code:      "component2",
+		DependsOn: []entities.ComponentCode{"component1"},
 	}
 	testComponent3 := Component{
-		// Metadata
-		Code: "bot_component3",
-	}
-	testComponent4 := Component{
-		// Metadata
-		Code:         "component4",
-		LoadPriority: 9999,
-	}
-	testComponent5 := Component{
-		// Metadata
-		Code:         "bot_component5",
-		LoadPriority: 9999,
-	}
-	testComponent6 := Component{
-		// Metadata
-		Code: "component6",
+		Code:      "component3",
+		DependsOn: []entities.ComponentCode{"component2"},
 	}
+	testComponent4 := Component{Code: "component4"}
 
-	hasCalled1 := false
-	loadFunc1 := func(session *discordgo.Session) error {
-		hasCalled1 = true
-		return nil
-	}
-	hasCalled2 := false
-	loadFunc2 := func(session *discordgo.Session) error {
-		hasCalled2 = true
-		return nil
-	}
-	hasCalled3 := false
-	loadFunc3 := func(session *discordgo.Session) error {
-		hasCalled3 = true
-		return nil
-	}
-	hasCalled4 := false
-	loadFunc4 := func(session *discordgo.Session) error {
-		hasCalled4 = true
-		return nil
-	}
-	hasCalled5 := false
-	loadFunc5 := func(session *discordgo.Session) error {
-		hasCalled5 = true
-		return nil
-	}
-	hasCalled6 := false
-	loadFunc6 := func(session *discordgo.Session) error {
-		hasCalled6 = true
-		return nil
-	}
+	RegisterComponent(&testComponent1, nil)
+	RegisterComponent(&testComponent2, nil)
+	RegisterComponent(&testComponent3, nil)
+	RegisterComponent(&testComponent4, nil)
 
-	RegisterComponent(&testComponent1, loadFunc1)
-	RegisterComponent(&testComponent2, loadFunc2)
-	RegisterComponent(&testComponent3, loadFunc3)
-	RegisterComponent(&testComponent4, loadFunc4)
-	RegisterComponent(&testComponent5, loadFunc5)
-	RegisterComponent(&testComponent6, loadFunc6)
+	suite.NoError(ResolveComponentDependencies())
 
-	suite.Len(Components, 6)
+	suite.Equal([]*Component{&testComponent1, &testComponent2}, GetComponentDependencies("component3"))
+	suite.Equal([]*Component{&testComponent1}, GetComponentDependencies("component2"))
+	suite.Empty(GetComponentDependencies("component4"))
+}
 
-	// Fist component -> Third component
-	resultComp1 := Components[2]
-	suite.NotNil(resultComp1)
-	suite.Equal(&testComponent1, resultComp1)
-	err1 := resultComp1.loadComponentFunction(nil)
-	suite.NoError(err1)
-	suite.True(hasCalled1)
+func (suite *RegisterComponentTestSuite) TestFindComponentByCode() {
+	testComponent1 := Component{Code: "component1"}
+	testComponent2 := Component{Code: "component2"}
 
-	// Second component -> Fith component
-	resultComp2 := Components[5]
-	suite.NotNil(resultComp2)
-	suite.Equal(&testComponent2, resultComp2)
-	err2 := resultComp2.loadComponentFunction(nil)
-	suite.NoError(err2)
-	suite.True(hasCalled2)
+	RegisterComponent(&testComponent1, nil)
+	RegisterComponent(&testComponent2, nil)
 
-	// Third component -> Second component
-	resultComp3 := Components[1]
-	suite.NotNil(resultComp3)
-	suite.Equal(&testComponent3, resultComp3)
-	err3 := resultComp3.loadComponentFunction(nil)
-	suite.NoError(err3)
-	suite.True(hasCalled3)
+	comp, ok := FindComponentByCode("component2")
+	suite.True(ok)
+	suite.Equal(&testComponent2, comp)
 
-	// Fourth component -> Fourth component
-	resultComp4 := Components[3]
-	suite.NotNil(resultComp4)
-	suite.Equal(&testComponent4, resultComp4)
-	err4 := resultComp4.loadComponentFunction(nil)
-	suite.NoError(err4)
-	suite.True(hasCalled4)
-
-	// Fifth component -> First component
-	resultComp5 := Components[0]
-	suite.NotNil(resultComp5)
-	suite.Equal(&testComponent5, resultComp5)
-	err5 := resultComp5.loadComponentFunction(nil)
-	suite.NoError(err5)
-	suite.True(hasCalled5)
-
-	// Sixth component -> Fourth component
-	resultComp6 := Components[4]
-	suite.NotNil(resultComp6)
-	suite.Equal(&testComponent6, resultComp6)
-	err6 := resultComp6.loadComponentFunction(nil)
-	suite.NoError(err6)
-	suite.True(hasCalled6)
+	comp, ok = FindComponentByCode("component3")
+	suite.False(ok)
+	suite.Nil(comp)
 }
 
 func TestRegisterComponent(t *testing.T) {
@@ -768,3 +347,62 @@ func (suite *GetGuildIdFromEventInterfaceTestSuite) TestGetGuildIdFromEventInter
 func TestGetGuildIdFromEventInterface(t *testing.T) {
 	suite.Run(t, new(GetGuildIdFromEventInterfaceTestSuite))
 }
+
+// IsCommandEnabledOnGuildTestSuite tests IsCommandEnabledOnGuild.
+// The entities are served from the cache, so no database is necessary.
+type IsCommandEnabledOnGuildTestSuite struct {
+	suite.Suite
+	component *Component
+}
+
+func (suite *IsCommandEnabledOnGuildTestSuite) SetupTest() {
+	err := cache.Init(cache.ModeMemory, 10*time.Minute, "")
+	suite.NoError(err)
+
+	loggerMock := &logmock.LoggerMock{}
+	loggerMock.On("Warn", mock.Anything, mock.Anything)
+	loggerMock.On("Err", mock.Anything, mock.Anything, mock.Anything)
+
+	suite.component = &Component{Code: "test_component", Name: "Test Component"}
+	suite.component.SetLogger(loggerMock)
+
+	suite.NoError(cache.Update("test_component", entities.RegisteredComponent{Model: gorm.Model{ID: 5}}))
+	suite.NoError(cache.Update("1", entities.Guild{Model: gorm.Model{ID: 1}}))
+	suite.NoError(cache.Update("guild_command_status_1_5", []entities.GuildCommandStatus{
+		{GuildID: 1, ComponentID: 5, Command: "disabled", Enabled: false},
+		{GuildID: 1, ComponentID: 5, Command: "enabled", Enabled: true},
+		{GuildID: 1, ComponentID: 5, Command: "2:user", Enabled: false},
+	}))
+}
+
+func (suite *IsCommandEnabledOnGuildTestSuite) TestIsCommandEnabledOnGuild() {
+	tables := []struct {
+		component   *Component
+		commandType discordgo.ApplicationCommandType
+		command     string
+		guildId     string
+		expected    bool
+	}{
+		{suite.component, discordgo.ChatApplicationCommand, "disabled", "1", false},
+		{suite.component, discordgo.ChatApplicationCommand, "enabled", "1", true},
+		{suite.component, discordgo.ChatApplicationCommand, "unknown", "1", true},
+		{suite.component, discordgo.ChatApplicationCommand, "disabled", "", true},
+		{suite.component, discordgo.UserApplicationCommand, "disabled", "1", true},
+		{suite.component, discordgo.UserApplicationCommand, "user", "1", false},
+		{suite.component, discordgo.ChatApplicationCommand, "user", "1", true},
+		{&Component{Code: "bot_core"}, discordgo.ChatApplicationCommand, "disabled", "1", true},
+	}
+
+	for _, table := range tables {
+		command := &Command{
+			Cmd: &discordgo.ApplicationCommand{Name: table.command, Type: table.commandType},
+			c:   table.component,
+		}
+
+		suite.Equal(table.expected, IsCommandEnabledOnGuild(command, table.guildId))
+	}
+}
+
+func TestIsCommandEnabledOnGuild(t *testing.T) {
+	suite.Run(t, new(IsCommandEnabledOnGuildTestSuite))
+}
@@ -0,0 +1,264 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidStorageKey is returned, when an empty key is passed
+// to the ComponentStorage.
+var ErrInvalidStorageKey = errors.New("the storage key must not be empty")
+
+// StorageScope defines where a value of the ComponentStorage is stored.
+// Values are always scoped by the component that stores them.
+// In addition, a value can be bound to a guild, a channel and a user.
+// Empty IDs mean that the value is not bound to a guild, channel or user.
+//
+// Values bound to a guild are removed automatically, when the data of the guild
+// is purged after the bot left the guild.
+type StorageScope struct {
+	GuildId   string
+	ChannelId string
+	UserId    string
+}
+
+// ComponentStorage is the interface that allows components to store
+// small amounts of persistent state as JSON values, without having
+// to register their own entities.
+//
+// Values are cached transparently and can have a time to live.
+// Expired values are treated as not existing.
+type ComponentStorage interface {
+	// Get decodes the value stored with the passed key in the passed scope into the passed target.
+	// The returned bool is false, when no (unexpired) value exists.
+	Get(scope StorageScope, key string, target interface{}) (bool, error)
+	// Set encodes the passed value as JSON and stores it with the passed key in the passed scope.
+	// A ttl of zero or less results in a value that does not expire.
+	Set(scope StorageScope, key string, value interface{}, ttl time.Duration) error
+	// Delete removes the value stored with the passed key in the passed scope.
+	// Deleting a value that does not exist is not an error.
+	Delete(scope StorageScope, key string) error
+	// List returns the sorted keys of all (unexpired) values stored in exactly the passed scope.
+	List(scope StorageScope) ([]string, error)
+}
+
+// ComponentStorageContainer is the default ComponentStorage implementation.
+type ComponentStorageContainer struct {
+	owner *Component
+}
+
+// storageScopeIds holds the parsed IDs of a StorageScope.
+type storageScopeIds struct {
+	guildId   uint64
+	channelId uint64
+	userId    uint64
+}
+
+// ComponentStorage returns the ComponentStorage of the component,
+// which allows to store small amounts of persistent state.
+func (c *Component) ComponentStorage() ComponentStorage {
+	if nil == c.storage {
+		c.storage = &ComponentStorageContainer{owner: c}
+	}
+
+	return c.storage
+}
+
+// Get decodes the value stored with the passed key in the passed scope into the passed target.
+// The returned bool is false, when no (unexpired) value exists.
+func (csc *ComponentStorageContainer) Get(scope StorageScope, key string, target interface{}) (bool, error) {
+	entry, err := csc.getEntry(scope, key)
+	if nil != err || nil == entry {
+		return false, err
+	}
+
+	err = json.Unmarshal([]byte(entry.Value), target)
+	if nil != err {
+		return false, fmt.Errorf("failed to decode stored value \"%s\" of component \"%s\": %w",
+			key,
+			csc.owner.Code,
+			err)
+	}
+
+	return true, nil
+}
+
+// Set encodes the passed value as JSON and stores it with the passed key in the passed scope.
+// A ttl of zero or less results in a value that does not expire.
+func (csc *ComponentStorageContainer) Set(scope StorageScope, key string, value interface{}, ttl time.Duration) error {
+	if "" == key {
+		return ErrInvalidStorageKey
+	}
+
+	encodedValue, err := json.Marshal(value)
+	if nil != err {
+		return fmt.Errorf("failed to encode value \"%s\" of component \"%s\": %w", key, csc.owner.Code, err)
+	}
+
+	var expiresAt *time.Time
+	if ttl > 0 {
+		expiry := time.Now().Add(ttl)
+		expiresAt = &expiry
+	}
+
+	regComp, ids, err := csc.getComponentAndScopeIds(scope)
+	if nil != err {
+		return err
+	}
+
+	return csc.owner.EntityManager().ComponentStorageEntry().Upsert(&entities.ComponentStorageEntry{
+		ComponentID: regComp.ID,
+		GuildID:     ids.guildId,
+		ChannelID:   ids.channelId,
+		UserID:      ids.userId,
+		Key:         key,
+		Value:       string(encodedValue),
+		ExpiresAt:   expiresAt,
+	})
+}
+
+// Delete removes the value stored with the passed key in the passed scope.
+// Deleting a value that does not exist is not an error.
+func (csc *ComponentStorageContainer) Delete(scope StorageScope, key string) error {
+	if "" == key {
+		return ErrInvalidStorageKey
+	}
+
+	regComp, ids, err := csc.getComponentAndScopeIds(scope)
+	if nil != err {
+		return err
+	}
+
+	em := csc.owner.EntityManager().ComponentStorageEntry()
+	entry, err := em.Get(regComp.ID, ids.guildId, ids.channelId, ids.userId, key)
+	if nil != err {
+		// Nothing stored, nothing to delete
+		return nil
+	}
+
+	return em.Delete(entry)
+}
+
+// List returns the sorted keys of all (unexpired) values stored in exactly the passed scope.
+func (csc *ComponentStorageContainer) List(scope StorageScope) ([]string, error) {
+	regComp, ids, err := csc.getComponentAndScopeIds(scope)
+	if nil != err {
+		return nil, err
+	}
+
+	em := csc.owner.EntityManager().ComponentStorageEntry()
+	entries, err := em.GetByScope(regComp.ID, ids.guildId, ids.channelId, ids.userId)
+	if nil != err {
+		return nil, err
+	}
+
+	now := time.Now()
+	keys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsExpired(now) {
+			continue
+		}
+
+		keys = append(keys, entry.Key)
+	}
+
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// getEntry returns the unexpired entry stored with the passed key in the passed scope.
+// When no entry exists, nil is returned without an error.
+// Expired entries are removed on access.
+func (csc *ComponentStorageContainer) getEntry(scope StorageScope, key string) (*entities.ComponentStorageEntry, error) {
+	if "" == key {
+		return nil, ErrInvalidStorageKey
+	}
+
+	regComp, ids, err := csc.getComponentAndScopeIds(scope)
+	if nil != err {
+		return nil, err
+	}
+
+	em := csc.owner.EntityManager().ComponentStorageEntry()
+	entry, err := em.Get(regComp.ID, ids.guildId, ids.channelId, ids.userId, key)
+	if nil != err {
+		// No entities entry = no value
+		return nil, nil
+	}
+
+	if entry.IsExpired(time.Now()) {
+		err = em.Delete(entry)
+		if nil != err {
+			csc.owner.Logger().Warn("Failed to remove expired storage value \"%s\": %v", key, err.Error())
+		}
+
+		return nil, nil
+	}
+
+	return entry, nil
+}
+
+// getComponentAndScopeIds returns the database entity of the component that owns
+// the ComponentStorageContainer and the parsed IDs of the passed StorageScope.
+func (csc *ComponentStorageContainer) getComponentAndScopeIds(
+	scope StorageScope,
+) (*entities.RegisteredComponent, storageScopeIds, error) {
+	ids := storageScopeIds{}
+
+	var err error
+	ids.guildId, err = parseStorageScopeId(scope.GuildId)
+	if nil != err {
+		return nil, ids, fmt.Errorf("invalid guild id \"%s\" in storage scope: %w", scope.GuildId, err)
+	}
+
+	ids.channelId, err = parseStorageScopeId(scope.ChannelId)
+	if nil != err {
+		return nil, ids, fmt.Errorf("invalid channel id \"%s\" in storage scope: %w", scope.ChannelId, err)
+	}
+
+	ids.userId, err = parseStorageScopeId(scope.UserId)
+	if nil != err {
+		return nil, ids, fmt.Errorf("invalid user id \"%s\" in storage scope: %w", scope.UserId, err)
+	}
+
+	regComp, err := csc.owner.EntityManager().RegisteredComponent().Get(csc.owner.Code)
+	if nil != err {
+		return nil, ids, fmt.Errorf("missing component with code \"%s\" in database: %w", csc.owner.Code, err)
+	}
+
+	return regComp, ids, nil
+}
+
+// parseStorageScopeId converts the passed Discord snowflake into an integer.
+// An empty ID results in zero, which means that the scope is not bound to the ID.
+func parseStorageScopeId(id string) (uint64, error) {
+	if "" == id {
+		return 0, nil
+	}
+
+	return strconv.ParseUint(id, 10, 64)
+}
@@ -0,0 +1,304 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"github.com/lazybytez/jojo-discord-bot/services/cache"
+	"github.com/lazybytez/jojo-discord-bot/test/dbmock"
+	"github.com/lazybytez/jojo-discord-bot/test/logmock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+// componentStorageEntryEntityManagerFake is an in-memory
+// ComponentStorageEntryEntityManager used to test the ComponentStorage.
+type componentStorageEntryEntityManagerFake struct {
+	entries map[string]*entities.ComponentStorageEntry
+}
+
+func (fake *componentStorageEntryEntityManagerFake) key(
+	componentId uint,
+	guildId uint64,
+	channelId uint64,
+	userId uint64,
+	key string,
+) string {
+	return fmt.Sprintf("%v_%v_%v_%v_%s", componentId, guildId, channelId, userId, key)
+}
+
+func (fake *componentStorageEntryEntityManagerFake) Get(
+	componentId uint,
+	guildId uint64,
+	channelId uint64,
+	userId uint64,
+	key string,
+) (*entities.ComponentStorageEntry, error) {
+	entry, ok := fake.entries[fake.key(componentId, guildId, channelId, userId, key)]
+	if !ok {
+		return &entities.ComponentStorageEntry{}, gorm.ErrRecordNotFound
+	}
+
+	copiedEntry := *entry
+
+	return &copiedEntry, nil
+}
+
+func (fake *componentStorageEntryEntityManagerFake) GetByScope(
+	componentId uint,
+	guildId uint64,
+	channelId uint64,
+	userId uint64,
+) ([]entities.ComponentStorageEntry, error) {
+	result := make([]entities.ComponentStorageEntry, 0)
+	for _, entry := range fake.entries {
+		if entry.ComponentID == componentId && entry.GuildID == guildId &&
+			entry.ChannelID == channelId && entry.UserID == userId {
+			result = append(result, *entry)
+		}
+	}
+
+	return result, nil
+}
+
+func (fake *componentStorageEntryEntityManagerFake) Create(entry *entities.ComponentStorageEntry) error {
+	return fake.Save(entry)
+}
+
+func (fake *componentStorageEntryEntityManagerFake) Save(entry *entities.ComponentStorageEntry) error {
+	copiedEntry := *entry
+	fake.entries[fake.key(entry.ComponentID, entry.GuildID, entry.ChannelID, entry.UserID, entry.Key)] = &copiedEntry
+
+	return nil
+}
+
+func (fake *componentStorageEntryEntityManagerFake) Upsert(entry *entities.ComponentStorageEntry) error {
+	return fake.Save(entry)
+}
+
+func (fake *componentStorageEntryEntityManagerFake) Delete(entry *entities.ComponentStorageEntry) error {
+	delete(fake.entries, fake.key(entry.ComponentID, entry.GuildID, entry.ChannelID, entry.UserID, entry.Key))
+
+	return nil
+}
+
+func (fake *componentStorageEntryEntityManagerFake) DeleteExpired(_ time.Time) (int, error) {
+	return 0, nil
+}
+
+type ComponentStorageTestSuite struct {
+	suite.Suite
+	previousEntityManager EntityManager
+	storageFake           *componentStorageEntryEntityManagerFake
+	component             *Component
+}
+
+func (suite *ComponentStorageTestSuite) SetupTest() {
+	err := cache.Init(cache.ModeMemory, 10*time.Minute, "")
+	suite.NoError(err)
+
+	suite.previousEntityManager = entityManager
+	suite.storageFake = &componentStorageEntryEntityManagerFake{
+		entries: make(map[string]*entities.ComponentStorageEntry),
+	}
+
+	logger := &logmock.LoggerMock{}
+	logger.On("Warn", mock.Anything, mock.Anything)
+
+	entityManager = NewEntityManager(&dbmock.DatabaseAccessMock{}, logger)
+	entityManager.componentStorageEntryEntityManager = suite.storageFake
+
+	suite.component = &Component{Code: "test_component"}
+
+	err = cache.Update(string(suite.component.Code), entities.RegisteredComponent{
+		Model: gorm.Model{ID: 42},
+		Code:  suite.component.Code,
+	})
+	suite.NoError(err)
+}
+
+func (suite *ComponentStorageTestSuite) TearDownTest() {
+	entityManager = suite.previousEntityManager
+}
+
+func (suite *ComponentStorageTestSuite) TestSetAndGet() {
+	type counter struct {
+		Name  string
+		Count int
+	}
+
+	storage := suite.component.ComponentStorage()
+	scope := StorageScope{GuildId: "65835858358583", UserId: "1234"}
+
+	err := storage.Set(scope, "counter", counter{Name: "dice", Count: 3}, 0)
+	suite.NoError(err)
+
+	result := counter{}
+	found, err := storage.Get(scope, "counter", &result)
+
+	suite.NoError(err)
+	suite.True(found)
+	suite.Equal(counter{Name: "dice", Count: 3}, result)
+
+	entry := suite.storageFake.entries["42_65835858358583_0_1234_counter"]
+	suite.NotNil(entry)
+	suite.Nil(entry.ExpiresAt)
+
+	// Overwrite the existing value
+	err = storage.Set(scope, "counter", counter{Name: "dice", Count: 4}, 0)
+	suite.NoError(err)
+
+	found, err = storage.Get(scope, "counter", &result)
+
+	suite.NoError(err)
+	suite.True(found)
+	suite.Equal(4, result.Count)
+	suite.Len(suite.storageFake.entries, 1)
+}
+
+func (suite *ComponentStorageTestSuite) TestGetWithMissingValue() {
+	var result string
+	found, err := suite.component.ComponentStorage().Get(StorageScope{}, "missing", &result)
+
+	suite.NoError(err)
+	suite.False(found)
+}
+
+func (suite *ComponentStorageTestSuite) TestScopesAreIsolated() {
+	storage := suite.component.ComponentStorage()
+
+	suite.NoError(storage.Set(StorageScope{GuildId: "1"}, "key", "guild", 0))
+	suite.NoError(storage.Set(StorageScope{GuildId: "1", ChannelId: "2"}, "key", "channel", 0))
+
+	var result string
+	found, err := storage.Get(StorageScope{GuildId: "1"}, "key", &result)
+	suite.NoError(err)
+	suite.True(found)
+	suite.Equal("guild", result)
+
+	found, err = storage.Get(StorageScope{GuildId: "1", ChannelId: "2"}, "key", &result)
+	suite.NoError(err)
+	suite.True(found)
+	suite.Equal("channel", result)
+
+	found, err = storage.Get(StorageScope{}, "key", &result)
+	suite.NoError(err)
+	suite.False(found)
+}
+
+func (suite *ComponentStorageTestSuite) TestSetWithTtl() {
+	storage := suite.component.ComponentStorage()
+
+	err := storage.Set(StorageScope{}, "key", "value", time.Hour)
+	suite.NoError(err)
+
+	entry := suite.storageFake.entries["42_0_0_0_key"]
+	suite.NotNil(entry)
+	suite.NotNil(entry.ExpiresAt)
+	suite.WithinDuration(time.Now().Add(time.Hour), *entry.ExpiresAt, time.Minute)
+}
+
+func (suite *ComponentStorageTestSuite) TestGetWithExpiredValue() {
+	expiresAt := time.Now().Add(-time.Minute)
+	suite.storageFake.entries["42_0_0_0_key"] = &entities.ComponentStorageEntry{
+		ComponentID: 42,
+		Key:         "key",
+		Value:       "\"value\"",
+		ExpiresAt:   &expiresAt,
+	}
+
+	var result string
+	found, err := suite.component.ComponentStorage().Get(StorageScope{}, "key", &result)
+
+	suite.NoError(err)
+	suite.False(found)
+	suite.Empty(result)
+	suite.Empty(suite.storageFake.entries)
+}
+
+func (suite *ComponentStorageTestSuite) TestGetWithInvalidValue() {
+	suite.storageFake.entries["42_0_0_0_key"] = &entities.ComponentStorageEntry{
+		ComponentID: 42,
+		Key:         "key",
+		Value:       "not json",
+	}
+
+	var result string
+	found, err := suite.component.ComponentStorage().Get(StorageScope{}, "key", &result)
+
+	suite.Error(err)
+	suite.False(found)
+}
+
+func (suite *ComponentStorageTestSuite) TestDelete() {
+	storage := suite.component.ComponentStorage()
+
+	suite.NoError(storage.Set(StorageScope{UserId: "1234"}, "key", "value", 0))
+	suite.NoError(storage.Delete(StorageScope{UserId: "1234"}, "key"))
+	suite.Empty(suite.storageFake.entries)
+
+	// Deleting a missing value is not an error
+	suite.NoError(storage.Delete(StorageScope{UserId: "1234"}, "key"))
+}
+
+func (suite *ComponentStorageTestSuite) TestList() {
+	storage := suite.component.ComponentStorage()
+	scope := StorageScope{GuildId: "1"}
+
+	suite.NoError(storage.Set(scope, "second", 2, 0))
+	suite.NoError(storage.Set(scope, "first", 1, 0))
+	suite.NoError(storage.Set(StorageScope{}, "other", 3, 0))
+
+	expiresAt := time.Now().Add(-time.Minute)
+	suite.storageFake.entries["42_1_0_0_expired"] = &entities.ComponentStorageEntry{
+		ComponentID: 42,
+		GuildID:     1,
+		Key:         "expired",
+		Value:       "4",
+		ExpiresAt:   &expiresAt,
+	}
+
+	keys, err := storage.List(scope)
+
+	suite.NoError(err)
+	suite.Equal([]string{"first", "second"}, keys)
+}
+
+func (suite *ComponentStorageTestSuite) TestInvalidKeyAndScope() {
+	storage := suite.component.ComponentStorage()
+
+	var result string
+	_, err := storage.Get(StorageScope{}, "", &result)
+	suite.True(errors.Is(err, ErrInvalidStorageKey))
+	suite.True(errors.Is(storage.Set(StorageScope{}, "", "value", 0), ErrInvalidStorageKey))
+	suite.True(errors.Is(storage.Delete(StorageScope{}, ""), ErrInvalidStorageKey))
+
+	suite.Error(storage.Set(StorageScope{GuildId: "not-a-snowflake"}, "key", "value", 0))
+	_, err = storage.List(StorageScope{UserId: "-1"})
+	suite.Error(err)
+}
+
+func TestComponentStorage(t *testing.T) {
+	suite.Run(t, new(ComponentStorageTestSuite))
+}
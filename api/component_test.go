@@ -24,6 +24,7 @@ import (
 	"github.com/lazybytez/jojo-discord-bot/test/discordgo_mock"
 	"github.com/stretchr/testify/suite"
 	"testing"
+	"time"
 )
 
 type ComponentTestSuite struct {
@@ -39,10 +40,9 @@ func (suite *ComponentTestSuite) TestLoadComponentWithSuccess() {
 	}
 
 	testComponent := Component{
-		Code:         "some-component",
-		Name:         "Some Component",
-		Description:  "This is a component!",
-		LoadPriority: 100,
+		Code:        "some-component",
+		Name:        "Some Component",
+		Description: "This is a component!",
 		State: &State{
 			DefaultEnabled: true,
 		},
@@ -59,6 +59,38 @@ func (suite *ComponentTestSuite) TestLoadComponentWithSuccess() {
 	suite.True(testComponent.State.Loaded)
 }
 
+func (suite *ComponentTestSuite) TestLoadComponentWithMissingIntents() {
+	SetAvailableIntents(DefaultIntents)
+	defer SetAvailableIntents(discordgo.IntentsAll)
+
+	hasCalled := false
+
+	mockLoadComponentFunction := func(session *discordgo.Session) error {
+		hasCalled = true
+
+		return nil
+	}
+
+	testComponent := Component{
+		Code:        "some-component",
+		Name:        "Some Component",
+		Description: "This is a component!",
+		Intents:     discordgo.IntentsGuilds | discordgo.IntentsMessageContent,
+		State: &State{
+			DefaultEnabled: true,
+		},
+		loadComponentFunction: mockLoadComponentFunction,
+	}
+
+	dgSession, _ := discordgo_mock.MockSession()
+
+	err := testComponent.LoadComponent(dgSession)
+
+	suite.ErrorContains(err, "message_content")
+	suite.False(hasCalled)
+	suite.False(testComponent.State.Loaded)
+}
+
 func (suite *ComponentTestSuite) TestLoadComponentWithFailure() {
 	hasCalled := false
 
@@ -69,10 +101,9 @@ func (suite *ComponentTestSuite) TestLoadComponentWithFailure() {
 	}
 
 	testComponent := Component{
-		Code:         "some-component",
-		Name:         "Some Component",
-		Description:  "This is a component!",
-		LoadPriority: 100,
+		Code:        "some-component",
+		Name:        "Some Component",
+		Description: "This is a component!",
 		State: &State{
 			DefaultEnabled: true,
 		},
@@ -89,12 +120,79 @@ func (suite *ComponentTestSuite) TestLoadComponentWithFailure() {
 	suite.False(testComponent.State.Loaded)
 }
 
+func (suite *ComponentTestSuite) TestLoadComponentWithFailureReleasesResources() {
+	failLoad := true
+	testComponent := Component{
+		Code: "some_component",
+		Name: "Some Component",
+		State: &State{
+			DefaultEnabled: true,
+		},
+	}
+	testComponent.loadComponentFunction = func(session *discordgo.Session) error {
+		err := testComponent.Scheduler().Schedule(Job{
+			Name:     "some_job",
+			Interval: time.Hour,
+			Handler:  func() error { return nil },
+		})
+		if nil != err {
+			return err
+		}
+
+		if failLoad {
+			return fmt.Errorf("something bad happened")
+		}
+
+		return nil
+	}
+	defer testComponent.Scheduler().CancelAll()
+
+	dgSession, _ := discordgo_mock.MockSession()
+
+	suite.Error(testComponent.LoadComponent(dgSession))
+	suite.Len(testComponent.Scheduler().Jobs(), 0)
+	suite.False(testComponent.State.Loaded)
+
+	failLoad = false
+
+	suite.NoError(testComponent.LoadComponent(dgSession))
+	suite.Len(testComponent.Scheduler().Jobs(), 1)
+	suite.True(testComponent.State.Loaded)
+}
+
+func (suite *ComponentTestSuite) TestLoadComponentWhenAlreadyLoaded() {
+	hasCalled := false
+	mockLoadComponentFunction := func(session *discordgo.Session) error {
+		hasCalled = true
+
+		return nil
+	}
+
+	testComponent := Component{
+		Code:        "some-component",
+		Name:        "Some Component",
+		Description: "This is a component!",
+		State: &State{
+			DefaultEnabled: true,
+			Loaded:         true,
+		},
+		loadComponentFunction: mockLoadComponentFunction,
+	}
+
+	dgSession, _ := discordgo_mock.MockSession()
+
+	err := testComponent.LoadComponent(dgSession)
+
+	suite.Error(err)
+	suite.False(hasCalled)
+	suite.True(testComponent.State.Loaded)
+}
+
 func (suite *ComponentTestSuite) TestUnloadComponent() {
 	testComponent := Component{
-		Code:         "some_component",
-		Name:         "Some Component",
-		Description:  "This is a component!",
-		LoadPriority: 100,
+		Code:        "some_component",
+		Name:        "Some Component",
+		Description: "This is a component!",
 		State: &State{
 			DefaultEnabled: true,
 		},
@@ -112,10 +210,83 @@ func (suite *ComponentTestSuite) TestUnloadComponent() {
 		unregister: func() {},
 	}
 
+	componentCommandMap = map[string]*Command{
+		"some_command": {
+			c: &testComponent,
+		},
+	}
+
+	componentInteractionMap = map[string]*componentInteraction{
+		"some_component:some_button": {
+			name: "some_button",
+			c:    &testComponent,
+		},
+	}
+
+	modalHandlerMap = map[string]*modalHandler{
+		"some_component:some_modal": {
+			name: "some_modal",
+			c:    &testComponent,
+		},
+	}
+
 	err := testComponent.UnloadComponent(dgSession)
 
 	suite.NoError(err)
 	suite.Len(handlerComponentMapping.handlers, 0)
+	suite.Len(componentCommandMap, 0)
+	suite.Len(componentInteractionMap, 0)
+	suite.Len(modalHandlerMap, 0)
+	suite.False(testComponent.State.Loaded)
+}
+
+func (suite *ComponentTestSuite) TestUnloadComponentWithUnloadHook() {
+	hasCalled := false
+	testComponent := Component{
+		Code: "some_component",
+		Name: "Some Component",
+		State: &State{
+			DefaultEnabled: true,
+			Loaded:         true,
+		},
+		unloadComponentFunction: func(session *discordgo.Session) error {
+			hasCalled = true
+
+			return fmt.Errorf("something bad happened")
+		},
+	}
+
+	dgSession, _ := discordgo_mock.MockSession()
+
+	err := testComponent.UnloadComponent(dgSession)
+
+	suite.Error(err)
+	suite.True(hasCalled)
+	suite.False(testComponent.State.Loaded)
+}
+
+func (suite *ComponentTestSuite) TestReloadComponent() {
+	loadCount := 0
+	testComponent := Component{
+		Code: "some_component",
+		Name: "Some Component",
+		State: &State{
+			DefaultEnabled: true,
+		},
+		loadComponentFunction: func(session *discordgo.Session) error {
+			loadCount++
+
+			return nil
+		},
+	}
+
+	dgSession, _ := discordgo_mock.MockSession()
+
+	suite.NoError(testComponent.LoadComponent(dgSession))
+	suite.NoError(testComponent.ReloadComponent(dgSession))
+
+	suite.Equal(2, loadCount)
+	suite.True(testComponent.State.Loaded)
 }
 
 func (suite *ComponentTestSuite) TestIsCoreComponent() {
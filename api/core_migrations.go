@@ -0,0 +1,276 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"gorm.io/gorm"
+	"time"
+)
+
+// coreMigrations holds the migrations of the core entities.
+//
+// Applied migrations must never be changed. To change the schema,
+// append a new migration with a higher version.
+var coreMigrations = Migrations{
+	{
+		Version:     202210010000,
+		Description: "create initial schema",
+		Up:          createInitialSchema,
+		Down:        dropInitialSchema,
+	},
+	{
+		Version:     202210170000,
+		Description: "add left at to guilds",
+		Up:          addGuildLeftAt,
+		Down:        dropGuildLeftAt,
+	},
+	{
+		Version:     202210240000,
+		Description: "make guild component config keys unique",
+		Up:          makeGuildComponentConfigKeysUnique,
+		Down:        makeGuildComponentConfigKeysNonUnique,
+	},
+}
+
+// guildComponentConfigKeyIndex is the name of the index on the guild,
+// component and key of the guild component configs.
+const guildComponentConfigKeyIndex = "idx_guild_component_config_guild_id_component_id_key"
+
+// coreEntities holds the entities of the core in the order
+// they have to be created to satisfy their foreign keys.
+var coreEntities = []interface{}{
+	&entities.Guild{},
+	&entities.RegisteredComponent{},
+	&entities.GlobalComponentStatus{},
+	&entities.GuildComponentStatus{},
+	&entities.GuildComponentConfig{},
+	&entities.GuildCommandStatus{},
+	&entities.CommandPermissionOverride{},
+	&entities.AuditLogConfig{},
+	&entities.AuditLog{},
+	&entities.ComponentStorageEntry{},
+}
+
+// createInitialSchema creates the tables of the core entities.
+//
+// The initial schema is created using automated migrations to stay compatible
+// with databases that have been set up before versioned migrations were introduced.
+func createInitialSchema(tx *gorm.DB) error {
+	return tx.AutoMigrate(getInitialSchema()...)
+}
+
+// dropInitialSchema drops the tables of the core entities
+// in the reverse order they have been created.
+func dropInitialSchema(tx *gorm.DB) error {
+	initialSchema := getInitialSchema()
+	for i := len(initialSchema) - 1; i >= 0; i-- {
+		err := tx.Migrator().DropTable(initialSchema[i])
+		if nil != err {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getInitialSchema returns snapshots of the core entities as they have been
+// when versioned migrations were introduced, in the order they have to be created.
+//
+// The snapshots must never be changed, as changes of the entities
+// have to be applied by their own migrations.
+func getInitialSchema() []interface{} {
+	type Guild struct {
+		gorm.Model
+		GuildID uint64 `gorm:"uniqueIndex"`
+		Name    string
+		Locale  string
+	}
+
+	type RegisteredComponent struct {
+		gorm.Model
+		Code           string `gorm:"uniqueIndex"`
+		Name           string
+		Description    string
+		DefaultEnabled bool
+	}
+
+	type GlobalComponentStatus struct {
+		gorm.Model
+		ComponentID uint                `gorm:"index:idx_global_component_status_component_id;"`
+		Component   RegisteredComponent `gorm:"constraint:OnDelete:CASCADE;"`
+		Enabled     bool
+	}
+
+	type GuildComponentStatus struct {
+		gorm.Model
+		GuildID     uint                `gorm:"index:idx_guild_component_status_guild_id;index:idx_guild_component_status_guild_id_component_id;"`
+		Guild       Guild               `gorm:"constraint:OnDelete:CASCADE;"`
+		ComponentID uint                `gorm:"index:idx_guild_component_status_component_id;index:idx_guild_component_status_guild_id_component_id;"`
+		Component   RegisteredComponent `gorm:"constraint:OnDelete:CASCADE;"`
+		Enabled     bool
+	}
+
+	type GuildComponentConfig struct {
+		gorm.Model
+		GuildID     uint                `gorm:"index:idx_guild_component_config_guild_id_component_id_key;"`
+		Guild       Guild               `gorm:"constraint:OnDelete:CASCADE;"`
+		ComponentID uint                `gorm:"index:idx_guild_component_config_guild_id_component_id_key;"`
+		Component   RegisteredComponent `gorm:"constraint:OnDelete:CASCADE;"`
+		Key         string              `gorm:"index:idx_guild_component_config_guild_id_component_id_key;"`
+		Value       string
+	}
+
+	type GuildCommandStatus struct {
+		gorm.Model
+		GuildID     uint                `gorm:"index:idx_guild_command_status_guild_id_component_id;"`
+		Guild       Guild               `gorm:"constraint:OnDelete:CASCADE;"`
+		ComponentID uint                `gorm:"index:idx_guild_command_status_guild_id_component_id;"`
+		Component   RegisteredComponent `gorm:"constraint:OnDelete:CASCADE;"`
+		Command     string
+		Enabled     bool
+	}
+
+	type CommandPermissionOverride struct {
+		gorm.Model
+		GuildID    uint   `gorm:"index:idx_command_permission_override_guild_id_command;"`
+		Guild      Guild  `gorm:"constraint:OnDelete:CASCADE;"`
+		Command    string `gorm:"index:idx_command_permission_override_guild_id_command;"`
+		TargetType string `gorm:"size:16;"`
+		TargetID   uint64
+		Allow      bool
+	}
+
+	type AuditLogConfig struct {
+		gorm.Model
+		GuildID   uint  `gorm:"uniqueIndex;"`
+		Guild     Guild `gorm:"constraint:OnDelete:CASCADE;"`
+		ChannelId *uint64
+		Enabled   bool
+	}
+
+	type AuditLog struct {
+		gorm.Model
+		GuildID               uint                `gorm:"index:idx_audit_log_guild_id;index:idx_audit_log_guild_id_user_id;index:idx_audit_log_guild_id_component_id_user_id;"`
+		Guild                 Guild               `gorm:"constraint:OnDelete:CASCADE;"`
+		RegisteredComponentID uint                `gorm:"index:idx_audit_log_guild_id;index:idx_audit_log_guild_id_user_id;index:idx_audit_log_guild_id_component_id_user_id;"`
+		RegisteredComponent   RegisteredComponent `gorm:"constraint:OnDelete:CASCADE;"`
+		UserID                uint64              `gorm:"index:idx_audit_log_user_id;index:idx_audit_log_guild_id_user_id;index:idx_audit_log_guild_id_component_id_user_id;"`
+		Message               string
+	}
+
+	type ComponentStorageEntry struct {
+		gorm.Model
+		ComponentID uint                `gorm:"uniqueIndex:idx_component_storage_entry_scope_key;"`
+		Component   RegisteredComponent `gorm:"constraint:OnDelete:CASCADE;"`
+		GuildID     uint64              `gorm:"uniqueIndex:idx_component_storage_entry_scope_key;index:idx_component_storage_entry_guild_id;"`
+		ChannelID   uint64              `gorm:"uniqueIndex:idx_component_storage_entry_scope_key;"`
+		UserID      uint64              `gorm:"uniqueIndex:idx_component_storage_entry_scope_key;"`
+		Key         string              `gorm:"uniqueIndex:idx_component_storage_entry_scope_key;"`
+		Value       string
+		ExpiresAt   *time.Time `gorm:"index:idx_component_storage_entry_expires_at;"`
+	}
+
+	return []interface{}{
+		&Guild{},
+		&RegisteredComponent{},
+		&GlobalComponentStatus{},
+		&GuildComponentStatus{},
+		&GuildComponentConfig{},
+		&GuildCommandStatus{},
+		&CommandPermissionOverride{},
+		&AuditLogConfig{},
+		&AuditLog{},
+		&ComponentStorageEntry{},
+	}
+}
+
+// addGuildLeftAt adds the column that marks guilds as left.
+func addGuildLeftAt(tx *gorm.DB) error {
+	err := tx.Migrator().AddColumn(&entities.Guild{}, "LeftAt")
+	if nil != err {
+		return err
+	}
+
+	return tx.Migrator().CreateIndex(&entities.Guild{}, "idx_guild_left_at")
+}
+
+// dropGuildLeftAt drops the column that marks guilds as left.
+func dropGuildLeftAt(tx *gorm.DB) error {
+	if tx.Migrator().HasIndex(&entities.Guild{}, "idx_guild_left_at") {
+		err := tx.Migrator().DropIndex(&entities.Guild{}, "idx_guild_left_at")
+		if nil != err {
+			return err
+		}
+	}
+
+	return tx.Migrator().DropColumn(&entities.Guild{}, "LeftAt")
+}
+
+// makeGuildComponentConfigKeysUnique replaces the index on the guild, component and key
+// of the guild component configs with a unique index.
+//
+// Soft-deleted values are removed and of duplicated values only the latest one is kept,
+// as it is the one that has been written last.
+func makeGuildComponentConfigKeysUnique(tx *gorm.DB) error {
+	type GuildComponentConfig struct {
+		ID          uint
+		GuildID     uint   `gorm:"uniqueIndex:idx_guild_component_config_guild_id_component_id_key;"`
+		ComponentID uint   `gorm:"uniqueIndex:idx_guild_component_config_guild_id_component_id_key;"`
+		Key         string `gorm:"uniqueIndex:idx_guild_component_config_guild_id_component_id_key;"`
+	}
+
+	err := tx.Where(entities.ColumnDeletedAt + " IS NOT NULL").Delete(&GuildComponentConfig{}).Error
+	if nil != err {
+		return err
+	}
+
+	latestIds := tx.Model(&GuildComponentConfig{}).
+		Select("MAX(id)").
+		Group(entities.ColumnGuild + ", " + entities.ColumnComponent + ", " + entities.ColumnKey)
+	err = tx.Where("id NOT IN (?)", latestIds).Delete(&GuildComponentConfig{}).Error
+	if nil != err {
+		return err
+	}
+
+	err = tx.Migrator().DropIndex(&GuildComponentConfig{}, guildComponentConfigKeyIndex)
+	if nil != err {
+		return err
+	}
+
+	return tx.Migrator().CreateIndex(&GuildComponentConfig{}, guildComponentConfigKeyIndex)
+}
+
+// makeGuildComponentConfigKeysNonUnique replaces the unique index on the guild, component
+// and key of the guild component configs with the previous non-unique index.
+func makeGuildComponentConfigKeysNonUnique(tx *gorm.DB) error {
+	type GuildComponentConfig struct {
+		ID          uint
+		GuildID     uint   `gorm:"index:idx_guild_component_config_guild_id_component_id_key;"`
+		ComponentID uint   `gorm:"index:idx_guild_component_config_guild_id_component_id_key;"`
+		Key         string `gorm:"index:idx_guild_component_config_guild_id_component_id_key;"`
+	}
+
+	err := tx.Migrator().DropIndex(&GuildComponentConfig{}, guildComponentConfigKeyIndex)
+	if nil != err {
+		return err
+	}
+
+	return tx.Migrator().CreateIndex(&GuildComponentConfig{}, guildComponentConfigKeyIndex)
+}
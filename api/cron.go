@@ -0,0 +1,253 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchLimit limits how far into the future the next execution
+// of a cron schedule is searched. Expressions that never match
+// (e.g. the 31st of February) are detected this way.
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// cronDescriptors holds the supported shortcuts for common cron expressions.
+var cronDescriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+// cronField describes the bounds of a single field of a cron expression.
+type cronField struct {
+	name string
+	min  int
+	max  int
+}
+
+// The fields of a cron expression in the order they are specified.
+var (
+	cronMinuteField     = cronField{name: "minute", min: 0, max: 59}
+	cronHourField       = cronField{name: "hour", min: 0, max: 23}
+	cronDayOfMonthField = cronField{name: "day of month", min: 1, max: 31}
+	cronMonthField      = cronField{name: "month", min: 1, max: 12}
+	cronDayOfWeekField  = cronField{name: "day of week", min: 0, max: 7}
+)
+
+// cronSchedule is a parsed cron expression.
+// Each field is a bit set, where a set bit marks an allowed value.
+type cronSchedule struct {
+	minutes     uint64
+	hours       uint64
+	daysOfMonth uint64
+	months      uint64
+	daysOfWeek  uint64
+	// When both the day of month and the day of week are restricted,
+	// a day matches if any of them matches, like in common cron implementations.
+	restrictedDayOfMonth bool
+	restrictedDayOfWeek  bool
+}
+
+// parseCronExpression parses a cron expression consisting of five fields:
+// minute, hour, day of month, month and day of week.
+//
+// Each field supports wildcards (*), single values, ranges (1-5), steps (*/15, 1-30/5)
+// and lists of them (1,15,30). Sunday is either 0 or 7.
+// Additionally, the descriptors @hourly, @daily, @weekly, @monthly and @yearly are supported.
+func parseCronExpression(expression string) (*cronSchedule, error) {
+	if descriptor, ok := cronDescriptors[strings.TrimSpace(expression)]; ok {
+		expression = descriptor
+	}
+
+	fields := strings.Fields(expression)
+	if 5 != len(fields) {
+		return nil, fmt.Errorf("the cron expression \"%s\" must consist of exactly five fields", expression)
+	}
+
+	schedule := &cronSchedule{
+		restrictedDayOfMonth: "*" != fields[2],
+		restrictedDayOfWeek:  "*" != fields[4],
+	}
+
+	var err error
+	targets := []*uint64{
+		&schedule.minutes,
+		&schedule.hours,
+		&schedule.daysOfMonth,
+		&schedule.months,
+		&schedule.daysOfWeek,
+	}
+	cronFields := []cronField{
+		cronMinuteField,
+		cronHourField,
+		cronDayOfMonthField,
+		cronMonthField,
+		cronDayOfWeekField,
+	}
+	for key, field := range fields {
+		*targets[key], err = parseCronField(field, cronFields[key])
+		if nil != err {
+			return nil, fmt.Errorf("the cron expression \"%s\" is invalid: %w", expression, err)
+		}
+	}
+
+	// Sunday can be specified as 0 or 7
+	if 0 != schedule.daysOfWeek&(1<<7) {
+		schedule.daysOfWeek |= 1
+	}
+
+	return schedule, nil
+}
+
+// parseCronField parses a single field of a cron expression into a bit set.
+func parseCronField(value string, field cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(value, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if nil != err || step < 1 {
+				return 0, fmt.Errorf("invalid step \"%s\" in %s field", stepPart, field.name)
+			}
+		}
+
+		start, end, err := parseCronRange(rangePart, field)
+		if nil != err {
+			return 0, err
+		}
+
+		// A single value with a step runs from the value until the end of the field
+		if hasStep && !strings.Contains(rangePart, "-") && "*" != rangePart {
+			end = field.max
+		}
+
+		for i := start; i <= end; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+
+	return bits, nil
+}
+
+// parseCronRange parses a wildcard, single value or range of a cron field
+// and returns the first and last value.
+func parseCronRange(value string, field cronField) (int, int, error) {
+	if "*" == value {
+		return field.min, field.max, nil
+	}
+
+	startPart, endPart, isRange := strings.Cut(value, "-")
+	start, err := parseCronValue(startPart, field)
+	if nil != err {
+		return 0, 0, err
+	}
+
+	if !isRange {
+		return start, start, nil
+	}
+
+	end, err := parseCronValue(endPart, field)
+	if nil != err {
+		return 0, 0, err
+	}
+
+	if end < start {
+		return 0, 0, fmt.Errorf("invalid range \"%s\" in %s field", value, field.name)
+	}
+
+	return start, end, nil
+}
+
+// parseCronValue parses a single value of a cron field and ensures
+// that it is within the bounds of the field.
+func parseCronValue(value string, field cronField) (int, error) {
+	number, err := strconv.Atoi(value)
+	if nil != err || number < field.min || number > field.max {
+		return 0, fmt.Errorf("invalid value \"%s\" in %s field, must be between %d and %d",
+			value,
+			field.name,
+			field.min,
+			field.max)
+	}
+
+	return number, nil
+}
+
+// next returns the first time after the passed time that matches the schedule.
+// The returned time has a precision of one minute.
+// If there is no such time within the cronSearchLimit, the zero time is returned.
+func (cs *cronSchedule) next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.Add(cronSearchLimit)
+
+	for t.Before(limit) {
+		if !cs.matches(cs.months, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+
+			continue
+		}
+
+		if !cs.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+
+			continue
+		}
+
+		if !cs.matches(cs.hours, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+
+			continue
+		}
+
+		if !cs.matches(cs.minutes, t.Minute()) {
+			t = t.Add(time.Minute)
+
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// matchesDay checks whether the day of the passed time matches the schedule.
+func (cs *cronSchedule) matchesDay(t time.Time) bool {
+	dayOfMonth := cs.matches(cs.daysOfMonth, t.Day())
+	dayOfWeek := cs.matches(cs.daysOfWeek, int(t.Weekday()))
+
+	if cs.restrictedDayOfMonth && cs.restrictedDayOfWeek {
+		return dayOfMonth || dayOfWeek
+	}
+
+	return dayOfMonth && dayOfWeek
+}
+
+// matches checks whether the passed value is set in the passed bit set.
+func (cs *cronSchedule) matches(bits uint64, value int) bool {
+	return 0 != bits&(1<<uint(value))
+}
@@ -0,0 +1,90 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type CronTestSuite struct {
+	suite.Suite
+}
+
+func (suite *CronTestSuite) TestParseCronExpressionWithInvalidExpressions() {
+	invalidExpressions := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+	}
+
+	for _, expression := range invalidExpressions {
+		_, err := parseCronExpression(expression)
+		suite.Error(err, expression)
+	}
+}
+
+func (suite *CronTestSuite) TestNext() {
+	start := time.Date(2024, time.January, 15, 10, 30, 45, 0, time.UTC)
+
+	tables := []struct {
+		expression string
+		expected   time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.January, 15, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.January, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2024, time.January, 15, 11, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, time.January, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 8 * * *", time.Date(2024, time.January, 16, 8, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * 1-5", time.Date(2024, time.January, 15, 12, 0, 0, 0, time.UTC)},
+		{"0 12 * * 0", time.Date(2024, time.January, 21, 12, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2024, time.January, 21, 12, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 20 * 6", time.Date(2024, time.January, 20, 0, 0, 0, 0, time.UTC)},
+		{"5,35 10 * * *", time.Date(2024, time.January, 15, 10, 35, 0, 0, time.UTC)},
+		{"10/20 * * * *", time.Date(2024, time.January, 15, 10, 50, 0, 0, time.UTC)},
+	}
+
+	for _, table := range tables {
+		schedule, err := parseCronExpression(table.expression)
+		suite.NoError(err, table.expression)
+		suite.Equal(table.expected, schedule.next(start), table.expression)
+	}
+}
+
+func (suite *CronTestSuite) TestNextWithoutMatch() {
+	schedule, err := parseCronExpression("0 0 31 2 *")
+	suite.NoError(err)
+
+	suite.True(schedule.next(time.Now()).IsZero())
+}
+
+func TestCron(t *testing.T) {
+	suite.Run(t, new(CronTestSuite))
+}
@@ -0,0 +1,350 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"fmt"
+	"github.com/lazybytez/jojo-discord-bot/services"
+	"github.com/lazybytez/jojo-discord-bot/services/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"reflect"
+	"strings"
+)
+
+// DefaultDatabaseCopyBatchSize is the default number of rows
+// that are copied at once by the DatabaseCopier.
+const DefaultDatabaseCopyBatchSize = 500
+
+// DatabaseCopyResult holds the number of rows that have been
+// copied of a single table by the DatabaseCopier.
+type DatabaseCopyResult struct {
+	Table string
+	Rows  int64
+}
+
+// DatabaseCopier copies all entities of the bot from a source database
+// into an empty target database, for example to move from SQLite to Postgres.
+//
+// Primary keys are preserved, therefore all foreign keys stay valid.
+// The copied entities are the core entities and the Entities of all components.
+type DatabaseCopier struct {
+	source    *gorm.DB
+	target    *gorm.DB
+	logger    services.Logger
+	batchSize int
+	entities  []interface{}
+}
+
+// NewDatabaseCopier creates a new DatabaseCopier that copies from the source
+// to the target database in batches of the passed size.
+func NewDatabaseCopier(source *gorm.DB, target *gorm.DB, logger services.Logger, batchSize int) *DatabaseCopier {
+	if batchSize < 1 {
+		batchSize = DefaultDatabaseCopyBatchSize
+	}
+
+	return &DatabaseCopier{
+		source:    source,
+		target:    target,
+		logger:    logger,
+		batchSize: batchSize,
+		entities:  getCopyableEntities(),
+	}
+}
+
+// getCopyableEntities returns the entities that are copied by the DatabaseCopier
+// in the order they have to be inserted to satisfy their foreign keys.
+// The core entities are followed by the entities of the components,
+// in the order the components are loaded.
+//
+// The history of the migrations is not copied, as the schema of
+// the target database is created by applying all migrations.
+func getCopyableEntities() []interface{} {
+	copyableEntities := append([]interface{}{}, coreEntities...)
+	for _, comp := range Components {
+		copyableEntities = append(copyableEntities, comp.Entities...)
+	}
+
+	return copyableEntities
+}
+
+// Copy copies all entities from the source to the target database.
+//
+// The source database must be fully migrated and must not contain any data
+// in tables that are not known by the DatabaseCopier. The schema of the target database
+// is created by applying all migrations and must not contain any data yet.
+// All rows are copied in a single transaction and the row counts of both
+// databases are compared afterwards.
+func (dc *DatabaseCopier) Copy() ([]DatabaseCopyResult, error) {
+	err := dc.ensureSourceMigrated()
+	if nil != err {
+		return nil, err
+	}
+
+	err = dc.ensureNoUnknownData()
+	if nil != err {
+		return nil, err
+	}
+
+	err = dc.migrateTarget()
+	if nil != err {
+		return nil, err
+	}
+
+	err = dc.ensureTargetEmpty()
+	if nil != err {
+		return nil, err
+	}
+
+	results := make([]DatabaseCopyResult, len(dc.entities))
+	err = dc.target.Transaction(func(tx *gorm.DB) error {
+		for key, entity := range dc.entities {
+			table, err := getEntityTableName(tx, entity)
+			if nil != err {
+				return err
+			}
+
+			rows, err := dc.copyEntity(tx, entity)
+			if nil != err {
+				return fmt.Errorf("failed to copy table \"%s\": %w", table, err)
+			}
+
+			err = resetPrimaryKeySequence(tx, entity)
+			if nil != err {
+				return fmt.Errorf("failed to reset primary key sequence of table \"%s\": %w", table, err)
+			}
+
+			dc.logger.Info("Copied %d rows of table \"%s\"", rows, table)
+			results[key] = DatabaseCopyResult{Table: table, Rows: rows}
+		}
+
+		return nil
+	})
+	if nil != err {
+		return nil, err
+	}
+
+	return results, dc.verifyRowCounts()
+}
+
+// ensureSourceMigrated ensures that all migrations have been applied
+// to the source database, so that both databases share the same schema.
+func (dc *DatabaseCopier) ensureSourceMigrated() error {
+	migrator, err := dc.getMigrator(dc.source)
+	if nil != err {
+		return err
+	}
+
+	status, err := migrator.Status()
+	if nil != err {
+		return err
+	}
+
+	for _, migration := range status {
+		if !migration.Applied {
+			return fmt.Errorf("the source database has pending migrations, migration %d of \"%s\" "+
+				"must be applied before copying", migration.Version, migration.Owner)
+		}
+	}
+
+	return nil
+}
+
+// migrateTarget creates the schema of the target database
+// by applying all pending migrations.
+func (dc *DatabaseCopier) migrateTarget() error {
+	migrator, err := dc.getMigrator(dc.target)
+	if nil != err {
+		return err
+	}
+
+	count, err := migrator.Up()
+	if nil != err {
+		return fmt.Errorf("failed to migrate the target database: %w", err)
+	}
+	dc.logger.Info("Applied %d migrations to the target database", count)
+
+	return nil
+}
+
+// ensureTargetEmpty ensures that none of the copied tables
+// of the target database contains any rows.
+func (dc *DatabaseCopier) ensureTargetEmpty() error {
+	for _, entity := range dc.entities {
+		count, err := countEntityRows(dc.target, entity)
+		if nil != err {
+			return err
+		}
+
+		if 0 != count {
+			table, _ := getEntityTableName(dc.target, entity)
+
+			return fmt.Errorf("the table \"%s\" of the target database is not empty", table)
+		}
+	}
+
+	return nil
+}
+
+// ensureNoUnknownData ensures that all tables of the source database that contain rows
+// are known by the DatabaseCopier, so that no data is lost silently.
+// Empty unknown tables are not copied and only logged.
+func (dc *DatabaseCopier) ensureNoUnknownData() error {
+	tables, err := dc.source.Migrator().GetTables()
+	if nil != err {
+		return fmt.Errorf("failed to list the tables of the source database: %w", err)
+	}
+
+	knownTables := map[string]bool{
+		dc.source.NamingStrategy.TableName("SchemaMigration"): true,
+	}
+	for _, entity := range dc.entities {
+		table, err := getEntityTableName(dc.source, entity)
+		if nil != err {
+			return err
+		}
+
+		knownTables[table] = true
+	}
+
+	for _, table := range tables {
+		if knownTables[table] || strings.HasPrefix(table, "sqlite_") {
+			continue
+		}
+
+		var count int64
+		err = dc.source.Table(table).Count(&count).Error
+		if nil != err {
+			return fmt.Errorf("failed to count the rows of the unknown table \"%s\": %w", table, err)
+		}
+
+		if 0 != count {
+			return fmt.Errorf("the table \"%s\" contains %d rows, but is not declared as entity "+
+				"of the core or any component and cannot be copied", table, count)
+		}
+
+		dc.logger.Warn("The table \"%s\" is unknown and will not be copied", table)
+	}
+
+	return nil
+}
+
+// copyEntity copies all rows of the passed entity in batches from the
+// source database into the passed target transaction.
+// Soft-deleted rows are copied as well.
+func (dc *DatabaseCopier) copyEntity(tx *gorm.DB, entity interface{}) (int64, error) {
+	batch := reflect.New(reflect.SliceOf(reflect.TypeOf(entity).Elem()))
+
+	var copied int64
+	err := dc.source.Unscoped().FindInBatches(batch.Interface(), dc.batchSize, func(_ *gorm.DB, _ int) error {
+		err := tx.Unscoped().Omit(clause.Associations).Create(batch.Interface()).Error
+		if nil != err {
+			return err
+		}
+
+		copied += int64(batch.Elem().Len())
+
+		return nil
+	}).Error
+
+	return copied, err
+}
+
+// verifyRowCounts compares the number of rows of all copied tables
+// in the source and target database.
+func (dc *DatabaseCopier) verifyRowCounts() error {
+	for _, entity := range dc.entities {
+		sourceCount, err := countEntityRows(dc.source, entity)
+		if nil != err {
+			return err
+		}
+
+		targetCount, err := countEntityRows(dc.target, entity)
+		if nil != err {
+			return err
+		}
+
+		if sourceCount != targetCount {
+			table, _ := getEntityTableName(dc.target, entity)
+
+			return fmt.Errorf("the table \"%s\" has %d rows in the source database, "+
+				"but %d rows in the target database", table, sourceCount, targetCount)
+		}
+	}
+
+	return nil
+}
+
+// getMigrator returns a Migrator for the passed database
+// that knows the migrations of the core and all components.
+func (dc *DatabaseCopier) getMigrator(db *gorm.DB) (*Migrator, error) {
+	em := NewEntityManager(database.New(db), dc.logger)
+
+	return em.Migrator()
+}
+
+// countEntityRows returns the number of rows of the passed entity,
+// including soft-deleted rows.
+func countEntityRows(db *gorm.DB, entity interface{}) (int64, error) {
+	var count int64
+	err := db.Unscoped().Model(entity).Count(&count).Error
+
+	return count, err
+}
+
+// getEntityTableName returns the name of the table of the passed entity.
+func getEntityTableName(db *gorm.DB, entity interface{}) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	err := stmt.Parse(entity)
+	if nil != err {
+		return "", err
+	}
+
+	return stmt.Schema.Table, nil
+}
+
+// resetPrimaryKeySequence updates the sequence of the auto-incremented
+// primary key of the passed entity to continue after the highest copied key.
+//
+// Only Postgres needs this, as inserting explicit primary keys does not
+// advance its sequences. SQLite always continues after the highest key.
+func resetPrimaryKeySequence(tx *gorm.DB, entity interface{}) error {
+	if "postgres" != tx.Dialector.Name() {
+		return nil
+	}
+
+	stmt := &gorm.Statement{DB: tx}
+	err := stmt.Parse(entity)
+	if nil != err {
+		return err
+	}
+
+	primaryKey := stmt.Schema.PrioritizedPrimaryField
+	if nil == primaryKey || !primaryKey.AutoIncrement {
+		return nil
+	}
+
+	return tx.Exec(
+		"SELECT setval(pg_get_serial_sequence(?, ?), COALESCE(MAX(?), 0) + 1, false) FROM ?",
+		stmt.Schema.Table,
+		primaryKey.DBName,
+		clause.Column{Name: primaryKey.DBName},
+		clause.Table{Name: stmt.Schema.Table},
+	).Error
+}
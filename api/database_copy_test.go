@@ -0,0 +1,231 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"github.com/lazybytez/jojo-discord-bot/services/database"
+	"github.com/lazybytez/jojo-discord-bot/test/logmock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"testing"
+)
+
+type databaseCopyTestEntity struct {
+	ID      uint
+	GuildID uint
+	Guild   entities.Guild `gorm:"constraint:OnDelete:CASCADE;"`
+	Value   string
+}
+
+type DatabaseCopierTestSuite struct {
+	suite.Suite
+	source             *gorm.DB
+	target             *gorm.DB
+	logger             *logmock.LoggerMock
+	previousComponents []*Component
+}
+
+func (suite *DatabaseCopierTestSuite) SetupTest() {
+	suite.previousComponents = Components
+	Components = []*Component{
+		{
+			Code: "test_component",
+			Migrations: Migrations{
+				{
+					Version:     202210200000,
+					Description: "create test entity",
+					Up: func(tx *gorm.DB) error {
+						return tx.Migrator().CreateTable(&databaseCopyTestEntity{})
+					},
+				},
+			},
+			Entities: []interface{}{&databaseCopyTestEntity{}},
+		},
+	}
+
+	suite.source = suite.openDatabase()
+	suite.target = suite.openDatabase()
+
+	suite.logger = &logmock.LoggerMock{}
+	suite.logger.On("Info", mock.Anything, mock.Anything)
+	suite.logger.On("Warn", mock.Anything, mock.Anything)
+	suite.logger.On("Err", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *DatabaseCopierTestSuite) TearDownTest() {
+	Components = suite.previousComponents
+
+	for _, db := range []*gorm.DB{suite.source, suite.target} {
+		sqlDB, err := db.DB()
+		suite.NoError(err)
+		suite.NoError(sqlDB.Close())
+	}
+}
+
+func (suite *DatabaseCopierTestSuite) openDatabase() *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	suite.NoError(err)
+
+	// Every connection would get its own in-memory database
+	sqlDB, err := db.DB()
+	suite.NoError(err)
+	sqlDB.SetMaxOpenConns(1)
+
+	return db
+}
+
+func (suite *DatabaseCopierTestSuite) migrate(db *gorm.DB) {
+	em := NewEntityManager(database.New(db), suite.logger)
+	migrator, err := em.Migrator()
+	suite.NoError(err)
+
+	_, err = migrator.Up()
+	suite.NoError(err)
+}
+
+func (suite *DatabaseCopierTestSuite) seedSource() {
+	suite.migrate(suite.source)
+
+	guilds := []entities.Guild{
+		{GuildID: 1234, Name: "First Guild"},
+		{GuildID: 5678, Name: "Second Guild"},
+		{GuildID: 9012, Name: "Third Guild"},
+	}
+	suite.NoError(suite.source.Create(&guilds).Error)
+	// Leave a gap in the primary keys to ensure they are preserved
+	suite.NoError(suite.source.Unscoped().Delete(&guilds[1]).Error)
+
+	component := &entities.RegisteredComponent{Code: "test_component", Name: "Test Component"}
+	suite.NoError(suite.source.Create(component).Error)
+
+	statuses := []entities.GuildComponentStatus{
+		{GuildID: guilds[0].ID, ComponentID: component.ID, Enabled: true},
+		{GuildID: guilds[2].ID, ComponentID: component.ID, Enabled: false},
+	}
+	suite.NoError(suite.source.Omit("Guild", "Component").Create(&statuses).Error)
+	// Soft-deleted rows must be copied as well
+	suite.NoError(suite.source.Delete(&statuses[1]).Error)
+
+	auditLogs := []entities.AuditLog{
+		{GuildID: guilds[0].ID, RegisteredComponentID: component.ID, UserID: 42, Message: "First"},
+		{GuildID: guilds[2].ID, RegisteredComponentID: component.ID, UserID: 42, Message: "Second"},
+		{GuildID: guilds[2].ID, RegisteredComponentID: component.ID, UserID: 42, Message: "Third"},
+	}
+	suite.NoError(suite.source.Omit("Guild", "RegisteredComponent").Create(&auditLogs).Error)
+
+	testEntities := []databaseCopyTestEntity{
+		{GuildID: guilds[0].ID, Value: "First"},
+		{GuildID: guilds[2].ID, Value: "Second"},
+	}
+	suite.NoError(suite.source.Omit("Guild").Create(&testEntities).Error)
+}
+
+func (suite *DatabaseCopierTestSuite) TestCopy() {
+	suite.seedSource()
+
+	results, err := NewDatabaseCopier(suite.source, suite.target, suite.logger, 1).Copy()
+
+	suite.NoError(err)
+	suite.Len(results, len(coreEntities)+1)
+
+	copiedRows := make(map[string]int64)
+	for _, result := range results {
+		copiedRows[result.Table] = result.Rows
+	}
+	suite.Equal(int64(2), copiedRows["guilds"])
+	suite.Equal(int64(1), copiedRows["registered_components"])
+	suite.Equal(int64(2), copiedRows["guild_component_statuses"])
+	suite.Equal(int64(3), copiedRows["audit_logs"])
+	suite.Equal(int64(0), copiedRows["component_storage_entries"])
+	suite.Equal(int64(2), copiedRows["database_copy_test_entities"])
+
+	var guilds []entities.Guild
+	suite.NoError(suite.target.Order("id").Find(&guilds).Error)
+	suite.Len(guilds, 2)
+	suite.Equal(uint(1), guilds[0].ID)
+	suite.Equal(uint(3), guilds[1].ID)
+	suite.Equal("Third Guild", guilds[1].Name)
+
+	var statuses []entities.GuildComponentStatus
+	suite.NoError(suite.target.Unscoped().Order("id").Find(&statuses).Error)
+	suite.Len(statuses, 2)
+	suite.Equal(uint(3), statuses[1].GuildID)
+	suite.True(statuses[1].DeletedAt.Valid)
+
+	var auditLogs []entities.AuditLog
+	suite.NoError(suite.target.Where("guild_id = ?", 3).Find(&auditLogs).Error)
+	suite.Len(auditLogs, 2)
+
+	// New rows must continue after the copied primary keys
+	guild := &entities.Guild{GuildID: 3456, Name: "New Guild"}
+	suite.NoError(suite.target.Create(guild).Error)
+	suite.Equal(uint(4), guild.ID)
+}
+
+func (suite *DatabaseCopierTestSuite) TestCopyWithPendingSourceMigrations() {
+	_, err := NewDatabaseCopier(suite.source, suite.target, suite.logger, 0).Copy()
+
+	suite.Error(err)
+	suite.False(suite.target.Migrator().HasTable(&entities.Guild{}))
+}
+
+func (suite *DatabaseCopierTestSuite) TestCopyWithNonEmptyTarget() {
+	suite.seedSource()
+	suite.migrate(suite.target)
+	suite.NoError(suite.target.Create(&entities.Guild{GuildID: 1234, Name: "Existing Guild"}).Error)
+
+	_, err := NewDatabaseCopier(suite.source, suite.target, suite.logger, 0).Copy()
+
+	suite.Error(err)
+
+	var count int64
+	suite.NoError(suite.target.Model(&entities.Guild{}).Count(&count).Error)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *DatabaseCopierTestSuite) TestCopyWithUnknownTable() {
+	suite.seedSource()
+	suite.NoError(suite.source.Exec("CREATE TABLE unknown_entities (id INTEGER PRIMARY KEY)").Error)
+
+	_, err := NewDatabaseCopier(suite.source, suite.target, suite.logger, 0).Copy()
+
+	suite.NoError(err)
+	suite.logger.AssertCalled(suite.T(), "Warn", mock.Anything, mock.Anything)
+}
+
+func (suite *DatabaseCopierTestSuite) TestCopyWithUnknownNonEmptyTable() {
+	suite.seedSource()
+	suite.NoError(suite.source.Exec("CREATE TABLE unknown_entities (id INTEGER PRIMARY KEY)").Error)
+	suite.NoError(suite.source.Exec("INSERT INTO unknown_entities (id) VALUES (1)").Error)
+
+	_, err := NewDatabaseCopier(suite.source, suite.target, suite.logger, 0).Copy()
+
+	suite.Error(err)
+	suite.False(suite.target.Migrator().HasTable(&entities.Guild{}))
+}
+
+func TestDatabaseCopier(t *testing.T) {
+	suite.Run(t, new(DatabaseCopierTestSuite))
+}
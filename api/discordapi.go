@@ -19,10 +19,17 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/services/cache"
+	"time"
 )
 
+// botOwnerIdsCacheKey is the cache key used to store the ids
+// of the users that own the bot application.
+const botOwnerIdsCacheKey = "discord_api_bot_owner_ids"
+
 // DiscordGoApiWrapper is a wrapper around some crucial discordgo
 // functions. It provides functions that might be
 // frequently used without an ongoing event.
@@ -39,10 +46,24 @@ type DiscordGoApiWrapper struct {
 // output the proper value.
 type DiscordApiWrapper interface {
 	// GuildCount returns the number of guilds the bot is currently on.
+	// The guild count is aggregated across all shards.
 	GuildCount() int
+	// ShardStatus returns the ShardStatus of all shards, ordered by their id.
+	ShardStatus() []ShardStatus
+	// Latency returns the average heartbeat latency of all connected shards.
+	Latency() time.Duration
 	// SetBotStatus updates the status of the bot according to the passed
 	// SimpleBotStatus data.
 	SetBotStatus(status SimpleBotStatus) error
+	// IsBotOwner checks whether the user with the passed id owns the bot application.
+	// If the application is owned by a team, all members of the team are considered owners.
+	IsBotOwner(userId string) (bool, error)
+	// CommandSyncDiff computes the changes a command sync of the guild with the passed id
+	// would apply. An empty guild id computes the changes of the global commands.
+	CommandSyncDiff(guildId string) (*CommandSyncDiff, error)
+	// BotUser returns the user of the bot.
+	// The result is nil, when the bot has not been connected yet.
+	BotUser() *discordgo.User
 }
 
 // DiscordApi is used to obtain the components slash DiscordApiWrapper management
@@ -58,10 +79,47 @@ func (c *Component) DiscordApi() DiscordApiWrapper {
 }
 
 // GuildCount returns the number of guilds the bot is currently on.
-//
-// TODO: As soon as sharding support is implemented, the guild count needs to be computed from data collected across all shards
+// The guild count is aggregated across all shards.
 func (dgw *DiscordGoApiWrapper) GuildCount() int {
-	return len(dgw.owner.discord.State.Guilds)
+	guildCount := 0
+	for _, status := range dgw.ShardStatus() {
+		guildCount += status.Guilds
+	}
+
+	return guildCount
+}
+
+// ShardStatus returns the ShardStatus of all shards, ordered by their id.
+func (dgw *DiscordGoApiWrapper) ShardStatus() []ShardStatus {
+	sessions := getShardSessions(dgw.owner.discord)
+
+	shardStatus := make([]ShardStatus, len(sessions))
+	for key, session := range sessions {
+		shardStatus[key] = getShardStatus(session)
+	}
+
+	return shardStatus
+}
+
+// Latency returns the average heartbeat latency of all connected shards.
+// If no shard is connected, the latency is zero.
+func (dgw *DiscordGoApiWrapper) Latency() time.Duration {
+	var totalLatency time.Duration
+	connectedShards := 0
+	for _, status := range dgw.ShardStatus() {
+		if !status.Connected {
+			continue
+		}
+
+		totalLatency += status.Latency
+		connectedShards++
+	}
+
+	if 0 == connectedShards {
+		return 0
+	}
+
+	return totalLatency / time.Duration(connectedShards)
 }
 
 // SimpleBotStatus is a simplified version of discordgo.UpdateStatusData
@@ -75,16 +133,81 @@ type SimpleBotStatus struct {
 
 // SetBotStatus updates the status of the bot according to the passed
 // SimpleBotStatus data.
+// The status is updated on all shards.
 func (dgw *DiscordGoApiWrapper) SetBotStatus(status SimpleBotStatus) error {
+	var err error
+	for _, session := range getShardSessions(dgw.owner.discord) {
+		err = errors.Join(err, setSessionBotStatus(session, status))
+	}
+
+	return err
+}
+
+// setSessionBotStatus updates the status of the bot on the passed session.
+func setSessionBotStatus(session *discordgo.Session, status SimpleBotStatus) error {
 	switch status.ActivityType {
 	case discordgo.ActivityTypeGame:
-		return dgw.owner.discord.UpdateGameStatus(0, status.Content)
+		return session.UpdateGameStatus(0, status.Content)
 	case discordgo.ActivityTypeStreaming:
-		return dgw.owner.discord.UpdateStreamingStatus(0, status.Content, status.Url)
+		return session.UpdateStreamingStatus(0, status.Content, status.Url)
 	case discordgo.ActivityTypeListening:
-		return dgw.owner.discord.UpdateListeningStatus(status.Content)
+		return session.UpdateListeningStatus(status.Content)
 	default:
 		return fmt.Errorf("tried to update bot status to activity type \"%d\", which is not supported",
 			status.ActivityType)
 	}
 }
+
+// IsBotOwner checks whether the user with the passed id owns the bot application.
+// If the application is owned by a team, all members of the team are considered owners.
+func (dgw *DiscordGoApiWrapper) IsBotOwner(userId string) (bool, error) {
+	ownerIds, ok := cache.Get(botOwnerIdsCacheKey, []string{})
+	if !ok {
+		application, err := dgw.owner.discord.Application("@me")
+		if nil != err {
+			return false, err
+		}
+
+		ownerIds = make([]string, 0)
+		if nil != application.Owner {
+			ownerIds = append(ownerIds, application.Owner.ID)
+		}
+
+		if nil != application.Team {
+			for _, member := range application.Team.Members {
+				if nil != member.User {
+					ownerIds = append(ownerIds, member.User.ID)
+				}
+			}
+		}
+
+		err = cache.Update(botOwnerIdsCacheKey, ownerIds)
+		if nil != err {
+			dgw.owner.Logger().Err(err, "Failed to cache the owners of the bot application!")
+		}
+	}
+
+	for _, ownerId := range ownerIds {
+		if ownerId == userId {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// CommandSyncDiff computes the changes a command sync of the guild with the passed id
+// would apply. An empty guild id computes the changes of the global commands.
+func (dgw *DiscordGoApiWrapper) CommandSyncDiff(guildId string) (*CommandSyncDiff, error) {
+	return dgw.owner.SlashCommandManager().SyncApplicationComponentCommandsDryRun(dgw.owner.discord, guildId)
+}
+
+// BotUser returns the user of the bot.
+// The result is nil, when the bot has not been connected yet.
+func (dgw *DiscordGoApiWrapper) BotUser() *discordgo.User {
+	if nil == dgw.owner.discord || nil == dgw.owner.discord.State {
+		return nil
+	}
+
+	return dgw.owner.discord.State.User
+}
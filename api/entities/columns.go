@@ -24,3 +24,13 @@ const ColumnGuild = "guild_id"
 const ColumnGuildId = "guild_id"
 const ColumnName = "name"
 const ColumnCode = "code"
+const ColumnKey = "key"
+const ColumnCommand = "command"
+const ColumnLocale = "locale"
+const ColumnChannel = "channel_id"
+const ColumnUser = "user_id"
+const ColumnExpiresAt = "expires_at"
+const ColumnLeftAt = "left_at"
+const ColumnValue = "value"
+const ColumnUpdatedAt = "updated_at"
+const ColumnDeletedAt = "deleted_at"
@@ -0,0 +1,147 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package entities
+
+import (
+	"fmt"
+	"github.com/lazybytez/jojo-discord-bot/services/cache"
+	"gorm.io/gorm"
+)
+
+// CommandPermissionOverrideTarget specifies what kind of Discord entity
+// is targeted by a CommandPermissionOverride.
+type CommandPermissionOverrideTarget string
+
+// Available targets of command permission overrides
+const (
+	CommandPermissionOverrideTargetRole    CommandPermissionOverrideTarget = "role"
+	CommandPermissionOverrideTargetUser    CommandPermissionOverrideTarget = "user"
+	CommandPermissionOverrideTargetChannel CommandPermissionOverrideTarget = "channel"
+)
+
+// CommandPermissionOverride holds a single rule that allows or denies the usage of a command
+// on a specific guild for a role, a user or in a channel.
+type CommandPermissionOverride struct {
+	gorm.Model
+	GuildID    uint                            `gorm:"index:idx_command_permission_override_guild_id_command;"`
+	Guild      Guild                           `gorm:"constraint:OnDelete:CASCADE;"`
+	Command    string                          `gorm:"index:idx_command_permission_override_guild_id_command;"`
+	TargetType CommandPermissionOverrideTarget `gorm:"size:16;"`
+	TargetID   uint64
+	Allow      bool
+}
+
+// CommandPermissionOverrideEntityManager is the command permission override specific entity manager
+// that allows easy access to the permission overrides of commands on guilds.
+type CommandPermissionOverrideEntityManager struct {
+	EntityManager
+}
+
+// NewCommandPermissionOverrideEntityManager creates a new CommandPermissionOverrideEntityManager.
+func NewCommandPermissionOverrideEntityManager(entityManager EntityManager) *CommandPermissionOverrideEntityManager {
+	cpoem := &CommandPermissionOverrideEntityManager{
+		entityManager,
+	}
+
+	return cpoem
+}
+
+// GetByGuildAndCommand returns all CommandPermissionOverride of the command with the
+// passed name on the passed guild. The function uses a cache and first tries to resolve
+// the overrides from it. If no cache entry is present, a request to the entities will be made.
+func (cpoem *CommandPermissionOverrideEntityManager) GetByGuildAndCommand(
+	guildId uint,
+	command string,
+) ([]CommandPermissionOverride, error) {
+	cacheKey := cpoem.getCacheKey(guildId, command)
+	cachedOverrides, ok := cache.Get(cacheKey, []CommandPermissionOverride{})
+
+	if ok {
+		return cachedOverrides, nil
+	}
+
+	var overrides []CommandPermissionOverride
+	queryStr := ColumnGuild + " = ? AND " + ColumnCommand + " = ?"
+	err := cpoem.DB().GetEntities(&overrides, queryStr, guildId, command)
+	if nil != err {
+		return overrides, err
+	}
+
+	_ = cache.Update(cacheKey, overrides)
+
+	return overrides, nil
+}
+
+// GetByGuild returns all CommandPermissionOverride of the passed guild.
+func (cpoem *CommandPermissionOverrideEntityManager) GetByGuild(guildId uint) ([]CommandPermissionOverride, error) {
+	var overrides []CommandPermissionOverride
+	queryStr := ColumnGuild + " = ?"
+	err := cpoem.DB().GetEntities(&overrides, queryStr, guildId)
+
+	return overrides, err
+}
+
+// Create saves the passed CommandPermissionOverride in the database.
+// Use Save to update an already existing CommandPermissionOverride.
+func (cpoem *CommandPermissionOverrideEntityManager) Create(override *CommandPermissionOverride) error {
+	err := cpoem.DB().Create(override)
+	if nil != err {
+		return err
+	}
+
+	cpoem.invalidateCache(override)
+
+	return nil
+}
+
+// Save updates the passed CommandPermissionOverride in the database.
+func (cpoem *CommandPermissionOverrideEntityManager) Save(override *CommandPermissionOverride) error {
+	err := cpoem.DB().Save(override)
+	if nil != err {
+		return err
+	}
+
+	cpoem.invalidateCache(override)
+
+	return nil
+}
+
+// Delete removes the passed CommandPermissionOverride from the database.
+func (cpoem *CommandPermissionOverrideEntityManager) Delete(override *CommandPermissionOverride) error {
+	err := cpoem.DB().DeleteEntity(override)
+	if nil != err {
+		return err
+	}
+
+	cpoem.invalidateCache(override)
+
+	return nil
+}
+
+// invalidateCache invalidates the cached overrides of the command
+// the passed CommandPermissionOverride belongs to (if present).
+func (cpoem *CommandPermissionOverrideEntityManager) invalidateCache(override *CommandPermissionOverride) {
+	cache.Invalidate(cpoem.getCacheKey(override.GuildID, override.Command), []CommandPermissionOverride{})
+}
+
+// getCacheKey concatenates the passed guild id and command name to create
+// a new unique cache key for the overrides of a command.
+func (cpoem *CommandPermissionOverrideEntityManager) getCacheKey(guildId uint, command string) string {
+	return fmt.Sprintf("command_permission_override_%v_%s", guildId, command)
+}
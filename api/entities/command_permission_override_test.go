@@ -0,0 +1,204 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package entities
+
+import (
+	"fmt"
+	"github.com/lazybytez/jojo-discord-bot/services/cache"
+	"github.com/lazybytez/jojo-discord-bot/test/dbmock"
+	"github.com/lazybytez/jojo-discord-bot/test/entity_manager_mock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type CommandPermissionOverrideEntityManagerTestSuite struct {
+	suite.Suite
+	dba   *dbmock.DatabaseAccessMock
+	em    entity_manager_mock.EntityManagerMock
+	cpoem *CommandPermissionOverrideEntityManager
+}
+
+func (suite *CommandPermissionOverrideEntityManagerTestSuite) SetupTest() {
+	suite.dba = &dbmock.DatabaseAccessMock{}
+	suite.em = entity_manager_mock.EntityManagerMock{}
+	suite.cpoem = &CommandPermissionOverrideEntityManager{
+		&suite.em,
+	}
+
+	err := cache.Init(cache.ModeMemory, 10*time.Minute, "")
+	suite.NoError(err)
+}
+
+func (suite *CommandPermissionOverrideEntityManagerTestSuite) TestGetCacheKey() {
+	result := suite.cpoem.getCacheKey(65835858358583, "dice")
+
+	suite.Equal("command_permission_override_65835858358583_dice", result)
+}
+
+func (suite *CommandPermissionOverrideEntityManagerTestSuite) TestNewCommandPermissionOverrideEntityManager() {
+	testEntityManager := entity_manager_mock.EntityManagerMock{}
+
+	cpoem := NewCommandPermissionOverrideEntityManager(&testEntityManager)
+
+	suite.NotNil(cpoem)
+	suite.Equal(&testEntityManager, cpoem.EntityManager)
+}
+
+func (suite *CommandPermissionOverrideEntityManagerTestSuite) TestGetByGuildAndCommand() {
+	guildId := uint(65835858358583)
+	testCacheKey := "command_permission_override_65835858358583_dice"
+
+	suite.em.On("DB").Return(suite.dba)
+	suite.dba.On(
+		"GetEntities",
+		mock.AnythingOfType(reflect.TypeOf(&[]CommandPermissionOverride{}).String()),
+		[]interface{}{
+			ColumnGuild + " = ? AND " + ColumnCommand + " = ?",
+			guildId,
+			"dice",
+		},
+	).Run(func(args mock.Arguments) {
+		switch v := args.Get(0).(type) {
+		case *[]CommandPermissionOverride:
+			*v = append(*v, CommandPermissionOverride{
+				GuildID:    guildId,
+				Command:    "dice",
+				TargetType: CommandPermissionOverrideTargetRole,
+				TargetID:   42,
+				Allow:      true,
+			})
+		}
+	}).Return(nil).Once()
+
+	result, err := suite.cpoem.GetByGuildAndCommand(guildId, "dice")
+
+	suite.dba.AssertExpectations(suite.T())
+	suite.NoError(err)
+	suite.Len(result, 1)
+	suite.Equal(uint64(42), result[0].TargetID)
+
+	cachedOverrides, ok := cache.Get(testCacheKey, []CommandPermissionOverride{})
+	suite.True(ok)
+	suite.Equal(result, cachedOverrides)
+
+	// Consecutive calls are served by the cache
+	result, err = suite.cpoem.GetByGuildAndCommand(guildId, "dice")
+
+	suite.dba.AssertExpectations(suite.T())
+	suite.NoError(err)
+	suite.Len(result, 1)
+}
+
+func (suite *CommandPermissionOverrideEntityManagerTestSuite) TestGetByGuildAndCommandWithError() {
+	guildId := uint(65835858358583)
+	testCacheKey := "command_permission_override_65835858358583_dice"
+	expectedError := fmt.Errorf("something bad happened during database read")
+
+	suite.em.On("DB").Return(suite.dba)
+	suite.dba.On(
+		"GetEntities",
+		mock.AnythingOfType(reflect.TypeOf(&[]CommandPermissionOverride{}).String()),
+		[]interface{}{
+			ColumnGuild + " = ? AND " + ColumnCommand + " = ?",
+			guildId,
+			"dice",
+		},
+	).Return(expectedError).Once()
+
+	_, err := suite.cpoem.GetByGuildAndCommand(guildId, "dice")
+
+	suite.dba.AssertExpectations(suite.T())
+	suite.Equal(expectedError, err)
+
+	_, ok := cache.Get(testCacheKey, []CommandPermissionOverride{})
+	suite.False(ok)
+}
+
+func (suite *CommandPermissionOverrideEntityManagerTestSuite) TestCreate() {
+	testCacheKey := "command_permission_override_65835858358583_dice"
+	testOverride := CommandPermissionOverride{
+		GuildID: 65835858358583,
+		Command: "dice",
+	}
+
+	err := cache.Update(testCacheKey, []CommandPermissionOverride{})
+	suite.NoError(err)
+
+	suite.em.On("DB").Return(suite.dba)
+	suite.dba.On("Create", &testOverride).Return(nil).Once()
+
+	err = suite.cpoem.Create(&testOverride)
+
+	suite.NoError(err)
+	suite.dba.AssertExpectations(suite.T())
+
+	_, ok := cache.Get(testCacheKey, []CommandPermissionOverride{})
+	suite.False(ok)
+}
+
+func (suite *CommandPermissionOverrideEntityManagerTestSuite) TestSave() {
+	testCacheKey := "command_permission_override_65835858358583_dice"
+	testOverride := CommandPermissionOverride{
+		GuildID: 65835858358583,
+		Command: "dice",
+	}
+
+	err := cache.Update(testCacheKey, []CommandPermissionOverride{})
+	suite.NoError(err)
+
+	suite.em.On("DB").Return(suite.dba)
+	suite.dba.On("Save", &testOverride).Return(nil).Once()
+
+	err = suite.cpoem.Save(&testOverride)
+
+	suite.NoError(err)
+	suite.dba.AssertExpectations(suite.T())
+
+	_, ok := cache.Get(testCacheKey, []CommandPermissionOverride{})
+	suite.False(ok)
+}
+
+func (suite *CommandPermissionOverrideEntityManagerTestSuite) TestDelete() {
+	testCacheKey := "command_permission_override_65835858358583_dice"
+	testOverride := CommandPermissionOverride{
+		GuildID: 65835858358583,
+		Command: "dice",
+	}
+
+	err := cache.Update(testCacheKey, []CommandPermissionOverride{})
+	suite.NoError(err)
+
+	suite.em.On("DB").Return(suite.dba)
+	suite.dba.On("DeleteEntity", &testOverride).Return(nil).Once()
+
+	err = suite.cpoem.Delete(&testOverride)
+
+	suite.NoError(err)
+	suite.dba.AssertExpectations(suite.T())
+
+	_, ok := cache.Get(testCacheKey, []CommandPermissionOverride{})
+	suite.False(ok)
+}
+
+func TestCommandPermissionOverrideEntityManager(t *testing.T) {
+	suite.Run(t, new(CommandPermissionOverrideEntityManagerTestSuite))
+}
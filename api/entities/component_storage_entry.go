@@ -0,0 +1,232 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package entities
+
+import (
+	"fmt"
+	"github.com/lazybytez/jojo-discord-bot/services/cache"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// ComponentStorageEntry holds a single JSON encoded value stored by a component.
+// Entries are scoped by the component and optionally by a guild, channel and user.
+// A scope ID of zero means that the entry is not bound to a guild, channel or user.
+// There can only be one entry per component, scope and key.
+//
+// Entries with an ExpiresAt in the past are considered as not existing
+// and are removed periodically.
+type ComponentStorageEntry struct {
+	gorm.Model
+	ComponentID uint                `gorm:"uniqueIndex:idx_component_storage_entry_scope_key;"`
+	Component   RegisteredComponent `gorm:"constraint:OnDelete:CASCADE;"`
+	GuildID     uint64              `gorm:"uniqueIndex:idx_component_storage_entry_scope_key;index:idx_component_storage_entry_guild_id;"`
+	ChannelID   uint64              `gorm:"uniqueIndex:idx_component_storage_entry_scope_key;"`
+	UserID      uint64              `gorm:"uniqueIndex:idx_component_storage_entry_scope_key;"`
+	Key         string              `gorm:"uniqueIndex:idx_component_storage_entry_scope_key;"`
+	Value       string
+	ExpiresAt   *time.Time `gorm:"index:idx_component_storage_entry_expires_at;"`
+}
+
+// IsExpired checks whether the ComponentStorageEntry has an expiry
+// that is before or equal to the passed time.
+func (cse *ComponentStorageEntry) IsExpired(now time.Time) bool {
+	return nil != cse.ExpiresAt && !cse.ExpiresAt.After(now)
+}
+
+// ComponentStorageEntryEntityManager is the component storage specific entity manager
+// that allows easy access to the values stored by components.
+type ComponentStorageEntryEntityManager struct {
+	EntityManager
+}
+
+// NewComponentStorageEntryEntityManager creates a new ComponentStorageEntryEntityManager.
+func NewComponentStorageEntryEntityManager(entityManager EntityManager) *ComponentStorageEntryEntityManager {
+	csem := &ComponentStorageEntryEntityManager{
+		entityManager,
+	}
+
+	return csem
+}
+
+// Get tries to get a ComponentStorageEntry from the
+// cache. If no cache entry is present, a request to the entities will be made.
+// If no ComponentStorageEntry can be found, the function returns a new empty
+// ComponentStorageEntry.
+func (csem *ComponentStorageEntryEntityManager) Get(
+	componentId uint,
+	guildId uint64,
+	channelId uint64,
+	userId uint64,
+	key string,
+) (*ComponentStorageEntry, error) {
+	cacheKey := csem.getComponentStorageEntryCacheKey(componentId, guildId, channelId, userId, key)
+	cachedEntry, ok := cache.Get(cacheKey, ComponentStorageEntry{})
+
+	if ok {
+		return &cachedEntry, nil
+	}
+
+	entry := &ComponentStorageEntry{}
+	queryStr := ColumnComponent + " = ? AND " + ColumnGuild + " = ? AND " + ColumnChannel + " = ? AND " +
+		ColumnUser + " = ? AND " + ColumnKey + " = ?"
+	err := csem.DB().GetFirstEntity(entry, queryStr, componentId, guildId, channelId, userId, key)
+	if nil != err {
+		return entry, err
+	}
+
+	_ = cache.Update(cacheKey, *entry)
+
+	return entry, nil
+}
+
+// GetByScope returns all ComponentStorageEntry of the passed component
+// that are stored in exactly the passed scope.
+func (csem *ComponentStorageEntryEntityManager) GetByScope(
+	componentId uint,
+	guildId uint64,
+	channelId uint64,
+	userId uint64,
+) ([]ComponentStorageEntry, error) {
+	var entries []ComponentStorageEntry
+
+	queryStr := ColumnComponent + " = ? AND " + ColumnGuild + " = ? AND " + ColumnChannel + " = ? AND " +
+		ColumnUser + " = ?"
+	err := csem.DB().GetEntities(&entries, queryStr, componentId, guildId, channelId, userId)
+	if nil != err {
+		return entries, err
+	}
+
+	return entries, nil
+}
+
+// Create saves the passed ComponentStorageEntry in the database.
+// Use Save to update an already existing ComponentStorageEntry.
+func (csem *ComponentStorageEntryEntityManager) Create(entry *ComponentStorageEntry) error {
+	err := csem.DB().Create(entry)
+	if nil != err {
+		return err
+	}
+
+	csem.invalidateCache(entry)
+
+	return nil
+}
+
+// Save updates the passed ComponentStorageEntry in the database.
+func (csem *ComponentStorageEntryEntityManager) Save(entry *ComponentStorageEntry) error {
+	err := csem.DB().Save(entry)
+	if nil != err {
+		return err
+	}
+
+	csem.invalidateCache(entry)
+
+	return nil
+}
+
+// Upsert creates the passed ComponentStorageEntry or, when there already is an entry
+// with the same component, scope and key, replaces its value and expiry.
+func (csem *ComponentStorageEntryEntityManager) Upsert(entry *ComponentStorageEntry) error {
+	err := csem.DB().WorkOn(entry).Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: ColumnComponent},
+			{Name: ColumnGuild},
+			{Name: ColumnChannel},
+			{Name: ColumnUser},
+			{Name: ColumnKey},
+		},
+		DoUpdates: clause.AssignmentColumns([]string{
+			ColumnValue,
+			ColumnExpiresAt,
+			ColumnUpdatedAt,
+			ColumnDeletedAt,
+		}),
+	}).Create(entry).Error
+	if nil != err {
+		return err
+	}
+
+	csem.invalidateCache(entry)
+
+	return nil
+}
+
+// Delete removes the passed ComponentStorageEntry from the database.
+// Entries are deleted permanently, so that the key can be used again.
+func (csem *ComponentStorageEntryEntityManager) Delete(entry *ComponentStorageEntry) error {
+	err := csem.DB().WorkOn(entry).Unscoped().Delete(entry).Error
+	if nil != err {
+		return err
+	}
+
+	csem.invalidateCache(entry)
+
+	return nil
+}
+
+// DeleteExpired removes all ComponentStorageEntry that expired before or at the passed time
+// and returns the number of removed entries.
+func (csem *ComponentStorageEntryEntityManager) DeleteExpired(now time.Time) (int, error) {
+	var entries []ComponentStorageEntry
+
+	err := csem.DB().GetEntities(&entries, ColumnExpiresAt+" IS NOT NULL AND "+ColumnExpiresAt+" <= ?", now)
+	if nil != err {
+		return 0, err
+	}
+
+	return csem.deleteAll(entries)
+}
+
+// deleteAll removes the passed entries one by one to ensure their cache items are invalidated.
+// The function stops on the first error and returns the number of entries removed until then.
+func (csem *ComponentStorageEntryEntityManager) deleteAll(entries []ComponentStorageEntry) (int, error) {
+	for i := range entries {
+		err := csem.Delete(&entries[i])
+		if nil != err {
+			return i, err
+		}
+	}
+
+	return len(entries), nil
+}
+
+// invalidateCache invalidates the cache item of the passed ComponentStorageEntry (if present).
+func (csem *ComponentStorageEntryEntityManager) invalidateCache(entry *ComponentStorageEntry) {
+	cacheKey := csem.getComponentStorageEntryCacheKey(
+		entry.ComponentID,
+		entry.GuildID,
+		entry.ChannelID,
+		entry.UserID,
+		entry.Key)
+	cache.Invalidate(cacheKey, ComponentStorageEntry{})
+}
+
+// getComponentStorageEntryCacheKey concatenates the passed component id, scope and key to create
+// a new unique cache key for the stored value.
+func (csem *ComponentStorageEntryEntityManager) getComponentStorageEntryCacheKey(
+	componentId uint,
+	guildId uint64,
+	channelId uint64,
+	userId uint64,
+	key string,
+) string {
+	return fmt.Sprintf("component_storage_entry_%v_%v_%v_%v_%s", componentId, guildId, channelId, userId, key)
+}
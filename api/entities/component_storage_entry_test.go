@@ -0,0 +1,388 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package entities
+
+import (
+	"fmt"
+	"github.com/lazybytez/jojo-discord-bot/services/cache"
+	"github.com/lazybytez/jojo-discord-bot/services/database"
+	"github.com/lazybytez/jojo-discord-bot/test/dbmock"
+	"github.com/lazybytez/jojo-discord-bot/test/entity_manager_mock"
+	"github.com/lazybytez/jojo-discord-bot/test/logmock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type ComponentStorageEntryEntityManagerTestSuite struct {
+	suite.Suite
+	dba    *dbmock.DatabaseAccessMock
+	logger *logmock.LoggerMock
+	em     entity_manager_mock.EntityManagerMock
+	csem   *ComponentStorageEntryEntityManager
+}
+
+func (suite *ComponentStorageEntryEntityManagerTestSuite) SetupTest() {
+	dba := &dbmock.DatabaseAccessMock{}
+	logger := &logmock.LoggerMock{}
+
+	suite.dba = dba
+	suite.logger = logger
+	suite.em = entity_manager_mock.EntityManagerMock{}
+	suite.csem = &ComponentStorageEntryEntityManager{
+		&suite.em,
+	}
+
+	err := cache.Init(cache.ModeMemory, 10*time.Minute, "")
+	suite.NoError(err)
+}
+
+func (suite *ComponentStorageEntryEntityManagerTestSuite) TestGetCacheKey() {
+	expectedCacheKey := "component_storage_entry_42_65835858358583_48688742646283_1234_some_key"
+
+	result := suite.csem.getComponentStorageEntryCacheKey(42, 65835858358583, 48688742646283, 1234, "some_key")
+
+	suite.Equal(expectedCacheKey, result)
+}
+
+func (suite *ComponentStorageEntryEntityManagerTestSuite) TestNewComponentStorageEntryEntityManager() {
+	testEntityManager := entity_manager_mock.EntityManagerMock{}
+
+	csem := NewComponentStorageEntryEntityManager(&testEntityManager)
+
+	suite.NotNil(csem)
+	suite.Equal(&testEntityManager, csem.EntityManager)
+}
+
+func (suite *ComponentStorageEntryEntityManagerTestSuite) TestIsExpired() {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	tables := []struct {
+		expiresAt *time.Time
+		expected  bool
+	}{
+		{nil, false},
+		{&past, true},
+		{&now, true},
+		{&future, false},
+	}
+
+	for _, table := range tables {
+		entry := ComponentStorageEntry{ExpiresAt: table.expiresAt}
+
+		suite.Equal(table.expected, entry.IsExpired(now))
+	}
+}
+
+func (suite *ComponentStorageEntryEntityManagerTestSuite) TestGet() {
+	testCacheKey := "component_storage_entry_42_65835858358583_0_1234_some_key"
+
+	suite.em.On("DB").Return(suite.dba)
+	suite.dba.On(
+		"GetFirstEntity",
+		mock.AnythingOfType(reflect.TypeOf(&ComponentStorageEntry{}).Name()),
+		[]interface{}{
+			ColumnComponent + " = ? AND " + ColumnGuild + " = ? AND " + ColumnChannel + " = ? AND " +
+				ColumnUser + " = ? AND " + ColumnKey + " = ?",
+			uint(42),
+			uint64(65835858358583),
+			uint64(0),
+			uint64(1234),
+			"some_key",
+		},
+	).Run(func(args mock.Arguments) {
+		switch v := args.Get(0).(type) {
+		case *ComponentStorageEntry:
+			v.ComponentID = 42
+			v.GuildID = 65835858358583
+			v.UserID = 1234
+			v.Key = "some_key"
+			v.Value = "\"some_value\""
+		}
+	}).Return(nil).Once()
+
+	result, err := suite.csem.Get(42, 65835858358583, 0, 1234, "some_key")
+
+	suite.dba.AssertExpectations(suite.T())
+	suite.NoError(err)
+	suite.Equal("\"some_value\"", result.Value)
+
+	cachedEntry, ok := cache.Get(testCacheKey, ComponentStorageEntry{})
+
+	suite.True(ok)
+	suite.Equal(*result, cachedEntry)
+
+	// Consecutive calls are served by the cache
+	result, err = suite.csem.Get(42, 65835858358583, 0, 1234, "some_key")
+
+	suite.dba.AssertExpectations(suite.T())
+	suite.NoError(err)
+	suite.Equal("\"some_value\"", result.Value)
+}
+
+func (suite *ComponentStorageEntryEntityManagerTestSuite) TestGetWithError() {
+	testCacheKey := "component_storage_entry_42_0_0_0_some_key"
+	expectedError := fmt.Errorf("something bad happened during database read")
+
+	suite.em.On("DB").Return(suite.dba)
+	suite.dba.On(
+		"GetFirstEntity",
+		mock.AnythingOfType(reflect.TypeOf(&ComponentStorageEntry{}).Name()),
+		mock.Anything,
+	).Return(expectedError).Once()
+
+	result, err := suite.csem.Get(42, 0, 0, 0, "some_key")
+
+	suite.dba.AssertExpectations(suite.T())
+	suite.Error(err)
+	suite.Equal(ComponentStorageEntry{}, *result)
+
+	_, ok := cache.Get(testCacheKey, ComponentStorageEntry{})
+	suite.False(ok)
+}
+
+func (suite *ComponentStorageEntryEntityManagerTestSuite) TestGetByScope() {
+	suite.em.On("DB").Return(suite.dba)
+	suite.dba.On(
+		"GetEntities",
+		mock.AnythingOfType(reflect.TypeOf(&[]ComponentStorageEntry{}).String()),
+		[]interface{}{
+			ColumnComponent + " = ? AND " + ColumnGuild + " = ? AND " + ColumnChannel + " = ? AND " +
+				ColumnUser + " = ?",
+			uint(42),
+			uint64(65835858358583),
+			uint64(0),
+			uint64(0),
+		},
+	).Run(func(args mock.Arguments) {
+		switch v := args.Get(0).(type) {
+		case *[]ComponentStorageEntry:
+			*v = append(*v,
+				ComponentStorageEntry{ComponentID: 42, GuildID: 65835858358583, Key: "first"},
+				ComponentStorageEntry{ComponentID: 42, GuildID: 65835858358583, Key: "second"})
+		}
+	}).Return(nil).Once()
+
+	result, err := suite.csem.GetByScope(42, 65835858358583, 0, 0)
+
+	suite.dba.AssertExpectations(suite.T())
+	suite.NoError(err)
+	suite.Len(result, 2)
+	suite.Equal("first", result[0].Key)
+	suite.Equal("second", result[1].Key)
+}
+
+func (suite *ComponentStorageEntryEntityManagerTestSuite) TestCreate() {
+	testCacheKey := "component_storage_entry_42_65835858358583_0_0_some_key"
+	testEntry := ComponentStorageEntry{
+		ComponentID: 42,
+		GuildID:     65835858358583,
+		Key:         "some_key",
+	}
+
+	err := cache.Update(testCacheKey, testEntry)
+	suite.NoError(err)
+
+	suite.em.On("DB").Return(suite.dba)
+	suite.dba.On("Create", &testEntry).Return(nil).Once()
+
+	err = suite.csem.Create(&testEntry)
+
+	suite.NoError(err)
+	suite.dba.AssertExpectations(suite.T())
+
+	_, ok := cache.Get(testCacheKey, ComponentStorageEntry{})
+	suite.False(ok)
+}
+
+func (suite *ComponentStorageEntryEntityManagerTestSuite) TestSave() {
+	testCacheKey := "component_storage_entry_42_65835858358583_0_0_some_key"
+	testEntry := ComponentStorageEntry{
+		ComponentID: 42,
+		GuildID:     65835858358583,
+		Key:         "some_key",
+	}
+
+	err := cache.Update(testCacheKey, testEntry)
+	suite.NoError(err)
+
+	suite.em.On("DB").Return(suite.dba)
+	suite.dba.On("Save", &testEntry).Return(nil).Once()
+
+	err = suite.csem.Save(&testEntry)
+
+	suite.NoError(err)
+	suite.dba.AssertExpectations(suite.T())
+
+	_, ok := cache.Get(testCacheKey, ComponentStorageEntry{})
+	suite.False(ok)
+}
+
+func (suite *ComponentStorageEntryEntityManagerTestSuite) TestSaveWithError() {
+	testEntry := ComponentStorageEntry{
+		ComponentID: 42,
+		Key:         "some_key",
+	}
+
+	expectedErr := fmt.Errorf("something happened during update")
+
+	suite.em.On("DB").Return(suite.dba)
+	suite.dba.On("Save", &testEntry).Return(expectedErr).Once()
+
+	err := suite.csem.Save(&testEntry)
+
+	suite.Error(err)
+	suite.Equal(expectedErr, err)
+	suite.dba.AssertExpectations(suite.T())
+}
+
+func (suite *ComponentStorageEntryEntityManagerTestSuite) TestUpsert() {
+	db := suite.useDatabase()
+	testCacheKey := "component_storage_entry_42_65835858358583_0_0_some_key"
+
+	err := suite.csem.Upsert(&ComponentStorageEntry{ComponentID: 42, GuildID: 65835858358583, Key: "some_key", Value: "1"})
+	suite.NoError(err)
+
+	err = cache.Update(testCacheKey, ComponentStorageEntry{})
+	suite.NoError(err)
+
+	err = suite.csem.Upsert(&ComponentStorageEntry{ComponentID: 42, GuildID: 65835858358583, Key: "some_key", Value: "2"})
+	suite.NoError(err)
+
+	var entries []ComponentStorageEntry
+	suite.NoError(db.Find(&entries).Error)
+	suite.Len(entries, 1)
+	suite.Equal("2", entries[0].Value)
+
+	_, ok := cache.Get(testCacheKey, ComponentStorageEntry{})
+	suite.False(ok)
+}
+
+func (suite *ComponentStorageEntryEntityManagerTestSuite) TestUpsertAfterDelete() {
+	db := suite.useDatabase()
+
+	entry := &ComponentStorageEntry{ComponentID: 42, Key: "some_key", Value: "1"}
+	suite.NoError(suite.csem.Upsert(entry))
+	suite.NoError(suite.csem.Delete(entry))
+	suite.NoError(suite.csem.Upsert(&ComponentStorageEntry{ComponentID: 42, Key: "some_key", Value: "2"}))
+
+	var entries []ComponentStorageEntry
+	suite.NoError(db.Unscoped().Find(&entries).Error)
+	suite.Len(entries, 1)
+	suite.Equal("2", entries[0].Value)
+}
+
+func (suite *ComponentStorageEntryEntityManagerTestSuite) TestDelete() {
+	db := suite.useDatabase()
+	testCacheKey := "component_storage_entry_42_65835858358583_0_0_some_key"
+	testEntry := ComponentStorageEntry{
+		ComponentID: 42,
+		GuildID:     65835858358583,
+		Key:         "some_key",
+	}
+	suite.NoError(db.Create(&testEntry).Error)
+
+	err := cache.Update(testCacheKey, testEntry)
+	suite.NoError(err)
+
+	err = suite.csem.Delete(&testEntry)
+
+	suite.NoError(err)
+
+	var count int64
+	suite.NoError(db.Unscoped().Model(&ComponentStorageEntry{}).Count(&count).Error)
+	suite.Equal(int64(0), count)
+
+	_, ok := cache.Get(testCacheKey, ComponentStorageEntry{})
+	suite.False(ok)
+}
+
+func (suite *ComponentStorageEntryEntityManagerTestSuite) TestDeleteExpired() {
+	db := suite.useDatabase()
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	suite.NoError(db.Create(&[]ComponentStorageEntry{
+		{ComponentID: 42, Key: "first", ExpiresAt: &past},
+		{ComponentID: 42, Key: "second", ExpiresAt: &now},
+		{ComponentID: 42, Key: "third", ExpiresAt: &future},
+		{ComponentID: 42, Key: "fourth"},
+	}).Error)
+
+	count, err := suite.csem.DeleteExpired(now)
+
+	suite.NoError(err)
+	suite.Equal(2, count)
+
+	var keys []string
+	suite.NoError(db.Unscoped().Model(&ComponentStorageEntry{}).Order(ColumnKey).Pluck(ColumnKey, &keys).Error)
+	suite.Equal([]string{"fourth", "third"}, keys)
+}
+
+func (suite *ComponentStorageEntryEntityManagerTestSuite) TestDeleteExpiredWithError() {
+	expectedErr := fmt.Errorf("something happened during lookup")
+
+	suite.em.On("DB").Return(suite.dba)
+	suite.dba.On(
+		"GetEntities",
+		mock.AnythingOfType(reflect.TypeOf(&[]ComponentStorageEntry{}).String()),
+		mock.Anything,
+	).Return(expectedErr).Once()
+
+	count, err := suite.csem.DeleteExpired(time.Now())
+
+	suite.Equal(expectedErr, err)
+	suite.Equal(0, count)
+	suite.dba.AssertExpectations(suite.T())
+}
+
+// useDatabase lets the entity manager work on an in-memory database
+// that holds the ComponentStorageEntry table.
+func (suite *ComponentStorageEntryEntityManagerTestSuite) useDatabase() *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	suite.NoError(err)
+
+	// Every connection would get its own in-memory database
+	sqlDB, err := db.DB()
+	suite.NoError(err)
+	sqlDB.SetMaxOpenConns(1)
+	suite.T().Cleanup(func() {
+		_ = sqlDB.Close()
+	})
+
+	suite.NoError(db.AutoMigrate(&RegisteredComponent{}, &ComponentStorageEntry{}))
+
+	suite.em.On("DB").Return(database.New(db))
+
+	return db
+}
+
+func TestComponentStorageEntryEntityManager(t *testing.T) {
+	suite.Run(t, new(ComponentStorageEntryEntityManagerTestSuite))
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/lazybytez/jojo-discord-bot/services/cache"
 	"gorm.io/gorm"
 	"strconv"
+	"time"
 )
 
 // Guild represents a single Discord guild
@@ -29,10 +30,19 @@ import (
 //
 // Note that the guild name is just stored for convenience when
 // manually searching the DB for a guild.
+//
+// The Locale is used to respond to interactions on the guild.
+// When it is empty, the locale of the user is used.
+//
+// LeftAt is set when the bot leaves the guild. The data of the guild
+// is kept until it is purged after the retention period, or restored
+// when the bot rejoins the guild.
 type Guild struct {
 	gorm.Model
 	GuildID uint64 `gorm:"uniqueIndex"`
 	Name    string
+	Locale  string
+	LeftAt  *time.Time `gorm:"index:idx_guild_left_at;"`
 }
 
 // GuildEntityManager is the Guild specific entity manager
@@ -86,6 +96,19 @@ func (gem *GuildEntityManager) Count() (int64, error) {
 	return count, db.Error
 }
 
+// GetLeftBefore returns all guilds that have been left by the bot
+// before or at the passed time.
+func (gem *GuildEntityManager) GetLeftBefore(before time.Time) ([]Guild, error) {
+	var guilds []Guild
+
+	err := gem.DB().GetEntities(&guilds, ColumnLeftAt+" IS NOT NULL AND "+ColumnLeftAt+" <= ?", before)
+	if nil != err {
+		return guilds, err
+	}
+
+	return guilds, nil
+}
+
 // Create saves the passed Guild in the database.
 // Use Update or Save to update an already existing Guild.
 func (gem *GuildEntityManager) Create(guild *Guild) error {
@@ -95,7 +118,7 @@ func (gem *GuildEntityManager) Create(guild *Guild) error {
 	}
 
 	// Invalidate cache item (if present)
-	cache.Invalidate(gem.getCacheKeyFromIntGuildId(guild.GuildID), GlobalComponentStatus{})
+	cache.Invalidate(gem.getCacheKeyFromIntGuildId(guild.GuildID), Guild{})
 
 	return nil
 }
@@ -110,7 +133,7 @@ func (gem *GuildEntityManager) Save(guild *Guild) error {
 	}
 
 	// Invalidate cache item (if present)
-	cache.Invalidate(gem.getCacheKeyFromIntGuildId(guild.GuildID), GlobalComponentStatus{})
+	cache.Invalidate(gem.getCacheKeyFromIntGuildId(guild.GuildID), Guild{})
 
 	return nil
 }
@@ -123,7 +146,7 @@ func (gem *GuildEntityManager) Update(guild *Guild, column string, value interfa
 	}
 
 	// Invalidate cache item (if present)
-	cache.Invalidate(gem.getCacheKeyFromIntGuildId(guild.GuildID), GlobalComponentStatus{})
+	cache.Invalidate(gem.getCacheKeyFromIntGuildId(guild.GuildID), Guild{})
 
 	return nil
 }
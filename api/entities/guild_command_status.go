@@ -0,0 +1,118 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package entities
+
+import (
+	"fmt"
+	"github.com/lazybytez/jojo-discord-bot/services/cache"
+	"gorm.io/gorm"
+)
+
+// GuildCommandStatus holds the status of a single command of a component on a specific guild.
+// Commands without a GuildCommandStatus are enabled, as long as their component is enabled.
+type GuildCommandStatus struct {
+	gorm.Model
+	GuildID     uint                `gorm:"index:idx_guild_command_status_guild_id_component_id;"`
+	Guild       Guild               `gorm:"constraint:OnDelete:CASCADE;"`
+	ComponentID uint                `gorm:"index:idx_guild_command_status_guild_id_component_id;"`
+	Component   RegisteredComponent `gorm:"constraint:OnDelete:CASCADE;"`
+	// Command is the key of the command, which is the name for chat commands
+	// and the name prefixed with the type for context-menu commands.
+	Command string
+	Enabled bool
+}
+
+// GuildCommandStatusEntityManager is the guild command status specific entity manager
+// that allows easy access to the status of commands on guilds.
+type GuildCommandStatusEntityManager struct {
+	EntityManager
+}
+
+// NewGuildCommandStatusEntityManager creates a new GuildCommandStatusEntityManager.
+func NewGuildCommandStatusEntityManager(entityManager EntityManager) *GuildCommandStatusEntityManager {
+	gcsem := &GuildCommandStatusEntityManager{
+		entityManager,
+	}
+
+	return gcsem
+}
+
+// GetByGuildAndComponent returns the GuildCommandStatus of all commands of the passed component
+// on the passed guild. The function uses a cache and first tries to resolve the statuses from it.
+// If no cache entry is present, a request to the entities will be made.
+func (gcsem *GuildCommandStatusEntityManager) GetByGuildAndComponent(
+	guildId uint,
+	componentId uint,
+) ([]GuildCommandStatus, error) {
+	cacheKey := gcsem.getCacheKey(guildId, componentId)
+	cachedStatuses, ok := cache.Get(cacheKey, []GuildCommandStatus{})
+
+	if ok {
+		return cachedStatuses, nil
+	}
+
+	var statuses []GuildCommandStatus
+	queryStr := ColumnGuild + " = ? AND " + ColumnComponent + " = ?"
+	err := gcsem.DB().GetEntities(&statuses, queryStr, guildId, componentId)
+	if nil != err {
+		return statuses, err
+	}
+
+	_ = cache.Update(cacheKey, statuses)
+
+	return statuses, nil
+}
+
+// Create saves the passed GuildCommandStatus in the database.
+// Use Save to update an already existing GuildCommandStatus.
+func (gcsem *GuildCommandStatusEntityManager) Create(guildCommandStatus *GuildCommandStatus) error {
+	err := gcsem.DB().Create(guildCommandStatus)
+	if nil != err {
+		return err
+	}
+
+	gcsem.invalidateCache(guildCommandStatus)
+
+	return nil
+}
+
+// Save updates the passed GuildCommandStatus in the database.
+func (gcsem *GuildCommandStatusEntityManager) Save(guildCommandStatus *GuildCommandStatus) error {
+	err := gcsem.DB().Save(guildCommandStatus)
+	if nil != err {
+		return err
+	}
+
+	gcsem.invalidateCache(guildCommandStatus)
+
+	return nil
+}
+
+// invalidateCache invalidates the cached statuses of the component
+// the passed GuildCommandStatus belongs to (if present).
+func (gcsem *GuildCommandStatusEntityManager) invalidateCache(guildCommandStatus *GuildCommandStatus) {
+	cacheKey := gcsem.getCacheKey(guildCommandStatus.GuildID, guildCommandStatus.ComponentID)
+	cache.Invalidate(cacheKey, []GuildCommandStatus{})
+}
+
+// getCacheKey concatenates the passed guild and component ids to create
+// a new unique cache key for the command statuses of a component.
+func (gcsem *GuildCommandStatusEntityManager) getCacheKey(guildId uint, componentId uint) string {
+	return fmt.Sprintf("guild_command_status_%v_%v", guildId, componentId)
+}
@@ -0,0 +1,185 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package entities
+
+import (
+	"fmt"
+	"github.com/lazybytez/jojo-discord-bot/services/cache"
+	"github.com/lazybytez/jojo-discord-bot/test/dbmock"
+	"github.com/lazybytez/jojo-discord-bot/test/entity_manager_mock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type GuildCommandStatusEntityManagerTestSuite struct {
+	suite.Suite
+	dba   *dbmock.DatabaseAccessMock
+	em    entity_manager_mock.EntityManagerMock
+	gcsem *GuildCommandStatusEntityManager
+}
+
+func (suite *GuildCommandStatusEntityManagerTestSuite) SetupTest() {
+	suite.dba = &dbmock.DatabaseAccessMock{}
+	suite.em = entity_manager_mock.EntityManagerMock{}
+	suite.gcsem = &GuildCommandStatusEntityManager{
+		&suite.em,
+	}
+
+	err := cache.Init(cache.ModeMemory, 10*time.Minute, "")
+	suite.NoError(err)
+}
+
+func (suite *GuildCommandStatusEntityManagerTestSuite) TestGetCacheKey() {
+	result := suite.gcsem.getCacheKey(65835858358583, 48688742646283)
+
+	suite.Equal("guild_command_status_65835858358583_48688742646283", result)
+}
+
+func (suite *GuildCommandStatusEntityManagerTestSuite) TestNewGuildCommandStatusEntityManager() {
+	testEntityManager := entity_manager_mock.EntityManagerMock{}
+
+	gcsem := NewGuildCommandStatusEntityManager(&testEntityManager)
+
+	suite.NotNil(gcsem)
+	suite.Equal(&testEntityManager, gcsem.EntityManager)
+}
+
+func (suite *GuildCommandStatusEntityManagerTestSuite) TestGetByGuildAndComponent() {
+	guildId := uint(65835858358583)
+	componentId := uint(48688742646283)
+	testCacheKey := "guild_command_status_65835858358583_48688742646283"
+
+	suite.em.On("DB").Return(suite.dba)
+	suite.dba.On(
+		"GetEntities",
+		mock.AnythingOfType(reflect.TypeOf(&[]GuildCommandStatus{}).String()),
+		[]interface{}{
+			ColumnGuild + " = ? AND " + ColumnComponent + " = ?",
+			guildId,
+			componentId,
+		},
+	).Run(func(args mock.Arguments) {
+		switch v := args.Get(0).(type) {
+		case *[]GuildCommandStatus:
+			*v = append(*v, GuildCommandStatus{
+				GuildID:     guildId,
+				ComponentID: componentId,
+				Command:     "dice",
+				Enabled:     false,
+			})
+		}
+	}).Return(nil).Once()
+
+	result, err := suite.gcsem.GetByGuildAndComponent(guildId, componentId)
+
+	suite.dba.AssertExpectations(suite.T())
+	suite.NoError(err)
+	suite.Len(result, 1)
+	suite.Equal("dice", result[0].Command)
+
+	cachedOverrides, ok := cache.Get(testCacheKey, []GuildCommandStatus{})
+	suite.True(ok)
+	suite.Equal(result, cachedOverrides)
+
+	// Consecutive calls are served by the cache
+	result, err = suite.gcsem.GetByGuildAndComponent(guildId, componentId)
+
+	suite.dba.AssertExpectations(suite.T())
+	suite.NoError(err)
+	suite.Len(result, 1)
+}
+
+func (suite *GuildCommandStatusEntityManagerTestSuite) TestGetByGuildAndComponentWithError() {
+	guildId := uint(65835858358583)
+	componentId := uint(48688742646283)
+	testCacheKey := "guild_command_status_65835858358583_48688742646283"
+	expectedError := fmt.Errorf("something bad happened during database read")
+
+	suite.em.On("DB").Return(suite.dba)
+	suite.dba.On(
+		"GetEntities",
+		mock.AnythingOfType(reflect.TypeOf(&[]GuildCommandStatus{}).String()),
+		[]interface{}{
+			ColumnGuild + " = ? AND " + ColumnComponent + " = ?",
+			guildId,
+			componentId,
+		},
+	).Return(expectedError).Once()
+
+	_, err := suite.gcsem.GetByGuildAndComponent(guildId, componentId)
+
+	suite.dba.AssertExpectations(suite.T())
+	suite.Equal(expectedError, err)
+
+	_, ok := cache.Get(testCacheKey, []GuildCommandStatus{})
+	suite.False(ok)
+}
+
+func (suite *GuildCommandStatusEntityManagerTestSuite) TestCreate() {
+	testCacheKey := "guild_command_status_65835858358583_48688742646283"
+	testOverride := GuildCommandStatus{
+		GuildID:     65835858358583,
+		ComponentID: 48688742646283,
+		Command:     "dice",
+	}
+
+	err := cache.Update(testCacheKey, []GuildCommandStatus{})
+	suite.NoError(err)
+
+	suite.em.On("DB").Return(suite.dba)
+	suite.dba.On("Create", &testOverride).Return(nil).Once()
+
+	err = suite.gcsem.Create(&testOverride)
+
+	suite.NoError(err)
+	suite.dba.AssertExpectations(suite.T())
+
+	_, ok := cache.Get(testCacheKey, []GuildCommandStatus{})
+	suite.False(ok)
+}
+
+func (suite *GuildCommandStatusEntityManagerTestSuite) TestSave() {
+	testCacheKey := "guild_command_status_65835858358583_48688742646283"
+	testOverride := GuildCommandStatus{
+		GuildID:     65835858358583,
+		ComponentID: 48688742646283,
+		Command:     "dice",
+	}
+
+	err := cache.Update(testCacheKey, []GuildCommandStatus{})
+	suite.NoError(err)
+
+	suite.em.On("DB").Return(suite.dba)
+	suite.dba.On("Save", &testOverride).Return(nil).Once()
+
+	err = suite.gcsem.Save(&testOverride)
+
+	suite.NoError(err)
+	suite.dba.AssertExpectations(suite.T())
+
+	_, ok := cache.Get(testCacheKey, []GuildCommandStatus{})
+	suite.False(ok)
+}
+
+func TestGuildCommandStatusEntityManager(t *testing.T) {
+	suite.Run(t, new(GuildCommandStatusEntityManagerTestSuite))
+}
@@ -0,0 +1,182 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package entities
+
+import (
+	"fmt"
+	"github.com/lazybytez/jojo-discord-bot/services/cache"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GuildComponentConfig holds a single configuration value of a component on a specific guild.
+// The value is stored as string and converted by the component configuration API
+// according to the configuration schema of the component.
+// There can only be one value per guild, component and key.
+type GuildComponentConfig struct {
+	gorm.Model
+	GuildID     uint                `gorm:"uniqueIndex:idx_guild_component_config_guild_id_component_id_key;"`
+	Guild       Guild               `gorm:"constraint:OnDelete:CASCADE;"`
+	ComponentID uint                `gorm:"uniqueIndex:idx_guild_component_config_guild_id_component_id_key;"`
+	Component   RegisteredComponent `gorm:"constraint:OnDelete:CASCADE;"`
+	Key         string              `gorm:"uniqueIndex:idx_guild_component_config_guild_id_component_id_key;"`
+	Value       string
+}
+
+// GuildComponentConfigEntityManager is the guild component config specific entity manager
+// that allows easy access to the configuration values of components on guilds.
+type GuildComponentConfigEntityManager struct {
+	EntityManager
+}
+
+// NewGuildComponentConfigEntityManager creates a new GuildComponentConfigEntityManager.
+func NewGuildComponentConfigEntityManager(entityManager EntityManager) *GuildComponentConfigEntityManager {
+	gccem := &GuildComponentConfigEntityManager{
+		entityManager,
+	}
+
+	return gccem
+}
+
+// Get tries to get a GuildComponentConfig from the
+// cache. If no cache entry is present, a request to the entities will be made.
+// If no GuildComponentConfig can be found, the function returns a new empty
+// GuildComponentConfig.
+func (gccem *GuildComponentConfigEntityManager) Get(
+	guildId uint,
+	componentId uint,
+	key string,
+) (*GuildComponentConfig, error) {
+	cacheKey := gccem.getComponentConfigCacheKey(guildId, componentId, key)
+	cachedConfig, ok := cache.Get(cacheKey, GuildComponentConfig{})
+
+	if ok {
+		return &cachedConfig, nil
+	}
+
+	guildCompConfig := &GuildComponentConfig{}
+	queryStr := ColumnGuild + " = ? AND " + ColumnComponent + " = ? AND " + ColumnKey + " = ?"
+	err := gccem.DB().GetFirstEntity(guildCompConfig, queryStr, guildId, componentId, key)
+	if nil != err {
+		return guildCompConfig, err
+	}
+
+	_ = cache.Update(cacheKey, *guildCompConfig)
+
+	return guildCompConfig, nil
+}
+
+// Create saves the passed GuildComponentConfig in the database.
+// Use Update or Save to update an already existing GuildComponentConfig.
+func (gccem *GuildComponentConfigEntityManager) Create(guildComponentConfig *GuildComponentConfig) error {
+	err := gccem.DB().Create(guildComponentConfig)
+	if nil != err {
+		return err
+	}
+
+	gccem.invalidateCache(guildComponentConfig)
+
+	return nil
+}
+
+// Save updates the passed GuildComponentConfig in the database.
+// This does a generic update, use Update to do a precise and more performant update
+// of the entity when only updating a single field!
+func (gccem *GuildComponentConfigEntityManager) Save(guildComponentConfig *GuildComponentConfig) error {
+	err := gccem.DB().Save(guildComponentConfig)
+	if nil != err {
+		return err
+	}
+
+	gccem.invalidateCache(guildComponentConfig)
+
+	return nil
+}
+
+// Update updates the defined field on the entity and saves it in the database.
+func (gccem *GuildComponentConfigEntityManager) Update(
+	guildComponentConfig *GuildComponentConfig,
+	column string,
+	value interface{},
+) error {
+	err := gccem.DB().UpdateEntity(guildComponentConfig, column, value)
+	if nil != err {
+		return err
+	}
+
+	gccem.invalidateCache(guildComponentConfig)
+
+	return nil
+}
+
+// Upsert creates the passed GuildComponentConfig or, when there already is a value
+// for the same guild, component and key, replaces the stored value.
+func (gccem *GuildComponentConfigEntityManager) Upsert(guildComponentConfig *GuildComponentConfig) error {
+	err := gccem.DB().WorkOn(guildComponentConfig).Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: ColumnGuild},
+			{Name: ColumnComponent},
+			{Name: ColumnKey},
+		},
+		DoUpdates: clause.AssignmentColumns([]string{
+			ColumnValue,
+			ColumnUpdatedAt,
+			ColumnDeletedAt,
+		}),
+	}).Omit(clause.Associations).Create(guildComponentConfig).Error
+	if nil != err {
+		return err
+	}
+
+	gccem.invalidateCache(guildComponentConfig)
+
+	return nil
+}
+
+// Delete removes the passed GuildComponentConfig from the database.
+// Values are deleted permanently, so that the key can be configured again.
+func (gccem *GuildComponentConfigEntityManager) Delete(guildComponentConfig *GuildComponentConfig) error {
+	err := gccem.DB().WorkOn(guildComponentConfig).Unscoped().Delete(guildComponentConfig).Error
+	if nil != err {
+		return err
+	}
+
+	gccem.invalidateCache(guildComponentConfig)
+
+	return nil
+}
+
+// invalidateCache invalidates the cache item of the passed GuildComponentConfig (if present).
+func (gccem *GuildComponentConfigEntityManager) invalidateCache(guildComponentConfig *GuildComponentConfig) {
+	cacheKey := gccem.getComponentConfigCacheKey(
+		guildComponentConfig.GuildID,
+		guildComponentConfig.ComponentID,
+		guildComponentConfig.Key)
+	cache.Invalidate(cacheKey, GuildComponentConfig{})
+}
+
+// getComponentConfigCacheKey concatenates the passed guild id, component id and key to create
+// a new unique cache key for the component config value.
+func (gccem *GuildComponentConfigEntityManager) getComponentConfigCacheKey(
+	guildId uint,
+	componentId uint,
+	key string,
+) string {
+	return fmt.Sprintf("guild_component_config_%v_%v_%s", guildId, componentId, key)
+}
@@ -0,0 +1,311 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package entities
+
+import (
+	"fmt"
+	"github.com/lazybytez/jojo-discord-bot/services/cache"
+	"github.com/lazybytez/jojo-discord-bot/services/database"
+	"github.com/lazybytez/jojo-discord-bot/test/dbmock"
+	"github.com/lazybytez/jojo-discord-bot/test/entity_manager_mock"
+	"github.com/lazybytez/jojo-discord-bot/test/logmock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type GuildComponentConfigEntityManagerTestSuite struct {
+	suite.Suite
+	dba    *dbmock.DatabaseAccessMock
+	logger *logmock.LoggerMock
+	em     entity_manager_mock.EntityManagerMock
+	gccem  *GuildComponentConfigEntityManager
+}
+
+func (suite *GuildComponentConfigEntityManagerTestSuite) SetupTest() {
+	dba := &dbmock.DatabaseAccessMock{}
+	logger := &logmock.LoggerMock{}
+
+	suite.dba = dba
+	suite.logger = logger
+	suite.em = entity_manager_mock.EntityManagerMock{}
+	suite.gccem = &GuildComponentConfigEntityManager{
+		&suite.em,
+	}
+
+	err := cache.Init(cache.ModeMemory, 10*time.Minute, "")
+	suite.NoError(err)
+}
+
+func (suite *GuildComponentConfigEntityManagerTestSuite) TestGetCacheKey() {
+	guildId := uint(65835858358583)
+	componentId := uint(48688742646283)
+	key := "some_key"
+
+	expectedCacheKey := "guild_component_config_65835858358583_48688742646283_some_key"
+
+	result := suite.gccem.getComponentConfigCacheKey(guildId, componentId, key)
+
+	suite.Equal(expectedCacheKey, result)
+}
+
+func (suite *GuildComponentConfigEntityManagerTestSuite) TestNewGuildComponentConfigEntityManager() {
+	testEntityManager := entity_manager_mock.EntityManagerMock{}
+
+	gccem := NewGuildComponentConfigEntityManager(&testEntityManager)
+
+	suite.NotNil(gccem)
+	suite.Equal(&testEntityManager, gccem.EntityManager)
+}
+
+func (suite *GuildComponentConfigEntityManagerTestSuite) TestGet() {
+	guildId := uint(65835858358583)
+	componentId := uint(48688742646283)
+	key := "some_key"
+	testCacheKey := "guild_component_config_65835858358583_48688742646283_some_key"
+
+	suite.em.On("DB").Return(suite.dba)
+	suite.dba.On(
+		"GetFirstEntity",
+		mock.AnythingOfType(reflect.TypeOf(&GuildComponentConfig{}).Name()),
+		[]interface{}{
+			ColumnGuild + " = ? AND " + ColumnComponent + " = ? AND " + ColumnKey + " = ?",
+			guildId,
+			componentId,
+			key,
+		},
+	).Run(func(args mock.Arguments) {
+		switch v := args.Get(0).(type) {
+		case *GuildComponentConfig:
+			v.GuildID = guildId
+			v.ComponentID = componentId
+			v.Key = key
+			v.Value = "some_value"
+		}
+	}).Return(nil).Once()
+
+	result, err := suite.gccem.Get(guildId, componentId, key)
+
+	suite.dba.AssertExpectations(suite.T())
+	suite.NoError(err)
+	suite.NotNil(result)
+	suite.Equal("some_value", result.Value)
+
+	cachedGuildComponentConfig, ok := cache.Get(testCacheKey, GuildComponentConfig{})
+
+	suite.True(ok)
+	suite.Equal(*result, cachedGuildComponentConfig)
+}
+
+func (suite *GuildComponentConfigEntityManagerTestSuite) TestGetWithCache() {
+	guildId := uint(65835858358583)
+	componentId := uint(48688742646283)
+	key := "some_key"
+	testCacheKey := "guild_component_config_65835858358583_48688742646283_some_key"
+
+	testGuildComponentConfig := GuildComponentConfig{
+		GuildID:     guildId,
+		ComponentID: componentId,
+		Key:         key,
+		Value:       "some_value",
+	}
+
+	err := cache.Update(testCacheKey, testGuildComponentConfig)
+	suite.NoError(err)
+
+	result, err := suite.gccem.Get(guildId, componentId, key)
+
+	suite.dba.AssertExpectations(suite.T())
+	suite.NoError(err)
+	suite.Equal(testGuildComponentConfig, *result)
+}
+
+func (suite *GuildComponentConfigEntityManagerTestSuite) TestGetWithError() {
+	guildId := uint(65835858358583)
+	componentId := uint(48688742646283)
+	key := "some_key"
+	testCacheKey := "guild_component_config_65835858358583_48688742646283_some_key"
+
+	expectedError := fmt.Errorf("something bad happened during database read")
+
+	suite.em.On("DB").Return(suite.dba)
+	suite.dba.On(
+		"GetFirstEntity",
+		mock.AnythingOfType(reflect.TypeOf(&GuildComponentConfig{}).Name()),
+		[]interface{}{
+			ColumnGuild + " = ? AND " + ColumnComponent + " = ? AND " + ColumnKey + " = ?",
+			guildId,
+			componentId,
+			key,
+		},
+	).Return(expectedError).Once()
+
+	result, err := suite.gccem.Get(guildId, componentId, key)
+
+	suite.dba.AssertExpectations(suite.T())
+	suite.Error(err)
+	suite.Equal(GuildComponentConfig{}, *result)
+
+	_, ok := cache.Get(testCacheKey, GuildComponentConfig{})
+	suite.False(ok)
+}
+
+func (suite *GuildComponentConfigEntityManagerTestSuite) TestCreate() {
+	testCacheKey := "guild_component_config_65835858358583_48688742646283_some_key"
+	testGuildComponentConfig := GuildComponentConfig{
+		GuildID:     65835858358583,
+		ComponentID: 48688742646283,
+		Key:         "some_key",
+	}
+
+	err := cache.Update(testCacheKey, testGuildComponentConfig)
+	suite.NoError(err)
+
+	suite.em.On("DB").Return(suite.dba)
+	suite.dba.On("Create", &testGuildComponentConfig).Return(nil).Once()
+
+	err = suite.gccem.Create(&testGuildComponentConfig)
+
+	suite.NoError(err)
+	suite.dba.AssertExpectations(suite.T())
+
+	_, ok := cache.Get(testCacheKey, GuildComponentConfig{})
+	suite.False(ok)
+}
+
+func (suite *GuildComponentConfigEntityManagerTestSuite) TestSave() {
+	testCacheKey := "guild_component_config_65835858358583_48688742646283_some_key"
+	testGuildComponentConfig := GuildComponentConfig{
+		GuildID:     65835858358583,
+		ComponentID: 48688742646283,
+		Key:         "some_key",
+	}
+
+	err := cache.Update(testCacheKey, testGuildComponentConfig)
+	suite.NoError(err)
+
+	suite.em.On("DB").Return(suite.dba)
+	suite.dba.On("Save", &testGuildComponentConfig).Return(nil).Once()
+
+	err = suite.gccem.Save(&testGuildComponentConfig)
+
+	suite.NoError(err)
+	suite.dba.AssertExpectations(suite.T())
+
+	_, ok := cache.Get(testCacheKey, GuildComponentConfig{})
+	suite.False(ok)
+}
+
+func (suite *GuildComponentConfigEntityManagerTestSuite) TestSaveWithError() {
+	testGuildComponentConfig := GuildComponentConfig{
+		GuildID:     65835858358583,
+		ComponentID: 48688742646283,
+		Key:         "some_key",
+	}
+
+	expectedErr := fmt.Errorf("something happened during update")
+
+	suite.em.On("DB").Return(suite.dba)
+	suite.dba.On("Save", &testGuildComponentConfig).Return(expectedErr).Once()
+
+	err := suite.gccem.Save(&testGuildComponentConfig)
+
+	suite.Error(err)
+	suite.Equal(expectedErr, err)
+	suite.dba.AssertExpectations(suite.T())
+}
+
+func (suite *GuildComponentConfigEntityManagerTestSuite) TestUpsert() {
+	db := suite.useDatabase()
+	testCacheKey := "guild_component_config_1_2_some_key"
+
+	err := suite.gccem.Upsert(&GuildComponentConfig{GuildID: 1, ComponentID: 2, Key: "some_key", Value: "1"})
+	suite.NoError(err)
+
+	err = cache.Update(testCacheKey, GuildComponentConfig{})
+	suite.NoError(err)
+
+	err = suite.gccem.Upsert(&GuildComponentConfig{GuildID: 1, ComponentID: 2, Key: "some_key", Value: "2"})
+	suite.NoError(err)
+
+	var configs []GuildComponentConfig
+	suite.NoError(db.Find(&configs).Error)
+	suite.Len(configs, 1)
+	suite.Equal("2", configs[0].Value)
+
+	_, ok := cache.Get(testCacheKey, GuildComponentConfig{})
+	suite.False(ok)
+}
+
+func (suite *GuildComponentConfigEntityManagerTestSuite) TestDelete() {
+	db := suite.useDatabase()
+	testCacheKey := "guild_component_config_65835858358583_48688742646283_some_key"
+	testGuildComponentConfig := GuildComponentConfig{
+		GuildID:     65835858358583,
+		ComponentID: 48688742646283,
+		Key:         "some_key",
+	}
+	suite.NoError(db.Omit("Guild", "Component").Create(&testGuildComponentConfig).Error)
+
+	err := cache.Update(testCacheKey, testGuildComponentConfig)
+	suite.NoError(err)
+
+	err = suite.gccem.Delete(&testGuildComponentConfig)
+
+	suite.NoError(err)
+
+	var count int64
+	suite.NoError(db.Unscoped().Model(&GuildComponentConfig{}).Count(&count).Error)
+	suite.Equal(int64(0), count)
+
+	_, ok := cache.Get(testCacheKey, GuildComponentConfig{})
+	suite.False(ok)
+}
+
+// useDatabase lets the entity manager work on an in-memory database
+// that holds the GuildComponentConfig table.
+func (suite *GuildComponentConfigEntityManagerTestSuite) useDatabase() *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	suite.NoError(err)
+
+	// Every connection would get its own in-memory database
+	sqlDB, err := db.DB()
+	suite.NoError(err)
+	sqlDB.SetMaxOpenConns(1)
+	suite.T().Cleanup(func() {
+		_ = sqlDB.Close()
+	})
+
+	suite.NoError(db.AutoMigrate(&Guild{}, &RegisteredComponent{}, &GuildComponentConfig{}))
+
+	suite.em.On("DB").Return(database.New(db))
+
+	return db
+}
+
+func TestGuildComponentConfigEntityManager(t *testing.T) {
+	suite.Run(t, new(GuildComponentConfigEntityManagerTestSuite))
+}
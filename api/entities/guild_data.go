@@ -0,0 +1,194 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package entities
+
+import (
+	"github.com/lazybytez/jojo-discord-bot/services/cache"
+	"gorm.io/gorm"
+)
+
+// guildScopedEntities holds the entities that reference a Guild
+// by its database ID and are removed when the Guild is purged.
+var guildScopedEntities = []interface{}{
+	&GuildComponentStatus{},
+	&GuildComponentConfig{},
+	&GuildCommandStatus{},
+	&CommandPermissionOverride{},
+	&AuditLogConfig{},
+	&AuditLog{},
+}
+
+// GuildData holds a Guild and all data bound to it.
+// The components referenced by the data are preloaded.
+type GuildData struct {
+	Guild                      Guild
+	ComponentStatuses          []GuildComponentStatus
+	ComponentConfigs           []GuildComponentConfig
+	CommandStatuses            []GuildCommandStatus
+	CommandPermissionOverrides []CommandPermissionOverride
+	AuditLogConfig             *AuditLogConfig
+	AuditLog                   []AuditLog
+	ComponentStorage           []ComponentStorageEntry
+}
+
+// GetData returns the passed Guild and all data bound to it.
+// The data is read directly from the database and bypasses the cache.
+func (gem *GuildEntityManager) GetData(guild *Guild) (*GuildData, error) {
+	data := &GuildData{
+		Guild: *guild,
+	}
+
+	db := gem.DB().DB()
+	queries := []struct {
+		rows    interface{}
+		preload string
+		id      interface{}
+	}{
+		{&data.ComponentStatuses, "Component", guild.ID},
+		{&data.ComponentConfigs, "Component", guild.ID},
+		{&data.CommandStatuses, "Component", guild.ID},
+		{&data.CommandPermissionOverrides, "", guild.ID},
+		{&data.AuditLog, "RegisteredComponent", guild.ID},
+		// The component storage references guilds by their Discord ID
+		{&data.ComponentStorage, "Component", guild.GuildID},
+	}
+
+	for _, query := range queries {
+		tx := db.Where(ColumnGuild+" = ?", query.id).Order("id")
+		if "" != query.preload {
+			tx = tx.Preload(query.preload)
+		}
+
+		err := tx.Find(query.rows).Error
+		if nil != err {
+			return nil, err
+		}
+	}
+
+	var auditLogConfigs []AuditLogConfig
+	err := db.Where(ColumnGuild+" = ?", guild.ID).Limit(1).Find(&auditLogConfigs).Error
+	if nil != err {
+		return nil, err
+	}
+
+	if len(auditLogConfigs) > 0 {
+		data.AuditLogConfig = &auditLogConfigs[0]
+	}
+
+	return data, nil
+}
+
+// Purge permanently removes the passed Guild and all data bound to it,
+// including soft-deleted rows. All rows are removed in a single transaction.
+//
+// The function returns the number of removed rows.
+func (gem *GuildEntityManager) Purge(guild *Guild) (int64, error) {
+	var componentStatuses []GuildComponentStatus
+	var componentConfigs []GuildComponentConfig
+	var commandStatuses []GuildCommandStatus
+	var overrides []CommandPermissionOverride
+	var storageEntries []ComponentStorageEntry
+	var removedRows int64
+
+	err := gem.DB().DB().Transaction(func(tx *gorm.DB) error {
+		// Remember cached rows, to invalidate them after the purge
+		cachedRows := []interface{}{&componentStatuses, &componentConfigs, &commandStatuses, &overrides}
+		for _, rows := range cachedRows {
+			err := tx.Unscoped().Where(ColumnGuild+" = ?", guild.ID).Find(rows).Error
+			if nil != err {
+				return err
+			}
+		}
+
+		err := tx.Unscoped().Where(ColumnGuild+" = ?", guild.GuildID).Find(&storageEntries).Error
+		if nil != err {
+			return err
+		}
+
+		for _, entity := range guildScopedEntities {
+			result := tx.Unscoped().Where(ColumnGuild+" = ?", guild.ID).Delete(entity)
+			if nil != result.Error {
+				return result.Error
+			}
+			removedRows += result.RowsAffected
+		}
+
+		// The component storage references guilds by their Discord ID
+		result := tx.Unscoped().Where(ColumnGuild+" = ?", guild.GuildID).Delete(&ComponentStorageEntry{})
+		if nil != result.Error {
+			return result.Error
+		}
+		removedRows += result.RowsAffected
+
+		result = tx.Unscoped().Delete(guild)
+		if nil != result.Error {
+			return result.Error
+		}
+		removedRows += result.RowsAffected
+
+		return nil
+	})
+	if nil != err {
+		return 0, err
+	}
+
+	gem.invalidatePurgedCache(guild, componentStatuses, componentConfigs, commandStatuses, overrides, storageEntries)
+
+	return removedRows, nil
+}
+
+// invalidatePurgedCache invalidates the cache items of the passed purged Guild and its data.
+func (gem *GuildEntityManager) invalidatePurgedCache(
+	guild *Guild,
+	componentStatuses []GuildComponentStatus,
+	componentConfigs []GuildComponentConfig,
+	commandStatuses []GuildCommandStatus,
+	overrides []CommandPermissionOverride,
+	storageEntries []ComponentStorageEntry,
+) {
+	cache.Invalidate(gem.getCacheKeyFromIntGuildId(guild.GuildID), Guild{})
+
+	gcsem := NewGuildComponentStatusEntityManager(gem.EntityManager)
+	for _, status := range componentStatuses {
+		cache.Invalidate(gcsem.getComponentStatusCacheKey(status.GuildID, status.ComponentID), GuildComponentStatus{})
+	}
+
+	gccem := NewGuildComponentConfigEntityManager(gem.EntityManager)
+	for i := range componentConfigs {
+		gccem.invalidateCache(&componentConfigs[i])
+	}
+
+	gcmdsem := NewGuildCommandStatusEntityManager(gem.EntityManager)
+	for i := range commandStatuses {
+		gcmdsem.invalidateCache(&commandStatuses[i])
+	}
+
+	cpoem := NewCommandPermissionOverrideEntityManager(gem.EntityManager)
+	for i := range overrides {
+		cpoem.invalidateCache(&overrides[i])
+	}
+
+	alcem := NewAuditLogConfigEntityManager(gem.EntityManager)
+	cache.Invalidate(alcem.getCacheKey(guild.ID), AuditLogConfig{})
+
+	csem := NewComponentStorageEntryEntityManager(gem.EntityManager)
+	for i := range storageEntries {
+		csem.invalidateCache(&storageEntries[i])
+	}
+}
@@ -0,0 +1,178 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package entities
+
+import (
+	"github.com/lazybytez/jojo-discord-bot/services/cache"
+	"github.com/lazybytez/jojo-discord-bot/services/database"
+	"github.com/lazybytez/jojo-discord-bot/test/entity_manager_mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"testing"
+	"time"
+)
+
+type GuildDataTestSuite struct {
+	suite.Suite
+	db  *gorm.DB
+	em  entity_manager_mock.EntityManagerMock
+	gem *GuildEntityManager
+}
+
+func (suite *GuildDataTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	suite.NoError(err)
+
+	// Every connection would get its own in-memory database
+	sqlDB, err := db.DB()
+	suite.NoError(err)
+	sqlDB.SetMaxOpenConns(1)
+
+	suite.NoError(db.AutoMigrate(
+		&Guild{},
+		&RegisteredComponent{},
+		&GuildComponentStatus{},
+		&GuildComponentConfig{},
+		&GuildCommandStatus{},
+		&CommandPermissionOverride{},
+		&AuditLogConfig{},
+		&AuditLog{},
+		&ComponentStorageEntry{}))
+
+	suite.db = db
+	suite.em = entity_manager_mock.EntityManagerMock{}
+	suite.em.On("DB").Return(database.New(db))
+	suite.gem = &GuildEntityManager{
+		&suite.em,
+	}
+
+	err = cache.Init(cache.ModeMemory, 10*time.Minute, "")
+	suite.NoError(err)
+}
+
+func (suite *GuildDataTestSuite) TearDownTest() {
+	sqlDB, err := suite.db.DB()
+	suite.NoError(err)
+	suite.NoError(sqlDB.Close())
+}
+
+func (suite *GuildDataTestSuite) createGuildWithData(guildId uint64) *Guild {
+	leftAt := time.Now()
+	guild := &Guild{GuildID: guildId, Name: "Test", LeftAt: &leftAt}
+	suite.NoError(suite.db.Create(guild).Error)
+
+	component := &RegisteredComponent{Code: ComponentCode("test_component_" + guild.Name)}
+	suite.NoError(suite.db.FirstOrCreate(component, RegisteredComponent{Code: component.Code}).Error)
+
+	suite.NoError(suite.db.Create(&GuildComponentStatus{GuildID: guild.ID, ComponentID: component.ID}).Error)
+	suite.NoError(suite.db.Create(&GuildComponentConfig{GuildID: guild.ID, ComponentID: component.ID, Key: "k"}).Error)
+	suite.NoError(suite.db.Create(&GuildCommandStatus{GuildID: guild.ID, ComponentID: component.ID}).Error)
+	suite.NoError(suite.db.Create(&CommandPermissionOverride{GuildID: guild.ID, Command: "dice"}).Error)
+	suite.NoError(suite.db.Create(&AuditLogConfig{GuildID: guild.ID}).Error)
+	suite.NoError(suite.db.Create(&AuditLog{GuildID: guild.ID, RegisteredComponentID: component.ID}).Error)
+	suite.NoError(suite.db.Create(&ComponentStorageEntry{ComponentID: component.ID, GuildID: guildId, Key: "k"}).Error)
+
+	// Soft-deleted rows are purged as well
+	deletedOverride := &CommandPermissionOverride{GuildID: guild.ID, Command: "other"}
+	suite.NoError(suite.db.Create(deletedOverride).Error)
+	suite.NoError(suite.db.Delete(deletedOverride).Error)
+
+	return guild
+}
+
+func (suite *GuildDataTestSuite) countRows(entity interface{}) int64 {
+	var count int64
+	suite.NoError(suite.db.Unscoped().Model(entity).Count(&count).Error)
+
+	return count
+}
+
+func (suite *GuildDataTestSuite) TestGetData() {
+	guild := suite.createGuildWithData(65835858358583)
+	suite.createGuildWithData(48688742646283)
+
+	data, err := suite.gem.GetData(guild)
+
+	suite.NoError(err)
+	suite.Equal(guild.GuildID, data.Guild.GuildID)
+	suite.Len(data.ComponentStatuses, 1)
+	suite.Equal(ComponentCode("test_component_Test"), data.ComponentStatuses[0].Component.Code)
+	suite.Len(data.ComponentConfigs, 1)
+	suite.Len(data.CommandStatuses, 1)
+	// Soft-deleted rows are not part of the data
+	suite.Len(data.CommandPermissionOverrides, 1)
+	suite.NotNil(data.AuditLogConfig)
+	suite.Len(data.AuditLog, 1)
+	suite.Equal(ComponentCode("test_component_Test"), data.AuditLog[0].RegisteredComponent.Code)
+	suite.Len(data.ComponentStorage, 1)
+	suite.Equal(guild.GuildID, data.ComponentStorage[0].GuildID)
+}
+
+func (suite *GuildDataTestSuite) TestGetDataWithoutData() {
+	guild := &Guild{GuildID: 65835858358583}
+	suite.NoError(suite.db.Create(guild).Error)
+
+	data, err := suite.gem.GetData(guild)
+
+	suite.NoError(err)
+	suite.Nil(data.AuditLogConfig)
+	suite.Empty(data.ComponentStatuses)
+	suite.Empty(data.AuditLog)
+}
+
+func (suite *GuildDataTestSuite) TestPurge() {
+	purgedGuild := suite.createGuildWithData(65835858358583)
+	keptGuild := suite.createGuildWithData(48688742646283)
+
+	err := cache.Update("65835858358583", *purgedGuild)
+	suite.NoError(err)
+
+	rows, err := suite.gem.Purge(purgedGuild)
+
+	suite.NoError(err)
+	suite.Equal(int64(9), rows)
+
+	for _, entity := range []interface{}{
+		&Guild{},
+		&GuildComponentStatus{},
+		&GuildComponentConfig{},
+		&GuildCommandStatus{},
+		&AuditLogConfig{},
+		&AuditLog{},
+		&ComponentStorageEntry{},
+	} {
+		suite.Equal(int64(1), suite.countRows(entity))
+	}
+	suite.Equal(int64(2), suite.countRows(&CommandPermissionOverride{}))
+
+	var remainingGuild Guild
+	suite.NoError(suite.db.First(&remainingGuild).Error)
+	suite.Equal(keptGuild.GuildID, remainingGuild.GuildID)
+
+	_, ok := cache.Get("65835858358583", Guild{})
+	suite.False(ok)
+}
+
+func TestGuildData(t *testing.T) {
+	suite.Run(t, new(GuildDataTestSuite))
+}
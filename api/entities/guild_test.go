@@ -140,6 +140,29 @@ func (suite *GuildEntityManagerTestSuite) TestGetWithError() {
 	suite.Equal(Guild{}, cachedGuild)
 }
 
+func (suite *GuildEntityManagerTestSuite) TestGetLeftBefore() {
+	before := time.Now()
+
+	suite.em.On("DB").Return(suite.dba)
+	suite.dba.On(
+		"GetEntities",
+		mock.AnythingOfType(reflect.TypeOf(&[]Guild{}).String()),
+		[]interface{}{ColumnLeftAt + " IS NOT NULL AND " + ColumnLeftAt + " <= ?", before},
+	).Run(func(args mock.Arguments) {
+		switch v := args.Get(0).(type) {
+		case *[]Guild:
+			*v = append(*v, Guild{GuildID: 65835858358583, LeftAt: &before})
+		}
+	}).Return(nil).Once()
+
+	result, err := suite.gem.GetLeftBefore(before)
+
+	suite.dba.AssertExpectations(suite.T())
+	suite.NoError(err)
+	suite.Len(result, 1)
+	suite.Equal(uint64(65835858358583), result[0].GuildID)
+}
+
 func (suite *GuildEntityManagerTestSuite) TestCreate() {
 	testId := uint64(652658256236529525)
 	testCacheKey := "652658256236529525"
@@ -0,0 +1,35 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package entities
+
+import "time"
+
+// SchemaMigration records a single versioned migration that has been
+// applied to the database schema. The table holding the records
+// is the schema history of the application.
+//
+// Unlike other entities, applied migrations are removed permanently
+// when they are rolled back.
+type SchemaMigration struct {
+	ID          uint   `gorm:"primarykey"`
+	Owner       string `gorm:"uniqueIndex:idx_schema_migration_owner_version;"`
+	Version     uint64 `gorm:"uniqueIndex:idx_schema_migration_owner_version;"`
+	Description string
+	AppliedAt   time.Time
+}
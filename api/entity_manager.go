@@ -19,23 +19,10 @@
 package api
 
 import (
-	"github.com/lazybytez/jojo-discord-bot/api/entities"
 	"github.com/lazybytez/jojo-discord-bot/api/util"
 	"github.com/lazybytez/jojo-discord-bot/services"
 )
 
-// defaultEntities holds a list of all entities that should be
-// registered by default. The list is registered in the order
-// the entities are added to the list.
-var defaultEntities = []interface{}{
-	&entities.Guild{},
-	&entities.RegisteredComponent{},
-	&entities.GlobalComponentStatus{},
-	&entities.GuildComponentStatus{},
-	&entities.AuditLogConfig{},
-	&entities.AuditLog{},
-}
-
 // EntityManager is a struct embedded by GormDatabaseAccessor
 // that holds the instances of the entity specific entity managers
 type EntityManager struct {
@@ -46,8 +33,12 @@ type EntityManager struct {
 	globalComponentStatusEntityManager GlobalComponentStatusEntityManager
 	registeredComponentEntityManager   RegisteredComponentEntityManager
 	guildComponentStatusEntityManager  GuildComponentStatusEntityManager
+	guildComponentConfigEntityManager  GuildComponentConfigEntityManager
+	guildCommandStatusEntityManager    GuildCommandStatusEntityManager
+	commandPermissionOverrideManager   CommandPermissionOverrideEntityManager
 	auditLogConfigEntityManager        AuditLogConfigEntityManager
 	auditLogEntityManager              AuditLogEntityManager
+	componentStorageEntryEntityManager ComponentStorageEntryEntityManager
 }
 
 // entityManager is the internal database.GormDatabaseAccess instance
@@ -66,6 +57,9 @@ func NewEntityManager(database services.DatabaseAccess, logger services.Logger)
 
 // RegisterEntity registers a new entity (struct) and runs its automated
 // migration to ensure the entities schema is up-to-date.
+//
+// Automated migrations cannot rename columns, migrate data or be rolled back.
+// Prefer declaring versioned Migrations on the Component instead.
 func (em *EntityManager) RegisterEntity(entityType interface{}) error {
 	err := em.database.RegisterEntity(entityType)
 	if nil != err {
@@ -84,19 +78,6 @@ func (em *EntityManager) DB() services.DatabaseAccess {
 	return em.database
 }
 
-// RegisterDefaultEntities takes care of letting gorm
-// know about all entities in this file.
-func (em *EntityManager) RegisterDefaultEntities() error {
-	for _, entity := range defaultEntities {
-		err := em.RegisterEntity(entity)
-		if nil != err {
-			return err
-		}
-	}
-
-	return nil
-}
-
 // Logger returns the services.Logger of the EntityManager.
 func (em *EntityManager) Logger() services.Logger {
 	return em.logger
@@ -20,6 +20,7 @@ package api
 
 import (
 	"fmt"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
 	"github.com/lazybytez/jojo-discord-bot/test/dbmock"
 	"github.com/lazybytez/jojo-discord-bot/test/logmock"
 	"github.com/stretchr/testify/mock"
@@ -44,21 +45,21 @@ func (suite *EntityManagerTestSuite) SetupTest() {
 	suite.em = NewEntityManager(suite.dba, suite.logger)
 }
 
-func (suite *EntityManagerTestSuite) TestRegisterDefaultEntitiesWithSuccess() {
-	for _, entity := range defaultEntities {
-		suite.dba.On("RegisterEntity", entity).Once().Return(nil)
-		suite.logger.On("Info",
-			mock.AnythingOfType("string"),
-			mock.AnythingOfType(reflect.TypeOf([]interface{}{}).Name()))
-	}
+func (suite *EntityManagerTestSuite) TestRegisterEntityWithSuccess() {
+	entity := &entities.Guild{}
 
-	err := suite.em.RegisterDefaultEntities()
+	suite.dba.On("RegisterEntity", entity).Once().Return(nil)
+	suite.logger.On("Info",
+		mock.AnythingOfType("string"),
+		mock.AnythingOfType(reflect.TypeOf([]interface{}{}).Name()))
+
+	err := suite.em.RegisterEntity(entity)
 
 	suite.dba.AssertExpectations(suite.T())
 	suite.NoError(err)
 }
 
-func (suite *EntityManagerTestSuite) TestRegisterDefaultEntitiesWithFailure() {
+func (suite *EntityManagerTestSuite) TestRegisterEntityWithFailure() {
 	simulatedErr := fmt.Errorf("something bad happened")
 
 	suite.dba.On("RegisterEntity", mock.Anything).Once().Return(simulatedErr)
@@ -67,7 +68,7 @@ func (suite *EntityManagerTestSuite) TestRegisterDefaultEntitiesWithFailure() {
 		mock.AnythingOfType("string"),
 		mock.AnythingOfType(reflect.TypeOf([]interface{}{}).Name()))
 
-	err := suite.em.RegisterDefaultEntities()
+	err := suite.em.RegisterEntity(&entities.Guild{})
 
 	suite.dba.AssertExpectations(suite.T())
 	suite.Error(err)
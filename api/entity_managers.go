@@ -20,6 +20,7 @@ package api
 
 import (
 	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"time"
 )
 
 // GuildEntityManager is an entity manager
@@ -32,6 +33,9 @@ type GuildEntityManager interface {
 	Get(guildId string) (*entities.Guild, error)
 	// Count returns the number of all guilds stored in the db
 	Count() (int64, error)
+	// GetLeftBefore returns all guilds that have been left by the bot
+	// before or at the passed time.
+	GetLeftBefore(before time.Time) ([]entities.Guild, error)
 
 	// Create saves the passed Guild in the db.
 	// Use Update or Save to update an already existing Guild.
@@ -42,6 +46,12 @@ type GuildEntityManager interface {
 	Save(guild *entities.Guild) error
 	// Update updates the defined field on the entity and saves it in the db.
 	Update(guild *entities.Guild, column string, value interface{}) error
+	// GetData returns the passed Guild and all data bound to it.
+	// The data is read directly from the db and bypasses the cache.
+	GetData(guild *entities.Guild) (*entities.GuildData, error)
+	// Purge permanently removes the passed Guild and all data bound to it.
+	// The function returns the number of removed rows.
+	Purge(guild *entities.Guild) (int64, error)
 }
 
 // Guilds returns the GuildEntityManager that is currently active,
@@ -158,6 +168,95 @@ func (em *EntityManager) GuildComponentStatus() GuildComponentStatusEntityManage
 	return em.guildComponentStatusEntityManager
 }
 
+// GuildComponentConfigEntityManager is an entity manager
+// that provides functionality for entities.GuildComponentConfig CRUD operations.
+type GuildComponentConfigEntityManager interface {
+	// Get tries to get a GuildComponentConfig from the
+	// cache. If no cache entry is present, a request to the entities will be made.
+	// If no GuildComponentConfig can be found, the function returns a new empty
+	// GuildComponentConfig.
+	Get(guildId uint, componentId uint, key string) (*entities.GuildComponentConfig, error)
+
+	// Create saves the passed GuildComponentConfig in the db.
+	// Use Update or Save to update an already existing GuildComponentConfig.
+	Create(guildComponentConfig *entities.GuildComponentConfig) error
+	// Save updates the passed GuildComponentConfig in the db.
+	// This does a generic update, use Update to do a precise and more performant update
+	// of the entity when only updating a single field!
+	Save(guildComponentConfig *entities.GuildComponentConfig) error
+	// Update updates the defined field on the entity and saves it in the db.
+	Update(guildComponentConfig *entities.GuildComponentConfig, column string, value interface{}) error
+	// Upsert creates the passed GuildComponentConfig or, when there already is a value
+	// for the same guild, component and key, replaces the stored value.
+	Upsert(guildComponentConfig *entities.GuildComponentConfig) error
+	// Delete permanently removes the passed GuildComponentConfig from the db.
+	Delete(guildComponentConfig *entities.GuildComponentConfig) error
+}
+
+// GuildComponentConfig returns the GuildComponentConfigEntityManager that is currently active,
+// which can be used to do GuildComponentConfig specific entities actions.
+func (em *EntityManager) GuildComponentConfig() GuildComponentConfigEntityManager {
+	if nil == em.guildComponentConfigEntityManager {
+		em.guildComponentConfigEntityManager = entities.NewGuildComponentConfigEntityManager(em)
+	}
+
+	return em.guildComponentConfigEntityManager
+}
+
+// GuildCommandStatusEntityManager is an entity manager
+// that provides functionality for entities.GuildCommandStatus CRUD operations.
+type GuildCommandStatusEntityManager interface {
+	// GetByGuildAndComponent returns the GuildCommandStatus of all commands of the passed component
+	// on the passed guild. The function uses a cache and first tries to resolve the statuses from it.
+	// If no cache entry is present, a request to the entities will be made.
+	GetByGuildAndComponent(guildId uint, componentId uint) ([]entities.GuildCommandStatus, error)
+
+	// Create saves the passed GuildCommandStatus in the db.
+	// Use Save to update an already existing GuildCommandStatus.
+	Create(guildCommandStatus *entities.GuildCommandStatus) error
+	// Save updates the passed GuildCommandStatus in the db.
+	Save(guildCommandStatus *entities.GuildCommandStatus) error
+}
+
+// GuildCommandStatus returns the GuildCommandStatusEntityManager that is currently active,
+// which can be used to do GuildCommandStatus specific entities actions.
+func (em *EntityManager) GuildCommandStatus() GuildCommandStatusEntityManager {
+	if nil == em.guildCommandStatusEntityManager {
+		em.guildCommandStatusEntityManager = entities.NewGuildCommandStatusEntityManager(em)
+	}
+
+	return em.guildCommandStatusEntityManager
+}
+
+// CommandPermissionOverrideEntityManager is an entity manager
+// that provides functionality for entities.CommandPermissionOverride CRUD operations.
+type CommandPermissionOverrideEntityManager interface {
+	// GetByGuildAndCommand returns all CommandPermissionOverride of the command with the
+	// passed name on the passed guild. The function uses a cache and first tries to resolve
+	// the overrides from it. If no cache entry is present, a request to the entities will be made.
+	GetByGuildAndCommand(guildId uint, command string) ([]entities.CommandPermissionOverride, error)
+	// GetByGuild returns all CommandPermissionOverride of the passed guild.
+	GetByGuild(guildId uint) ([]entities.CommandPermissionOverride, error)
+
+	// Create saves the passed CommandPermissionOverride in the db.
+	// Use Save to update an already existing CommandPermissionOverride.
+	Create(override *entities.CommandPermissionOverride) error
+	// Save updates the passed CommandPermissionOverride in the db.
+	Save(override *entities.CommandPermissionOverride) error
+	// Delete removes the passed CommandPermissionOverride from the db.
+	Delete(override *entities.CommandPermissionOverride) error
+}
+
+// CommandPermissionOverride returns the CommandPermissionOverrideEntityManager that is currently active,
+// which can be used to do CommandPermissionOverride specific entities actions.
+func (em *EntityManager) CommandPermissionOverride() CommandPermissionOverrideEntityManager {
+	if nil == em.commandPermissionOverrideManager {
+		em.commandPermissionOverrideManager = entities.NewCommandPermissionOverrideEntityManager(em)
+	}
+
+	return em.commandPermissionOverrideManager
+}
+
 // AuditLogConfigEntityManager is an entity manager
 // that provides functionality for entities.AuditLogConfig CRUD operations.
 type AuditLogConfigEntityManager interface {
@@ -217,3 +316,51 @@ func (em *EntityManager) AuditLog() AuditLogEntityManager {
 
 	return em.auditLogEntityManager
 }
+
+// ComponentStorageEntryEntityManager is an entity manager
+// that provides functionality for entities.ComponentStorageEntry CRUD operations.
+type ComponentStorageEntryEntityManager interface {
+	// Get tries to get a ComponentStorageEntry from the
+	// cache. If no cache entry is present, a request to the db will be made.
+	// If no ComponentStorageEntry can be found, the function returns a new empty
+	// ComponentStorageEntry.
+	Get(
+		componentId uint,
+		guildId uint64,
+		channelId uint64,
+		userId uint64,
+		key string,
+	) (*entities.ComponentStorageEntry, error)
+	// GetByScope returns all ComponentStorageEntry of the passed component
+	// that are stored in exactly the passed scope.
+	GetByScope(
+		componentId uint,
+		guildId uint64,
+		channelId uint64,
+		userId uint64,
+	) ([]entities.ComponentStorageEntry, error)
+
+	// Create saves the passed ComponentStorageEntry in the db.
+	// Use Save to update an already existing ComponentStorageEntry.
+	Create(entry *entities.ComponentStorageEntry) error
+	// Save updates the passed ComponentStorageEntry in the db.
+	Save(entry *entities.ComponentStorageEntry) error
+	// Upsert creates the passed ComponentStorageEntry or, when there already is an entry
+	// with the same component, scope and key, replaces its value and expiry.
+	Upsert(entry *entities.ComponentStorageEntry) error
+	// Delete permanently removes the passed ComponentStorageEntry from the db.
+	Delete(entry *entities.ComponentStorageEntry) error
+	// DeleteExpired removes all ComponentStorageEntry that expired before or at the passed time
+	// and returns the number of removed entries.
+	DeleteExpired(now time.Time) (int, error)
+}
+
+// ComponentStorageEntry returns the ComponentStorageEntryEntityManager that is currently active,
+// which can be used to do entities.ComponentStorageEntry specific entities actions.
+func (em *EntityManager) ComponentStorageEntry() ComponentStorageEntryEntityManager {
+	if nil == em.componentStorageEntryEntityManager {
+		em.componentStorageEntryEntityManager = entities.NewComponentStorageEntryEntityManager(em)
+	}
+
+	return em.componentStorageEntryEntityManager
+}
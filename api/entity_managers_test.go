@@ -134,6 +134,75 @@ func (suite *EntityManagersTestSuite) TestGetGuildComponentStatusEntityManagerWi
 	suite.Equal(result, result2)
 }
 
+func (suite *EntityManagersTestSuite) TestGetGuildComponentConfigEntityManagerWithExistingGuildComponentConfigEntityManager() {
+	guildComponentConfigEntityManager := &entities.GuildComponentConfigEntityManager{}
+
+	suite.em.guildComponentConfigEntityManager = guildComponentConfigEntityManager
+
+	result := suite.em.GuildComponentConfig()
+
+	suite.NotNil(result)
+	suite.Equal(guildComponentConfigEntityManager, result)
+}
+
+func (suite *EntityManagersTestSuite) TestGetGuildComponentConfigEntityManagerWithNoExistingGuildComponentConfigEntityManager() {
+	result := suite.em.GuildComponentConfig()
+	result2 := suite.em.GuildComponentConfig()
+
+	// First call
+	suite.NotNil(result)
+	suite.IsType(&entities.GuildComponentConfigEntityManager{}, result)
+
+	// Consecutive calls
+	suite.Equal(result, result2)
+}
+
+func (suite *EntityManagersTestSuite) TestGetGuildCommandStatusEntityManagerWithExistingGuildCommandStatusEntityManager() {
+	guildCommandStatusEntityManager := &entities.GuildCommandStatusEntityManager{}
+
+	suite.em.guildCommandStatusEntityManager = guildCommandStatusEntityManager
+
+	result := suite.em.GuildCommandStatus()
+
+	suite.NotNil(result)
+	suite.Equal(guildCommandStatusEntityManager, result)
+}
+
+func (suite *EntityManagersTestSuite) TestGetGuildCommandStatusEntityManagerWithNoExistingGuildCommandStatusEntityManager() {
+	result := suite.em.GuildCommandStatus()
+	result2 := suite.em.GuildCommandStatus()
+
+	// First call
+	suite.NotNil(result)
+	suite.IsType(&entities.GuildCommandStatusEntityManager{}, result)
+
+	// Consecutive calls
+	suite.Equal(result, result2)
+}
+
+func (suite *EntityManagersTestSuite) TestGetCommandPermissionOverrideEntityManagerWithExistingCommandPermissionOverrideEntityManager() {
+	commandPermissionOverrideEntityManager := &entities.CommandPermissionOverrideEntityManager{}
+
+	suite.em.commandPermissionOverrideManager = commandPermissionOverrideEntityManager
+
+	result := suite.em.CommandPermissionOverride()
+
+	suite.NotNil(result)
+	suite.Equal(commandPermissionOverrideEntityManager, result)
+}
+
+func (suite *EntityManagersTestSuite) TestGetCommandPermissionOverrideEntityManagerWithNoExistingCommandPermissionOverrideEntityManager() {
+	result := suite.em.CommandPermissionOverride()
+	result2 := suite.em.CommandPermissionOverride()
+
+	// First call
+	suite.NotNil(result)
+	suite.IsType(&entities.CommandPermissionOverrideEntityManager{}, result)
+
+	// Consecutive calls
+	suite.Equal(result, result2)
+}
+
 func (suite *EntityManagersTestSuite) TestGetAuditLogConfigEntityManagerWithExistingAuditLogConfigEntityManager() {
 	auditLogEntityManager := &entities.AuditLogConfigEntityManager{}
 
@@ -157,6 +226,29 @@ func (suite *EntityManagersTestSuite) TestGetAuditLogConfigEntityManagerWithNoEx
 	suite.Equal(result, result2)
 }
 
+func (suite *EntityManagersTestSuite) TestGetComponentStorageEntryEntityManagerWithExistingComponentStorageEntryEntityManager() {
+	componentStorageEntryEntityManager := &entities.ComponentStorageEntryEntityManager{}
+
+	suite.em.componentStorageEntryEntityManager = componentStorageEntryEntityManager
+
+	result := suite.em.ComponentStorageEntry()
+
+	suite.NotNil(result)
+	suite.Equal(componentStorageEntryEntityManager, result)
+}
+
+func (suite *EntityManagersTestSuite) TestGetComponentStorageEntryEntityManagerWithNoExistingComponentStorageEntryEntityManager() {
+	result := suite.em.ComponentStorageEntry()
+	result2 := suite.em.ComponentStorageEntry()
+
+	// First call
+	suite.NotNil(result)
+	suite.IsType(&entities.ComponentStorageEntryEntityManager{}, result)
+
+	// Consecutive calls
+	suite.Equal(result, result2)
+}
+
 func TestEntityManagers(t *testing.T) {
 	suite.Run(t, new(EntityManagersTestSuite))
 }
@@ -0,0 +1,238 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// The event bus allows components to communicate with each other,
+// without depending on each other directly.
+//
+// Events are plain structs that are published as pointers (e.g. *ComponentToggledEvent).
+// Subscribers register a function that takes exactly one argument, which is the
+// pointer type of the event they want to receive. An event is delivered to all
+// subscribers whose argument type matches the type of the event.
+//
+// Like event handlers, subscriptions are owned by a component. Events are not
+// delivered to subscribers of components that are disabled. For events that
+// implement GuildEvent, the status of the component on the guild is respected.
+// Subscribers are called in their own goroutine, so publishing an event never blocks.
+
+// SubscriptionName is the name of a specific subscription
+// that belongs to a specific component.
+// It is computed from the component code and the name of the subscription,
+// like names of event handlers (see GetHandlerName).
+type SubscriptionName string
+
+// GuildEvent is the interface of events that happened on a specific guild.
+// Events implementing the interface are only delivered to subscribers
+// of components that are enabled on the guild.
+type GuildEvent interface {
+	// GetGuildId returns the id of the guild the event happened on.
+	GetGuildId() string
+}
+
+// eventSubscription holds a single subscriber of the event bus.
+type eventSubscription struct {
+	name      SubscriptionName
+	component *Component
+	eventType reflect.Type
+	handler   reflect.Value
+}
+
+// eventSubscriptionMap holds all subscriptions of the event bus.
+type eventSubscriptionMap struct {
+	sync.RWMutex
+	subscriptions map[SubscriptionName]*eventSubscription
+}
+
+// eventSubscriptions holds the subscriptions of all components.
+var eventSubscriptions = eventSubscriptionMap{
+	subscriptions: make(map[SubscriptionName]*eventSubscription),
+}
+
+// EventBus is the interface that allows components to publish events
+// and to subscribe to events published by other components.
+//
+// Subscriptions are removed when the owning component is unloaded.
+type EventBus interface {
+	// Subscribe registers the passed handler for the event type it accepts.
+	//
+	// The handler must be a function with exactly one argument, which
+	// is the pointer type of the event, e.g.:
+	//   func (event *api.ComponentToggledEvent)
+	//
+	// The passed name is concatenated with the code of the component
+	// that owns the subscription and must be unique.
+	Subscribe(name string, handler interface{}) (SubscriptionName, error)
+	// Unsubscribe removes the subscription with the given name.
+	//
+	// If the specified subscription does not exist, an error will be returned.
+	Unsubscribe(name SubscriptionName) error
+	// UnsubscribeAll removes all subscriptions of the component.
+	UnsubscribeAll()
+	// Publish delivers the passed event to all subscribers of its type.
+	Publish(event interface{})
+}
+
+// ComponentEventBus is the EventBus implementation
+// that is bound to a specific component.
+type ComponentEventBus struct {
+	owner *Component
+}
+
+// EventBus returns the EventBus of the component,
+// which allows to publish and subscribe to events.
+//
+// On first call, this function initializes the private Component.eventBus
+// field. On consecutive calls, the already present EventBus will be used.
+func (c *Component) EventBus() EventBus {
+	if nil == c.eventBus {
+		c.eventBus = &ComponentEventBus{owner: c}
+	}
+
+	return c.eventBus
+}
+
+// Subscribe registers the passed handler for the event type it accepts.
+//
+// The handler must be a function with exactly one argument, which
+// is the pointer type of the event, e.g.:
+//
+//	func (event *api.ComponentToggledEvent)
+//
+// The passed name is concatenated with the code of the component
+// that owns the subscription and must be unique.
+func (ceb *ComponentEventBus) Subscribe(name string, handler interface{}) (SubscriptionName, error) {
+	subscriptionName := SubscriptionName(GetHandlerName(ceb.owner.Code, name))
+
+	handlerType := reflect.TypeOf(handler)
+	if nil == handlerType || reflect.Func != handlerType.Kind() || 1 != handlerType.NumIn() {
+		return subscriptionName, errors.New("the handler of a subscription must be a function with exactly one argument")
+	}
+
+	eventSubscriptions.Lock()
+	defer eventSubscriptions.Unlock()
+
+	if _, ok := eventSubscriptions.subscriptions[subscriptionName]; ok {
+		return subscriptionName, fmt.Errorf(
+			"a subscription for component \"%s\" with name \"%s\" is already registered",
+			ceb.owner.Code,
+			name)
+	}
+
+	eventSubscriptions.subscriptions[subscriptionName] = &eventSubscription{
+		name:      subscriptionName,
+		component: ceb.owner,
+		eventType: handlerType.In(0),
+		handler:   reflect.ValueOf(handler),
+	}
+
+	ceb.owner.Logger().Info("Subscribed to events of type \"%s\" with name \"%s\"!",
+		handlerType.In(0),
+		subscriptionName)
+
+	return subscriptionName, nil
+}
+
+// Unsubscribe removes the subscription with the given name.
+//
+// If the specified subscription does not exist, an error will be returned.
+func (ceb *ComponentEventBus) Unsubscribe(name SubscriptionName) error {
+	eventSubscriptions.Lock()
+	defer eventSubscriptions.Unlock()
+
+	subscription, ok := eventSubscriptions.subscriptions[name]
+	if !ok || subscription.component != ceb.owner {
+		return fmt.Errorf(
+			"there is no subscription called \"%s\" registered that could be removed",
+			name)
+	}
+
+	delete(eventSubscriptions.subscriptions, name)
+
+	return nil
+}
+
+// UnsubscribeAll removes all subscriptions of the component.
+func (ceb *ComponentEventBus) UnsubscribeAll() {
+	eventSubscriptions.Lock()
+	defer eventSubscriptions.Unlock()
+
+	for name, subscription := range eventSubscriptions.subscriptions {
+		if subscription.component == ceb.owner {
+			delete(eventSubscriptions.subscriptions, name)
+		}
+	}
+}
+
+// Publish delivers the passed event to all subscribers of its type.
+// Every subscriber is called in its own goroutine.
+func (ceb *ComponentEventBus) Publish(event interface{}) {
+	if nil == event {
+		return
+	}
+
+	eventType := reflect.TypeOf(event)
+	guildId := ""
+	if guildEvent, ok := event.(GuildEvent); ok {
+		guildId = guildEvent.GetGuildId()
+	}
+
+	for _, subscription := range getEventSubscriptions(eventType) {
+		go subscription.deliver(event, guildId)
+	}
+}
+
+// getEventSubscriptions returns a snapshot of all subscriptions
+// that accept events of the passed type.
+func getEventSubscriptions(eventType reflect.Type) []*eventSubscription {
+	eventSubscriptions.RLock()
+	defer eventSubscriptions.RUnlock()
+
+	subscriptions := make([]*eventSubscription, 0)
+	for _, subscription := range eventSubscriptions.subscriptions {
+		if eventType == subscription.eventType {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+
+	return subscriptions
+}
+
+// deliver calls the handler of the subscription with the passed event,
+// unless the owning component is disabled.
+// Panics are recovered and count as failure of the owning component.
+func (es *eventSubscription) deliver(event interface{}, guildId string) {
+	defer func() {
+		if r := recover(); nil != r {
+			recoverFromPanic(es.component, r, fmt.Sprintf("event subscription \"%s\"", es.name))
+		}
+	}()
+
+	if !IsComponentEnabled(es.component, guildId) {
+		return
+	}
+
+	es.handler.Call([]reflect.Value{reflect.ValueOf(event)})
+}
@@ -0,0 +1,156 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"github.com/lazybytez/jojo-discord-bot/test/logmock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type EventBusTestSuite struct {
+	suite.Suite
+	owningComponent *Component
+	eventBus        EventBus
+}
+
+func (suite *EventBusTestSuite) SetupTest() {
+	loggerMock := &logmock.LoggerMock{}
+	loggerMock.On("Info", mock.Anything, mock.Anything)
+	loggerMock.On("Warn", mock.Anything, mock.Anything)
+	loggerMock.On("Err", mock.Anything, mock.Anything, mock.Anything)
+
+	suite.owningComponent = &Component{
+		Code:   "bot_test_component",
+		Name:   "Test Component",
+		logger: loggerMock,
+	}
+	suite.eventBus = suite.owningComponent.EventBus()
+}
+
+func (suite *EventBusTestSuite) TearDownTest() {
+	suite.eventBus.UnsubscribeAll()
+}
+
+func (suite *EventBusTestSuite) TestSubscribeWithInvalidHandler() {
+	invalidHandlers := []interface{}{
+		nil,
+		"handler",
+		func() {},
+		func(_ *ComponentToggledEvent, _ string) {},
+	}
+
+	for _, handler := range invalidHandlers {
+		_, err := suite.eventBus.Subscribe("test", handler)
+		suite.Error(err)
+	}
+}
+
+func (suite *EventBusTestSuite) TestSubscribeTwice() {
+	name, err := suite.eventBus.Subscribe("test", func(_ *ComponentToggledEvent) {})
+	suite.NoError(err)
+	suite.Equal(SubscriptionName("bot_test_component_test"), name)
+
+	_, err = suite.eventBus.Subscribe("test", func(_ *ComponentToggledEvent) {})
+	suite.Error(err)
+}
+
+func (suite *EventBusTestSuite) TestPublishDeliversToMatchingSubscribers() {
+	toggledEvents := make(chan *ComponentToggledEvent, 2)
+	syncedEvents := make(chan *CommandsSyncedEvent, 1)
+
+	_, err := suite.eventBus.Subscribe("first", func(event *ComponentToggledEvent) {
+		toggledEvents <- event
+	})
+	suite.NoError(err)
+	_, err = suite.eventBus.Subscribe("second", func(event *ComponentToggledEvent) {
+		toggledEvents <- event
+	})
+	suite.NoError(err)
+	_, err = suite.eventBus.Subscribe("third", func(event *CommandsSyncedEvent) {
+		syncedEvents <- event
+	})
+	suite.NoError(err)
+
+	event := &ComponentToggledEvent{GuildId: "1234", Component: "test", Enabled: true}
+	suite.eventBus.Publish(event)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case received := <-toggledEvents:
+			suite.Same(event, received)
+		case <-time.After(time.Second):
+			suite.Fail("The event has not been delivered to all subscribers")
+		}
+	}
+
+	select {
+	case <-syncedEvents:
+		suite.Fail("The event has been delivered to a subscriber of another event type")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func (suite *EventBusTestSuite) TestUnsubscribe() {
+	delivered := make(chan struct{}, 1)
+	name, err := suite.eventBus.Subscribe("test", func(_ *GuildRegisteredEvent) {
+		delivered <- struct{}{}
+	})
+	suite.NoError(err)
+
+	otherEventBus := (&Component{Code: "bot_other_component"}).EventBus()
+	suite.Error(otherEventBus.Unsubscribe(name))
+
+	suite.NoError(suite.eventBus.Unsubscribe(name))
+	suite.Error(suite.eventBus.Unsubscribe(name))
+
+	suite.eventBus.Publish(&GuildRegisteredEvent{GuildId: "1234"})
+
+	select {
+	case <-delivered:
+		suite.Fail("The event has been delivered to a removed subscription")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func (suite *EventBusTestSuite) TestPublishRecoversFromPanic() {
+	delivered := make(chan struct{}, 1)
+	_, err := suite.eventBus.Subscribe("panicking", func(_ *GuildRegisteredEvent) {
+		panic("something went wrong")
+	})
+	suite.NoError(err)
+	_, err = suite.eventBus.Subscribe("working", func(_ *GuildRegisteredEvent) {
+		delivered <- struct{}{}
+	})
+	suite.NoError(err)
+
+	suite.eventBus.Publish(&GuildRegisteredEvent{GuildId: "1234"})
+
+	select {
+	case <-delivered:
+	case <-time.After(time.Second):
+		suite.Fail("The event has not been delivered to the working subscriber")
+	}
+}
+
+func TestEventBus(t *testing.T) {
+	suite.Run(t, new(EventBusTestSuite))
+}
@@ -0,0 +1,84 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+)
+
+// The events published by the core of the bot.
+// Subscribe to them using the EventBus, e.g.:
+//
+//	C.EventBus().Subscribe("on_module_toggle", func(event *api.ComponentToggledEvent) {})
+
+// GuildRegisteredEvent is published when the bot joined a guild
+// that has not been known before and the guild has been registered.
+type GuildRegisteredEvent struct {
+	GuildId string
+	Name    string
+}
+
+// GetGuildId returns the id of the guild the event happened on.
+func (e *GuildRegisteredEvent) GetGuildId() string {
+	return e.GuildId
+}
+
+// ComponentToggledEvent is published when a component
+// has been enabled or disabled on a guild.
+type ComponentToggledEvent struct {
+	GuildId   string
+	Component entities.ComponentCode
+	Enabled   bool
+	// UserId is the id of the user that toggled the component.
+	UserId string
+}
+
+// GetGuildId returns the id of the guild the event happened on.
+func (e *ComponentToggledEvent) GetGuildId() string {
+	return e.GuildId
+}
+
+// AuditLogConfigChangedEvent is published when the bot audit log
+// of a guild has been enabled, disabled or moved to another channel.
+type AuditLogConfigChangedEvent struct {
+	GuildId string
+	Enabled bool
+	// ChannelId is the id of the audit log channel.
+	// It is empty, when the audit log has been disabled.
+	ChannelId string
+	// UserId is the id of the user that changed the configuration.
+	UserId string
+}
+
+// GetGuildId returns the id of the guild the event happened on.
+func (e *AuditLogConfigChangedEvent) GetGuildId() string {
+	return e.GuildId
+}
+
+// CommandsSyncedEvent is published when the commands of a guild
+// or the global commands have been overwritten during a command sync.
+//
+// The event does not implement GuildEvent, as the commands of
+// disabled components are removed during the sync.
+type CommandsSyncedEvent struct {
+	// GuildId is the id of the guild whose commands have been synced.
+	// It is empty for global commands.
+	GuildId string
+	Diff    *CommandSyncDiff
+}
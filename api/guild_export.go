@@ -0,0 +1,302 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"strconv"
+	"time"
+)
+
+// GuildExportVersion is the version of the format of a GuildExport.
+// It is increased whenever the format changes in an incompatible way.
+const GuildExportVersion = 1
+
+// GuildExport holds all data the bot stores about a single guild.
+// Discord IDs are exported as strings, to avoid precision loss in JSON.
+type GuildExport struct {
+	Version                    int                                    `json:"version"`
+	ExportedAt                 time.Time                              `json:"exported_at"`
+	Guild                      GuildExportGuild                       `json:"guild"`
+	ComponentStatuses          []GuildExportComponentStatus           `json:"component_statuses"`
+	ComponentConfigs           []GuildExportComponentConfig           `json:"component_configs"`
+	CommandStatuses            []GuildExportCommandStatus             `json:"command_statuses"`
+	CommandPermissionOverrides []GuildExportCommandPermissionOverride `json:"command_permission_overrides"`
+	AuditLogConfig             *GuildExportAuditLogConfig             `json:"audit_log_config"`
+	AuditLog                   []GuildExportAuditLogEntry             `json:"audit_log"`
+	ComponentStorage           []GuildExportComponentStorageEntry     `json:"component_storage"`
+}
+
+// GuildExportGuild holds the general information stored about a guild.
+type GuildExportGuild struct {
+	Id        string     `json:"id"`
+	Name      string     `json:"name"`
+	Locale    string     `json:"locale"`
+	CreatedAt time.Time  `json:"created_at"`
+	LeftAt    *time.Time `json:"left_at"`
+}
+
+// GuildExportComponentStatus holds whether a component is enabled on a guild.
+type GuildExportComponentStatus struct {
+	Component entities.ComponentCode `json:"component"`
+	Enabled   bool                   `json:"enabled"`
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+// GuildExportComponentConfig holds a configuration value of a component on a guild.
+type GuildExportComponentConfig struct {
+	Component entities.ComponentCode `json:"component"`
+	Key       string                 `json:"key"`
+	Value     string                 `json:"value"`
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+// GuildExportCommandStatus holds whether a command is enabled on a guild.
+type GuildExportCommandStatus struct {
+	Component entities.ComponentCode `json:"component"`
+	Command   string                 `json:"command"`
+	Enabled   bool                   `json:"enabled"`
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+// GuildExportCommandPermissionOverride holds a permission rule of a command on a guild.
+type GuildExportCommandPermissionOverride struct {
+	Command    string                                   `json:"command"`
+	TargetType entities.CommandPermissionOverrideTarget `json:"target_type"`
+	TargetId   string                                   `json:"target_id"`
+	Allow      bool                                     `json:"allow"`
+	UpdatedAt  time.Time                                `json:"updated_at"`
+}
+
+// GuildExportAuditLogConfig holds the bot audit log configuration of a guild.
+type GuildExportAuditLogConfig struct {
+	Enabled   bool      `json:"enabled"`
+	ChannelId *string   `json:"channel_id"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GuildExportAuditLogEntry holds a single bot audit log entry of a guild.
+type GuildExportAuditLogEntry struct {
+	Component entities.ComponentCode `json:"component"`
+	UserId    string                 `json:"user_id"`
+	Message   string                 `json:"message"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// GuildExportComponentStorageEntry holds a value stored by a component for a guild.
+type GuildExportComponentStorageEntry struct {
+	Component entities.ComponentCode `json:"component"`
+	ChannelId string                 `json:"channel_id,omitempty"`
+	UserId    string                 `json:"user_id,omitempty"`
+	Key       string                 `json:"key"`
+	Value     json.RawMessage        `json:"value"`
+	ExpiresAt *time.Time             `json:"expires_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+// GuildExportFile is an encoded GuildExport that can be
+// sent as attachment or download.
+type GuildExportFile struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// ExportGuildData collects all data stored about the guild
+// with the passed id into a GuildExport.
+func ExportGuildData(guildId string) (*GuildExport, error) {
+	em := GetEntityManager()
+
+	guild, err := em.Guilds().Get(guildId)
+	if nil != err {
+		return nil, fmt.Errorf("missing guild with id \"%s\" in database: %w", guildId, err)
+	}
+
+	data, err := em.Guilds().GetData(guild)
+	if nil != err {
+		return nil, fmt.Errorf("failed to read data of guild with id \"%s\": %w", guildId, err)
+	}
+
+	return NewGuildExport(data, time.Now()), nil
+}
+
+// NewGuildExport converts the passed entities.GuildData into a GuildExport.
+func NewGuildExport(data *entities.GuildData, exportedAt time.Time) *GuildExport {
+	export := &GuildExport{
+		Version:    GuildExportVersion,
+		ExportedAt: exportedAt,
+		Guild: GuildExportGuild{
+			Id:        formatExportId(data.Guild.GuildID),
+			Name:      data.Guild.Name,
+			Locale:    data.Guild.Locale,
+			CreatedAt: data.Guild.CreatedAt,
+			LeftAt:    data.Guild.LeftAt,
+		},
+		ComponentStatuses:          make([]GuildExportComponentStatus, len(data.ComponentStatuses)),
+		ComponentConfigs:           make([]GuildExportComponentConfig, len(data.ComponentConfigs)),
+		CommandStatuses:            make([]GuildExportCommandStatus, len(data.CommandStatuses)),
+		CommandPermissionOverrides: make([]GuildExportCommandPermissionOverride, len(data.CommandPermissionOverrides)),
+		AuditLog:                   make([]GuildExportAuditLogEntry, len(data.AuditLog)),
+		ComponentStorage:           make([]GuildExportComponentStorageEntry, len(data.ComponentStorage)),
+	}
+
+	for key, status := range data.ComponentStatuses {
+		export.ComponentStatuses[key] = GuildExportComponentStatus{
+			Component: status.Component.Code,
+			Enabled:   status.Enabled,
+			UpdatedAt: status.UpdatedAt,
+		}
+	}
+
+	for key, config := range data.ComponentConfigs {
+		export.ComponentConfigs[key] = GuildExportComponentConfig{
+			Component: config.Component.Code,
+			Key:       config.Key,
+			Value:     config.Value,
+			UpdatedAt: config.UpdatedAt,
+		}
+	}
+
+	for key, status := range data.CommandStatuses {
+		export.CommandStatuses[key] = GuildExportCommandStatus{
+			Component: status.Component.Code,
+			Command:   status.Command,
+			Enabled:   status.Enabled,
+			UpdatedAt: status.UpdatedAt,
+		}
+	}
+
+	for key, override := range data.CommandPermissionOverrides {
+		export.CommandPermissionOverrides[key] = GuildExportCommandPermissionOverride{
+			Command:    override.Command,
+			TargetType: override.TargetType,
+			TargetId:   formatExportId(override.TargetID),
+			Allow:      override.Allow,
+			UpdatedAt:  override.UpdatedAt,
+		}
+	}
+
+	if nil != data.AuditLogConfig {
+		export.AuditLogConfig = &GuildExportAuditLogConfig{
+			Enabled:   data.AuditLogConfig.Enabled,
+			UpdatedAt: data.AuditLogConfig.UpdatedAt,
+		}
+
+		if nil != data.AuditLogConfig.ChannelId {
+			channelId := formatExportId(*data.AuditLogConfig.ChannelId)
+			export.AuditLogConfig.ChannelId = &channelId
+		}
+	}
+
+	for key, entry := range data.AuditLog {
+		export.AuditLog[key] = GuildExportAuditLogEntry{
+			Component: entry.RegisteredComponent.Code,
+			UserId:    formatExportId(entry.UserID),
+			Message:   entry.Message,
+			CreatedAt: entry.CreatedAt,
+		}
+	}
+
+	for key, entry := range data.ComponentStorage {
+		export.ComponentStorage[key] = GuildExportComponentStorageEntry{
+			Component: entry.Component.Code,
+			ChannelId: formatOptionalExportId(entry.ChannelID),
+			UserId:    formatOptionalExportId(entry.UserID),
+			Key:       entry.Key,
+			Value:     getExportStorageValue(entry.Value),
+			ExpiresAt: entry.ExpiresAt,
+			UpdatedAt: entry.UpdatedAt,
+		}
+	}
+
+	return export
+}
+
+// ToFile encodes the GuildExport as indented JSON file.
+// When zipped is true, the JSON file is put into a zip archive.
+func (ge *GuildExport) ToFile(zipped bool) (*GuildExportFile, error) {
+	data, err := json.MarshalIndent(ge, "", "  ")
+	if nil != err {
+		return nil, err
+	}
+
+	baseName := fmt.Sprintf("guild-export-%s-%s", ge.Guild.Id, ge.ExportedAt.UTC().Format("20060102-150405"))
+	if !zipped {
+		return &GuildExportFile{
+			Name:        baseName + ".json",
+			ContentType: "application/json",
+			Data:        data,
+		}, nil
+	}
+
+	buffer := &bytes.Buffer{}
+	archive := zip.NewWriter(buffer)
+
+	writer, err := archive.Create(baseName + ".json")
+	if nil != err {
+		return nil, err
+	}
+
+	_, err = writer.Write(data)
+	if nil != err {
+		return nil, err
+	}
+
+	err = archive.Close()
+	if nil != err {
+		return nil, err
+	}
+
+	return &GuildExportFile{
+		Name:        baseName + ".zip",
+		ContentType: "application/zip",
+		Data:        buffer.Bytes(),
+	}, nil
+}
+
+// formatExportId converts the passed Discord ID into its string representation.
+func formatExportId(id uint64) string {
+	return strconv.FormatUint(id, 10)
+}
+
+// formatOptionalExportId converts the passed Discord ID into its string representation.
+// Zero, which stands for no ID, results in an empty string.
+func formatOptionalExportId(id uint64) string {
+	if 0 == id {
+		return ""
+	}
+
+	return formatExportId(id)
+}
+
+// getExportStorageValue returns the passed stored value as raw JSON.
+// Values that are not valid JSON are exported as string.
+func getExportStorageValue(value string) json.RawMessage {
+	if json.Valid([]byte(value)) {
+		return json.RawMessage(value)
+	}
+
+	encodedValue, _ := json.Marshal(value)
+
+	return encodedValue
+}
@@ -0,0 +1,160 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"testing"
+	"time"
+)
+
+type GuildExportTestSuite struct {
+	suite.Suite
+	data       *entities.GuildData
+	exportedAt time.Time
+}
+
+func (suite *GuildExportTestSuite) SetupTest() {
+	channelId := uint64(987654321987654321)
+	component := entities.RegisteredComponent{Code: "test_component"}
+
+	suite.exportedAt = time.Date(2022, 10, 17, 12, 30, 0, 0, time.UTC)
+	suite.data = &entities.GuildData{
+		Guild: entities.Guild{
+			GuildID: 123456789123456789,
+			Name:    "Test Guild",
+			Locale:  "en-US",
+		},
+		ComponentStatuses: []entities.GuildComponentStatus{
+			{Component: component, Enabled: true},
+		},
+		CommandPermissionOverrides: []entities.CommandPermissionOverride{
+			{
+				Command:    "jojo",
+				TargetType: entities.CommandPermissionOverrideTargetRole,
+				TargetID:   111111111111111111,
+				Allow:      true,
+			},
+		},
+		AuditLogConfig: &entities.AuditLogConfig{
+			Enabled:   true,
+			ChannelId: &channelId,
+		},
+		AuditLog: []entities.AuditLog{
+			{RegisteredComponent: component, UserID: 222222222222222222, Message: "Test message"},
+		},
+		ComponentStorage: []entities.ComponentStorageEntry{
+			{Component: component, UserID: 333333333333333333, Key: "counter", Value: "42"},
+			{Component: component, Key: "broken", Value: "not json"},
+		},
+	}
+}
+
+func (suite *GuildExportTestSuite) TestNewGuildExport() {
+	export := NewGuildExport(suite.data, suite.exportedAt)
+
+	suite.Equal(GuildExportVersion, export.Version)
+	suite.Equal(suite.exportedAt, export.ExportedAt)
+	suite.Equal("123456789123456789", export.Guild.Id)
+	suite.Equal("Test Guild", export.Guild.Name)
+	suite.Equal("en-US", export.Guild.Locale)
+
+	suite.Len(export.ComponentStatuses, 1)
+	suite.Equal(entities.ComponentCode("test_component"), export.ComponentStatuses[0].Component)
+	suite.True(export.ComponentStatuses[0].Enabled)
+
+	suite.Len(export.ComponentConfigs, 0)
+	suite.Len(export.CommandStatuses, 0)
+
+	suite.Len(export.CommandPermissionOverrides, 1)
+	suite.Equal("111111111111111111", export.CommandPermissionOverrides[0].TargetId)
+	suite.Equal(entities.CommandPermissionOverrideTargetRole, export.CommandPermissionOverrides[0].TargetType)
+
+	suite.NotNil(export.AuditLogConfig)
+	suite.True(export.AuditLogConfig.Enabled)
+	suite.Equal("987654321987654321", *export.AuditLogConfig.ChannelId)
+
+	suite.Len(export.AuditLog, 1)
+	suite.Equal("222222222222222222", export.AuditLog[0].UserId)
+	suite.Equal("Test message", export.AuditLog[0].Message)
+
+	suite.Len(export.ComponentStorage, 2)
+	suite.Equal("", export.ComponentStorage[0].ChannelId)
+	suite.Equal("333333333333333333", export.ComponentStorage[0].UserId)
+	suite.Equal(json.RawMessage("42"), export.ComponentStorage[0].Value)
+	suite.Equal(json.RawMessage("\"not json\""), export.ComponentStorage[1].Value)
+}
+
+func (suite *GuildExportTestSuite) TestNewGuildExportWithoutAuditLogConfig() {
+	suite.data.AuditLogConfig = nil
+
+	export := NewGuildExport(suite.data, suite.exportedAt)
+
+	suite.Nil(export.AuditLogConfig)
+}
+
+func (suite *GuildExportTestSuite) TestToFile() {
+	export := NewGuildExport(suite.data, suite.exportedAt)
+
+	file, err := export.ToFile(false)
+
+	suite.NoError(err)
+	suite.Equal("guild-export-123456789123456789-20221017-123000.json", file.Name)
+	suite.Equal("application/json", file.ContentType)
+
+	decoded := &GuildExport{}
+	suite.NoError(json.Unmarshal(file.Data, decoded))
+	suite.Equal(export.Guild, decoded.Guild)
+	suite.Equal(export.ComponentStorage, decoded.ComponentStorage)
+}
+
+func (suite *GuildExportTestSuite) TestToFileZipped() {
+	export := NewGuildExport(suite.data, suite.exportedAt)
+
+	file, err := export.ToFile(true)
+
+	suite.NoError(err)
+	suite.Equal("guild-export-123456789123456789-20221017-123000.zip", file.Name)
+	suite.Equal("application/zip", file.ContentType)
+
+	archive, err := zip.NewReader(bytes.NewReader(file.Data), int64(len(file.Data)))
+	suite.NoError(err)
+	suite.Len(archive.File, 1)
+	suite.Equal("guild-export-123456789123456789-20221017-123000.json", archive.File[0].Name)
+
+	reader, err := archive.File[0].Open()
+	suite.NoError(err)
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	suite.NoError(err)
+
+	jsonFile, err := export.ToFile(false)
+	suite.NoError(err)
+	suite.Equal(jsonFile.Data, content)
+}
+
+func TestGuildExport(t *testing.T) {
+	suite.Run(t, new(GuildExportTestSuite))
+}
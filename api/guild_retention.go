@@ -0,0 +1,43 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import "time"
+
+// DefaultGuildDataRetention is the default duration the data of a guild
+// is kept after the bot left the guild.
+const DefaultGuildDataRetention = 30 * 24 * time.Hour
+
+// guildDataRetention is the duration the data of a guild is kept
+// after the bot left the guild. A retention of zero disables purging.
+var guildDataRetention = DefaultGuildDataRetention
+
+// SetGuildDataRetention sets the duration the data of a guild is kept
+// after the bot left the guild. When the bot rejoins the guild within
+// the retention period, the data of the guild is restored.
+// Passing a duration of zero disables purging guild data.
+func SetGuildDataRetention(retention time.Duration) {
+	guildDataRetention = retention
+}
+
+// GetGuildDataRetention returns the duration the data of a guild is kept
+// after the bot left the guild. A retention of zero means that guild data is never purged.
+func GetGuildDataRetention() time.Duration {
+	return guildDataRetention
+}
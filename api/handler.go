@@ -248,7 +248,9 @@ func (c *ComponentHandlerContainer) addDiscordGoHandler(assignedEvent *AssignedE
 		return reflect.ValueOf(assignedEvent.handler).Call(args)
 	})
 
-	assignedEvent.unregister = c.owner.discord.AddHandler(typedHandler.Interface())
+	assignedEvent.unregister = addHandlerToShards(c.owner.discord, func(session *discordgo.Session) func() {
+		return session.AddHandler(typedHandler.Interface())
+	})
 }
 
 // === One-Time Handlers
@@ -321,7 +323,28 @@ func (c *ComponentHandlerContainer) addDiscordGoOnceTimeHandler(assignedEvent *A
 		return reflect.ValueOf(assignedEvent.handler).Call(args)
 	})
 
-	assignedEvent.unregister = c.owner.discord.AddHandlerOnce(typedHandler.Interface())
+	assignedEvent.unregister = addHandlerToShards(c.owner.discord, func(session *discordgo.Session) func() {
+		return session.AddHandlerOnce(typedHandler.Interface())
+	})
+}
+
+// addHandlerToShards registers a handler on the sessions of all shards using the passed
+// register function. The returned function unregisters the handler from all shards.
+func addHandlerToShards(
+	fallback *discordgo.Session,
+	register func(session *discordgo.Session) func(),
+) func() {
+	sessions := getShardSessions(fallback)
+	unregisterFunctions := make([]func(), len(sessions))
+	for key, session := range sessions {
+		unregisterFunctions[key] = register(session)
+	}
+
+	return func() {
+		for _, unregister := range unregisterFunctions {
+			unregister()
+		}
+	}
 }
 
 // === Handler removal
@@ -405,6 +428,7 @@ func GetHandlerName(c entities.ComponentCode, name string) HandlerName {
 // wrapWithComponentStatusHandler wraps the original handler function and
 // ensures that the original handler function is only called when
 // the owning component is enabled.
+// Panics of the original handler function are recovered and logged.
 func (c *ComponentHandlerContainer) wrapWithComponentStatusHandler(name HandlerName) {
 	assignedEventHandler, ok := GetHandler(name)
 	if !ok {
@@ -424,6 +448,14 @@ func (c *ComponentHandlerContainer) wrapWithComponentStatusHandler(name HandlerN
 		comp := assignedEventHandler.GetComponent()
 		guildId := getGuildIdFromEventInterface(event)
 
+		defer func() {
+			if r := recover(); nil != r {
+				recoverFromPanic(comp, r, fmt.Sprintf("event handler \"%s\" on guild \"%s\"",
+					assignedEventHandler.name,
+					guildId))
+			}
+		}()
+
 		if IsComponentEnabled(comp, guildId) {
 			reflect.ValueOf(originalHandler).Call([]reflect.Value{
 				reflect.ValueOf(session),
@@ -445,16 +477,23 @@ func (c *ComponentHandlerContainer) wrapOneTimeHandler(name HandlerName) {
 			"Failed to apply component one-time removal handler!")
 	}
 
+	// The handler is registered once per shard, but must be executed only once.
+	// The handler is unregistered asynchronously, as DiscordGo holds a lock
+	// on its handlers while they are being executed.
+	var once sync.Once
 	originalHandler := assignedEventHandler.handler
 	assignedEventHandler.handler = func(
 		session *discordgo.Session,
 		event interface{},
 	) {
-		removeComponentHandler(assignedEventHandler.name)
+		once.Do(func() {
+			removeComponentHandler(assignedEventHandler.name)
+			go assignedEventHandler.unregister()
 
-		reflect.ValueOf(originalHandler).Call([]reflect.Value{
-			reflect.ValueOf(session),
-			reflect.ValueOf(event),
+			reflect.ValueOf(originalHandler).Call([]reflect.Value{
+				reflect.ValueOf(session),
+				reflect.ValueOf(event),
+			})
 		})
 	}
 }
@@ -0,0 +1,222 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is the locale that is used when a message
+// is not available in the requested locale.
+//
+// The messages of the DefaultLocale are also used as the default names and
+// descriptions of commands, all other locales are added as localizations.
+const DefaultLocale = discordgo.EnglishUS
+
+// catalogFileExtension is the extension of message catalog files
+// that can be loaded using RegisterCatalogFiles.
+const catalogFileExtension = ".json"
+
+// Catalog holds the messages of a single locale,
+// with the key of the message as map key.
+//
+// Messages can contain formatting verbs, which are
+// replaced with the arguments passed to Translate.
+type Catalog map[string]string
+
+// catalogMap holds the registered catalogs,
+// with the locale of the catalog as key.
+type catalogMap struct {
+	sync.RWMutex
+	catalogs map[discordgo.Locale]Catalog
+}
+
+// catalogs holds all registered catalogs.
+var catalogs = catalogMap{
+	catalogs: make(map[discordgo.Locale]Catalog),
+}
+
+// RegisterCatalog adds the messages of the passed Catalog to the catalog
+// of the passed locale. Messages that are already registered are overridden.
+//
+// Components should use unique prefixes for the keys of their messages,
+// like the code of the component.
+func RegisterCatalog(locale discordgo.Locale, catalog Catalog) {
+	catalogs.Lock()
+	defer catalogs.Unlock()
+
+	registeredCatalog, ok := catalogs.catalogs[locale]
+	if !ok {
+		registeredCatalog = make(Catalog, len(catalog))
+		catalogs.catalogs[locale] = registeredCatalog
+	}
+
+	for key, message := range catalog {
+		registeredCatalog[key] = message
+	}
+}
+
+// RegisterCatalogFiles loads all message catalogs in the passed directory
+// of the passed fs.FS and registers them using RegisterCatalog.
+//
+// The catalogs must be JSON files named after their locale (e.g. "de.json" or "en-US.json"),
+// that contain a single object with the message keys as keys and the messages as values.
+// The function is intended to be used with embed.FS.
+func RegisterCatalogFiles(fileSystem fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fileSystem, dir)
+	if nil != err {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), catalogFileExtension) {
+			continue
+		}
+
+		locale := discordgo.Locale(strings.TrimSuffix(entry.Name(), catalogFileExtension))
+		if _, ok := discordgo.Locales[locale]; !ok {
+			return fmt.Errorf("the message catalog \"%s\" is not named after a locale supported by Discord",
+				entry.Name())
+		}
+
+		data, err := fs.ReadFile(fileSystem, path.Join(dir, entry.Name()))
+		if nil != err {
+			return err
+		}
+
+		catalog := Catalog{}
+		err = json.Unmarshal(data, &catalog)
+		if nil != err {
+			return fmt.Errorf("failed to parse the message catalog \"%s\": %v", entry.Name(), err)
+		}
+
+		RegisterCatalog(locale, catalog)
+	}
+
+	return nil
+}
+
+// Translate returns the message with the passed key in the passed locale.
+// When the message is not available in the passed locale, the message of
+// the DefaultLocale is used. When the message is not available at all,
+// the key itself is returned.
+//
+// When arguments are passed, they replace the formatting verbs of the message.
+func Translate(locale discordgo.Locale, key string, args ...interface{}) string {
+	message, ok := lookup(locale, key)
+	if !ok {
+		message, ok = lookup(DefaultLocale, key)
+	}
+
+	if !ok {
+		message = key
+	}
+
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+
+	return message
+}
+
+// HasTranslation checks whether the message with the passed key
+// is available in the passed locale.
+func HasTranslation(locale discordgo.Locale, key string) bool {
+	_, ok := lookup(locale, key)
+
+	return ok
+}
+
+// Localizations returns the message with the passed key in all locales
+// except the DefaultLocale, with the locale as key.
+// The result can be used to fill the localizations of commands.
+//
+// When the message is not available in any other locale, nil is returned.
+func Localizations(key string) map[discordgo.Locale]string {
+	catalogs.RLock()
+	defer catalogs.RUnlock()
+
+	var localizations map[discordgo.Locale]string
+	for locale, catalog := range catalogs.catalogs {
+		if DefaultLocale == locale {
+			continue
+		}
+
+		message, ok := catalog[key]
+		if !ok {
+			continue
+		}
+
+		if nil == localizations {
+			localizations = make(map[discordgo.Locale]string)
+		}
+		localizations[locale] = message
+	}
+
+	return localizations
+}
+
+// Locales returns all locales that have a registered catalog, sorted by their code.
+func Locales() []discordgo.Locale {
+	catalogs.RLock()
+	defer catalogs.RUnlock()
+
+	locales := make([]discordgo.Locale, 0, len(catalogs.catalogs))
+	for locale := range catalogs.catalogs {
+		locales = append(locales, locale)
+	}
+
+	sort.Slice(locales, func(a, b int) bool {
+		return locales[a] < locales[b]
+	})
+
+	return locales
+}
+
+// IsSupportedLocale checks whether a catalog has been registered for the passed locale.
+func IsSupportedLocale(locale discordgo.Locale) bool {
+	catalogs.RLock()
+	defer catalogs.RUnlock()
+
+	_, ok := catalogs.catalogs[locale]
+
+	return ok
+}
+
+// lookup returns the message with the passed key from the catalog of the passed locale.
+func lookup(locale discordgo.Locale, key string) (string, bool) {
+	catalogs.RLock()
+	defer catalogs.RUnlock()
+
+	catalog, ok := catalogs.catalogs[locale]
+	if !ok {
+		return "", false
+	}
+
+	message, ok := catalog[key]
+
+	return message, ok
+}
@@ -0,0 +1,126 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package i18n
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"testing/fstest"
+)
+
+type I18nTestSuite struct {
+	suite.Suite
+}
+
+func (suite *I18nTestSuite) SetupTest() {
+	catalogs.catalogs = make(map[discordgo.Locale]Catalog)
+
+	RegisterCatalog(DefaultLocale, Catalog{
+		"greeting":     "Hello!",
+		"greeting.who": "Hello %s!",
+		"only.english": "Only in english",
+	})
+	RegisterCatalog(discordgo.German, Catalog{
+		"greeting":     "Hallo!",
+		"greeting.who": "Hallo %s!",
+	})
+}
+
+func (suite *I18nTestSuite) TestTranslate() {
+	tables := []struct {
+		locale   discordgo.Locale
+		key      string
+		args     []interface{}
+		expected string
+	}{
+		{DefaultLocale, "greeting", nil, "Hello!"},
+		{discordgo.German, "greeting", nil, "Hallo!"},
+		{discordgo.German, "greeting.who", []interface{}{"Jojo"}, "Hallo Jojo!"},
+		{discordgo.German, "only.english", nil, "Only in english"},
+		{discordgo.French, "greeting", nil, "Hello!"},
+		{discordgo.German, "unknown.key", nil, "unknown.key"},
+	}
+
+	for _, table := range tables {
+		suite.Equal(table.expected, Translate(table.locale, table.key, table.args...))
+	}
+}
+
+func (suite *I18nTestSuite) TestRegisterCatalogMergesMessages() {
+	RegisterCatalog(discordgo.German, Catalog{
+		"greeting": "Moin!",
+		"farewell": "Tschüss!",
+	})
+
+	suite.Equal("Moin!", Translate(discordgo.German, "greeting"))
+	suite.Equal("Tschüss!", Translate(discordgo.German, "farewell"))
+	suite.Equal("Hallo %s!", Translate(discordgo.German, "greeting.who"))
+}
+
+func (suite *I18nTestSuite) TestHasTranslation() {
+	suite.True(HasTranslation(discordgo.German, "greeting"))
+	suite.False(HasTranslation(discordgo.German, "only.english"))
+	suite.False(HasTranslation(discordgo.French, "greeting"))
+}
+
+func (suite *I18nTestSuite) TestLocalizations() {
+	suite.Equal(map[discordgo.Locale]string{discordgo.German: "Hallo!"}, Localizations("greeting"))
+	suite.Nil(Localizations("only.english"))
+	suite.Nil(Localizations("unknown.key"))
+}
+
+func (suite *I18nTestSuite) TestLocales() {
+	suite.Equal([]discordgo.Locale{discordgo.German, DefaultLocale}, Locales())
+	suite.True(IsSupportedLocale(discordgo.German))
+	suite.False(IsSupportedLocale(discordgo.French))
+}
+
+func (suite *I18nTestSuite) TestRegisterCatalogFiles() {
+	fileSystem := fstest.MapFS{
+		"locales/fr.json":    {Data: []byte(`{"greeting": "Bonjour!"}`)},
+		"locales/README.md":  {Data: []byte("Not a catalog")},
+		"locales/de.json":    {Data: []byte(`{"farewell": "Tschüss!"}`)},
+		"locales/nested/a.b": {Data: []byte("")},
+	}
+
+	err := RegisterCatalogFiles(fileSystem, "locales")
+	suite.NoError(err)
+
+	suite.Equal("Bonjour!", Translate(discordgo.French, "greeting"))
+	suite.Equal("Tschüss!", Translate(discordgo.German, "farewell"))
+	suite.Equal("Hallo!", Translate(discordgo.German, "greeting"))
+}
+
+func (suite *I18nTestSuite) TestRegisterCatalogFilesWithInvalidFiles() {
+	tables := []fstest.MapFS{
+		{"locales/unknown.json": {Data: []byte(`{}`)}},
+		{"locales/de.json": {Data: []byte(`["not", "an", "object"]`)}},
+	}
+
+	for _, fileSystem := range tables {
+		suite.Error(RegisterCatalogFiles(fileSystem, "locales"))
+	}
+
+	suite.Error(RegisterCatalogFiles(fstest.MapFS{}, "missing"))
+}
+
+func TestI18n(t *testing.T) {
+	suite.Run(t, new(I18nTestSuite))
+}
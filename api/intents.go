@@ -0,0 +1,108 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"sort"
+	"strings"
+)
+
+// DefaultIntents are the gateway intents that are always requested,
+// as the state of the bot (e.g. the known guilds) depends on them.
+const DefaultIntents = discordgo.IntentsGuilds
+
+// PrivilegedIntents are the gateway intents that must be enabled
+// in the Discord developer portal before they can be used.
+const PrivilegedIntents = discordgo.IntentsGuildMembers |
+	discordgo.IntentsGuildPresences |
+	discordgo.IntentsMessageContent
+
+// privilegedIntentNames maps the names used in the configuration
+// to the privileged intents.
+var privilegedIntentNames = map[string]discordgo.Intent{
+	"guild_members":   discordgo.IntentsGuildMembers,
+	"guild_presences": discordgo.IntentsGuildPresences,
+	"message_content": discordgo.IntentsMessageContent,
+}
+
+// availableIntents are the intents the bot has been started with.
+// Components that require other intents cannot be loaded.
+var availableIntents = discordgo.IntentsAll
+
+// SetAvailableIntents sets the intents the bot has been started with.
+// Components that require intents that are not available cannot be loaded.
+func SetAvailableIntents(intents discordgo.Intent) {
+	availableIntents = intents
+}
+
+// ParsePrivilegedIntents parses a comma separated list of privileged intent names
+// (guild_members, guild_presences and message_content) into an intent.
+func ParsePrivilegedIntents(names string) (discordgo.Intent, error) {
+	var intents discordgo.Intent
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if "" == name {
+			continue
+		}
+
+		intent, ok := privilegedIntentNames[name]
+		if !ok {
+			return 0, fmt.Errorf("the privileged intent \"%s\" does not exist", name)
+		}
+
+		intents |= intent
+	}
+
+	return intents, nil
+}
+
+// GetPrivilegedIntentNames returns the names of the privileged intents
+// contained in the passed intents as comma separated list.
+func GetPrivilegedIntentNames(intents discordgo.Intent) string {
+	names := make([]string, 0, len(privilegedIntentNames))
+	for name, intent := range privilegedIntentNames {
+		if intent == intents&intent {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return strings.Join(names, ", ")
+}
+
+// ComputeIntents computes the intents the bot should be started with.
+// These are the DefaultIntents together with the intents required by the passed components.
+// Privileged intents are only included, when they are part of the passed enabled privileged intents.
+func ComputeIntents(components []*Component, enabledPrivilegedIntents discordgo.Intent) discordgo.Intent {
+	intents := DefaultIntents
+	for _, comp := range components {
+		intents |= comp.Intents
+	}
+
+	return intents &^ (PrivilegedIntents &^ enabledPrivilegedIntents)
+}
+
+// GetMissingIntents returns the intents required by the component,
+// that are not available.
+func (c *Component) GetMissingIntents() discordgo.Intent {
+	return c.Intents &^ availableIntents
+}
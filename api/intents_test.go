@@ -0,0 +1,83 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type IntentsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *IntentsTestSuite) TearDownTest() {
+	SetAvailableIntents(discordgo.IntentsAll)
+}
+
+func (suite *IntentsTestSuite) TestParsePrivilegedIntents() {
+	intents, err := ParsePrivilegedIntents("")
+	suite.NoError(err)
+	suite.Equal(discordgo.Intent(0), intents)
+
+	intents, err = ParsePrivilegedIntents("guild_members, message_content")
+	suite.NoError(err)
+	suite.Equal(discordgo.IntentsGuildMembers|discordgo.IntentsMessageContent, intents)
+
+	_, err = ParsePrivilegedIntents("guild_members,guild_messages")
+	suite.Error(err)
+}
+
+func (suite *IntentsTestSuite) TestGetPrivilegedIntentNames() {
+	suite.Equal("", GetPrivilegedIntentNames(discordgo.IntentsGuilds))
+	suite.Equal("guild_presences, message_content",
+		GetPrivilegedIntentNames(discordgo.IntentsMessageContent|discordgo.IntentsGuildPresences|discordgo.IntentsGuilds))
+}
+
+func (suite *IntentsTestSuite) TestComputeIntents() {
+	components := []*Component{
+		{Code: "first", Intents: discordgo.IntentsGuildMessages | discordgo.IntentsMessageContent},
+		{Code: "second", Intents: discordgo.IntentsGuildMembers},
+		{Code: "third"},
+	}
+
+	suite.Equal(
+		DefaultIntents|discordgo.IntentsGuildMessages,
+		ComputeIntents(components, 0))
+	suite.Equal(
+		DefaultIntents|discordgo.IntentsGuildMessages|discordgo.IntentsMessageContent,
+		ComputeIntents(components, discordgo.IntentsMessageContent))
+	suite.Equal(
+		DefaultIntents|discordgo.IntentsGuildMessages|discordgo.IntentsMessageContent|discordgo.IntentsGuildMembers,
+		ComputeIntents(components, PrivilegedIntents))
+}
+
+func (suite *IntentsTestSuite) TestGetMissingIntents() {
+	comp := &Component{Intents: discordgo.IntentsGuildMessages | discordgo.IntentsMessageContent}
+
+	suite.Equal(discordgo.Intent(0), comp.GetMissingIntents())
+
+	SetAvailableIntents(DefaultIntents | discordgo.IntentsGuildMessages)
+	suite.Equal(discordgo.IntentsMessageContent, comp.GetMissingIntents())
+}
+
+func TestIntents(t *testing.T) {
+	suite.Run(t, new(IntentsTestSuite))
+}
@@ -0,0 +1,294 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"sync"
+	"time"
+)
+
+// Discord requires an initial response to an interaction within three seconds.
+// Commands are wrapped with the CommandAutoDeferMiddleware, that sends a deferred
+// response when the handler of a command did not respond in time.
+// Responses should therefore be sent using RespondToInteraction (or the response writer
+// of the slash_commands package), which transparently edits the deferred response
+// or sends a follow-up message when necessary.
+
+// DefaultAutoDeferThreshold is the default duration after which a deferred
+// response is sent for commands that did not respond yet.
+const DefaultAutoDeferThreshold = 2 * time.Second
+
+// InteractionResponseKind describes how a response to an interaction
+// has to be delivered.
+type InteractionResponseKind int
+
+const (
+	// InteractionResponseInitial means the response is the initial response
+	// to the interaction.
+	InteractionResponseInitial InteractionResponseKind = iota
+	// InteractionResponseEdit means the interaction has been deferred and
+	// the response has to replace the deferred response.
+	InteractionResponseEdit
+	// InteractionResponseFollowup means the interaction has already been
+	// responded to and the response has to be sent as follow-up message.
+	InteractionResponseFollowup
+)
+
+// interactionResponseState holds whether a tracked interaction
+// has been deferred, whether the deferred response is ephemeral
+// and whether it has been responded to.
+type interactionResponseState struct {
+	mu                sync.Mutex
+	deferred          bool
+	deferredEphemeral bool
+	responded         bool
+}
+
+// interactionResponseStateMap holds the states of all interactions
+// that are currently processed, with the interaction id as key.
+type interactionResponseStateMap struct {
+	sync.RWMutex
+	states map[string]*interactionResponseState
+}
+
+// interactionResponseStates holds the states of all tracked interactions.
+var interactionResponseStates = interactionResponseStateMap{
+	states: make(map[string]*interactionResponseState),
+}
+
+// autoDeferThreshold is the duration after which a deferred response is sent.
+// A threshold of zero disables automatic deferred responses.
+var autoDeferThreshold = DefaultAutoDeferThreshold
+
+// SetAutoDeferThreshold sets the duration after which a deferred response
+// is sent for commands that did not respond yet.
+// Passing a duration of zero disables automatic deferred responses.
+func SetAutoDeferThreshold(threshold time.Duration) {
+	autoDeferThreshold = threshold
+}
+
+// CommandAutoDeferMiddleware sends a deferred response, when the handler
+// of a command did not respond within the configured threshold.
+// The deferred response is ephemeral, when DeferEphemeral is set on the Command.
+//
+// After the interaction has been deferred, responses sent using RespondToInteraction
+// edit the deferred response.
+func CommandAutoDeferMiddleware(command *Command, next CommandHandlerFunc) CommandHandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		if autoDeferThreshold <= 0 {
+			next(s, i)
+
+			return
+		}
+
+		state := trackInteractionResponse(i)
+		defer untrackInteractionResponse(i)
+
+		timer := time.AfterFunc(autoDeferThreshold, func() {
+			deferInteractionResponse(s, i, command, state)
+		})
+		defer timer.Stop()
+
+		next(s, i)
+	}
+}
+
+// deferInteractionResponse sends a deferred response for the interaction,
+// if it has not been responded to yet.
+//
+// The state is locked while the deferred response is sent, so that responses
+// sent at the same time wait until it is known how they have to be delivered.
+func deferInteractionResponse(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	command *Command,
+	state *interactionResponseState,
+) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if state.responded {
+		return
+	}
+
+	var flags discordgo.MessageFlags
+	if command.DeferEphemeral {
+		flags = discordgo.MessageFlagsEphemeral
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: flags,
+		},
+	})
+	if nil != err {
+		command.c.Logger().Err(err, "Failed to send deferred response for command \"%s\"!", command.Cmd.Name)
+
+		return
+	}
+
+	state.deferred = true
+	state.deferredEphemeral = command.DeferEphemeral
+}
+
+// trackInteractionResponse starts tracking the responses to the passed interaction.
+func trackInteractionResponse(i *discordgo.InteractionCreate) *interactionResponseState {
+	interactionResponseStates.Lock()
+	defer interactionResponseStates.Unlock()
+
+	state := &interactionResponseState{}
+	interactionResponseStates.states[i.ID] = state
+
+	return state
+}
+
+// untrackInteractionResponse stops tracking the responses to the passed interaction.
+func untrackInteractionResponse(i *discordgo.InteractionCreate) {
+	interactionResponseStates.Lock()
+	defer interactionResponseStates.Unlock()
+
+	delete(interactionResponseStates.states, i.ID)
+}
+
+// ClaimInteractionResponse marks the passed interaction as responded to and returns
+// how the response has to be delivered.
+//
+// Interactions that are not tracked, because they are not processed
+// by a command handler, always get an InteractionResponseInitial.
+// Therefore, the function must only be called right before a response is sent.
+func ClaimInteractionResponse(i *discordgo.InteractionCreate) InteractionResponseKind {
+	kind, _ := claimInteractionResponse(i)
+
+	return kind
+}
+
+// claimInteractionResponse works like ClaimInteractionResponse, but additionally
+// returns whether the deferred response of the interaction is ephemeral.
+func claimInteractionResponse(i *discordgo.InteractionCreate) (InteractionResponseKind, bool) {
+	interactionResponseStates.RLock()
+	state, ok := interactionResponseStates.states[i.ID]
+	interactionResponseStates.RUnlock()
+
+	if !ok {
+		return InteractionResponseInitial, false
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if state.responded {
+		return InteractionResponseFollowup, state.deferredEphemeral
+	}
+	state.responded = true
+
+	if state.deferred {
+		return InteractionResponseEdit, state.deferredEphemeral
+	}
+
+	return InteractionResponseInitial, false
+}
+
+// RespondToInteraction sends the passed discordgo.InteractionResponseData as
+// response to the interaction.
+//
+// Depending on the state of the interaction, the response is sent as initial response,
+// replaces the deferred response or is sent as follow-up message.
+//
+// As editing a response cannot make it ephemeral, ephemeral responses to interactions
+// with a public deferred response are sent as ephemeral follow-up message
+// and the deferred response is deleted.
+func RespondToInteraction(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	resp *discordgo.InteractionResponseData,
+) (*discordgo.Message, error) {
+	kind, deferredEphemeral := claimInteractionResponse(i)
+	switch kind {
+	case InteractionResponseEdit:
+		if isEphemeralResponse(resp) && !deferredEphemeral {
+			return replaceDeferredResponse(s, i, resp)
+		}
+
+		return s.InteractionResponseEdit(i.Interaction, newWebhookEditFromResponseData(resp))
+	case InteractionResponseFollowup:
+		return s.FollowupMessageCreate(i.Interaction, true, newWebhookParamsFromResponseData(resp))
+	default:
+		return nil, s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: resp,
+		})
+	}
+}
+
+// replaceDeferredResponse sends the passed discordgo.InteractionResponseData as
+// follow-up message and deletes the deferred response of the interaction.
+func replaceDeferredResponse(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	resp *discordgo.InteractionResponseData,
+) (*discordgo.Message, error) {
+	msg, err := s.FollowupMessageCreate(i.Interaction, true, newWebhookParamsFromResponseData(resp))
+	if nil != err {
+		return nil, err
+	}
+
+	return msg, s.InteractionResponseDelete(i.Interaction)
+}
+
+// isEphemeralResponse checks whether the passed discordgo.InteractionResponseData
+// is only visible to the user that triggered the interaction.
+func isEphemeralResponse(resp *discordgo.InteractionResponseData) bool {
+	return resp.Flags&discordgo.MessageFlagsEphemeral == discordgo.MessageFlagsEphemeral
+}
+
+// newWebhookParamsFromResponseData creates discordgo.WebhookParams that send
+// the passed discordgo.InteractionResponseData as follow-up message.
+func newWebhookParamsFromResponseData(resp *discordgo.InteractionResponseData) *discordgo.WebhookParams {
+	return &discordgo.WebhookParams{
+		Content:         resp.Content,
+		Components:      resp.Components,
+		Embeds:          resp.Embeds,
+		Files:           resp.Files,
+		AllowedMentions: resp.AllowedMentions,
+		Flags:           resp.Flags,
+		TTS:             resp.TTS,
+	}
+}
+
+// newWebhookEditFromResponseData creates a discordgo.WebhookEdit that replaces
+// a deferred response with the passed discordgo.InteractionResponseData.
+func newWebhookEditFromResponseData(resp *discordgo.InteractionResponseData) *discordgo.WebhookEdit {
+	edit := &discordgo.WebhookEdit{
+		Content:         &resp.Content,
+		Files:           resp.Files,
+		AllowedMentions: resp.AllowedMentions,
+	}
+
+	if len(resp.Embeds) > 0 {
+		edit.Embeds = &resp.Embeds
+	}
+
+	if len(resp.Components) > 0 {
+		edit.Components = &resp.Components
+	}
+
+	return edit
+}
@@ -0,0 +1,216 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/test/discordgo_mock"
+	"github.com/lazybytez/jojo-discord-bot/test/logmock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type InteractionResponseTestSuite struct {
+	suite.Suite
+	owningComponent *Component
+}
+
+func (suite *InteractionResponseTestSuite) SetupTest() {
+	loggerMock := &logmock.LoggerMock{}
+	loggerMock.On("Err", mock.Anything, mock.Anything, mock.Anything)
+
+	suite.owningComponent = &Component{
+		Code: "bot_test_component",
+		Name: "Test Component",
+	}
+	suite.owningComponent.SetLogger(loggerMock)
+}
+
+func (suite *InteractionResponseTestSuite) TearDownTest() {
+	SetAutoDeferThreshold(DefaultAutoDeferThreshold)
+}
+
+func (suite *InteractionResponseTestSuite) createInteraction() *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:      "1234",
+			AppID:   "5678",
+			Type:    discordgo.InteractionApplicationCommand,
+			GuildID: "1",
+		},
+	}
+}
+
+func (suite *InteractionResponseTestSuite) TestClaimInteractionResponseUntracked() {
+	i := suite.createInteraction()
+
+	suite.Equal(InteractionResponseInitial, ClaimInteractionResponse(i))
+	suite.Equal(InteractionResponseInitial, ClaimInteractionResponse(i))
+}
+
+func (suite *InteractionResponseTestSuite) TestClaimInteractionResponse() {
+	i := suite.createInteraction()
+	trackInteractionResponse(i)
+	defer untrackInteractionResponse(i)
+
+	suite.Equal(InteractionResponseInitial, ClaimInteractionResponse(i))
+	suite.Equal(InteractionResponseFollowup, ClaimInteractionResponse(i))
+}
+
+func (suite *InteractionResponseTestSuite) TestClaimInteractionResponseDeferred() {
+	i := suite.createInteraction()
+	state := trackInteractionResponse(i)
+	defer untrackInteractionResponse(i)
+
+	state.deferred = true
+
+	suite.Equal(InteractionResponseEdit, ClaimInteractionResponse(i))
+	suite.Equal(InteractionResponseFollowup, ClaimInteractionResponse(i))
+}
+
+func (suite *InteractionResponseTestSuite) TestCommandAutoDeferMiddleware() {
+	SetAutoDeferThreshold(10 * time.Millisecond)
+
+	session, transport := discordgo_mock.MockSession()
+	deferredResponse := &discordgo.InteractionResponse{}
+	transport.OnRequestCaptureResult(http.MethodPost, deferredResponse).Once().Return(
+		&http.Response{
+			StatusCode: http.StatusNoContent,
+		}, nil)
+
+	editedResponse := &json.RawMessage{}
+	_, _ = transport.RespondWith(
+		transport.OnRequestCaptureResult(http.MethodPatch, editedResponse).Once(),
+		discordgo.Message{ID: "42"})
+
+	command := &Command{
+		Cmd:            &discordgo.ApplicationCommand{Name: "test"},
+		DeferEphemeral: true,
+		c:              suite.owningComponent,
+	}
+	handler := CommandAutoDeferMiddleware(command, func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		time.Sleep(50 * time.Millisecond)
+
+		_, err := RespondToInteraction(s, i, &discordgo.InteractionResponseData{
+			Content: "Done!",
+		})
+		suite.NoError(err)
+	})
+
+	handler(session, suite.createInteraction())
+
+	transport.AssertExpectations(suite.T())
+	suite.Equal(discordgo.InteractionResponseDeferredChannelMessageWithSource, deferredResponse.Type)
+	suite.Equal(discordgo.MessageFlagsEphemeral, deferredResponse.Data.Flags)
+	suite.Contains(string(*editedResponse), "Done!")
+	suite.NotContains(interactionResponseStates.states, "1234")
+}
+
+func (suite *InteractionResponseTestSuite) TestCommandAutoDeferMiddlewareRespondsInTime() {
+	SetAutoDeferThreshold(50 * time.Millisecond)
+
+	session, transport := discordgo_mock.MockSession()
+	response := &discordgo.InteractionResponse{}
+	transport.OnRequestCaptureResult(http.MethodPost, response).Once().Return(
+		&http.Response{
+			StatusCode: http.StatusNoContent,
+		}, nil)
+
+	command := &Command{
+		Cmd: &discordgo.ApplicationCommand{Name: "test"},
+		c:   suite.owningComponent,
+	}
+	handler := CommandAutoDeferMiddleware(command, func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		_, err := RespondToInteraction(s, i, &discordgo.InteractionResponseData{
+			Content: "Done!",
+		})
+		suite.NoError(err)
+
+		// Give the timer the chance to fire, if it has not been stopped
+		time.Sleep(100 * time.Millisecond)
+	})
+
+	handler(session, suite.createInteraction())
+
+	transport.AssertExpectations(suite.T())
+	suite.Equal(discordgo.InteractionResponseChannelMessageWithSource, response.Type)
+	suite.Equal("Done!", response.Data.Content)
+}
+
+func (suite *InteractionResponseTestSuite) TestRespondToInteractionReplacesPublicDeferredResponse() {
+	session, transport := discordgo_mock.MockSession()
+	followup := &discordgo.WebhookParams{}
+	_, _ = transport.RespondWith(
+		transport.OnRequestCaptureResult(http.MethodPost, followup).Once(),
+		discordgo.Message{ID: "42"})
+	transport.On("RoundTrip", mock.MatchedBy(func(req *http.Request) bool {
+		return http.MethodDelete == req.Method
+	})).Once().Return(&http.Response{
+		StatusCode: http.StatusNoContent,
+	}, nil)
+
+	i := suite.createInteraction()
+	state := trackInteractionResponse(i)
+	defer untrackInteractionResponse(i)
+
+	state.deferred = true
+
+	msg, err := RespondToInteraction(session, i, &discordgo.InteractionResponseData{
+		Flags:   discordgo.MessageFlagsEphemeral,
+		Content: "Stop!",
+	})
+
+	suite.NoError(err)
+	suite.Equal("42", msg.ID)
+	transport.AssertExpectations(suite.T())
+	suite.Equal(discordgo.MessageFlagsEphemeral, followup.Flags)
+	suite.Equal("Stop!", followup.Content)
+}
+
+func (suite *InteractionResponseTestSuite) TestRespondToInteractionEditsEphemeralDeferredResponse() {
+	session, transport := discordgo_mock.MockSession()
+	editedResponse := &json.RawMessage{}
+	_, _ = transport.RespondWith(
+		transport.OnRequestCaptureResult(http.MethodPatch, editedResponse).Once(),
+		discordgo.Message{ID: "42"})
+
+	i := suite.createInteraction()
+	state := trackInteractionResponse(i)
+	defer untrackInteractionResponse(i)
+
+	state.deferred = true
+	state.deferredEphemeral = true
+
+	_, err := RespondToInteraction(session, i, &discordgo.InteractionResponseData{
+		Flags:   discordgo.MessageFlagsEphemeral,
+		Content: "Stop!",
+	})
+
+	suite.NoError(err)
+	transport.AssertExpectations(suite.T())
+	suite.Contains(string(*editedResponse), "Stop!")
+}
+
+func TestInteractionResponse(t *testing.T) {
+	suite.Run(t, new(InteractionResponseTestSuite))
+}
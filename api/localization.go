@@ -0,0 +1,144 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"embed"
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api/i18n"
+)
+
+// Localizations of commands are taken from the message catalogs of the i18n package.
+// The keys of the messages follow the path of the command and its options:
+//
+//	command.<command>.name
+//	command.<command>.description
+//	command.<command>.<option>.name
+//	command.<command>.<option>.description
+//	command.<command>.<option>.<sub-option>.description
+//
+// The name and description set on the command itself are used for the i18n.DefaultLocale.
+
+// commandLocalizationKeyPrefix is the prefix of all message keys used to localize commands.
+const commandLocalizationKeyPrefix = "command"
+
+// The keys of the messages provided by the API.
+const (
+	GenericErrorResponseEmbedNameKey  = "api.generic_error.name"
+	GenericErrorResponseEmbedValueKey = "api.generic_error.value"
+)
+
+// catalogFiles holds the message catalogs of the API.
+// The messages of the i18n.DefaultLocale are registered from the constants
+// of the API, therefore only the translations are stored as files.
+//
+//go:embed locales/*.json
+var catalogFiles embed.FS
+
+// init registers the message catalogs of the API.
+func init() {
+	i18n.RegisterCatalog(i18n.DefaultLocale, i18n.Catalog{
+		GenericErrorResponseEmbedNameKey:  GenericErrorResponseEmbedName,
+		GenericErrorResponseEmbedValueKey: GenericErrorResponseEmbedValue,
+	})
+
+	err := i18n.RegisterCatalogFiles(catalogFiles, "locales")
+	if nil != err {
+		panic(err)
+	}
+}
+
+// GetInteractionLocale returns the locale that should be used to respond to the passed interaction.
+//
+// The locale configured for the guild has precedence over the locale of the user.
+// When neither of them has a message catalog, the i18n.DefaultLocale is used.
+func GetInteractionLocale(i *discordgo.InteractionCreate) discordgo.Locale {
+	if "" != i.GuildID {
+		guild, err := GetEntityManager().Guilds().Get(i.GuildID)
+		if nil == err && i18n.IsSupportedLocale(discordgo.Locale(guild.Locale)) {
+			return discordgo.Locale(guild.Locale)
+		}
+	}
+
+	if i18n.IsSupportedLocale(i.Locale) {
+		return i.Locale
+	}
+
+	return i18n.DefaultLocale
+}
+
+// Translate returns the message with the passed key in the locale
+// that should be used to respond to the passed interaction.
+// See GetInteractionLocale and i18n.Translate for details.
+func Translate(i *discordgo.InteractionCreate, key string, args ...interface{}) string {
+	return i18n.Translate(GetInteractionLocale(i), key, args...)
+}
+
+// localizeCommand returns a copy of the passed discordgo.ApplicationCommand
+// with the localizations of the command and its options being filled
+// from the message catalogs.
+//
+// A copy is returned, as commands might be synced for multiple guilds at the same time.
+func localizeCommand(cmd *discordgo.ApplicationCommand) *discordgo.ApplicationCommand {
+	keyPrefix := fmt.Sprintf("%s.%s", commandLocalizationKeyPrefix, cmd.Name)
+
+	localizedCmd := *cmd
+	localizedCmd.NameLocalizations = getLocalizationsPointer(keyPrefix + ".name")
+	localizedCmd.DescriptionLocalizations = getLocalizationsPointer(keyPrefix + ".description")
+	localizedCmd.Options = localizeCommandOptions(keyPrefix, cmd.Options)
+
+	return &localizedCmd
+}
+
+// localizeCommandOptions returns copies of the passed options with their
+// localizations being filled from the message catalogs.
+func localizeCommandOptions(
+	keyPrefix string,
+	options []*discordgo.ApplicationCommandOption,
+) []*discordgo.ApplicationCommandOption {
+	if nil == options {
+		return nil
+	}
+
+	localizedOptions := make([]*discordgo.ApplicationCommandOption, len(options))
+	for key, option := range options {
+		optionKeyPrefix := fmt.Sprintf("%s.%s", keyPrefix, option.Name)
+
+		localizedOption := *option
+		localizedOption.NameLocalizations = i18n.Localizations(optionKeyPrefix + ".name")
+		localizedOption.DescriptionLocalizations = i18n.Localizations(optionKeyPrefix + ".description")
+		localizedOption.Options = localizeCommandOptions(optionKeyPrefix, option.Options)
+
+		localizedOptions[key] = &localizedOption
+	}
+
+	return localizedOptions
+}
+
+// getLocalizationsPointer returns a pointer to the localizations of the message with
+// the passed key, or nil if there are no localizations.
+func getLocalizationsPointer(key string) *map[discordgo.Locale]string {
+	localizations := i18n.Localizations(key)
+	if nil == localizations {
+		return nil
+	}
+
+	return &localizations
+}
@@ -0,0 +1,148 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"github.com/lazybytez/jojo-discord-bot/api/i18n"
+	"github.com/lazybytez/jojo-discord-bot/services/cache"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+type LocalizationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *LocalizationTestSuite) SetupTest() {
+	err := cache.Init(cache.ModeMemory, 10*time.Minute, "")
+	suite.NoError(err)
+
+	suite.NoError(cache.Update("1", entities.Guild{Model: gorm.Model{ID: 1}, Locale: string(discordgo.German)}))
+	suite.NoError(cache.Update("2", entities.Guild{Model: gorm.Model{ID: 2}}))
+	suite.NoError(cache.Update("3", entities.Guild{Model: gorm.Model{ID: 3}, Locale: string(discordgo.Finnish)}))
+
+	i18n.RegisterCatalog(i18n.DefaultLocale, i18n.Catalog{
+		"localization_test.greeting": "Hello %s!",
+	})
+	i18n.RegisterCatalog(discordgo.German, i18n.Catalog{
+		"localization_test.greeting":                              "Hallo %s!",
+		"command.localization_test.description":                   "Ein Test-Befehl",
+		"command.localization_test.group.description":             "Eine Gruppe",
+		"command.localization_test.group.sub.name":                "unter",
+		"command.localization_test.group.sub.option.description":  "Eine Option",
+		"command.localization_test.unrelated.option.description":  "Nicht verwendet",
+		"command.another_localization_test.group.sub.description": "Nicht verwendet",
+	})
+}
+
+func (suite *LocalizationTestSuite) TestGetInteractionLocale() {
+	tables := []struct {
+		guildId  string
+		locale   discordgo.Locale
+		expected discordgo.Locale
+	}{
+		{"1", discordgo.EnglishUS, discordgo.German},
+		{"2", discordgo.German, discordgo.German},
+		{"2", discordgo.Finnish, i18n.DefaultLocale},
+		{"3", discordgo.German, discordgo.German},
+		{"", discordgo.German, discordgo.German},
+		{"", "", i18n.DefaultLocale},
+	}
+
+	for _, table := range tables {
+		i := &discordgo.InteractionCreate{
+			Interaction: &discordgo.Interaction{
+				GuildID: table.guildId,
+				Locale:  table.locale,
+			},
+		}
+
+		suite.Equal(table.expected, GetInteractionLocale(i))
+	}
+}
+
+func (suite *LocalizationTestSuite) TestTranslate() {
+	i := &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			GuildID: "1",
+		},
+	}
+
+	suite.Equal("Hallo Jojo!", Translate(i, "localization_test.greeting", "Jojo"))
+}
+
+func (suite *LocalizationTestSuite) TestLocalizeCommand() {
+	cmd := &discordgo.ApplicationCommand{
+		Name:        "localization_test",
+		Description: "A test command",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        "group",
+				Description: "A group",
+				Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Name:        "sub",
+						Description: "A sub-command",
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Name:        "option",
+								Description: "An option",
+								Type:        discordgo.ApplicationCommandOptionString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	localizedCmd := localizeCommand(cmd)
+
+	suite.Nil(localizedCmd.NameLocalizations)
+	suite.Equal(&map[discordgo.Locale]string{discordgo.German: "Ein Test-Befehl"},
+		localizedCmd.DescriptionLocalizations)
+
+	group := localizedCmd.Options[0]
+	suite.Nil(group.NameLocalizations)
+	suite.Equal(map[discordgo.Locale]string{discordgo.German: "Eine Gruppe"}, group.DescriptionLocalizations)
+
+	sub := group.Options[0]
+	suite.Equal(map[discordgo.Locale]string{discordgo.German: "unter"}, sub.NameLocalizations)
+	suite.Nil(sub.DescriptionLocalizations)
+
+	option := sub.Options[0]
+	suite.Equal(map[discordgo.Locale]string{discordgo.German: "Eine Option"}, option.DescriptionLocalizations)
+	suite.Equal("An option", option.Description)
+
+	// The registered command must not be modified
+	suite.Nil(cmd.DescriptionLocalizations)
+	suite.Nil(cmd.Options[0].DescriptionLocalizations)
+	suite.Nil(cmd.Options[0].Options[0].NameLocalizations)
+	suite.Nil(cmd.Options[0].Options[0].Options[0].DescriptionLocalizations)
+}
+
+func TestLocalization(t *testing.T) {
+	suite.Run(t, new(LocalizationTestSuite))
+}
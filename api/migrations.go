@@ -0,0 +1,372 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"github.com/lazybytez/jojo-discord-bot/services"
+	"gorm.io/gorm"
+	"sort"
+	"time"
+)
+
+// CoreMigrationOwner is the owner of the migrations that
+// manage the schema of the core entities.
+// Core migrations are always applied before the migrations of components.
+const CoreMigrationOwner = "core"
+
+// ErrIrreversibleMigration is returned, when a migration without
+// a Down function should be rolled back.
+var ErrIrreversibleMigration = errors.New("the migration cannot be rolled back")
+
+// MigrationFunc is a function that changes the database schema or data.
+// The passed gorm.DB is a transaction, which is rolled back when an error is returned.
+type MigrationFunc func(tx *gorm.DB) error
+
+// Migration is a single versioned change of the database schema.
+//
+// The Version must be unique per owner and defines the order in which the
+// migrations of an owner are applied. Use the time of creation
+// in the format YYYYMMDDhhmm (e.g. 202210170000) as version.
+// Down is optional. Migrations without Down cannot be rolled back.
+type Migration struct {
+	Version     uint64
+	Description string
+	Up          MigrationFunc
+	Down        MigrationFunc
+}
+
+// Migrations is a list of Migration.
+type Migrations []Migration
+
+// MigrationStatus holds whether a migration has already been applied.
+//
+// Migrations that are recorded in the schema history, but are no longer
+// known to the application, are not Registered.
+type MigrationStatus struct {
+	Owner       string
+	Version     uint64
+	Description string
+	Applied     bool
+	AppliedAt   time.Time
+	Registered  bool
+}
+
+// migrationSet holds the migrations of a single owner.
+type migrationSet struct {
+	owner      string
+	migrations Migrations
+}
+
+// Migrator applies and rolls back versioned migrations and records
+// them in the schema history table.
+//
+// Migrations are applied in the order their owners have been added,
+// the migrations of an owner are applied ordered by their version.
+// Rollbacks happen in the reverse order the migrations have been applied.
+type Migrator struct {
+	db     *gorm.DB
+	logger services.Logger
+	sets   []migrationSet
+}
+
+// NewMigrator creates a new Migrator that works on the passed gorm.DB.
+func NewMigrator(db *gorm.DB, logger services.Logger) *Migrator {
+	return &Migrator{
+		db:     db,
+		logger: logger,
+		sets:   make([]migrationSet, 0),
+	}
+}
+
+// Migrator creates a Migrator that knows the core migrations and the
+// migrations of all registered components.
+func (em *EntityManager) Migrator() (*Migrator, error) {
+	migrator := NewMigrator(em.DB().DB(), em.Logger())
+
+	err := migrator.Add(CoreMigrationOwner, coreMigrations)
+	if nil != err {
+		return nil, err
+	}
+
+	for _, comp := range Components {
+		if 0 == len(comp.Migrations) {
+			continue
+		}
+
+		err = migrator.Add(string(comp.Code), comp.Migrations)
+		if nil != err {
+			return nil, err
+		}
+	}
+
+	return migrator, nil
+}
+
+// Add adds the migrations of the passed owner to the Migrator.
+// The migrations are validated and sorted by their version.
+func (m *Migrator) Add(owner string, migrations Migrations) error {
+	for _, set := range m.sets {
+		if set.owner == owner {
+			return fmt.Errorf("the migrations of \"%s\" have already been added", owner)
+		}
+	}
+
+	sortedMigrations := make(Migrations, len(migrations))
+	copy(sortedMigrations, migrations)
+	sort.SliceStable(sortedMigrations, func(i, j int) bool {
+		return sortedMigrations[i].Version < sortedMigrations[j].Version
+	})
+
+	for i, migration := range sortedMigrations {
+		if 0 == migration.Version {
+			return fmt.Errorf("a migration of \"%s\" has no version", owner)
+		}
+
+		if nil == migration.Up {
+			return fmt.Errorf("the migration \"%d\" of \"%s\" has no up function", migration.Version, owner)
+		}
+
+		if i > 0 && sortedMigrations[i-1].Version == migration.Version {
+			return fmt.Errorf("the migration \"%d\" of \"%s\" is defined twice", migration.Version, owner)
+		}
+	}
+
+	m.sets = append(m.sets, migrationSet{
+		owner:      owner,
+		migrations: sortedMigrations,
+	})
+
+	return nil
+}
+
+// Up applies all pending migrations and returns the number
+// of applied migrations.
+//
+// Every migration is applied in its own transaction.
+// The function stops on the first failing migration.
+func (m *Migrator) Up() (int, error) {
+	history, err := m.getHistory()
+	if nil != err {
+		return 0, err
+	}
+
+	applied := make(map[string]bool, len(history))
+	for _, record := range history {
+		applied[getMigrationKey(record.Owner, record.Version)] = true
+	}
+
+	count := 0
+	for _, set := range m.sets {
+		for _, migration := range set.migrations {
+			if applied[getMigrationKey(set.owner, migration.Version)] {
+				continue
+			}
+
+			err = m.apply(set.owner, migration)
+			if nil != err {
+				return count, err
+			}
+
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// Down rolls back the passed number of most recently applied migrations
+// and returns the number of rolled back migrations.
+//
+// Every migration is rolled back in its own transaction.
+// The function stops on the first failing migration.
+func (m *Migrator) Down(steps int) (int, error) {
+	history, err := m.getHistory()
+	if nil != err {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(history) - 1; i >= 0 && count < steps; i-- {
+		record := history[i]
+
+		migration, ok := m.findMigration(record.Owner, record.Version)
+		if !ok {
+			return count, fmt.Errorf("the applied migration \"%d\" of \"%s\" is unknown",
+				record.Version,
+				record.Owner)
+		}
+
+		err = m.rollback(record, migration)
+		if nil != err {
+			return count, err
+		}
+
+		count++
+	}
+
+	return count, nil
+}
+
+// Status returns the status of all known and applied migrations.
+//
+// Known migrations are returned in the order they would be applied,
+// followed by applied migrations that are no longer known.
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	history, err := m.getHistory()
+	if nil != err {
+		return nil, err
+	}
+
+	applied := make(map[string]entities.SchemaMigration, len(history))
+	for _, record := range history {
+		applied[getMigrationKey(record.Owner, record.Version)] = record
+	}
+
+	status := make([]MigrationStatus, 0, len(history))
+	for _, set := range m.sets {
+		for _, migration := range set.migrations {
+			key := getMigrationKey(set.owner, migration.Version)
+			record, ok := applied[key]
+			delete(applied, key)
+
+			status = append(status, MigrationStatus{
+				Owner:       set.owner,
+				Version:     migration.Version,
+				Description: migration.Description,
+				Applied:     ok,
+				AppliedAt:   record.AppliedAt,
+				Registered:  true,
+			})
+		}
+	}
+
+	for _, record := range history {
+		if _, ok := applied[getMigrationKey(record.Owner, record.Version)]; !ok {
+			continue
+		}
+
+		status = append(status, MigrationStatus{
+			Owner:       record.Owner,
+			Version:     record.Version,
+			Description: record.Description,
+			Applied:     true,
+			AppliedAt:   record.AppliedAt,
+			Registered:  false,
+		})
+	}
+
+	return status, nil
+}
+
+// apply runs the passed migration and records it in the schema history.
+func (m *Migrator) apply(owner string, migration Migration) error {
+	m.logger.Info("Applying migration \"%d\" (%s) of \"%s\"...", migration.Version, migration.Description, owner)
+
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		err := migration.Up(tx)
+		if nil != err {
+			return err
+		}
+
+		return tx.Create(&entities.SchemaMigration{
+			Owner:       owner,
+			Version:     migration.Version,
+			Description: migration.Description,
+			AppliedAt:   time.Now(),
+		}).Error
+	})
+	if nil != err {
+		m.logger.Err(err, "Failed to apply migration \"%d\" of \"%s\"!", migration.Version, owner)
+
+		return fmt.Errorf("failed to apply migration \"%d\" of \"%s\": %w", migration.Version, owner, err)
+	}
+
+	return nil
+}
+
+// rollback reverts the passed migration and removes it from the schema history.
+func (m *Migrator) rollback(record entities.SchemaMigration, migration Migration) error {
+	if nil == migration.Down {
+		return fmt.Errorf("%w: \"%d\" of \"%s\"", ErrIrreversibleMigration, record.Version, record.Owner)
+	}
+
+	m.logger.Info("Rolling back migration \"%d\" (%s) of \"%s\"...",
+		record.Version,
+		migration.Description,
+		record.Owner)
+
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		err := migration.Down(tx)
+		if nil != err {
+			return err
+		}
+
+		return tx.Delete(&record).Error
+	})
+	if nil != err {
+		m.logger.Err(err, "Failed to roll back migration \"%d\" of \"%s\"!", record.Version, record.Owner)
+
+		return fmt.Errorf("failed to roll back migration \"%d\" of \"%s\": %w", record.Version, record.Owner, err)
+	}
+
+	return nil
+}
+
+// getHistory ensures the schema history table exists and returns
+// all applied migrations in the order they have been applied.
+func (m *Migrator) getHistory() ([]entities.SchemaMigration, error) {
+	err := m.db.AutoMigrate(&entities.SchemaMigration{})
+	if nil != err {
+		return nil, fmt.Errorf("failed to prepare the schema history table: %w", err)
+	}
+
+	var history []entities.SchemaMigration
+	err = m.db.Order("id").Find(&history).Error
+	if nil != err {
+		return nil, fmt.Errorf("failed to read the schema history: %w", err)
+	}
+
+	return history, nil
+}
+
+// findMigration returns the known migration with the passed owner and version.
+func (m *Migrator) findMigration(owner string, version uint64) (Migration, bool) {
+	for _, set := range m.sets {
+		if set.owner != owner {
+			continue
+		}
+
+		for _, migration := range set.migrations {
+			if migration.Version == version {
+				return migration, true
+			}
+		}
+	}
+
+	return Migration{}, false
+}
+
+// getMigrationKey creates a key that uniquely identifies the migration
+// with the passed version of the passed owner.
+func getMigrationKey(owner string, version uint64) string {
+	return fmt.Sprintf("%s:%d", owner, version)
+}
@@ -0,0 +1,330 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"errors"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"github.com/lazybytez/jojo-discord-bot/services/database"
+	"github.com/lazybytez/jojo-discord-bot/test/logmock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"testing"
+)
+
+type migrationTestTable struct {
+	ID   uint
+	Name string
+}
+
+type MigratorTestSuite struct {
+	suite.Suite
+	db       *gorm.DB
+	logger   *logmock.LoggerMock
+	migrator *Migrator
+}
+
+func (suite *MigratorTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	suite.NoError(err)
+
+	// Every connection would get its own in-memory database
+	sqlDB, err := db.DB()
+	suite.NoError(err)
+	sqlDB.SetMaxOpenConns(1)
+
+	suite.db = db
+	suite.logger = &logmock.LoggerMock{}
+	suite.logger.On("Info", mock.Anything, mock.Anything)
+	suite.logger.On("Err", mock.Anything, mock.Anything, mock.Anything)
+	suite.migrator = NewMigrator(db, suite.logger)
+}
+
+func (suite *MigratorTestSuite) TearDownTest() {
+	sqlDB, err := suite.db.DB()
+	suite.NoError(err)
+	suite.NoError(sqlDB.Close())
+}
+
+func (suite *MigratorTestSuite) getTestMigrations() Migrations {
+	return Migrations{
+		{
+			Version:     202210020000,
+			Description: "add test row",
+			Up: func(tx *gorm.DB) error {
+				return tx.Create(&migrationTestTable{Name: "first"}).Error
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Where("name = ?", "first").Delete(&migrationTestTable{}).Error
+			},
+		},
+		{
+			Version:     202210010000,
+			Description: "create test table",
+			Up: func(tx *gorm.DB) error {
+				return tx.Migrator().CreateTable(&migrationTestTable{})
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&migrationTestTable{})
+			},
+		},
+	}
+}
+
+func (suite *MigratorTestSuite) TestAddWithInvalidMigrations() {
+	tables := []struct {
+		migrations Migrations
+	}{
+		{Migrations{{Version: 0, Up: func(_ *gorm.DB) error { return nil }}}},
+		{Migrations{{Version: 1}}},
+		{Migrations{
+			{Version: 1, Up: func(_ *gorm.DB) error { return nil }},
+			{Version: 1, Up: func(_ *gorm.DB) error { return nil }},
+		}},
+	}
+
+	for _, table := range tables {
+		suite.Error(suite.migrator.Add("test", table.migrations))
+	}
+
+	suite.NoError(suite.migrator.Add("test", Migrations{}))
+	suite.Error(suite.migrator.Add("test", Migrations{}))
+}
+
+func (suite *MigratorTestSuite) TestUpAppliesMigrationsInOrder() {
+	suite.NoError(suite.migrator.Add("test", suite.getTestMigrations()))
+
+	count, err := suite.migrator.Up()
+
+	suite.NoError(err)
+	suite.Equal(2, count)
+
+	var rows []migrationTestTable
+	suite.NoError(suite.db.Find(&rows).Error)
+	suite.Len(rows, 1)
+
+	var history []entities.SchemaMigration
+	suite.NoError(suite.db.Order("id").Find(&history).Error)
+	suite.Len(history, 2)
+	suite.Equal(uint64(202210010000), history[0].Version)
+	suite.Equal(uint64(202210020000), history[1].Version)
+
+	// Consecutive calls do not apply migrations twice
+	count, err = suite.migrator.Up()
+
+	suite.NoError(err)
+	suite.Equal(0, count)
+}
+
+func (suite *MigratorTestSuite) TestUpWithFailingMigration() {
+	testErr := errors.New("something bad happened")
+	migrations := suite.getTestMigrations()
+	migrations = append(migrations, Migration{
+		Version: 202210030000,
+		Up: func(tx *gorm.DB) error {
+			err := tx.Create(&migrationTestTable{Name: "second"}).Error
+			if nil != err {
+				return err
+			}
+
+			return testErr
+		},
+	})
+	suite.NoError(suite.migrator.Add("test", migrations))
+
+	count, err := suite.migrator.Up()
+
+	suite.True(errors.Is(err, testErr))
+	suite.Equal(2, count)
+
+	// The failing migration has been rolled back
+	var rows []migrationTestTable
+	suite.NoError(suite.db.Find(&rows).Error)
+	suite.Len(rows, 1)
+
+	status, err := suite.migrator.Status()
+	suite.NoError(err)
+	suite.Len(status, 3)
+	suite.False(status[2].Applied)
+}
+
+func (suite *MigratorTestSuite) TestDown() {
+	suite.NoError(suite.migrator.Add("test", suite.getTestMigrations()))
+
+	_, err := suite.migrator.Up()
+	suite.NoError(err)
+
+	count, err := suite.migrator.Down(1)
+
+	suite.NoError(err)
+	suite.Equal(1, count)
+
+	var rows []migrationTestTable
+	suite.NoError(suite.db.Find(&rows).Error)
+	suite.Len(rows, 0)
+
+	count, err = suite.migrator.Down(5)
+
+	suite.NoError(err)
+	suite.Equal(1, count)
+	suite.False(suite.db.Migrator().HasTable(&migrationTestTable{}))
+
+	status, err := suite.migrator.Status()
+	suite.NoError(err)
+	for _, migration := range status {
+		suite.False(migration.Applied)
+	}
+}
+
+func (suite *MigratorTestSuite) TestDownWithIrreversibleMigration() {
+	suite.NoError(suite.migrator.Add("test", Migrations{
+		{Version: 1, Up: func(_ *gorm.DB) error { return nil }},
+	}))
+
+	_, err := suite.migrator.Up()
+	suite.NoError(err)
+
+	count, err := suite.migrator.Down(1)
+
+	suite.True(errors.Is(err, ErrIrreversibleMigration))
+	suite.Equal(0, count)
+}
+
+func (suite *MigratorTestSuite) TestStatus() {
+	suite.NoError(suite.migrator.Add("test", suite.getTestMigrations()))
+
+	// Simulate an applied migration that is no longer known
+	suite.NoError(suite.db.AutoMigrate(&entities.SchemaMigration{}))
+	suite.NoError(suite.db.Create(&entities.SchemaMigration{
+		Owner:       "removed_component",
+		Version:     1,
+		Description: "removed",
+	}).Error)
+
+	status, err := suite.migrator.Status()
+
+	suite.NoError(err)
+	suite.Len(status, 3)
+	suite.Equal(uint64(202210010000), status[0].Version)
+	suite.False(status[0].Applied)
+	suite.True(status[0].Registered)
+	suite.Equal("removed_component", status[2].Owner)
+	suite.True(status[2].Applied)
+	suite.False(status[2].Registered)
+
+	// Unknown migrations cannot be rolled back
+	_, err = suite.migrator.Down(1)
+	suite.Error(err)
+}
+
+func (suite *MigratorTestSuite) TestCoreMigrations() {
+	previousEntityManager := entityManager
+	defer func() {
+		entityManager = previousEntityManager
+	}()
+
+	entityManager = NewEntityManager(database.New(suite.db), suite.logger)
+
+	migrator, err := GetEntityManager().Migrator()
+	suite.NoError(err)
+
+	_, err = migrator.Up()
+	suite.NoError(err)
+
+	for _, entity := range coreEntities {
+		suite.True(suite.db.Migrator().HasTable(entity))
+	}
+
+	count, err := migrator.Down(len(coreMigrations))
+	suite.NoError(err)
+	suite.Equal(len(coreMigrations), count)
+
+	for _, entity := range coreEntities {
+		suite.False(suite.db.Migrator().HasTable(entity))
+	}
+}
+
+func (suite *MigratorTestSuite) TestAddGuildLeftAt() {
+	suite.NoError(createInitialSchema(suite.db))
+	suite.False(suite.db.Migrator().HasColumn(&entities.Guild{}, "LeftAt"))
+
+	suite.NoError(addGuildLeftAt(suite.db))
+	suite.True(suite.db.Migrator().HasColumn(&entities.Guild{}, "LeftAt"))
+	suite.True(suite.db.Migrator().HasIndex(&entities.Guild{}, "idx_guild_left_at"))
+
+	suite.NoError(dropGuildLeftAt(suite.db))
+	suite.False(suite.db.Migrator().HasColumn(&entities.Guild{}, "LeftAt"))
+}
+
+func (suite *MigratorTestSuite) TestMakeGuildComponentConfigKeysUnique() {
+	suite.NoError(createInitialSchema(suite.db))
+
+	configs := []entities.GuildComponentConfig{
+		{GuildID: 1, ComponentID: 2, Key: "key", Value: "first"},
+		{GuildID: 1, ComponentID: 2, Key: "key", Value: "second"},
+		{GuildID: 1, ComponentID: 2, Key: "other", Value: "other"},
+		{GuildID: 1, ComponentID: 2, Key: "deleted", Value: "deleted"},
+	}
+	suite.NoError(suite.db.Omit("Guild", "Component").Create(&configs).Error)
+	suite.NoError(suite.db.Delete(&configs[3]).Error)
+
+	suite.NoError(makeGuildComponentConfigKeysUnique(suite.db))
+
+	var values []string
+	suite.NoError(suite.db.Unscoped().Model(&entities.GuildComponentConfig{}).Order("id").Pluck("value", &values).Error)
+	suite.Equal([]string{"second", "other"}, values)
+
+	duplicate := &entities.GuildComponentConfig{GuildID: 1, ComponentID: 2, Key: "key"}
+	suite.Error(suite.db.Omit("Guild", "Component").Create(duplicate).Error)
+
+	suite.NoError(makeGuildComponentConfigKeysNonUnique(suite.db))
+
+	duplicate = &entities.GuildComponentConfig{GuildID: 1, ComponentID: 2, Key: "key"}
+	suite.NoError(suite.db.Omit("Guild", "Component").Create(duplicate).Error)
+}
+
+func (suite *MigratorTestSuite) TestCoreMigrationsMatchEntities() {
+	migrator := NewMigrator(suite.db, suite.logger)
+	suite.NoError(migrator.Add(CoreMigrationOwner, coreMigrations))
+
+	_, err := migrator.Up()
+	suite.NoError(err)
+
+	for _, entity := range coreEntities {
+		stmt := &gorm.Statement{DB: suite.db}
+		suite.NoError(stmt.Parse(entity))
+
+		for _, column := range stmt.Schema.DBNames {
+			suite.True(suite.db.Migrator().HasColumn(entity, column), "%s.%s", stmt.Schema.Table, column)
+		}
+
+		for _, index := range stmt.Schema.ParseIndexes() {
+			suite.True(suite.db.Migrator().HasIndex(entity, index.Name), "%s.%s", stmt.Schema.Table, index.Name)
+		}
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	suite.Run(t, new(MigratorTestSuite))
+}
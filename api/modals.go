@@ -0,0 +1,330 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// modalFieldTag is the struct tag used to map the text inputs
+// of a submitted modal to the fields of a struct.
+const modalFieldTag = "modal"
+
+// ModalSubmitHandler is a function that handles the submission of a modal.
+//
+// The payload holds the state that has been encoded in the custom id
+// of the modal when it was opened. It is empty, if there is no payload.
+type ModalSubmitHandler func(s *discordgo.Session, i *discordgo.InteractionCreate, payload string)
+
+// ModalInputError is returned when the value of a text input
+// of a submitted modal cannot be decoded into the target field.
+type ModalInputError struct {
+	CustomId string
+	Value    string
+	Err      error
+}
+
+// Error returns the message of the ModalInputError.
+func (mie *ModalInputError) Error() string {
+	return fmt.Sprintf("the value \"%s\" of the input \"%s\" is invalid: %v", mie.Value, mie.CustomId, mie.Err)
+}
+
+// Unwrap returns the error that caused the ModalInputError.
+func (mie *ModalInputError) Unwrap() error {
+	return mie.Err
+}
+
+// modalHandler holds a registered ModalSubmitHandler
+// together with the component that owns it.
+type modalHandler struct {
+	name    string
+	handler ModalSubmitHandler
+	c       *Component
+}
+
+var (
+	// modalHandlerMap holds all registered modalHandler
+	// with the namespaced name (<component code>:<name>) as key.
+	modalHandlerMap = make(map[string]*modalHandler)
+
+	// modalHandlerMapMu is used to synchronize access to the modalHandlerMap,
+	// as handlers can be registered and unregistered at runtime.
+	modalHandlerMapMu sync.RWMutex
+)
+
+// ModalContainer is the default implementation of the ModalManager.
+type ModalContainer struct {
+	owner *Component
+}
+
+// ModalManager allows components to handle the submission of modals.
+//
+// The custom ids of modals are namespaced using the code of the
+// component that owns the handler. Always use CustomId to create the custom id
+// of a modal, that should be handled by a registered handler.
+// Use slash_commands.OpenModal to open a modal in response to an interaction.
+type ModalManager interface {
+	// Register registers the passed handler with the passed name.
+	// The name must be unique within the component and must not contain a colon.
+	Register(name string, handler ModalSubmitHandler) error
+	// Unregister removes the handler with the passed name.
+	Unregister(name string) error
+	// UnregisterAll removes all handlers owned by the component.
+	UnregisterAll()
+	// CustomId creates the custom id for a modal that should be
+	// handled by the handler with the passed name.
+	// The optional payload is passed to the handler, when the modal is submitted.
+	CustomId(name string, payload string) (string, error)
+}
+
+// ModalManager returns the ModalManager of the component,
+// which allows to handle the submission of modals.
+func (c *Component) ModalManager() ModalManager {
+	if nil == c.modalManager {
+		c.modalManager = &ModalContainer{owner: c}
+	}
+
+	return c.modalManager
+}
+
+// Register registers the passed handler with the passed name.
+// The name must be unique within the component and must not contain a colon.
+func (mc *ModalContainer) Register(name string, handler ModalSubmitHandler) error {
+	if "" == name || strings.Contains(name, customIdSeparator) {
+		return fmt.Errorf("the name \"%s\" of the modal handler is invalid", name)
+	}
+
+	key := getComponentInteractionKey(mc.owner.Code, name)
+
+	modalHandlerMapMu.Lock()
+	defer modalHandlerMapMu.Unlock()
+
+	if _, ok := modalHandlerMap[key]; ok {
+		return fmt.Errorf("there is already a modal handler with the name \"%s\"", key)
+	}
+
+	modalHandlerMap[key] = &modalHandler{
+		name:    name,
+		handler: handler,
+		c:       mc.owner,
+	}
+
+	mc.owner.Logger().Info("Registered modal handler \"%s\"!", key)
+
+	return nil
+}
+
+// Unregister removes the handler with the passed name.
+func (mc *ModalContainer) Unregister(name string) error {
+	key := getComponentInteractionKey(mc.owner.Code, name)
+
+	modalHandlerMapMu.Lock()
+	defer modalHandlerMapMu.Unlock()
+
+	if _, ok := modalHandlerMap[key]; !ok {
+		return fmt.Errorf("there is no modal handler with the name \"%s\"", key)
+	}
+
+	delete(modalHandlerMap, key)
+
+	mc.owner.Logger().Info("Unregistered modal handler \"%s\"!", key)
+
+	return nil
+}
+
+// UnregisterAll removes all handlers owned by the component.
+func (mc *ModalContainer) UnregisterAll() {
+	modalHandlerMapMu.Lock()
+	defer modalHandlerMapMu.Unlock()
+
+	for key, handler := range modalHandlerMap {
+		if handler.c.Code == mc.owner.Code {
+			delete(modalHandlerMap, key)
+		}
+	}
+}
+
+// CustomId creates the custom id for a modal that should be
+// handled by the handler with the passed name.
+// The optional payload is passed to the handler, when the modal is submitted.
+//
+// The resulting custom id has the format <component code>:<name>[:<payload>]
+// and must not exceed the maximum length of custom ids allowed by Discord.
+func (mc *ModalContainer) CustomId(name string, payload string) (string, error) {
+	return mc.owner.ComponentInteractionManager().CustomId(name, payload)
+}
+
+// TypedModalSubmitHandler creates a ModalSubmitHandler that decodes the values
+// of the submitted modal into a new instance of T before the passed handler is called.
+//
+// When the values cannot be decoded, the handler is called with the occurred error.
+// See DecodeModalSubmitData for how the values are mapped to the fields of T.
+func TypedModalSubmitHandler[T any](
+	handler func(s *discordgo.Session, i *discordgo.InteractionCreate, payload string, data *T, err error),
+) ModalSubmitHandler {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate, payload string) {
+		data := new(T)
+		err := DecodeModalSubmitData(i.ModalSubmitData(), data)
+
+		handler(s, i, payload, data, err)
+	}
+}
+
+// DecodeModalSubmitData decodes the values of the text inputs of a submitted
+// modal into the passed struct pointer.
+//
+// Fields are mapped using the modal struct tag, which holds the custom id
+// of the text input:
+//
+//	type AuditLogSetup struct {
+//		Channel string `modal:"channel"`
+//		Limit   int    `modal:"limit"`
+//	}
+//
+// Supported field types are strings, integers and booleans.
+// Empty values leave the field untouched.
+func DecodeModalSubmitData(data discordgo.ModalSubmitInteractionData, target interface{}) error {
+	targetValue := reflect.ValueOf(target)
+	if targetValue.Kind() != reflect.Pointer || targetValue.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("the target of the modal submit data must be a pointer to a struct")
+	}
+
+	values := getModalSubmitValues(data.Components)
+	targetValue = targetValue.Elem()
+	targetType := targetValue.Type()
+
+	for fieldIndex := 0; fieldIndex < targetType.NumField(); fieldIndex++ {
+		customId, ok := targetType.Field(fieldIndex).Tag.Lookup(modalFieldTag)
+		if !ok || "" == customId {
+			continue
+		}
+
+		value, ok := values[customId]
+		if !ok || "" == value {
+			continue
+		}
+
+		err := setModalFieldValue(targetValue.Field(fieldIndex), value)
+		if nil != err {
+			return &ModalInputError{
+				CustomId: customId,
+				Value:    value,
+				Err:      err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// getModalSubmitValues collects the values of all text inputs in the
+// passed components with the custom id of the text input as key.
+func getModalSubmitValues(components []discordgo.MessageComponent) map[string]string {
+	values := make(map[string]string)
+
+	for _, component := range components {
+		switch v := component.(type) {
+		case *discordgo.ActionsRow:
+			for customId, value := range getModalSubmitValues(v.Components) {
+				values[customId] = value
+			}
+		case *discordgo.TextInput:
+			values[v.CustomID] = v.Value
+		}
+	}
+
+	return values
+}
+
+// setModalFieldValue parses the passed raw value into the type of the passed field
+// and sets the value of the field.
+func setModalFieldValue(field reflect.Value, rawValue string) error {
+	rawValue = strings.TrimSpace(rawValue)
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(rawValue)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := strconv.ParseInt(rawValue, 10, field.Type().Bits())
+		if nil != err {
+			return fmt.Errorf("the value is not a valid integer")
+		}
+		field.SetInt(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err := strconv.ParseUint(rawValue, 10, field.Type().Bits())
+		if nil != err {
+			return fmt.Errorf("the value is not a valid positive integer")
+		}
+		field.SetUint(value)
+	case reflect.Bool:
+		value, err := strconv.ParseBool(rawValue)
+		if nil != err {
+			return fmt.Errorf("the value is not a valid boolean")
+		}
+		field.SetBool(value)
+	default:
+		return fmt.Errorf("the field type \"%s\" is not supported", field.Type())
+	}
+
+	return nil
+}
+
+// getModalHandler returns the modalHandler registered with the passed key.
+func getModalHandler(key string) (*modalHandler, bool) {
+	modalHandlerMapMu.RLock()
+	defer modalHandlerMapMu.RUnlock()
+
+	handler, ok := modalHandlerMap[key]
+
+	return handler, ok
+}
+
+// handleModalSubmitDispatch handles the processing of a submitted modal.
+// The submission is delegated to the handler that has been encoded in the
+// custom id of the modal.
+//
+// Like slash commands, submissions of modals of disabled components are rejected.
+func handleModalSubmitDispatch(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	code, name, payload, ok := ParseCustomId(i.ModalSubmitData().CustomID)
+	if !ok {
+		return
+	}
+
+	handler, ok := getModalHandler(getComponentInteractionKey(code, name))
+	if !ok {
+		return
+	}
+
+	defer recoverFromInteractionPanic(s, i, handler.c, fmt.Sprintf("modal \"%s\" on guild \"%s\"",
+		name,
+		i.GuildID))
+
+	if !IsComponentEnabled(handler.c, i.GuildID) {
+		respondWithComponentDisabled(s, i, handler.c)
+
+		return
+	}
+
+	handler.handler(s, i, payload)
+}
@@ -0,0 +1,196 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"errors"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type ModalManagerTestSuite struct {
+	suite.Suite
+	owningComponent *Component
+}
+
+type modalTestData struct {
+	Name     string `modal:"name"`
+	Amount   int    `modal:"amount"`
+	Positive uint8  `modal:"positive"`
+	Enabled  bool   `modal:"enabled"`
+	Ignored  string
+}
+
+func (suite *ModalManagerTestSuite) SetupTest() {
+	suite.owningComponent = &Component{
+		Code: "bot_test_component",
+		Name: "Test Component",
+	}
+	modalHandlerMap = make(map[string]*modalHandler)
+}
+
+func (suite *ModalManagerTestSuite) TestRegisterAndUnregister() {
+	manager := suite.owningComponent.ModalManager()
+
+	suite.NoError(manager.Register("setup", nil))
+	suite.Contains(modalHandlerMap, "bot_test_component:setup")
+	suite.Error(manager.Register("setup", nil))
+	suite.Error(manager.Register("set:up", nil))
+
+	suite.NoError(manager.Unregister("setup"))
+	suite.Len(modalHandlerMap, 0)
+	suite.Error(manager.Unregister("setup"))
+}
+
+func (suite *ModalManagerTestSuite) TestUnregisterAll() {
+	foreignComponent := &Component{Code: "foreign_component"}
+
+	suite.NoError(suite.owningComponent.ModalManager().Register("setup", nil))
+	suite.NoError(suite.owningComponent.ModalManager().Register("edit", nil))
+	suite.NoError(foreignComponent.ModalManager().Register("setup", nil))
+
+	suite.owningComponent.ModalManager().UnregisterAll()
+
+	suite.Len(modalHandlerMap, 1)
+	suite.Contains(modalHandlerMap, "foreign_component:setup")
+}
+
+func (suite *ModalManagerTestSuite) TestCustomId() {
+	customId, err := suite.owningComponent.ModalManager().CustomId("setup", "42")
+
+	suite.NoError(err)
+	suite.Equal("bot_test_component:setup:42", customId)
+}
+
+func (suite *ModalManagerTestSuite) TestDecodeModalSubmitData() {
+	data := createModalSubmitData(map[string]string{
+		"name":     " Jotaro ",
+		"amount":   "-5",
+		"positive": "7",
+		"enabled":  "true",
+		"unknown":  "value",
+	})
+
+	result := modalTestData{Ignored: "untouched"}
+	err := DecodeModalSubmitData(data, &result)
+
+	suite.NoError(err)
+	suite.Equal(modalTestData{
+		Name:     "Jotaro",
+		Amount:   -5,
+		Positive: 7,
+		Enabled:  true,
+		Ignored:  "untouched",
+	}, result)
+}
+
+func (suite *ModalManagerTestSuite) TestDecodeModalSubmitDataWithEmptyValues() {
+	data := createModalSubmitData(map[string]string{
+		"name":   "",
+		"amount": "",
+	})
+
+	result := modalTestData{Name: "Dio", Amount: 3}
+	err := DecodeModalSubmitData(data, &result)
+
+	suite.NoError(err)
+	suite.Equal("Dio", result.Name)
+	suite.Equal(3, result.Amount)
+}
+
+func (suite *ModalManagerTestSuite) TestDecodeModalSubmitDataWithInvalidValues() {
+	tables := []struct {
+		customId string
+		value    string
+	}{
+		{"amount", "five"},
+		{"positive", "-1"},
+		{"positive", "256"},
+		{"enabled", "maybe"},
+	}
+
+	for _, table := range tables {
+		result := modalTestData{}
+		err := DecodeModalSubmitData(createModalSubmitData(map[string]string{table.customId: table.value}), &result)
+
+		var inputErr *ModalInputError
+		suite.True(errors.As(err, &inputErr))
+		suite.Equal(table.customId, inputErr.CustomId)
+		suite.Equal(table.value, inputErr.Value)
+	}
+}
+
+func (suite *ModalManagerTestSuite) TestDecodeModalSubmitDataWithInvalidTarget() {
+	data := createModalSubmitData(map[string]string{})
+
+	suite.Error(DecodeModalSubmitData(data, modalTestData{}))
+	suite.Error(DecodeModalSubmitData(data, new(string)))
+}
+
+func (suite *ModalManagerTestSuite) TestHandleModalSubmitDispatchWithTypedHandler() {
+	var receivedData *modalTestData
+	var receivedErr error
+	receivedPayload := ""
+
+	err := suite.owningComponent.ModalManager().Register("setup", TypedModalSubmitHandler(
+		func(_ *discordgo.Session, _ *discordgo.InteractionCreate, payload string, data *modalTestData, err error) {
+			receivedPayload = payload
+			receivedData = data
+			receivedErr = err
+		}))
+	suite.NoError(err)
+
+	data := createModalSubmitData(map[string]string{"name": "Jotaro"})
+	data.CustomID = "bot_test_component:setup:42"
+
+	handleCommandDispatch(nil, &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type: discordgo.InteractionModalSubmit,
+			Data: data,
+		},
+	})
+
+	suite.NoError(receivedErr)
+	suite.Equal("42", receivedPayload)
+	suite.NotNil(receivedData)
+	suite.Equal("Jotaro", receivedData.Name)
+}
+
+// createModalSubmitData creates discordgo.ModalSubmitInteractionData with
+// one text input per passed value.
+func createModalSubmitData(values map[string]string) discordgo.ModalSubmitInteractionData {
+	components := make([]discordgo.MessageComponent, 0, len(values))
+	for customId, value := range values {
+		components = append(components, &discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				&discordgo.TextInput{
+					CustomID: customId,
+					Value:    value,
+				},
+			},
+		})
+	}
+
+	return discordgo.ModalSubmitInteractionData{Components: components}
+}
+
+func TestModalManager(t *testing.T) {
+	suite.Run(t, new(ModalManagerTestSuite))
+}
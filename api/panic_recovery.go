@@ -0,0 +1,238 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+const (
+	// GenericErrorResponseEmbedName is the name of the embed field that is
+	// used to tell users that something went wrong.
+	GenericErrorResponseEmbedName = ":x: Damn, something went wrong!"
+	// GenericErrorResponseEmbedValue is the value of the embed field that is
+	// used to tell users that something went wrong.
+	GenericErrorResponseEmbedValue = "Something unexpected happened while processing the command!"
+
+	// ComponentFailureThreshold is the number of failures a component may produce
+	// within the ComponentFailureWindow before it is disabled globally.
+	ComponentFailureThreshold = 5
+	// ComponentFailureWindow is the duration in which failures of a component are counted.
+	ComponentFailureWindow = 10 * time.Minute
+)
+
+// componentFailureMap holds the points in time at which components failed,
+// with the code of the failing component as key.
+type componentFailureMap struct {
+	sync.Mutex
+	failures map[entities.ComponentCode][]time.Time
+}
+
+// componentFailures holds the recent failures of all components.
+var componentFailures = componentFailureMap{
+	failures: make(map[entities.ComponentCode][]time.Time),
+}
+
+// componentFailureNow returns the current time.
+// It can be overridden in tests to simulate the passing of time.
+var componentFailureNow = time.Now
+
+// recoverFromPanic handles a value recovered from a panic that occurred
+// in code owned by the passed Component.
+//
+// The panic is logged together with its stack and the passed context,
+// and the failure is counted for the component. When the component failed
+// too often, it is disabled globally.
+// The returned error reference is part of the log entry and can be shown to users,
+// so that they can report the failure.
+func recoverFromPanic(c *Component, recovered interface{}, context string) string {
+	reference := newErrorReference()
+
+	c.Logger().Err(
+		fmt.Errorf("%v", recovered),
+		"Recovered from panic in %s of component \"%s\" (reference: %s)!\n%s",
+		context,
+		c.Code,
+		reference,
+		debug.Stack())
+
+	if recordComponentFailure(c.Code) {
+		disableFailingComponent(c)
+	}
+
+	return reference
+}
+
+// newErrorReference generates a random reference that allows
+// to find the log entry of a failure.
+func newErrorReference() string {
+	reference := make([]byte, 4)
+	_, _ = rand.Read(reference)
+
+	return hex.EncodeToString(reference)
+}
+
+// recordComponentFailure counts a failure of the component with the passed code.
+// The function returns true, when the component reached the ComponentFailureThreshold
+// within the ComponentFailureWindow. In that case, the counted failures are reset.
+func recordComponentFailure(code entities.ComponentCode) bool {
+	componentFailures.Lock()
+	defer componentFailures.Unlock()
+
+	now := componentFailureNow()
+	recentFailures := make([]time.Time, 0, ComponentFailureThreshold)
+	for _, failure := range componentFailures.failures[code] {
+		if now.Sub(failure) < ComponentFailureWindow {
+			recentFailures = append(recentFailures, failure)
+		}
+	}
+	recentFailures = append(recentFailures, now)
+
+	if len(recentFailures) >= ComponentFailureThreshold {
+		delete(componentFailures.failures, code)
+
+		return true
+	}
+
+	componentFailures.failures[code] = recentFailures
+
+	return false
+}
+
+// disableFailingComponent disables the passed Component globally,
+// because it failed too often. Core components are never disabled.
+//
+// Afterwards, the commands are synced in the background, so that the
+// commands of the disabled component are removed from all guilds.
+func disableFailingComponent(c *Component) {
+	if IsCoreComponent(c) {
+		c.Logger().Warn("The core component failed %d times within %v, but cannot be disabled!",
+			ComponentFailureThreshold,
+			ComponentFailureWindow)
+
+		return
+	}
+
+	em := c.EntityManager()
+	regComp, err := em.RegisteredComponent().Get(c.Code)
+	if nil != err {
+		c.Logger().Err(err, "Failed to find the failing component in the database!")
+
+		return
+	}
+
+	globalStatus, err := em.GlobalComponentStatus().Get(regComp.ID)
+	if nil != err {
+		c.Logger().Err(err, "Failed to get the global status of the failing component!")
+
+		return
+	}
+
+	if !globalStatus.Enabled {
+		return
+	}
+
+	err = em.GlobalComponentStatus().Update(globalStatus, entities.ColumnEnabled, false)
+	if nil != err {
+		c.Logger().Err(err, "Failed to disable the failing component globally!")
+
+		return
+	}
+
+	c.Logger().Warn("The component has been disabled globally, because it failed %d times within %v!",
+		ComponentFailureThreshold,
+		ComponentFailureWindow)
+
+	if nil != c.discord {
+		go syncCommandsOfAllGuilds(c.discord, c.SlashCommandManager())
+	}
+}
+
+// recoverFromInteractionPanic recovers from a panic that occurred while the passed Component
+// processed the passed interaction and informs the user that something went wrong.
+// Autocomplete interactions receive no suggestions instead of an error message.
+//
+// The function must be deferred directly by the dispatcher of the interaction,
+// as recover has no effect otherwise.
+func recoverFromInteractionPanic(s *discordgo.Session, i *discordgo.InteractionCreate, c *Component, context string) {
+	r := recover()
+	if nil == r {
+		return
+	}
+
+	reference := recoverFromPanic(c, r, context)
+
+	if discordgo.InteractionApplicationCommandAutocomplete == i.Type {
+		err := RespondWithAutocompleteChoices(s, i, []*discordgo.ApplicationCommandOptionChoice{})
+		if nil != err {
+			c.Logger().Err(err, "Failed to deliver empty autocomplete response after panic!")
+		}
+
+		return
+	}
+
+	respondWithGenericError(s, i, c, reference)
+}
+
+// respondWithGenericError responds to the passed interaction with the generic
+// error message and the passed error reference.
+//
+// When the interaction has already been responded to by the handler without being tracked,
+// the message is sent as follow-up.
+func respondWithGenericError(s *discordgo.Session, i *discordgo.InteractionCreate, c *Component, reference string) {
+	embeds := []*discordgo.MessageEmbed{
+		{
+			Title: "JOJO Discord Bot",
+			Color: DefaultEmbedColor,
+			Fields: []*discordgo.MessageEmbedField{
+				{
+					Name:  Translate(i, GenericErrorResponseEmbedNameKey),
+					Value: Translate(i, GenericErrorResponseEmbedValueKey),
+				},
+				{
+					Name:  "Error Reference",
+					Value: fmt.Sprintf("`%s`", reference),
+				},
+			},
+		},
+	}
+
+	_, err := RespondToInteraction(s, i, &discordgo.InteractionResponseData{
+		Flags:  discordgo.MessageFlagsEphemeral,
+		Embeds: embeds,
+	})
+	if nil == err {
+		return
+	}
+
+	_, err = s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
+		Flags:  discordgo.MessageFlagsEphemeral,
+		Embeds: embeds,
+	})
+	if nil != err {
+		c.Logger().Err(err, "Failed to deliver error response!")
+	}
+}
@@ -0,0 +1,253 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"github.com/lazybytez/jojo-discord-bot/test/discordgo_mock"
+	"github.com/lazybytez/jojo-discord-bot/test/logmock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type PanicRecoveryTestSuite struct {
+	suite.Suite
+	owningComponent *Component
+	loggerMock      *logmock.LoggerMock
+	now             time.Time
+}
+
+func (suite *PanicRecoveryTestSuite) SetupTest() {
+	suite.loggerMock = &logmock.LoggerMock{}
+	suite.loggerMock.On("Info", mock.Anything, mock.Anything)
+	suite.loggerMock.On("Warn", mock.Anything, mock.Anything)
+	suite.loggerMock.On("Err", mock.Anything, mock.Anything, mock.Anything)
+
+	suite.owningComponent = &Component{
+		Code: "bot_test_component",
+		Name: "Test Component",
+	}
+	suite.owningComponent.SetLogger(suite.loggerMock)
+
+	componentFailures.failures = make(map[entities.ComponentCode][]time.Time)
+
+	suite.now = time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	componentFailureNow = func() time.Time {
+		return suite.now
+	}
+}
+
+func (suite *PanicRecoveryTestSuite) TearDownTest() {
+	componentFailureNow = time.Now
+}
+
+func (suite *PanicRecoveryTestSuite) TestNewErrorReference() {
+	reference := newErrorReference()
+
+	suite.Len(reference, 8)
+	suite.Regexp("^[0-9a-f]{8}$", reference)
+	suite.NotEqual(reference, newErrorReference())
+}
+
+func (suite *PanicRecoveryTestSuite) TestRecordComponentFailure() {
+	for i := 1; i < ComponentFailureThreshold; i++ {
+		suite.False(recordComponentFailure(suite.owningComponent.Code))
+	}
+
+	suite.True(recordComponentFailure(suite.owningComponent.Code))
+
+	// Failures are reset once the threshold has been reached
+	suite.False(recordComponentFailure(suite.owningComponent.Code))
+}
+
+func (suite *PanicRecoveryTestSuite) TestRecordComponentFailureOutsideOfWindow() {
+	for i := 1; i < ComponentFailureThreshold; i++ {
+		suite.False(recordComponentFailure(suite.owningComponent.Code))
+	}
+
+	suite.now = suite.now.Add(ComponentFailureWindow)
+
+	suite.False(recordComponentFailure(suite.owningComponent.Code))
+	suite.Len(componentFailures.failures[suite.owningComponent.Code], 1)
+}
+
+func (suite *PanicRecoveryTestSuite) TestRecordComponentFailureIsPerComponent() {
+	for i := 1; i < ComponentFailureThreshold; i++ {
+		suite.False(recordComponentFailure(suite.owningComponent.Code))
+	}
+
+	suite.False(recordComponentFailure("another_component"))
+}
+
+func (suite *PanicRecoveryTestSuite) TestRecoverFromPanic() {
+	reference := recoverFromPanic(suite.owningComponent, "something really bad happened", "test")
+
+	suite.Len(reference, 8)
+	suite.Len(componentFailures.failures[suite.owningComponent.Code], 1)
+	suite.loggerMock.AssertCalled(suite.T(), "Err", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *PanicRecoveryTestSuite) TestRecoverFromPanicDoesNotDisableCoreComponent() {
+	for i := 0; i < ComponentFailureThreshold; i++ {
+		recoverFromPanic(suite.owningComponent, "something really bad happened", "test")
+	}
+
+	suite.loggerMock.AssertCalled(suite.T(), "Warn", mock.Anything, mock.Anything)
+}
+
+func (suite *PanicRecoveryTestSuite) TestWrapWithComponentStatusHandlerRecovers() {
+	container := suite.owningComponent.HandlerManager().(*ComponentHandlerContainer)
+	handlerName := GetHandlerName(suite.owningComponent.Code, "panicking_handler")
+	assignedEvent := &AssignedEventHandler{
+		name:      handlerName,
+		component: suite.owningComponent,
+		handler: func(_ *discordgo.Session, _ *discordgo.MessageCreate) {
+			panic("something really bad happened")
+		},
+	}
+	container.addComponentHandler(handlerName, assignedEvent)
+	defer removeComponentHandler(handlerName)
+
+	container.wrapWithComponentStatusHandler(handlerName)
+
+	suite.NotPanics(func() {
+		assignedEvent.handler.(func(*discordgo.Session, interface{}))(nil, &discordgo.MessageCreate{
+			Message: &discordgo.Message{GuildID: "1"},
+		})
+	})
+	suite.Len(componentFailures.failures[suite.owningComponent.Code], 1)
+}
+
+func (suite *PanicRecoveryTestSuite) TestHandleAutocompleteDispatchRecovers() {
+	previousCommandMap := componentCommandMap
+	defer func() {
+		componentCommandMap = previousCommandMap
+	}()
+
+	componentCommandMap = map[string]*Command{
+		"a": {
+			Cmd: &discordgo.ApplicationCommand{Name: "a"},
+			Autocomplete: func(_ *discordgo.Session, _ *discordgo.InteractionCreate) {
+				panic("something really bad happened")
+			},
+			c: suite.owningComponent,
+		},
+	}
+
+	s, r := discordgo_mock.MockSession()
+	interactionResponse := &discordgo.InteractionResponse{}
+	r.OnRequestCaptureResult(http.MethodPost, interactionResponse).Once().Return(
+		&http.Response{
+			StatusCode: http.StatusNoContent,
+		}, nil)
+
+	suite.NotPanics(func() {
+		handleCommandDispatch(s, suite.createInteraction(
+			discordgo.InteractionApplicationCommandAutocomplete,
+			discordgo.ApplicationCommandInteractionData{Name: "a"}))
+	})
+	suite.Len(componentFailures.failures[suite.owningComponent.Code], 1)
+	r.AssertExpectations(suite.T())
+	suite.Equal(discordgo.InteractionApplicationCommandAutocompleteResult, interactionResponse.Type)
+	suite.Empty(interactionResponse.Data.Choices)
+}
+
+func (suite *PanicRecoveryTestSuite) TestHandleComponentInteractionDispatchRecovers() {
+	previousInteractionMap := componentInteractionMap
+	defer func() {
+		componentInteractionMap = previousInteractionMap
+	}()
+	componentInteractionMap = make(map[string]*componentInteraction)
+
+	err := suite.owningComponent.ComponentInteractionManager().Register("confirm",
+		func(_ *discordgo.Session, _ *discordgo.InteractionCreate, _ string) {
+			panic("something really bad happened")
+		})
+	suite.NoError(err)
+
+	s, r := discordgo_mock.MockSession()
+	interactionResponse := &discordgo.InteractionResponse{}
+	r.OnRequestCaptureResult(http.MethodPost, interactionResponse).Once().Return(
+		&http.Response{
+			StatusCode: http.StatusNoContent,
+		}, nil)
+
+	suite.NotPanics(func() {
+		handleCommandDispatch(s, suite.createInteraction(
+			discordgo.InteractionMessageComponent,
+			discordgo.MessageComponentInteractionData{CustomID: "bot_test_component:confirm"}))
+	})
+	suite.Len(componentFailures.failures[suite.owningComponent.Code], 1)
+	r.AssertExpectations(suite.T())
+	suite.Equal(discordgo.MessageFlagsEphemeral, interactionResponse.Data.Flags)
+}
+
+func (suite *PanicRecoveryTestSuite) TestHandleModalSubmitDispatchRecovers() {
+	previousModalHandlerMap := modalHandlerMap
+	defer func() {
+		modalHandlerMap = previousModalHandlerMap
+	}()
+	modalHandlerMap = make(map[string]*modalHandler)
+
+	err := suite.owningComponent.ModalManager().Register("setup",
+		func(_ *discordgo.Session, _ *discordgo.InteractionCreate, _ string) {
+			panic("something really bad happened")
+		})
+	suite.NoError(err)
+
+	s, r := discordgo_mock.MockSession()
+	interactionResponse := &discordgo.InteractionResponse{}
+	r.OnRequestCaptureResult(http.MethodPost, interactionResponse).Once().Return(
+		&http.Response{
+			StatusCode: http.StatusNoContent,
+		}, nil)
+
+	suite.NotPanics(func() {
+		handleCommandDispatch(s, suite.createInteraction(
+			discordgo.InteractionModalSubmit,
+			discordgo.ModalSubmitInteractionData{CustomID: "bot_test_component:setup"}))
+	})
+	suite.Len(componentFailures.failures[suite.owningComponent.Code], 1)
+	r.AssertExpectations(suite.T())
+	suite.Equal(discordgo.MessageFlagsEphemeral, interactionResponse.Data.Flags)
+}
+
+// createInteraction creates an interaction of the passed type with the passed data.
+func (suite *PanicRecoveryTestSuite) createInteraction(
+	interactionType discordgo.InteractionType,
+	data discordgo.InteractionData,
+) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:    "interaction.ID",
+			Token: "interaction.Token",
+			Type:  interactionType,
+			Data:  data,
+			User:  &discordgo.User{ID: "42"},
+		},
+	}
+}
+
+func TestPanicRecovery(t *testing.T) {
+	suite.Run(t, new(PanicRecoveryTestSuite))
+}
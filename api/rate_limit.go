@@ -0,0 +1,244 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/services/cache"
+	"time"
+)
+
+// RateLimitStrategy specifies the algorithm used by a RateLimit.
+type RateLimitStrategy string
+
+// Available rate limit strategies
+const (
+	// RateLimitStrategyFixedWindow allows Limit usages within consecutive
+	// windows of the configured length.
+	RateLimitStrategyFixedWindow RateLimitStrategy = "fixed_window"
+	// RateLimitStrategyTokenBucket allows bursts of up to Limit usages.
+	// Used tokens are continuously refilled, so that the bucket
+	// is full again after the configured window.
+	RateLimitStrategyTokenBucket RateLimitStrategy = "token_bucket"
+)
+
+// RateLimitScope specifies who shares a RateLimit.
+type RateLimitScope string
+
+// Available rate limit scopes
+const (
+	RateLimitScopeUser    RateLimitScope = "user"
+	RateLimitScopeGuild   RateLimitScope = "guild"
+	RateLimitScopeChannel RateLimitScope = "channel"
+	RateLimitScopeGlobal  RateLimitScope = "global"
+)
+
+// RateLimit describes how often something can be used within a period of time.
+//
+// The state of rate limits is stored using the atomic counters of the cache service,
+// which allows to share rate limits between multiple instances that use the same Redis cache.
+// The state expires after the Window, independent of the lifetime of the cache.
+//
+// A RateLimit with a non-positive Limit or Window never limits.
+type RateLimit struct {
+	// Name identifies the rate limit in the cache.
+	// It is set to the command name automatically, when the RateLimit
+	// is declared on a Command without a name.
+	Name     string
+	Strategy RateLimitStrategy
+	Scope    RateLimitScope
+	Limit    int
+	Window   time.Duration
+}
+
+// rateLimitNow returns the current time and can be replaced in tests.
+var rateLimitNow = time.Now
+
+// Take tries to use the rate limit for the scope derived from the passed interaction.
+// It returns whether the usage is allowed and, if not, how long
+// it takes until the rate limit allows the next usage.
+func (rl *RateLimit) Take(i *discordgo.InteractionCreate) (bool, time.Duration) {
+	return rl.TakeKey(GetRateLimitScopeKey(rl.Scope, i))
+}
+
+// TakeKey tries to use the rate limit for the passed scope key.
+// It returns whether the usage is allowed and, if not, how long
+// it takes until the rate limit allows the next usage.
+//
+// If the state of the rate limit cannot be updated, the usage is allowed.
+func (rl *RateLimit) TakeKey(scopeKey string) (bool, time.Duration) {
+	if rl.Limit <= 0 || rl.Window <= 0 {
+		return true, 0
+	}
+
+	now := rateLimitNow()
+
+	var allowed bool
+	var retryAfter time.Duration
+	var err error
+	switch rl.Strategy {
+	case RateLimitStrategyTokenBucket:
+		allowed, retryAfter, err = cache.TakeToken(rl.getCacheKey(scopeKey), int64(rl.Limit), rl.Window, now)
+	default:
+		allowed, retryAfter, err = rl.takeFixedWindow(scopeKey, now)
+	}
+
+	if nil != err {
+		slashCommandManagerLogger.Err(err, "Failed to update the state of the rate limit \"%s\"!",
+			rl.getCacheKey(scopeKey))
+
+		return true, 0
+	}
+
+	return allowed, retryAfter
+}
+
+// Reset removes the state of the rate limit for the passed scope key.
+func (rl *RateLimit) Reset(scopeKey string) {
+	cache.Delete(rl.getCacheKey(scopeKey))
+	cache.Delete(rl.getWindowCacheKey(scopeKey, rl.getWindowIndex(rateLimitNow())))
+}
+
+// takeFixedWindow applies the fixed window strategy.
+// Every window has its own counter, which expires at the end of the window.
+func (rl *RateLimit) takeFixedWindow(scopeKey string, now time.Time) (bool, time.Duration, error) {
+	windowIndex := rl.getWindowIndex(now)
+	windowEnd := time.Unix(0, (windowIndex+1)*rl.Window.Nanoseconds())
+
+	count, err := cache.Increment(rl.getWindowCacheKey(scopeKey, windowIndex), windowEnd.Sub(now))
+	if nil != err {
+		return false, 0, err
+	}
+
+	if count > int64(rl.Limit) {
+		return false, windowEnd.Sub(now), nil
+	}
+
+	return true, 0, nil
+}
+
+// getWindowIndex returns the index of the fixed window the passed time belongs to.
+func (rl *RateLimit) getWindowIndex(now time.Time) int64 {
+	return now.UnixNano() / rl.Window.Nanoseconds()
+}
+
+// getCacheKey returns the cache key used to store the state
+// of the rate limit for the passed scope key.
+func (rl *RateLimit) getCacheKey(scopeKey string) string {
+	return fmt.Sprintf("rate_limit_%s_%s_%s", rl.Name, rl.Scope, scopeKey)
+}
+
+// getWindowCacheKey returns the cache key used to store the usages
+// of the passed fixed window for the passed scope key.
+func (rl *RateLimit) getWindowCacheKey(scopeKey string, windowIndex int64) string {
+	return fmt.Sprintf("%s_%d", rl.getCacheKey(scopeKey), windowIndex)
+}
+
+// GetRateLimitScopeKey returns the key that identifies the passed scope
+// for the passed interaction. For example, the id of the user is returned
+// for RateLimitScopeUser.
+func GetRateLimitScopeKey(scope RateLimitScope, i *discordgo.InteractionCreate) string {
+	switch scope {
+	case RateLimitScopeUser:
+		return getInteractionUser(i).ID
+	case RateLimitScopeGuild:
+		return i.GuildID
+	case RateLimitScopeChannel:
+		return i.ChannelID
+	default:
+		return "global"
+	}
+}
+
+// CommandRateLimitMiddleware applies the RateLimit declared on the command, if there is one.
+// When the rate limit is exceeded, the user is asked to slow down.
+func CommandRateLimitMiddleware(command *Command, next CommandHandlerFunc) CommandHandlerFunc {
+	if nil == command.RateLimit {
+		return next
+	}
+
+	rateLimit := *command.RateLimit
+	if "" == rateLimit.Name {
+		rateLimit.Name = getCommandKey(command.Cmd.Type, command.Cmd.Name)
+	}
+
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		allowed, retryAfter := rateLimit.Take(i)
+		if !allowed {
+			respondWithRateLimited(s, i, command.c, retryAfter)
+
+			return
+		}
+
+		next(s, i)
+	}
+}
+
+// NewRateLimitedEmbedField creates the standard embed field that asks
+// users to slow down, because they hit a rate limit.
+func NewRateLimitedEmbedField(retryAfter time.Duration) *discordgo.MessageEmbedField {
+	return &discordgo.MessageEmbedField{
+		Name: ":x: Slow down my friend!",
+		Value: fmt.Sprintf("You are doing this too often! Please try again in %s.",
+			formatRetryAfter(retryAfter)),
+	}
+}
+
+// respondWithRateLimited responds to the passed interaction with
+// an ephemeral embed that holds the standard rate limit embed field.
+func respondWithRateLimited(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	c *Component,
+	retryAfter time.Duration,
+) {
+	_, err := RespondToInteraction(s, i, &discordgo.InteractionResponseData{
+		Flags: discordgo.MessageFlagsEphemeral,
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title:  "JOJO Discord Bot",
+				Color:  DefaultEmbedColor,
+				Fields: []*discordgo.MessageEmbedField{NewRateLimitedEmbedField(retryAfter)},
+			},
+		},
+	})
+
+	if nil != err {
+		c.Logger().Err(err, "Failed to deliver interaction response!")
+	}
+}
+
+// formatRetryAfter formats the passed duration for humans.
+// The duration is rounded up to full seconds.
+func formatRetryAfter(retryAfter time.Duration) string {
+	if remainder := retryAfter % time.Second; 0 != remainder {
+		retryAfter += time.Second - remainder
+	}
+
+	if retryAfter <= time.Second {
+		return "1 second"
+	}
+
+	if retryAfter < time.Minute {
+		return fmt.Sprintf("%d seconds", int(retryAfter.Seconds()))
+	}
+
+	return retryAfter.String()
+}
@@ -0,0 +1,190 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/services/cache"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type RateLimitTestSuite struct {
+	suite.Suite
+	now time.Time
+}
+
+func (suite *RateLimitTestSuite) SetupTest() {
+	err := cache.Init(cache.ModeMemory, 10*time.Minute, "")
+	suite.NoError(err)
+
+	suite.now = time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	rateLimitNow = func() time.Time {
+		return suite.now
+	}
+}
+
+func (suite *RateLimitTestSuite) TearDownTest() {
+	rateLimitNow = time.Now
+}
+
+func (suite *RateLimitTestSuite) TestFixedWindow() {
+	rateLimit := &RateLimit{
+		Name:     "fixed",
+		Strategy: RateLimitStrategyFixedWindow,
+		Scope:    RateLimitScopeUser,
+		Limit:    2,
+		Window:   time.Minute,
+	}
+
+	allowed, _ := rateLimit.TakeKey("42")
+	suite.True(allowed)
+
+	suite.now = suite.now.Add(10 * time.Second)
+	allowed, _ = rateLimit.TakeKey("42")
+	suite.True(allowed)
+
+	allowed, retryAfter := rateLimit.TakeKey("42")
+	suite.False(allowed)
+	suite.Equal(50*time.Second, retryAfter)
+
+	allowed, _ = rateLimit.TakeKey("43")
+	suite.True(allowed)
+
+	suite.now = suite.now.Add(50 * time.Second)
+	allowed, _ = rateLimit.TakeKey("42")
+	suite.True(allowed)
+}
+
+func (suite *RateLimitTestSuite) TestTokenBucket() {
+	rateLimit := &RateLimit{
+		Name:     "bucket",
+		Strategy: RateLimitStrategyTokenBucket,
+		Scope:    RateLimitScopeGuild,
+		Limit:    3,
+		Window:   30 * time.Second,
+	}
+
+	for idx := 0; idx < 3; idx++ {
+		allowed, _ := rateLimit.TakeKey("42")
+		suite.True(allowed)
+	}
+
+	allowed, retryAfter := rateLimit.TakeKey("42")
+	suite.False(allowed)
+	suite.Equal(10*time.Second, retryAfter)
+
+	suite.now = suite.now.Add(10 * time.Second)
+	allowed, _ = rateLimit.TakeKey("42")
+	suite.True(allowed)
+
+	allowed, _ = rateLimit.TakeKey("42")
+	suite.False(allowed)
+}
+
+func (suite *RateLimitTestSuite) TestWindowLongerThanCacheLifetime() {
+	for _, strategy := range []RateLimitStrategy{RateLimitStrategyFixedWindow, RateLimitStrategyTokenBucket} {
+		rateLimit := &RateLimit{
+			Name:     "long_" + string(strategy),
+			Strategy: strategy,
+			Scope:    RateLimitScopeGuild,
+			Limit:    1,
+			Window:   time.Hour,
+		}
+
+		allowed, _ := rateLimit.TakeKey("42")
+		suite.True(allowed)
+
+		suite.now = suite.now.Add(30 * time.Minute)
+		allowed, retryAfter := rateLimit.TakeKey("42")
+		suite.False(allowed, "Strategy: %s", strategy)
+		suite.Equal(30*time.Minute, retryAfter, "Strategy: %s", strategy)
+
+		suite.now = suite.now.Add(30 * time.Minute)
+		allowed, _ = rateLimit.TakeKey("42")
+		suite.True(allowed, "Strategy: %s", strategy)
+
+		suite.now = time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	}
+}
+
+func (suite *RateLimitTestSuite) TestReset() {
+	rateLimit := &RateLimit{
+		Name:   "reset",
+		Scope:  RateLimitScopeGlobal,
+		Limit:  1,
+		Window: time.Minute,
+	}
+
+	allowed, _ := rateLimit.TakeKey("global")
+	suite.True(allowed)
+	allowed, _ = rateLimit.TakeKey("global")
+	suite.False(allowed)
+
+	rateLimit.Reset("global")
+
+	allowed, _ = rateLimit.TakeKey("global")
+	suite.True(allowed)
+}
+
+func (suite *RateLimitTestSuite) TestWithoutLimit() {
+	rateLimit := &RateLimit{Name: "unlimited", Scope: RateLimitScopeUser}
+
+	for idx := 0; idx < 5; idx++ {
+		allowed, _ := rateLimit.TakeKey("42")
+		suite.True(allowed)
+	}
+}
+
+func (suite *RateLimitTestSuite) TestGetRateLimitScopeKey() {
+	interaction := &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			GuildID:   "1",
+			ChannelID: "2",
+			Member:    &discordgo.Member{User: &discordgo.User{ID: "3"}},
+		},
+	}
+
+	suite.Equal("3", GetRateLimitScopeKey(RateLimitScopeUser, interaction))
+	suite.Equal("1", GetRateLimitScopeKey(RateLimitScopeGuild, interaction))
+	suite.Equal("2", GetRateLimitScopeKey(RateLimitScopeChannel, interaction))
+	suite.Equal("global", GetRateLimitScopeKey(RateLimitScopeGlobal, interaction))
+}
+
+func (suite *RateLimitTestSuite) TestFormatRetryAfter() {
+	tables := []struct {
+		input    time.Duration
+		expected string
+	}{
+		{0, "1 second"},
+		{500 * time.Millisecond, "1 second"},
+		{10 * time.Second, "10 seconds"},
+		{10*time.Second + time.Millisecond, "11 seconds"},
+		{90 * time.Second, "1m30s"},
+	}
+
+	for _, table := range tables {
+		suite.Equal(table.expected, formatRetryAfter(table.input))
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	suite.Run(t, new(RateLimitTestSuite))
+}
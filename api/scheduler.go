@@ -0,0 +1,441 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Jobs are executed in their own goroutine. Every scheduled job is driven
+// by a goroutine that waits for the next execution, which allows to cancel
+// jobs at any time. Jobs of a component are cancelled when the component is unloaded
+// and are not executed while the component is globally disabled.
+
+// JobHandler is the function that is executed when a Job is due.
+// A returned error is logged and visible in the JobStatus.
+type JobHandler func() error
+
+// Job describes a job that is executed by the Scheduler.
+//
+// A job is either executed periodically (Interval or Cron)
+// or once after the configured Delay, when neither Interval nor Cron are set.
+type Job struct {
+	// Name identifies the job within the owning component.
+	Name string
+	// Interval executes the job periodically with the passed interval.
+	Interval time.Duration
+	// Cron executes the job according to the passed cron expression.
+	// See parseCronExpression for the supported syntax.
+	// Cron expressions are evaluated in the local time zone.
+	Cron string
+	// Delay executes the job once after the passed delay.
+	// It is ignored, when Interval or Cron are set.
+	Delay time.Duration
+	// Jitter delays every execution by a random duration up to the passed jitter.
+	// This prevents many jobs from being executed at the exact same time.
+	Jitter time.Duration
+	// RunImmediately executes periodic jobs once when they are scheduled,
+	// instead of waiting for the first interval to pass.
+	RunImmediately bool
+	// Handler is the function that is executed when the job is due.
+	// When the previous execution is still running, the execution is skipped.
+	Handler JobHandler
+}
+
+// JobStatus holds information about the current state of a scheduled job.
+type JobStatus struct {
+	Component entities.ComponentCode
+	Name      string
+	// Schedule is a readable representation of when the job is executed.
+	Schedule string
+	Running  bool
+	// Runs is the number of finished executions.
+	Runs int
+	// Skipped is the number of executions that have been skipped,
+	// because the previous execution was still running.
+	Skipped   int
+	LastRun   time.Time
+	NextRun   time.Time
+	LastError string
+}
+
+// Scheduler is the interface that allows components to schedule jobs
+// that are executed periodically or once after a delay.
+//
+// Jobs scheduled through the Scheduler are cancelled when the component is unloaded.
+type Scheduler interface {
+	// Schedule schedules the passed job.
+	// The job name must be unique within the component.
+	Schedule(job Job) error
+	// Cancel cancels the scheduled job with the passed name.
+	// A currently running execution of the job is not interrupted.
+	Cancel(name string) error
+	// CancelAll cancels all jobs scheduled by the component.
+	CancelAll()
+	// Jobs returns the JobStatus of all jobs scheduled by the component.
+	Jobs() []JobStatus
+}
+
+// ComponentScheduler is the Scheduler implementation
+// that is bound to a specific component.
+type ComponentScheduler struct {
+	owner *Component
+}
+
+// scheduledJob is a job that has been scheduled and holds
+// the state of its executions.
+type scheduledJob struct {
+	mu        sync.Mutex
+	job       Job
+	component *Component
+	cron      *cronSchedule
+	stop      chan struct{}
+
+	running   bool
+	runs      int
+	skipped   int
+	lastRun   time.Time
+	nextRun   time.Time
+	lastError error
+}
+
+// scheduledJobMap holds all scheduled jobs.
+// The key of the map is computed using getScheduledJobKey.
+type scheduledJobMap struct {
+	sync.RWMutex
+	jobs map[string]*scheduledJob
+}
+
+// scheduledJobs holds all currently scheduled jobs of all components.
+var scheduledJobs = scheduledJobMap{
+	jobs: make(map[string]*scheduledJob),
+}
+
+// Scheduler returns the Scheduler of the component,
+// which allows to schedule periodic and one-off jobs.
+//
+// On first call, this function initializes the private Component.scheduler
+// field. On consecutive calls, the already present Scheduler will be used.
+func (c *Component) Scheduler() Scheduler {
+	if nil == c.scheduler {
+		c.scheduler = &ComponentScheduler{owner: c}
+	}
+
+	return c.scheduler
+}
+
+// Schedule schedules the passed job.
+// The job name must be unique within the component.
+func (cs *ComponentScheduler) Schedule(job Job) error {
+	sj, err := cs.newScheduledJob(job)
+	if nil != err {
+		return err
+	}
+
+	key := getScheduledJobKey(cs.owner.Code, job.Name)
+
+	scheduledJobs.Lock()
+	if _, ok := scheduledJobs.jobs[key]; ok {
+		scheduledJobs.Unlock()
+
+		return fmt.Errorf("the job \"%s\" of component \"%s\" is already scheduled", job.Name, cs.owner.Code)
+	}
+	scheduledJobs.jobs[key] = sj
+	scheduledJobs.Unlock()
+
+	if sj.isPeriodic() && job.RunImmediately {
+		sj.execute()
+	}
+
+	go sj.loop()
+
+	cs.owner.Logger().Info("Job \"%s\" has been scheduled (%s)!", job.Name, sj.describeSchedule())
+
+	return nil
+}
+
+// newScheduledJob validates the passed job and creates a scheduledJob for it.
+func (cs *ComponentScheduler) newScheduledJob(job Job) (*scheduledJob, error) {
+	if "" == job.Name {
+		return nil, errors.New("cannot schedule a job without a name")
+	}
+
+	if nil == job.Handler {
+		return nil, fmt.Errorf("cannot schedule the job \"%s\" without a handler", job.Name)
+	}
+
+	if "" != job.Cron && 0 != job.Interval {
+		return nil, fmt.Errorf("the job \"%s\" cannot have both an interval and a cron expression", job.Name)
+	}
+
+	if job.Interval < 0 || job.Delay < 0 || job.Jitter < 0 {
+		return nil, fmt.Errorf("the job \"%s\" cannot have a negative interval, delay or jitter", job.Name)
+	}
+
+	sj := &scheduledJob{
+		job:       job,
+		component: cs.owner,
+		stop:      make(chan struct{}),
+	}
+
+	if "" != job.Cron {
+		var err error
+		sj.cron, err = parseCronExpression(job.Cron)
+		if nil != err {
+			return nil, err
+		}
+	}
+
+	return sj, nil
+}
+
+// Cancel cancels the scheduled job with the passed name.
+// A currently running execution of the job is not interrupted.
+func (cs *ComponentScheduler) Cancel(name string) error {
+	key := getScheduledJobKey(cs.owner.Code, name)
+
+	scheduledJobs.Lock()
+	defer scheduledJobs.Unlock()
+
+	sj, ok := scheduledJobs.jobs[key]
+	if !ok {
+		return fmt.Errorf("there is no job called \"%s\" scheduled that could be cancelled", name)
+	}
+
+	delete(scheduledJobs.jobs, key)
+	close(sj.stop)
+
+	cs.owner.Logger().Info("Job \"%s\" has been cancelled!", name)
+
+	return nil
+}
+
+// CancelAll cancels all jobs scheduled by the component.
+func (cs *ComponentScheduler) CancelAll() {
+	for _, status := range cs.Jobs() {
+		_ = cs.Cancel(status.Name)
+	}
+}
+
+// Jobs returns the JobStatus of all jobs scheduled by the component.
+func (cs *ComponentScheduler) Jobs() []JobStatus {
+	jobs := make([]JobStatus, 0)
+	for _, status := range GetScheduledJobs() {
+		if status.Component == cs.owner.Code {
+			jobs = append(jobs, status)
+		}
+	}
+
+	return jobs
+}
+
+// GetScheduledJobs returns the JobStatus of all scheduled jobs
+// of all components, ordered by component and name.
+func GetScheduledJobs() []JobStatus {
+	scheduledJobs.RLock()
+	jobs := make([]JobStatus, 0, len(scheduledJobs.jobs))
+	for _, sj := range scheduledJobs.jobs {
+		jobs = append(jobs, sj.status())
+	}
+	scheduledJobs.RUnlock()
+
+	sort.SliceStable(jobs, func(i, j int) bool {
+		if jobs[i].Component != jobs[j].Component {
+			return jobs[i].Component < jobs[j].Component
+		}
+
+		return jobs[i].Name < jobs[j].Name
+	})
+
+	return jobs
+}
+
+// getScheduledJobKey returns the key of a job in the scheduledJobMap.
+func getScheduledJobKey(code entities.ComponentCode, name string) string {
+	return fmt.Sprintf("%s:%s", code, name)
+}
+
+// loop waits for the next executions of the job until it is cancelled.
+// One-off jobs are removed after they have been executed.
+func (sj *scheduledJob) loop() {
+	for {
+		nextRun := sj.computeNextRun(time.Now())
+		if nextRun.IsZero() {
+			sj.component.Logger().Warn("Job \"%s\" will never be executed again!", sj.job.Name)
+			sj.remove()
+
+			return
+		}
+
+		sj.mu.Lock()
+		sj.nextRun = nextRun
+		sj.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(nextRun))
+		select {
+		case <-sj.stop:
+			timer.Stop()
+
+			return
+		case <-timer.C:
+		}
+
+		sj.execute()
+
+		if !sj.isPeriodic() {
+			sj.remove()
+
+			return
+		}
+	}
+}
+
+// computeNextRun computes the time of the next execution of the job,
+// including a random jitter.
+// The zero time is returned, when the job will never be executed again.
+func (sj *scheduledJob) computeNextRun(now time.Time) time.Time {
+	var nextRun time.Time
+	switch {
+	case nil != sj.cron:
+		nextRun = sj.cron.next(now)
+		if nextRun.IsZero() {
+			return nextRun
+		}
+	case 0 != sj.job.Interval:
+		nextRun = now.Add(sj.job.Interval)
+	default:
+		nextRun = now.Add(sj.job.Delay)
+	}
+
+	if sj.job.Jitter > 0 {
+		nextRun = nextRun.Add(rand.N(sj.job.Jitter))
+	}
+
+	return nextRun
+}
+
+// execute starts an execution of the job in its own goroutine.
+// The execution is skipped, when the previous execution is still running
+// or the owning component is globally disabled.
+func (sj *scheduledJob) execute() {
+	if !IsComponentEnabled(sj.component, "") {
+		return
+	}
+
+	sj.mu.Lock()
+	defer sj.mu.Unlock()
+
+	if sj.running {
+		sj.skipped++
+		sj.component.Logger().Warn(
+			"Skipped execution of job \"%s\", as the previous execution is still running!",
+			sj.job.Name)
+
+		return
+	}
+
+	sj.running = true
+	go sj.run()
+}
+
+// run executes the handler of the job and records the result.
+// Panics are recovered and count as failure of the owning component.
+func (sj *scheduledJob) run() {
+	startedAt := time.Now()
+	var err error
+
+	defer func() {
+		if r := recover(); nil != r {
+			reference := recoverFromPanic(sj.component, r, fmt.Sprintf("job \"%s\"", sj.job.Name))
+			err = fmt.Errorf("the job panicked (reference: %s)", reference)
+		}
+
+		sj.mu.Lock()
+		defer sj.mu.Unlock()
+
+		sj.running = false
+		sj.runs++
+		sj.lastRun = startedAt
+		sj.lastError = err
+	}()
+
+	err = sj.job.Handler()
+	if nil != err {
+		sj.component.Logger().Err(err, "Execution of job \"%s\" failed!", sj.job.Name)
+	}
+}
+
+// remove removes the job from the scheduled jobs,
+// unless it has already been cancelled or replaced.
+func (sj *scheduledJob) remove() {
+	key := getScheduledJobKey(sj.component.Code, sj.job.Name)
+
+	scheduledJobs.Lock()
+	defer scheduledJobs.Unlock()
+
+	if scheduledJobs.jobs[key] == sj {
+		delete(scheduledJobs.jobs, key)
+	}
+}
+
+// isPeriodic checks whether the job is executed periodically.
+func (sj *scheduledJob) isPeriodic() bool {
+	return nil != sj.cron || 0 != sj.job.Interval
+}
+
+// describeSchedule returns a readable representation of when the job is executed.
+func (sj *scheduledJob) describeSchedule() string {
+	switch {
+	case nil != sj.cron:
+		return fmt.Sprintf("cron \"%s\"", sj.job.Cron)
+	case 0 != sj.job.Interval:
+		return fmt.Sprintf("every %v", sj.job.Interval)
+	default:
+		return fmt.Sprintf("once after %v", sj.job.Delay)
+	}
+}
+
+// status returns the current JobStatus of the job.
+func (sj *scheduledJob) status() JobStatus {
+	sj.mu.Lock()
+	defer sj.mu.Unlock()
+
+	status := JobStatus{
+		Component: sj.component.Code,
+		Name:      sj.job.Name,
+		Schedule:  sj.describeSchedule(),
+		Running:   sj.running,
+		Runs:      sj.runs,
+		Skipped:   sj.skipped,
+		LastRun:   sj.lastRun,
+		NextRun:   sj.nextRun,
+	}
+
+	if nil != sj.lastError {
+		status.LastError = sj.lastError.Error()
+	}
+
+	return status
+}
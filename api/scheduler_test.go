@@ -0,0 +1,235 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"errors"
+	"github.com/lazybytez/jojo-discord-bot/test/logmock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type SchedulerTestSuite struct {
+	suite.Suite
+	owningComponent *Component
+	scheduler       Scheduler
+}
+
+func (suite *SchedulerTestSuite) SetupTest() {
+	loggerMock := &logmock.LoggerMock{}
+	loggerMock.On("Info", mock.Anything, mock.Anything)
+	loggerMock.On("Warn", mock.Anything, mock.Anything)
+	loggerMock.On("Err", mock.Anything, mock.Anything, mock.Anything)
+
+	suite.owningComponent = &Component{
+		Code:   "bot_test_component",
+		Name:   "Test Component",
+		logger: loggerMock,
+	}
+	suite.scheduler = suite.owningComponent.Scheduler()
+}
+
+func (suite *SchedulerTestSuite) TearDownTest() {
+	suite.scheduler.CancelAll()
+}
+
+func (suite *SchedulerTestSuite) TestScheduleWithInvalidJobs() {
+	handler := func() error { return nil }
+
+	suite.Error(suite.scheduler.Schedule(Job{Handler: handler}))
+	suite.Error(suite.scheduler.Schedule(Job{Name: "test"}))
+	suite.Error(suite.scheduler.Schedule(Job{Name: "test", Interval: time.Minute, Cron: "* * * * *", Handler: handler}))
+	suite.Error(suite.scheduler.Schedule(Job{Name: "test", Interval: -time.Minute, Handler: handler}))
+	suite.Error(suite.scheduler.Schedule(Job{Name: "test", Cron: "invalid", Handler: handler}))
+	suite.Empty(suite.scheduler.Jobs())
+}
+
+func (suite *SchedulerTestSuite) TestScheduleTwice() {
+	job := Job{Name: "test", Interval: time.Hour, Handler: func() error { return nil }}
+
+	suite.NoError(suite.scheduler.Schedule(job))
+	suite.Error(suite.scheduler.Schedule(job))
+}
+
+func (suite *SchedulerTestSuite) TestScheduleInterval() {
+	var runs atomic.Int32
+	suite.NoError(suite.scheduler.Schedule(Job{
+		Name:     "test",
+		Interval: 5 * time.Millisecond,
+		Handler: func() error {
+			runs.Add(1)
+
+			return nil
+		},
+	}))
+
+	suite.Eventually(func() bool {
+		return runs.Load() >= 3
+	}, time.Second, time.Millisecond)
+
+	jobs := suite.scheduler.Jobs()
+	suite.Len(jobs, 1)
+	suite.Equal("every 5ms", jobs[0].Schedule)
+	suite.False(jobs[0].NextRun.IsZero())
+}
+
+func (suite *SchedulerTestSuite) TestScheduleRunImmediately() {
+	var runs atomic.Int32
+	suite.NoError(suite.scheduler.Schedule(Job{
+		Name:           "test",
+		Interval:       time.Hour,
+		RunImmediately: true,
+		Handler: func() error {
+			runs.Add(1)
+
+			return nil
+		},
+	}))
+
+	suite.Eventually(func() bool {
+		return 1 == runs.Load()
+	}, time.Second, time.Millisecond)
+}
+
+func (suite *SchedulerTestSuite) TestScheduleOneOff() {
+	var runs atomic.Int32
+	suite.NoError(suite.scheduler.Schedule(Job{
+		Name:  "test",
+		Delay: 5 * time.Millisecond,
+		Handler: func() error {
+			runs.Add(1)
+
+			return nil
+		},
+	}))
+
+	suite.Equal("once after 5ms", suite.scheduler.Jobs()[0].Schedule)
+	suite.Eventually(func() bool {
+		return 1 == runs.Load() && 0 == len(suite.scheduler.Jobs())
+	}, time.Second, time.Millisecond)
+}
+
+func (suite *SchedulerTestSuite) TestScheduleSkipsOverlappingExecutions() {
+	release := make(chan struct{})
+	var runs atomic.Int32
+	suite.NoError(suite.scheduler.Schedule(Job{
+		Name:     "test",
+		Interval: 2 * time.Millisecond,
+		Handler: func() error {
+			runs.Add(1)
+			<-release
+
+			return nil
+		},
+	}))
+
+	suite.Eventually(func() bool {
+		jobs := suite.scheduler.Jobs()
+
+		return 1 == len(jobs) && jobs[0].Skipped >= 2
+	}, time.Second, time.Millisecond)
+	suite.Equal(int32(1), runs.Load())
+	suite.True(suite.scheduler.Jobs()[0].Running)
+
+	close(release)
+}
+
+func (suite *SchedulerTestSuite) TestScheduleRecordsErrors() {
+	suite.NoError(suite.scheduler.Schedule(Job{
+		Name:     "test",
+		Interval: time.Hour,
+		Handler: func() error {
+			return errors.New("something went wrong")
+		},
+		RunImmediately: true,
+	}))
+
+	suite.Eventually(func() bool {
+		jobs := suite.scheduler.Jobs()
+
+		return 1 == jobs[0].Runs && "something went wrong" == jobs[0].LastError
+	}, time.Second, time.Millisecond)
+}
+
+func (suite *SchedulerTestSuite) TestScheduleRecoversFromPanic() {
+	suite.NoError(suite.scheduler.Schedule(Job{
+		Name:     "test",
+		Interval: time.Hour,
+		Handler: func() error {
+			panic("something went wrong")
+		},
+		RunImmediately: true,
+	}))
+
+	suite.Eventually(func() bool {
+		jobs := suite.scheduler.Jobs()
+
+		return 1 == jobs[0].Runs && "" != jobs[0].LastError && !jobs[0].Running
+	}, time.Second, time.Millisecond)
+}
+
+func (suite *SchedulerTestSuite) TestCancel() {
+	var runs atomic.Int32
+	suite.NoError(suite.scheduler.Schedule(Job{
+		Name:  "test",
+		Delay: 20 * time.Millisecond,
+		Handler: func() error {
+			runs.Add(1)
+
+			return nil
+		},
+	}))
+
+	suite.NoError(suite.scheduler.Cancel("test"))
+	suite.Error(suite.scheduler.Cancel("test"))
+
+	time.Sleep(40 * time.Millisecond)
+	suite.Equal(int32(0), runs.Load())
+	suite.Empty(suite.scheduler.Jobs())
+}
+
+func (suite *SchedulerTestSuite) TestCancelAllOnlyCancelsOwnJobs() {
+	otherScheduler := (&Component{Code: "bot_other_component", logger: suite.owningComponent.logger}).Scheduler()
+	handler := func() error { return nil }
+
+	suite.NoError(suite.scheduler.Schedule(Job{Name: "first", Interval: time.Hour, Handler: handler}))
+	suite.NoError(suite.scheduler.Schedule(Job{Name: "second", Cron: "@daily", Handler: handler}))
+	suite.NoError(otherScheduler.Schedule(Job{Name: "first", Interval: time.Hour, Handler: handler}))
+	suite.Len(GetScheduledJobs(), 3)
+
+	suite.scheduler.CancelAll()
+
+	jobs := GetScheduledJobs()
+	suite.Len(jobs, 1)
+	suite.Equal(JobStatus{
+		Component: "bot_other_component",
+		Name:      "first",
+		Schedule:  "every 1h0m0s",
+		NextRun:   jobs[0].NextRun,
+	}, jobs[0])
+
+	otherScheduler.CancelAll()
+}
+
+func TestScheduler(t *testing.T) {
+	suite.Run(t, new(SchedulerTestSuite))
+}
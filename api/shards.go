@@ -0,0 +1,138 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"errors"
+	"github.com/bwmarrin/discordgo"
+	"sync"
+	"time"
+)
+
+// The bot can be split into multiple shards, where each shard has its own
+// discordgo.Session with its own gateway connection and state.
+// REST calls are not bound to a shard, which is why components keep
+// using the session of the first shard for them.
+// Everything that depends on the gateway connection, like event handlers
+// or the bot status, is applied to the sessions of all shards.
+
+// ShardStatus holds information about the health of a single shard.
+type ShardStatus struct {
+	// ID is the id of the shard.
+	ID int
+	// Guilds is the number of guilds handled by the shard.
+	Guilds int
+	// Latency is the latency of the last heartbeat of the shard.
+	Latency time.Duration
+	// Connected indicates whether the shard is connected and ready.
+	Connected bool
+}
+
+// shardSessionList holds the sessions of all shards.
+type shardSessionList struct {
+	sync.RWMutex
+	sessions []*discordgo.Session
+}
+
+// shardSessions holds the sessions of all shards,
+// ordered by their shard id.
+var shardSessions = shardSessionList{}
+
+// InitShards registers the sessions of all shards of the bot.
+// The sessions must be ordered by their shard id.
+//
+// The function must be called before the components are loaded,
+// as registered event handlers are added to the sessions of all shards.
+func InitShards(sessions []*discordgo.Session) error {
+	if 0 == len(sessions) {
+		return errors.New("cannot initialize shards without any session")
+	}
+
+	shardSessions.Lock()
+	defer shardSessions.Unlock()
+
+	if nil != shardSessions.sessions {
+		return errors.New("cannot initialize shards twice")
+	}
+
+	shardSessions.sessions = sessions
+
+	return nil
+}
+
+// getShardSessions returns the sessions of all shards.
+// When no shards have been initialized, the passed fallback
+// session is used as the only shard.
+func getShardSessions(fallback *discordgo.Session) []*discordgo.Session {
+	shardSessions.RLock()
+	defer shardSessions.RUnlock()
+
+	if 0 == len(shardSessions.sessions) {
+		if nil == fallback {
+			return []*discordgo.Session{}
+		}
+
+		return []*discordgo.Session{fallback}
+	}
+
+	sessions := make([]*discordgo.Session, len(shardSessions.sessions))
+	copy(sessions, shardSessions.sessions)
+
+	return sessions
+}
+
+// getShardStatus computes the ShardStatus of the passed shard session.
+func getShardStatus(session *discordgo.Session) ShardStatus {
+	status := ShardStatus{
+		ID:        session.ShardID,
+		Connected: session.DataReady,
+	}
+
+	if nil != session.State {
+		session.State.RLock()
+		status.Guilds = len(session.State.Guilds)
+		session.State.RUnlock()
+	}
+
+	if status.Connected {
+		status.Latency = session.HeartbeatLatency()
+	}
+
+	return status
+}
+
+// getShardGuildIds returns the ids of the guilds of all shards.
+// When no shards have been initialized, the passed fallback
+// session is used as the only shard.
+func getShardGuildIds(fallback *discordgo.Session) []string {
+	guildIds := make([]string, 0)
+	for _, session := range getShardSessions(fallback) {
+		if nil == session.State {
+			continue
+		}
+
+		session.State.RLock()
+		for _, guild := range session.State.Guilds {
+			guildIds = append(guildIds, guild.ID)
+		}
+		session.State.RUnlock()
+	}
+
+	return guildIds
+}
@@ -0,0 +1,153 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type ShardsTestSuite struct {
+	suite.Suite
+	owningComponent *Component
+}
+
+func (suite *ShardsTestSuite) SetupTest() {
+	shardSessions.sessions = nil
+
+	suite.owningComponent = &Component{
+		Code: "test_component",
+		Name: "Test Component",
+	}
+}
+
+func (suite *ShardsTestSuite) TearDownTest() {
+	shardSessions.sessions = nil
+}
+
+func (suite *ShardsTestSuite) TestInitShards() {
+	sessions := []*discordgo.Session{suite.createShard(0, 2, 0), suite.createShard(1, 2, 0)}
+
+	suite.Error(InitShards([]*discordgo.Session{}))
+	suite.NoError(InitShards(sessions))
+	suite.Error(InitShards(sessions))
+	suite.Equal(sessions, getShardSessions(nil))
+}
+
+func (suite *ShardsTestSuite) TestGetShardSessionsWithoutShards() {
+	fallback := suite.createShard(0, 1, 0)
+
+	suite.Equal([]*discordgo.Session{fallback}, getShardSessions(fallback))
+	suite.Empty(getShardSessions(nil))
+}
+
+func (suite *ShardsTestSuite) TestGetShardStatus() {
+	shard := suite.createShard(1, 2, 3)
+	shard.DataReady = true
+	shard.LastHeartbeatSent = time.Now()
+	shard.LastHeartbeatAck = shard.LastHeartbeatSent.Add(42 * time.Millisecond)
+
+	suite.Equal(ShardStatus{
+		ID:        1,
+		Guilds:    3,
+		Latency:   42 * time.Millisecond,
+		Connected: true,
+	}, getShardStatus(shard))
+}
+
+func (suite *ShardsTestSuite) TestGetShardStatusOfDisconnectedShard() {
+	shard := suite.createShard(0, 1, 2)
+	shard.LastHeartbeatSent = time.Now()
+
+	suite.Equal(ShardStatus{
+		ID:     0,
+		Guilds: 2,
+	}, getShardStatus(shard))
+}
+
+func (suite *ShardsTestSuite) TestDiscordApiAggregatesShards() {
+	firstShard := suite.createShard(0, 2, 3)
+	firstShard.DataReady = true
+	firstShard.LastHeartbeatAck = firstShard.LastHeartbeatSent.Add(20 * time.Millisecond)
+	secondShard := suite.createShard(1, 2, 4)
+	secondShard.DataReady = true
+	secondShard.LastHeartbeatAck = secondShard.LastHeartbeatSent.Add(40 * time.Millisecond)
+
+	suite.NoError(InitShards([]*discordgo.Session{firstShard, secondShard}))
+	suite.owningComponent.discord = firstShard
+
+	suite.Equal(7, suite.owningComponent.DiscordApi().GuildCount())
+	suite.Equal(30*time.Millisecond, suite.owningComponent.DiscordApi().Latency())
+	suite.Len(suite.owningComponent.DiscordApi().ShardStatus(), 2)
+}
+
+func (suite *ShardsTestSuite) TestAddHandlerToShards() {
+	sessions := []*discordgo.Session{suite.createShard(0, 2, 0), suite.createShard(1, 2, 0)}
+	suite.NoError(InitShards(sessions))
+
+	registeredSessions := make([]*discordgo.Session, 0)
+	unregisteredCount := 0
+	unregister := addHandlerToShards(nil, func(session *discordgo.Session) func() {
+		registeredSessions = append(registeredSessions, session)
+
+		return func() {
+			unregisteredCount++
+		}
+	})
+
+	suite.Equal(sessions, registeredSessions)
+
+	unregister()
+	suite.Equal(2, unregisteredCount)
+}
+
+func (suite *ShardsTestSuite) TestGetShardGuildIds() {
+	firstShard := suite.createShard(0, 2, 0)
+	firstShard.State.Guilds = []*discordgo.Guild{{ID: "1"}, {ID: "2"}}
+	secondShard := suite.createShard(1, 2, 0)
+	secondShard.State.Guilds = []*discordgo.Guild{{ID: "3"}}
+
+	suite.Equal([]string{"1", "2"}, getShardGuildIds(firstShard))
+	suite.Empty(getShardGuildIds(nil))
+
+	suite.NoError(InitShards([]*discordgo.Session{firstShard, secondShard}))
+
+	suite.Equal([]string{"1", "2", "3"}, getShardGuildIds(firstShard))
+}
+
+// createShard creates a session for the shard with the passed id,
+// that is on the passed number of guilds.
+func (suite *ShardsTestSuite) createShard(shardId int, shardCount int, guildCount int) *discordgo.Session {
+	state := discordgo.NewState()
+	for guildId := 0; guildId < guildCount; guildId++ {
+		state.Guilds = append(state.Guilds, &discordgo.Guild{})
+	}
+
+	return &discordgo.Session{
+		ShardID:    shardId,
+		ShardCount: shardCount,
+		State:      state,
+	}
+}
+
+func TestShards(t *testing.T) {
+	suite.Run(t, new(ShardsTestSuite))
+}
@@ -25,17 +25,27 @@ import (
 	"github.com/lazybytez/jojo-discord-bot/api/entities"
 	"github.com/lazybytez/jojo-discord-bot/api/util"
 	"github.com/lazybytez/jojo-discord-bot/services/logger"
+	"sync"
 )
 
 // slashCommandLogPrefix is the prefix used by the log management during
 // lifecycle events that cannot be assigned to a specific component.
 const slashCommandLogPrefix = "slash_command_manager"
 
+// MaxAutocompleteChoices is the maximum number of choices
+// Discord accepts in a single autocomplete response.
+const MaxAutocompleteChoices = 25
+
 var (
 	// componentCommandMap is a map that holds the discordgo.ApplicationCommand
-	// as value and the name of the discordgo.ApplicationCommand as key.
+	// as value and the type and name of the discordgo.ApplicationCommand as key.
+	// See getCommandKey for the format of the key.
 	componentCommandMap map[string]*Command
 
+	// componentCommandMapMu is used to synchronize access to the componentCommandMap,
+	// as commands can be registered and unregistered at runtime.
+	componentCommandMapMu sync.RWMutex
+
 	// unregisterCommandHandler holds the function that can be used to unregister
 	// the command Handler registered by InitCommandHandling
 	unregisterCommandHandler func()
@@ -57,13 +67,36 @@ func init() {
 // Command is a struct that acts as a container for
 // discordgo.ApplicationCommand and the assigned command Handler.
 //
-// Create an instance of the struct and pass to Register a command
+// Create an instance of the struct and pass to Register a command.
+//
+// Next to chat input (slash) commands, user and message context-menu commands
+// are supported. The type of the command is set through the Type of the
+// discordgo.ApplicationCommand. Commands of different types may share the same name.
+//
+// The optional Autocomplete handler is called for autocomplete interactions
+// of options that have autocomplete enabled.
 type Command struct {
-	Cmd      *discordgo.ApplicationCommand
-	Global   bool
-	Category Category
-	Handler  func(s *discordgo.Session, i *discordgo.InteractionCreate)
-	c        *Component
+	Cmd          *discordgo.ApplicationCommand
+	Global       bool
+	Category     Category
+	Handler      func(s *discordgo.Session, i *discordgo.InteractionCreate)
+	Autocomplete func(s *discordgo.Session, i *discordgo.InteractionCreate)
+	// Permissions are the member permissions required to use the command.
+	// In contrast to the DefaultMemberPermissions of the discordgo.ApplicationCommand,
+	// they are checked by the bot, which allows guilds to grant access to
+	// other members using permission overrides.
+	Permissions int64
+	// RateLimit optionally limits how often the command can be used.
+	// See RateLimit for details.
+	RateLimit *RateLimit
+	// DeferEphemeral makes the deferred response, that is sent automatically
+	// when the Handler does not respond in time, ephemeral.
+	// See CommandAutoDeferMiddleware for details.
+	DeferEphemeral bool
+	// Middlewares are wrapped around the Handler of the command.
+	// They are executed after the global and component middlewares.
+	Middlewares []CommandMiddleware
+	c           *Component
 }
 
 // SlashCommandManager is a type that is used to hold
@@ -92,6 +125,10 @@ type CommonSlashCommandManager interface {
 	// Also orphaned commands are cleaned up.
 	// This is executed whenever a guild is joined or a component is toggled.
 	SyncApplicationComponentCommands(session *discordgo.Session, guildId string)
+	// SyncApplicationComponentCommandsDryRun computes the changes a sync of the commands
+	// of the given guild would apply, without applying them.
+	// An empty guild id computes the changes of the global commands.
+	SyncApplicationComponentCommandsDryRun(session *discordgo.Session, guildId string) (*CommandSyncDiff, error)
 	// GetCommandsForComponent returns all commands for the
 	// specified component. The component needs to be specified by its unique code.
 	GetCommandsForComponent(code entities.ComponentCode) []*Command
@@ -99,6 +136,19 @@ type CommonSlashCommandManager interface {
 	GetCommands() []*Command
 	// GetCommandCount returns the number of registered slash commands
 	GetCommandCount() int
+	// Unregister removes the command with the passed name that is owned
+	// by the component from the list of registered commands.
+	//
+	// The command will be removed from Discord on the next command sync.
+	Unregister(name string) error
+	// UnregisterAll removes all commands owned by the component
+	// from the list of registered commands.
+	//
+	// The commands will be removed from Discord on the next command sync.
+	UnregisterAll()
+	// Use registers the passed middlewares for all commands owned by the component.
+	// See CommandMiddleware for details.
+	Use(middlewares ...CommandMiddleware)
 }
 
 // InitCommandHandling initializes the command handling
@@ -108,96 +158,70 @@ func InitCommandHandling(session *discordgo.Session) error {
 		return errors.New("cannot initialize command handling system twice")
 	}
 
-	unregisterCommandHandler = session.AddHandler(handleCommandDispatch)
+	unregisterCommandHandler = addHandlerToShards(session, func(session *discordgo.Session) func() {
+		return session.AddHandler(handleCommandDispatch)
+	})
 
 	return nil
 }
 
-// handleCommandDispatch handles the processing of a command
-// that has been executed by a user. It already takes
-// component status in account to ensure that inconsistent command
-// states do not end in prohibited execution of a command.
+// handleCommandDispatch delegates the passed interaction to the
+// appropriate handler depending on the type of the interaction.
+// Unsupported interactions are ignored.
 func handleCommandDispatch(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if command, ok := componentCommandMap[i.ApplicationCommandData().Name]; ok {
-		user := i.User
-		if nil == user {
-			user = i.Member.User
-		}
-		if nil == user {
-			command.c.Logger().Warn("Cannot handle the command \"%s\" without a user!", command.Cmd.Name)
-		}
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		handleApplicationCommandDispatch(s, i)
+	case discordgo.InteractionApplicationCommandAutocomplete:
+		handleAutocompleteDispatch(s, i)
+	case discordgo.InteractionMessageComponent:
+		handleComponentInteractionDispatch(s, i)
+	case discordgo.InteractionModalSubmit:
+		handleModalSubmitDispatch(s, i)
+	}
+}
 
-		if !IsComponentEnabled(command.c, i.GuildID) {
-			resp := &discordgo.InteractionResponseData{
-				Flags: discordgo.MessageFlagsEphemeral,
-				Embeds: []*discordgo.MessageEmbed{
-					{
-						Title:       "JOJO Discord Bot",
-						Description: "",
-						Color:       DefaultEmbedColor,
-						Fields: []*discordgo.MessageEmbedField{
-							{
-								Name:  ":no_entry_sign: STOP :no_entry_sign:",
-								Value: "An unexpected error happened while computing your error message!",
-							},
-						},
-					},
-				},
-			}
-
-			component := command.c
-			registeredComponent, err := component.EntityManager().RegisteredComponent().Get(command.c.Code)
-			if nil == err {
-				globalStatus, err := component.EntityManager().GlobalComponentStatus().Get(registeredComponent.ID)
-				if nil == err {
-					switch globalStatus.Enabled {
-					case true:
-						resp.Embeds[0].Fields[0].Value = fmt.Sprintf("The command `/%s` is disabled on this "+
-							"guild! Ask your guilds administrator to enable the `%s` component to use this command!",
-							command.Cmd.Name,
-							command.c.Name)
-					case false:
-						resp.Embeds[0].Fields[0].Value = fmt.Sprintf("The command `/%s` is globally disabled. "+
-							"This might be due to some maintenance on the `%s` module.",
-							command.Cmd.Name,
-							command.c.Name)
-					}
-				}
-			}
-
-			err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-				Type: discordgo.InteractionResponseChannelMessageWithSource,
-				Data: resp,
-			})
-
-			if nil != err {
-				command.c.Logger().Err(err, "Failed to deliver interaction response on slash-command!")
-
-				return
-			}
-
-			command.c.Logger().Info("The user \"%s#%s\" with id \"%s\" tried to execute the "+
-				"disabled command \"%s\" with options \"%s\" %s",
-				user.Username,
-				user.Discriminator,
-				user.ID,
-				command.c.SlashCommandManager().computeFullCommandStringFromInteractionData(i.ApplicationCommandData()),
-				command.c.SlashCommandManager().computeConfiguredOptionsString(i.ApplicationCommandData().Options),
-				getGuildOrGlobalLogPart(i.GuildID, "on"))
-
-			return
+// handleAutocompleteDispatch handles the processing of an autocomplete
+// interaction triggered by a user while filling a command option.
+//
+// Commands of disabled components, commands without an autocomplete
+// handler and commands the member is not allowed to use receive no suggestions.
+func handleAutocompleteDispatch(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ApplicationCommandData()
+	command, ok := getComponentCommand(data.CommandType, data.Name)
+	if !ok {
+		return
+	}
+
+	defer recoverFromInteractionPanic(s, i, command.c, fmt.Sprintf("autocomplete of command \"%s\" on guild \"%s\"",
+		command.Cmd.Name,
+		i.GuildID))
+
+	if nil == command.Autocomplete ||
+		!isCommandAllowedForInteraction(command, i) ||
+		!IsCommandEnabled(command, i.GuildID) {
+		err := RespondWithAutocompleteChoices(s, i, []*discordgo.ApplicationCommandOptionChoice{})
+		if nil != err {
+			command.c.Logger().Err(err, "Failed to deliver empty autocomplete response for command \"%s\"!",
+				command.Cmd.Name)
 		}
 
-		command.c.Logger().Info("The user \"%s#%s\" with id \"%s\" executed the "+
-			"command \"%s\" with options \"%s\" %s",
-			user.Username,
-			user.Discriminator,
-			user.ID,
-			command.c.SlashCommandManager().computeFullCommandStringFromInteractionData(i.ApplicationCommandData()),
-			command.c.SlashCommandManager().computeConfiguredOptionsString(i.ApplicationCommandData().Options),
-			getGuildOrGlobalLogPart(i.GuildID, "on"))
+		return
+	}
+
+	command.Autocomplete(s, i)
+}
 
-		command.Handler(s, i)
+// handleApplicationCommandDispatch handles the processing of a command
+// that has been executed by a user.
+//
+// The handler of the command is wrapped by the middleware chain of the command,
+// which takes care of things like checking the component status and logging.
+// See CommandMiddleware for details.
+func handleApplicationCommandDispatch(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ApplicationCommandData()
+	if command, ok := getComponentCommand(data.CommandType, data.Name); ok {
+		buildCommandHandlerChain(command)(s, i)
 	}
 }
 
@@ -318,8 +342,16 @@ func (c *SlashCommandManager) Register(cmd *Command) error {
 		return err
 	}
 
-	if _, ok := componentCommandMap[cmd.Cmd.Name]; ok {
-		err = errors.New("cannot register a command with the same name twice")
+	if 0 == cmd.Cmd.Type {
+		cmd.Cmd.Type = discordgo.ChatApplicationCommand
+	}
+
+	componentCommandMapMu.Lock()
+	defer componentCommandMapMu.Unlock()
+
+	commandKey := getCommandKey(cmd.Cmd.Type, cmd.Cmd.Name)
+	if _, ok := componentCommandMap[commandKey]; ok {
+		err = errors.New("cannot register a command with the same type and name twice")
 
 		c.owner.Logger().Err(
 			err,
@@ -331,305 +363,200 @@ func (c *SlashCommandManager) Register(cmd *Command) error {
 		return err
 	}
 
-	if 0 == cmd.Cmd.Type {
-		cmd.Cmd.Type = discordgo.ChatApplicationCommand
-	}
-
 	if cmd.Global {
 		cmd.Cmd.DMPermission = &slashCommandDmPermission
 	}
 
-	componentCommandMap[cmd.Cmd.Name] = cmd
+	componentCommandMap[commandKey] = cmd
 
 	return nil
 }
 
-// validateCommand validates the passed command to ensure it is valid
-// and can be registered properly.
-func (c *SlashCommandManager) validateCommand(cmd *Command) error {
-	if nil == cmd.Cmd {
-		err := errors.New("the discordgo.ApplicationCommand of the passed command is nil")
+// Unregister removes the commands with the passed name that are owned
+// by the component from the list of registered commands.
+// This includes all types of commands (chat input and context-menu) with the passed name.
+//
+// The commands will be removed from Discord on the next command sync.
+func (c *SlashCommandManager) Unregister(name string) error {
+	componentCommandMapMu.Lock()
+	defer componentCommandMapMu.Unlock()
+
+	found := false
+	for key, command := range componentCommandMap {
+		if command.Cmd.Name != name {
+			continue
+		}
+		found = true
 
-		c.owner.Logger().Err(
-			err,
-			"Failed to register the slash-Cmd \"%v\" for component \"%v\": %v!",
-			cmd.Cmd.Name,
-			c.owner.Name,
-			err.Error())
+		if command.c != c.owner {
+			return fmt.Errorf("the command \"%s\" is not owned by component \"%s\"", name, c.owner.Code)
+		}
 
-		return err
+		delete(componentCommandMap, key)
 	}
 
-	if nil == cmd.Handler {
-		err := errors.New("the Handler of the passed command is nil")
-
-		c.owner.Logger().Err(
-			err,
-			"Failed to register the slash-Cmd \"%v\" for component \"%v\": %v!",
-			cmd.Cmd.Name,
-			c.owner.Name,
-			err.Error())
-
-		return err
+	if !found {
+		return fmt.Errorf("there is no command with name \"%s\" registered", name)
 	}
 
 	return nil
 }
 
-// GetCommands returns all currently registered commands.
-func (c *SlashCommandManager) GetCommands() []*Command {
-	commands := make([]*Command, 0)
-
-	for _, command := range componentCommandMap {
-		commands = append(commands, command)
+// UnregisterAll removes all commands owned by the component
+// from the list of registered commands.
+//
+// The commands will be removed from Discord on the next command sync.
+func (c *SlashCommandManager) UnregisterAll() {
+	componentCommandMapMu.Lock()
+	defer componentCommandMapMu.Unlock()
+
+	for name, command := range componentCommandMap {
+		if command.c == c.owner {
+			delete(componentCommandMap, name)
+		}
 	}
-
-	return commands
 }
 
-// GetCommandsForComponent returns all commands for the
-// specified component. The component needs to be specified by its unique code.
-func (c *SlashCommandManager) GetCommandsForComponent(code entities.ComponentCode) []*Command {
-	commands := make([]*Command, 0)
+// getComponentCommand returns the command with the passed type and name
+// in a thread-safe manner.
+func getComponentCommand(cmdType discordgo.ApplicationCommandType, name string) (*Command, bool) {
+	componentCommandMapMu.RLock()
+	defer componentCommandMapMu.RUnlock()
 
-	for _, command := range componentCommandMap {
-		if command.c.Code == code {
-			commands = append(commands, command)
-		}
-	}
+	command, ok := componentCommandMap[getCommandKey(cmdType, name)]
 
-	return commands
+	return command, ok
 }
 
-// SyncApplicationComponentCommands ensures that the available discordgo.ApplicationCommand
-// are synced for the given component with the given guild.
-//
-// This means that disabled commands are enabled and enabled commands are disabled
-// depending on the component enable state.
-//
-// Also orphaned commands are cleaned up.
-// This is executed whenever a guild is joined or a component is toggled.
+// getCommandKey returns the key used to store a command with the
+// passed type and name in the componentCommandMap.
 //
-// Sync is a four-step process:
-//   - remove orphaned commands
-//   - remove disabled commands
-//   - add new commands
-//   - update existing commands
-func (c *SlashCommandManager) SyncApplicationComponentCommands(
-	session *discordgo.Session,
-	guildId string,
-) {
-	registeredCommands, err := session.ApplicationCommands(session.State.User.ID, guildId)
-	if nil != err {
-		slashCommandManagerLogger.Err(
-			err,
-			"Failed to handle guild slash-command sync for guild \"%s\"!",
-			guildId)
-
-		return
+// Chat input commands are stored by their name, context-menu commands
+// are prefixed with their type, as commands of different types may share their name.
+// A missing type is treated as chat input command.
+func getCommandKey(cmdType discordgo.ApplicationCommandType, name string) string {
+	if 0 == cmdType || discordgo.ChatApplicationCommand == cmdType {
+		return name
 	}
 
-	slashCommandManagerLogger.Info(
-		"Syncing slash-commands for guild \"%v\"...",
-		guildId)
-	registeredCommands = c.removeOrphanedCommands(session, guildId, registeredCommands)
-	registeredCommands = c.removeCommandsByComponentState(session, guildId, registeredCommands)
-	registeredCommands = c.addCommandsByComponentState(session, guildId, registeredCommands)
-	_ = c.updateRegisteredCommands(session, guildId, registeredCommands)
-
-	slashCommandManagerLogger.Info(
-		"Finished syncing slash-commands for guild \"%s\"...",
-		guildId)
+	return fmt.Sprintf("%d:%s", cmdType, name)
 }
 
-// removeOrphanedCommands removes all slash-commands commands from a guild,
-// that are no longer registered in the bots slash-command management.
-//
-// The function returns the passed list of commands,
-// with the removed commands being removed.
-func (c *SlashCommandManager) removeOrphanedCommands(
-	session *discordgo.Session,
-	guildId string,
-	commands []*discordgo.ApplicationCommand,
-) []*discordgo.ApplicationCommand {
-	stillAvailableCommands := commands
-
-	for key, registeredCommand := range commands {
-		presentCompCmd, ok := componentCommandMap[registeredCommand.Name]
-		if !ok || (ok && "" == guildId && !presentCompCmd.Global) || (ok && "" != guildId && presentCompCmd.Global) {
-			err := session.ApplicationCommandDelete(session.State.User.ID, guildId, registeredCommand.ID)
-			if nil != err {
-				slashCommandManagerLogger.Err(
-					err,
-					"Failed to remove orphaned slash-command \"%s\" %s!",
-					registeredCommand.Name,
-					getGuildOrGlobalLogPart(guildId, "from"))
-
-				continue
-			}
-
-			slicedCommands := make([]*discordgo.ApplicationCommand, 0)
-			if len(commands)-1 >= key+1 {
-				slicedCommands = commands[key+1:]
-			}
-			stillAvailableCommands = append(stillAvailableCommands[:], slicedCommands...)
-
-			slashCommandManagerLogger.Info(
-				"Removed orphaned slash-command \"%s\" %s!",
-				registeredCommand.Name,
-				getGuildOrGlobalLogPart(guildId, "from"))
-		}
+// normalizeCommandType returns the passed command type, with a missing type
+// being treated as discordgo.ChatApplicationCommand like Discord does.
+func normalizeCommandType(cmdType discordgo.ApplicationCommandType) discordgo.ApplicationCommandType {
+	if 0 == cmdType {
+		return discordgo.ChatApplicationCommand
 	}
 
-	return stillAvailableCommands
+	return cmdType
 }
 
-// removeCommandsByComponentState removes commands from
-// the specified guild depending on the owning components state.
-func (c *SlashCommandManager) removeCommandsByComponentState(
-	session *discordgo.Session,
-	guildId string,
-	commands []*discordgo.ApplicationCommand,
-) []*discordgo.ApplicationCommand {
-	stillAvailableCommands := commands
+// getComponentCommands returns a snapshot of all registered commands
+// in a thread-safe manner.
+func getComponentCommands() []*Command {
+	componentCommandMapMu.RLock()
+	defer componentCommandMapMu.RUnlock()
 
-	// First of all remove disabled existing commands
-	for key, command := range commands {
-		componentCommand, ok := componentCommandMap[command.Name]
-		if !ok {
-			slashCommandManagerLogger.Warn(
-				"Missing component command for registered slash-command \"%s\"!",
-				command.Name)
+	commands := make([]*Command, 0, len(componentCommandMap))
+	for _, command := range componentCommandMap {
+		commands = append(commands, command)
+	}
 
-			continue
-		}
+	return commands
+}
 
-		if componentCommand.Global && "" != guildId || !componentCommand.Global && "" == guildId {
-			continue
-		}
+// validateCommand validates the passed command to ensure it is valid
+// and can be registered properly.
+func (c *SlashCommandManager) validateCommand(cmd *Command) error {
+	if nil == cmd.Cmd {
+		err := errors.New("the discordgo.ApplicationCommand of the passed command is nil")
 
-		if IsComponentEnabled(componentCommand.c, guildId) {
-			continue
-		}
+		c.owner.Logger().Err(
+			err,
+			"Failed to register the slash-Cmd \"%v\" for component \"%v\": %v!",
+			cmd.Cmd.Name,
+			c.owner.Name,
+			err.Error())
 
-		err := session.ApplicationCommandDelete(session.State.User.ID, guildId, command.ID)
-		if nil != err {
-			componentCommand.c.Logger().Err(
-				err,
-				"Failed to remove disabled slash-command \"%s\" %s!",
-				command.Name,
-				getGuildOrGlobalLogPart(guildId, "from"))
+		return err
+	}
 
-			continue
-		}
+	if nil == cmd.Handler {
+		err := errors.New("the Handler of the passed command is nil")
 
-		slicedCommands := make([]*discordgo.ApplicationCommand, 0)
-		if len(commands)-1 >= key+1 {
-			slicedCommands = commands[key+1:]
-		}
-		stillAvailableCommands = append(stillAvailableCommands[:], slicedCommands...)
+		c.owner.Logger().Err(
+			err,
+			"Failed to register the slash-Cmd \"%v\" for component \"%v\": %v!",
+			cmd.Cmd.Name,
+			c.owner.Name,
+			err.Error())
 
-		componentCommand.c.Logger().Info(
-			"Removed disabled slash-command \"%s\" %s!",
-			command.Name,
-			getGuildOrGlobalLogPart(guildId, "from"))
+		return err
 	}
 
-	return stillAvailableCommands
-}
-
-// addCommandsByComponentState removes commands from
-// the specified guild depending on the owning components state.
-func (c *SlashCommandManager) addCommandsByComponentState(
-	session *discordgo.Session,
-	guildId string,
-	commands []*discordgo.ApplicationCommand,
-) []*discordgo.ApplicationCommand {
-	for _, componentCommand := range componentCommandMap {
-		if componentCommand.Global && "" != guildId || !componentCommand.Global && "" == guildId {
-			continue
-		}
+	err := validateContextMenuCommand(cmd)
+	if nil != err {
+		c.owner.Logger().Err(
+			err,
+			"Failed to register the context-menu command \"%v\" for component \"%v\": %v!",
+			cmd.Cmd.Name,
+			c.owner.Name,
+			err.Error())
 
-		if c.isCommandNameInApplicationCommandList(commands, componentCommand.Cmd.Name) {
-			continue
-		}
+		return err
+	}
 
-		if !IsComponentEnabled(componentCommand.c, guildId) {
-			continue
-		}
+	return nil
+}
 
-		createdCommand, err := session.ApplicationCommandCreate(session.State.User.ID, guildId, componentCommand.Cmd)
-		if nil != err {
-			componentCommand.c.Logger().Err(
-				err,
-				"Failed to add enabled slash-command \"%v\" %s!",
-				componentCommand.Cmd.Name,
-				getGuildOrGlobalLogPart(guildId, "to"))
+// validateContextMenuCommand ensures that user and message context-menu commands
+// do not use features that are only available for chat input commands.
+func validateContextMenuCommand(cmd *Command) error {
+	switch cmd.Cmd.Type {
+	case discordgo.UserApplicationCommand, discordgo.MessageApplicationCommand:
+	default:
+		return nil
+	}
 
-			continue
-		}
+	if "" != cmd.Cmd.Description || len(cmd.Cmd.Options) > 0 {
+		return errors.New("context-menu commands cannot have a description or options")
+	}
 
-		commands = append(commands, createdCommand)
-		componentCommand.c.Logger().Info(
-			"Added enabled slash-command \"%s\" %s!",
-			componentCommand.Cmd.Name,
-			getGuildOrGlobalLogPart(guildId, "to"))
+	if nil != cmd.Autocomplete {
+		return errors.New("context-menu commands cannot have an autocomplete handler")
 	}
 
-	return commands
+	return nil
 }
 
-// updateRegisteredCommands checks all registered commands
-// and updates (deletes and creates) differing commands.
-func (c *SlashCommandManager) updateRegisteredCommands(
-	session *discordgo.Session,
-	guildId string,
-	commands []*discordgo.ApplicationCommand,
-) []*discordgo.ApplicationCommand {
-	for key, command := range commands {
-		componentCommand, ok := componentCommandMap[command.Name]
-		if !ok {
-			slashCommandManagerLogger.Warn("Cannot check for command updates for \"%s\" "+
-				"as a corresponding component command is missing!",
-				command.Name)
-
-			continue
-		}
-
-		if componentCommand.Global && "" != guildId || !componentCommand.Global && "" == guildId {
-			continue
-		}
-
-		if c.compareCommands(command, componentCommand.Cmd) {
-			continue
-		}
+// GetCommands returns all currently registered commands.
+func (c *SlashCommandManager) GetCommands() []*Command {
+	return getComponentCommands()
+}
 
-		createdCommand, err := session.ApplicationCommandCreate(session.State.User.ID, guildId, componentCommand.Cmd)
-		if nil != err {
-			componentCommand.c.Logger().Err(
-				err,
-				"Failed to add slash-command \"%s\" %s during command update!",
-				componentCommand.Cmd.Name,
-				getGuildOrGlobalLogPart(guildId, "to"))
+// GetCommandsForComponent returns all commands for the
+// specified component. The component needs to be specified by its unique code.
+func (c *SlashCommandManager) GetCommandsForComponent(code entities.ComponentCode) []*Command {
+	commands := make([]*Command, 0)
 
-			continue
+	for _, command := range getComponentCommands() {
+		if command.c.Code == code {
+			commands = append(commands, command)
 		}
-
-		commands[key] = createdCommand
-		componentCommand.c.Logger().Info(
-			"Updated slash-command \"%s\" %s!",
-			componentCommand.Cmd.Name,
-			getGuildOrGlobalLogPart(guildId, "for"))
 	}
 
 	return commands
 }
 
-// isCommandNameInApplicationCommandList checks if a command with the provided name
-// is present in the provided discordgo.ApplicationCommand slice.
-func (c *SlashCommandManager) isCommandNameInApplicationCommandList(commands []*discordgo.ApplicationCommand, name string) bool {
+// isCommandInApplicationCommandList checks if a command with the same type and name as the
+// provided command is present in the provided discordgo.ApplicationCommand slice.
+func (c *SlashCommandManager) isCommandInApplicationCommandList(
+	commands []*discordgo.ApplicationCommand,
+	cmd *discordgo.ApplicationCommand,
+) bool {
 	for _, command := range commands {
-		if command.Name == name {
+		if getCommandKey(command.Type, command.Name) == getCommandKey(cmd.Type, cmd.Name) {
 			return true
 		}
 	}
@@ -676,7 +603,7 @@ func (c *SlashCommandManager) compareCommands(
 		return false
 	}
 
-	if uint8(a.Type) != uint8(b.Type) {
+	if normalizeCommandType(a.Type) != normalizeCommandType(b.Type) {
 		return false
 	}
 
@@ -842,11 +769,14 @@ func runHandler(
 	handler, ok := handlers[name]
 
 	if !ok {
-		_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: "The executed (sub)command is invalid or does not exist!",
-			},
+		if discordgo.InteractionApplicationCommandAutocomplete == i.Type {
+			_ = RespondWithAutocompleteChoices(s, i, []*discordgo.ApplicationCommandOptionChoice{})
+
+			return false
+		}
+
+		_, _ = RespondToInteraction(s, i, &discordgo.InteractionResponseData{
+			Content: "The executed (sub)command is invalid or does not exist!",
 		})
 
 		return false
@@ -857,8 +787,54 @@ func runHandler(
 	return true
 }
 
+// GetFocusedOption searches the passed options and their nested options
+// for the option that is currently focused in an autocomplete interaction.
+//
+// Next to the focused option, all options on the same level as the focused option
+// are returned. This allows to access already filled options next to the focused one.
+// Returns nil, if no option is focused.
+func GetFocusedOption(
+	options []*discordgo.ApplicationCommandInteractionDataOption,
+) (*discordgo.ApplicationCommandInteractionDataOption, []*discordgo.ApplicationCommandInteractionDataOption) {
+	for _, option := range options {
+		if option.Focused {
+			return option, options
+		}
+
+		focused, siblings := GetFocusedOption(option.Options)
+		if nil != focused {
+			return focused, siblings
+		}
+	}
+
+	return nil, nil
+}
+
+// RespondWithAutocompleteChoices responds to the passed autocomplete interaction
+// with the passed choices. Discord only allows a limited amount of choices,
+// additional choices are cut off.
+func RespondWithAutocompleteChoices(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	choices []*discordgo.ApplicationCommandOptionChoice,
+) error {
+	if len(choices) > MaxAutocompleteChoices {
+		choices = choices[:MaxAutocompleteChoices]
+	}
+
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: choices,
+		},
+	})
+}
+
 // GetCommandCount returns the number of registered slash commands
 func (c *SlashCommandManager) GetCommandCount() int {
+	componentCommandMapMu.RLock()
+	defer componentCommandMapMu.RUnlock()
+
 	return len(componentCommandMap)
 }
 
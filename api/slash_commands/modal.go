@@ -0,0 +1,158 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package slash_commands
+
+import (
+	"errors"
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
+)
+
+// These are the limits Discord applies to modals and their text inputs.
+const (
+	MaxModalTitleLength     = 45
+	MaxModalTextInputs      = 5
+	MaxTextInputLabelLength = 45
+	MaxTextInputValueLength = 4000
+)
+
+const (
+	ModalInputErrorResponseEmbedName = ":x: Invalid input!"
+	ModalInputErrorResponseTitle     = "Invalid Input"
+)
+
+// ValidateModal checks whether the passed modal title and text inputs
+// satisfy the limits applied by Discord.
+func ValidateModal(title string, inputs []*discordgo.TextInput) error {
+	if "" == title || len(title) > MaxModalTitleLength {
+		return fmt.Errorf("the title of a modal must have between 1 and %d characters", MaxModalTitleLength)
+	}
+
+	if len(inputs) < 1 || len(inputs) > MaxModalTextInputs {
+		return fmt.Errorf("a modal must have between 1 and %d text inputs", MaxModalTextInputs)
+	}
+
+	knownCustomIds := make(map[string]bool)
+	for _, input := range inputs {
+		if "" == input.CustomID || len(input.CustomID) > api.MaxCustomIdLength {
+			return fmt.Errorf("the custom id of a text input must have between 1 and %d characters",
+				api.MaxCustomIdLength)
+		}
+
+		if knownCustomIds[input.CustomID] {
+			return fmt.Errorf("the custom id \"%s\" is used by multiple text inputs", input.CustomID)
+		}
+		knownCustomIds[input.CustomID] = true
+
+		if "" == input.Label || len(input.Label) > MaxTextInputLabelLength {
+			return fmt.Errorf("the label of the text input \"%s\" must have between 1 and %d characters",
+				input.CustomID,
+				MaxTextInputLabelLength)
+		}
+
+		if input.MinLength < 0 || input.MaxLength < 0 || input.MaxLength > MaxTextInputValueLength ||
+			(input.MaxLength > 0 && input.MinLength > input.MaxLength) {
+			return fmt.Errorf("the length limits of the text input \"%s\" are invalid", input.CustomID)
+		}
+
+		if len(input.Value) > MaxTextInputValueLength {
+			return fmt.Errorf("the prefilled value of the text input \"%s\" is too long", input.CustomID)
+		}
+	}
+
+	return nil
+}
+
+// OpenModal validates the passed title and text inputs and responds to the
+// passed interaction with a modal built from them.
+//
+// The custom id of the modal should be created using the api.ModalManager
+// of the component, so the submission is delivered to the registered handler.
+func OpenModal(
+	c *api.Component,
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	customId string,
+	title string,
+	inputs ...*discordgo.TextInput,
+) error {
+	err := ValidateModal(title, inputs)
+	if nil != err {
+		c.Logger().Err(err, "Refusing to open invalid modal \"%s\"!", customId)
+
+		return err
+	}
+
+	components := make([]discordgo.MessageComponent, len(inputs))
+	for key, input := range inputs {
+		if 0 == input.Style {
+			input.Style = discordgo.TextInputShort
+		}
+
+		components[key] = discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{input},
+		}
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID:   customId,
+			Title:      title,
+			Components: components,
+		},
+	})
+
+	if nil != err {
+		c.Logger().Err(err, "Failed to open modal \"%s\"!", customId)
+	}
+
+	return err
+}
+
+// RespondWithModalInputError responds to a submitted modal with an ephemeral message
+// that describes why the submitted values could not be decoded.
+//
+// Errors that are no api.ModalInputError result in a generic error message.
+func RespondWithModalInputError(
+	c *api.Component,
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	err error,
+) {
+	resp := GenerateEphemeralInteractionResponseTemplate(ModalInputErrorResponseTitle, "")
+
+	var inputErr *api.ModalInputError
+	if !errors.As(err, &inputErr) {
+		RespondWithGenericErrorMessage(c, s, i, resp)
+
+		return
+	}
+
+	RespondWithSimpleEmbedMessage(c,
+		s,
+		i,
+		resp,
+		ModalInputErrorResponseEmbedName,
+		fmt.Sprintf("The value `%s` of the field `%s` is invalid: %s",
+			inputErr.Value,
+			inputErr.CustomId,
+			inputErr.Err.Error()))
+}
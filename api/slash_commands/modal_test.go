@@ -0,0 +1,179 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package slash_commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/test/discordgo_mock"
+	"github.com/lazybytez/jojo-discord-bot/test/logmock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type ModalTestSuite struct {
+	suite.Suite
+	interactionCreate *discordgo.InteractionCreate
+}
+
+func (suite *ModalTestSuite) SetupTest() {
+	suite.interactionCreate = &discordgo.InteractionCreate{}
+	suite.interactionCreate.Interaction = &discordgo.Interaction{
+		ID:    "12345123451234512345",
+		Token: "4z842ghh2908ghviu2gz908vh42f90824ph2h298zrf928fdh2gi",
+	}
+}
+
+func (suite *ModalTestSuite) TestValidateModal() {
+	validInput := &discordgo.TextInput{CustomID: "name", Label: "Name"}
+
+	tables := []struct {
+		title   string
+		inputs  []*discordgo.TextInput
+		isValid bool
+	}{
+		{"Setup", []*discordgo.TextInput{validInput}, true},
+		{"", []*discordgo.TextInput{validInput}, false},
+		{strings.Repeat("a", MaxModalTitleLength+1), []*discordgo.TextInput{validInput}, false},
+		{"Setup", []*discordgo.TextInput{}, false},
+		{"Setup", []*discordgo.TextInput{
+			validInput,
+			{CustomID: "b", Label: "B"},
+			{CustomID: "c", Label: "C"},
+			{CustomID: "d", Label: "D"},
+			{CustomID: "e", Label: "E"},
+			{CustomID: "f", Label: "F"},
+		}, false},
+		{"Setup", []*discordgo.TextInput{validInput, {CustomID: "name", Label: "Other"}}, false},
+		{"Setup", []*discordgo.TextInput{{CustomID: "", Label: "Name"}}, false},
+		{"Setup", []*discordgo.TextInput{{CustomID: "name", Label: ""}}, false},
+		{"Setup", []*discordgo.TextInput{{CustomID: "name", Label: "Name", MinLength: 5, MaxLength: 2}}, false},
+		{"Setup", []*discordgo.TextInput{{CustomID: "name", Label: "Name", MaxLength: MaxTextInputValueLength + 1}}, false},
+		{"Setup", []*discordgo.TextInput{{CustomID: "name", Label: "Name", MinLength: 2, MaxLength: 5}}, true},
+	}
+
+	for _, table := range tables {
+		err := ValidateModal(table.title, table.inputs)
+
+		if table.isValid {
+			suite.NoError(err, "Arguments: %v, %v", table.title, table.inputs)
+
+			continue
+		}
+
+		suite.Error(err, "Arguments: %v, %v", table.title, table.inputs)
+	}
+}
+
+func (suite *ModalTestSuite) TestOpenModal() {
+	session, transport := discordgo_mock.MockSession()
+
+	component := &api.Component{}
+	loggerMock := &logmock.LoggerMock{}
+	component.SetLogger(loggerMock)
+
+	var requestInteractionResponse json.RawMessage
+	transport.OnRequestCaptureResult(http.MethodPost, &requestInteractionResponse).Once().Return(
+		&http.Response{
+			StatusCode: http.StatusNoContent,
+		}, nil)
+
+	err := OpenModal(component,
+		session,
+		suite.interactionCreate,
+		"bot_test:setup",
+		"Setup",
+		&discordgo.TextInput{CustomID: "name", Label: "Name"})
+
+	suite.NoError(err)
+	transport.AssertExpectations(suite.T())
+	loggerMock.AssertNotCalled(suite.T(), "Err", mock.Anything, mock.Anything, mock.Anything)
+
+	var response struct {
+		Type discordgo.InteractionResponseType `json:"type"`
+		Data struct {
+			CustomID   string `json:"custom_id"`
+			Title      string `json:"title"`
+			Components []struct {
+				Components []struct {
+					CustomID string                   `json:"custom_id"`
+					Style    discordgo.TextInputStyle `json:"style"`
+				} `json:"components"`
+			} `json:"components"`
+		} `json:"data"`
+	}
+	suite.NoError(json.Unmarshal(requestInteractionResponse, &response))
+
+	suite.Equal(discordgo.InteractionResponseModal, response.Type)
+	suite.Equal("bot_test:setup", response.Data.CustomID)
+	suite.Equal("Setup", response.Data.Title)
+	suite.Len(response.Data.Components, 1)
+	suite.Len(response.Data.Components[0].Components, 1)
+	suite.Equal("name", response.Data.Components[0].Components[0].CustomID)
+	suite.Equal(discordgo.TextInputShort, response.Data.Components[0].Components[0].Style)
+}
+
+func (suite *ModalTestSuite) TestOpenModalWithInvalidModal() {
+	session, transport := discordgo_mock.MockSession()
+
+	component := &api.Component{}
+	loggerMock := &logmock.LoggerMock{}
+	component.SetLogger(loggerMock)
+
+	loggerMock.On("Err", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	err := OpenModal(component, session, suite.interactionCreate, "bot_test:setup", "")
+
+	suite.Error(err)
+	transport.AssertNotCalled(suite.T(), "RoundTrip", mock.Anything)
+}
+
+func (suite *ModalTestSuite) TestRespondWithModalInputError() {
+	session, transport := discordgo_mock.MockSession()
+
+	component := &api.Component{}
+	loggerMock := &logmock.LoggerMock{}
+	component.SetLogger(loggerMock)
+
+	requestInteractionResponse := &discordgo.InteractionResponse{}
+	transport.OnRequestCaptureResult(http.MethodPost, requestInteractionResponse).Once().Return(
+		&http.Response{
+			StatusCode: http.StatusCreated,
+		}, nil)
+
+	RespondWithModalInputError(component, session, suite.interactionCreate, &api.ModalInputError{
+		CustomId: "amount",
+		Value:    "five",
+		Err:      fmt.Errorf("the value is not a valid integer"),
+	})
+
+	transport.AssertExpectations(suite.T())
+	suite.Equal(discordgo.MessageFlagsEphemeral, requestInteractionResponse.Data.Flags)
+	suite.Equal(ModalInputErrorResponseEmbedName, requestInteractionResponse.Data.Embeds[0].Fields[0].Name)
+	suite.Contains(requestInteractionResponse.Data.Embeds[0].Fields[0].Value, "amount")
+}
+
+func TestModal(t *testing.T) {
+	suite.Run(t, new(ModalTestSuite))
+}
@@ -0,0 +1,557 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package slash_commands
+
+import (
+	"errors"
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// These are the struct tags that are used to bind command options
+// to the fields of a struct.
+//
+// Only fields with the option tag are considered. The tag holds the name of the option.
+// All other tags are optional:
+//   - description: the description of the option
+//   - required: "true" if the option must be passed
+//   - default: the value used when the option has not been passed
+//   - min / max: the value range of integers and numbers or the length range of strings
+//   - channel_types: comma separated list of allowed channel types (text, voice, category, news, stage, forum)
+//   - autocomplete: "true" if suggestions for the option are provided by an autocomplete handler
+const (
+	OptionTagName         = "option"
+	OptionTagDescription  = "description"
+	OptionTagRequired     = "required"
+	OptionTagDefault      = "default"
+	OptionTagMin          = "min"
+	OptionTagMax          = "max"
+	OptionTagChannelTypes = "channel_types"
+	OptionTagAutocomplete = "autocomplete"
+)
+
+const (
+	OptionBindingErrorResponseTitle     = "Invalid Options"
+	OptionBindingErrorResponseEmbedName = ":x: Invalid option!"
+)
+
+// channelTypeNames maps the names that can be used in the channel_types tag
+// to the corresponding discordgo.ChannelType.
+var channelTypeNames = map[string]discordgo.ChannelType{
+	"text":     discordgo.ChannelTypeGuildText,
+	"voice":    discordgo.ChannelTypeGuildVoice,
+	"category": discordgo.ChannelTypeGuildCategory,
+	"news":     discordgo.ChannelTypeGuildNews,
+	"stage":    discordgo.ChannelTypeGuildStageVoice,
+	"forum":    discordgo.ChannelTypeGuildForum,
+}
+
+var (
+	userType    = reflect.TypeOf(&discordgo.User{})
+	channelType = reflect.TypeOf(&discordgo.Channel{})
+	roleType    = reflect.TypeOf(&discordgo.Role{})
+)
+
+// OptionBindingError is returned when a command option cannot be bound
+// to the target struct, because it is missing or invalid.
+type OptionBindingError struct {
+	Option string
+	Err    error
+}
+
+// Error returns the message of the OptionBindingError.
+func (obe *OptionBindingError) Error() string {
+	return fmt.Sprintf("the option \"%s\" is invalid: %v", obe.Option, obe.Err)
+}
+
+// Unwrap returns the error that caused the OptionBindingError.
+func (obe *OptionBindingError) Unwrap() error {
+	return obe.Err
+}
+
+// boundOption holds the information about a single struct field
+// that is bound to a command option.
+type boundOption struct {
+	fieldIndex   int
+	name         string
+	description  string
+	optionType   discordgo.ApplicationCommandOptionType
+	required     bool
+	defaultValue *string
+	min          *float64
+	max          *float64
+	channelTypes []discordgo.ChannelType
+	autocomplete bool
+}
+
+// BindOptions decodes the passed command options into the passed struct pointer.
+//
+// Pass the options of the (sub-)command the struct describes, e.g. the options
+// of the sub-command option received by a sub-command handler.
+// Users, channels and roles are resolved using the resolved data of the interaction
+// and fall back to the session state or the Discord API.
+//
+// Options that have not been passed and have no default leave the field untouched,
+// which allows to prefill the struct with dynamic defaults.
+// Missing required options and values outside the configured range result in an OptionBindingError.
+//
+// See the OptionTagName constant for the supported struct tags.
+func BindOptions(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	options []*discordgo.ApplicationCommandInteractionDataOption,
+	target interface{},
+) error {
+	targetValue := reflect.ValueOf(target)
+	if targetValue.Kind() != reflect.Pointer || targetValue.Elem().Kind() != reflect.Struct {
+		return errors.New("the target of the option binding must be a pointer to a struct")
+	}
+	targetValue = targetValue.Elem()
+
+	boundOptions, err := parseBoundOptions(targetValue.Type())
+	if nil != err {
+		return err
+	}
+
+	passedOptions := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, option := range options {
+		passedOptions[option.Name] = option
+	}
+
+	for _, bound := range boundOptions {
+		field := targetValue.Field(bound.fieldIndex)
+
+		option, ok := passedOptions[bound.name]
+		switch {
+		case ok:
+			err = bindOptionValue(s, i, bound, option, field)
+		case nil != bound.defaultValue:
+			err = setFieldFromString(field, *bound.defaultValue)
+		case bound.required:
+			err = errors.New("the option is required")
+		default:
+			continue
+		}
+
+		if nil == err {
+			err = validateBoundValue(bound, field)
+		}
+
+		if nil != err {
+			return &OptionBindingError{
+				Option: bound.name,
+				Err:    err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// GenerateOptions generates the discordgo.ApplicationCommandOption definitions
+// from the struct tags of the passed struct.
+// Using the same struct for BindOptions ensures that definition and parsing do not drift apart.
+//
+// Discord requires required options to be listed before optional ones,
+// so the fields of required options must be declared first.
+func GenerateOptions(target interface{}) ([]*discordgo.ApplicationCommandOption, error) {
+	targetType := reflect.TypeOf(target)
+	if targetType.Kind() == reflect.Pointer {
+		targetType = targetType.Elem()
+	}
+
+	if targetType.Kind() != reflect.Struct {
+		return nil, errors.New("the options can only be generated from a struct")
+	}
+
+	boundOptions, err := parseBoundOptions(targetType)
+	if nil != err {
+		return nil, err
+	}
+
+	commandOptions := make([]*discordgo.ApplicationCommandOption, len(boundOptions))
+	hasOptionalOption := false
+	for key, bound := range boundOptions {
+		if bound.required && hasOptionalOption {
+			return nil, fmt.Errorf("the required option \"%s\" must be declared before optional options",
+				bound.name)
+		}
+		hasOptionalOption = hasOptionalOption || !bound.required
+
+		commandOptions[key] = bound.toCommandOption()
+	}
+
+	return commandOptions, nil
+}
+
+// MustGenerateOptions works like GenerateOptions but panics, if the options
+// cannot be generated. It simplifies the declaration of commands in package variables.
+func MustGenerateOptions(target interface{}) []*discordgo.ApplicationCommandOption {
+	options, err := GenerateOptions(target)
+	if nil != err {
+		panic(fmt.Sprintf("cannot generate command options: %v", err))
+	}
+
+	return options
+}
+
+// RespondWithOptionBindingError responds with an ephemeral message that describes
+// why the passed options could not be bound.
+//
+// Errors that are no OptionBindingError result in a generic error message.
+func RespondWithOptionBindingError(
+	c *api.Component,
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	err error,
+) {
+	resp := GenerateEphemeralInteractionResponseTemplate(OptionBindingErrorResponseTitle, "")
+
+	var bindingErr *OptionBindingError
+	if !errors.As(err, &bindingErr) {
+		c.Logger().Err(err, "Failed to bind command options!")
+		RespondWithGenericErrorMessage(c, s, i, resp)
+
+		return
+	}
+
+	RespondWithSimpleEmbedMessage(c,
+		s,
+		i,
+		resp,
+		OptionBindingErrorResponseEmbedName,
+		fmt.Sprintf("The option `%s` is invalid: %s", bindingErr.Option, bindingErr.Err.Error()))
+}
+
+// parseBoundOptions collects the information about all fields of the passed
+// struct type that are tagged with the option tag.
+func parseBoundOptions(targetType reflect.Type) ([]*boundOption, error) {
+	boundOptions := make([]*boundOption, 0)
+
+	for fieldIndex := 0; fieldIndex < targetType.NumField(); fieldIndex++ {
+		field := targetType.Field(fieldIndex)
+
+		name, ok := field.Tag.Lookup(OptionTagName)
+		if !ok || "" == name {
+			continue
+		}
+
+		optionType, err := getOptionTypeForField(field.Type)
+		if nil != err {
+			return nil, fmt.Errorf("cannot bind option \"%s\": %w", name, err)
+		}
+
+		bound := &boundOption{
+			fieldIndex:   fieldIndex,
+			name:         name,
+			description:  field.Tag.Get(OptionTagDescription),
+			optionType:   optionType,
+			required:     "true" == field.Tag.Get(OptionTagRequired),
+			autocomplete: "true" == field.Tag.Get(OptionTagAutocomplete),
+		}
+
+		if defaultValue, ok := field.Tag.Lookup(OptionTagDefault); ok {
+			bound.defaultValue = &defaultValue
+		}
+
+		bound.min, err = parseFloatTag(field, OptionTagMin)
+		if nil != err {
+			return nil, fmt.Errorf("cannot bind option \"%s\": %w", name, err)
+		}
+
+		bound.max, err = parseFloatTag(field, OptionTagMax)
+		if nil != err {
+			return nil, fmt.Errorf("cannot bind option \"%s\": %w", name, err)
+		}
+
+		bound.channelTypes, err = parseChannelTypesTag(field)
+		if nil != err {
+			return nil, fmt.Errorf("cannot bind option \"%s\": %w", name, err)
+		}
+
+		boundOptions = append(boundOptions, bound)
+	}
+
+	return boundOptions, nil
+}
+
+// getOptionTypeForField returns the discordgo.ApplicationCommandOptionType
+// that corresponds to the passed field type.
+func getOptionTypeForField(fieldType reflect.Type) (discordgo.ApplicationCommandOptionType, error) {
+	switch fieldType {
+	case userType:
+		return discordgo.ApplicationCommandOptionUser, nil
+	case channelType:
+		return discordgo.ApplicationCommandOptionChannel, nil
+	case roleType:
+		return discordgo.ApplicationCommandOptionRole, nil
+	}
+
+	switch fieldType.Kind() {
+	case reflect.String:
+		return discordgo.ApplicationCommandOptionString, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return discordgo.ApplicationCommandOptionInteger, nil
+	case reflect.Float32, reflect.Float64:
+		return discordgo.ApplicationCommandOptionNumber, nil
+	case reflect.Bool:
+		return discordgo.ApplicationCommandOptionBoolean, nil
+	default:
+		return 0, fmt.Errorf("the field type \"%s\" is not supported", fieldType)
+	}
+}
+
+// parseFloatTag parses the value of the passed tag of the passed field as float.
+// Returns nil if the tag is not present.
+func parseFloatTag(field reflect.StructField, tag string) (*float64, error) {
+	rawValue, ok := field.Tag.Lookup(tag)
+	if !ok {
+		return nil, nil
+	}
+
+	value, err := strconv.ParseFloat(rawValue, 64)
+	if nil != err {
+		return nil, fmt.Errorf("the value \"%s\" of the %s tag is not a valid number", rawValue, tag)
+	}
+
+	return &value, nil
+}
+
+// parseChannelTypesTag parses the channel_types tag of the passed field.
+// Returns nil if the tag is not present.
+func parseChannelTypesTag(field reflect.StructField) ([]discordgo.ChannelType, error) {
+	rawValue, ok := field.Tag.Lookup(OptionTagChannelTypes)
+	if !ok {
+		return nil, nil
+	}
+
+	channelTypes := make([]discordgo.ChannelType, 0)
+	for _, name := range strings.Split(rawValue, ",") {
+		channelType, ok := channelTypeNames[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("the channel type \"%s\" is unknown", name)
+		}
+
+		channelTypes = append(channelTypes, channelType)
+	}
+
+	return channelTypes, nil
+}
+
+// bindOptionValue sets the value of the passed option to the passed field.
+func bindOptionValue(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	bound *boundOption,
+	option *discordgo.ApplicationCommandInteractionDataOption,
+	field reflect.Value,
+) error {
+	if option.Type != bound.optionType {
+		return fmt.Errorf("expected an option of type %s but received %s", bound.optionType, option.Type)
+	}
+
+	switch bound.optionType {
+	case discordgo.ApplicationCommandOptionString:
+		field.SetString(option.StringValue())
+	case discordgo.ApplicationCommandOptionInteger:
+		field.SetInt(option.IntValue())
+	case discordgo.ApplicationCommandOptionNumber:
+		field.SetFloat(option.FloatValue())
+	case discordgo.ApplicationCommandOptionBoolean:
+		field.SetBool(option.BoolValue())
+	case discordgo.ApplicationCommandOptionUser:
+		field.Set(reflect.ValueOf(resolveUser(s, i, option)))
+	case discordgo.ApplicationCommandOptionChannel:
+		field.Set(reflect.ValueOf(resolveChannel(s, i, option)))
+	case discordgo.ApplicationCommandOptionRole:
+		field.Set(reflect.ValueOf(resolveRole(s, i, option)))
+	}
+
+	return nil
+}
+
+// setFieldFromString parses the passed raw value (e.g. a default value)
+// into the type of the passed field and sets the value of the field.
+func setFieldFromString(field reflect.Value, rawValue string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(rawValue)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := strconv.ParseInt(rawValue, 10, field.Type().Bits())
+		if nil != err {
+			return fmt.Errorf("the default value \"%s\" is not a valid integer", rawValue)
+		}
+		field.SetInt(value)
+	case reflect.Float32, reflect.Float64:
+		value, err := strconv.ParseFloat(rawValue, field.Type().Bits())
+		if nil != err {
+			return fmt.Errorf("the default value \"%s\" is not a valid number", rawValue)
+		}
+		field.SetFloat(value)
+	case reflect.Bool:
+		value, err := strconv.ParseBool(rawValue)
+		if nil != err {
+			return fmt.Errorf("the default value \"%s\" is not a valid boolean", rawValue)
+		}
+		field.SetBool(value)
+	default:
+		return fmt.Errorf("default values are not supported for fields of type \"%s\"", field.Type())
+	}
+
+	return nil
+}
+
+// validateBoundValue checks whether the value of the passed field
+// is within the range configured for the option.
+func validateBoundValue(bound *boundOption, field reflect.Value) error {
+	var value float64
+	var unit string
+
+	switch field.Kind() {
+	case reflect.String:
+		value = float64(utf8.RuneCountInString(field.String()))
+		unit = " characters"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value = float64(field.Int())
+	case reflect.Float32, reflect.Float64:
+		value = field.Float()
+	default:
+		return nil
+	}
+
+	if nil != bound.min && value < *bound.min {
+		return fmt.Errorf("the value must be at least %v%s", *bound.min, unit)
+	}
+
+	if nil != bound.max && value > *bound.max {
+		return fmt.Errorf("the value must be at most %v%s", *bound.max, unit)
+	}
+
+	return nil
+}
+
+// getResolvedData returns the resolved data of the passed interaction, if available.
+func getResolvedData(i *discordgo.InteractionCreate) *discordgo.ApplicationCommandInteractionDataResolved {
+	if nil == i || nil == i.Interaction {
+		return nil
+	}
+
+	if i.Type != discordgo.InteractionApplicationCommand &&
+		i.Type != discordgo.InteractionApplicationCommandAutocomplete {
+		return nil
+	}
+
+	return i.ApplicationCommandData().Resolved
+}
+
+// getOptionSnowflake returns the id of the user, channel or role
+// passed as value of the passed option.
+func getOptionSnowflake(option *discordgo.ApplicationCommandInteractionDataOption) string {
+	snowflake, _ := option.Value.(string)
+
+	return snowflake
+}
+
+// resolveUser returns the user passed as option value.
+func resolveUser(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	option *discordgo.ApplicationCommandInteractionDataOption,
+) *discordgo.User {
+	resolved := getResolvedData(i)
+	if nil != resolved {
+		if user, ok := resolved.Users[getOptionSnowflake(option)]; ok {
+			return user
+		}
+	}
+
+	return option.UserValue(s)
+}
+
+// resolveChannel returns the channel passed as option value.
+func resolveChannel(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	option *discordgo.ApplicationCommandInteractionDataOption,
+) *discordgo.Channel {
+	resolved := getResolvedData(i)
+	if nil != resolved {
+		if channel, ok := resolved.Channels[getOptionSnowflake(option)]; ok {
+			return channel
+		}
+	}
+
+	return option.ChannelValue(s)
+}
+
+// resolveRole returns the role passed as option value.
+func resolveRole(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	option *discordgo.ApplicationCommandInteractionDataOption,
+) *discordgo.Role {
+	resolved := getResolvedData(i)
+	if nil != resolved {
+		if role, ok := resolved.Roles[getOptionSnowflake(option)]; ok {
+			return role
+		}
+	}
+
+	guildId := ""
+	if nil != i && nil != i.Interaction {
+		guildId = i.GuildID
+	}
+
+	return option.RoleValue(s, guildId)
+}
+
+// toCommandOption converts the boundOption to a discordgo.ApplicationCommandOption.
+func (bo *boundOption) toCommandOption() *discordgo.ApplicationCommandOption {
+	commandOption := &discordgo.ApplicationCommandOption{
+		Name:         bo.name,
+		Description:  bo.description,
+		Type:         bo.optionType,
+		Required:     bo.required,
+		Autocomplete: bo.autocomplete,
+		ChannelTypes: bo.channelTypes,
+	}
+
+	switch bo.optionType {
+	case discordgo.ApplicationCommandOptionInteger, discordgo.ApplicationCommandOptionNumber:
+		commandOption.MinValue = bo.min
+		if nil != bo.max {
+			commandOption.MaxValue = *bo.max
+		}
+	case discordgo.ApplicationCommandOptionString:
+		if nil != bo.min {
+			minLength := int(*bo.min)
+			commandOption.MinLength = &minLength
+		}
+		if nil != bo.max {
+			commandOption.MaxLength = int(*bo.max)
+		}
+	}
+
+	return commandOption
+}
@@ -0,0 +1,258 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package slash_commands
+
+import (
+	"errors"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type OptionBindingTestSuite struct {
+	suite.Suite
+}
+
+type optionBindingTestOptions struct {
+	Target  *discordgo.User    `option:"target" description:"The target user" required:"true"`
+	Channel *discordgo.Channel `option:"channel" description:"The channel" channel_types:"text,news"`
+	Role    *discordgo.Role    `option:"role" description:"The role"`
+	Amount  int                `option:"amount" description:"The amount" default:"6" min:"2" max:"10"`
+	Ratio   float64            `option:"ratio" description:"The ratio" min:"0" max:"1"`
+	Reason  string             `option:"reason" description:"The reason" max:"5" autocomplete:"true"`
+	Silent  bool               `option:"silent" description:"Whether to be silent"`
+	Ignored string
+}
+
+func (suite *OptionBindingTestSuite) TestBindOptions() {
+	i := &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type:    discordgo.InteractionApplicationCommand,
+			GuildID: "42",
+			Data: discordgo.ApplicationCommandInteractionData{
+				Resolved: &discordgo.ApplicationCommandInteractionDataResolved{
+					Users: map[string]*discordgo.User{
+						"1": {ID: "1", Username: "jotaro"},
+					},
+					Channels: map[string]*discordgo.Channel{
+						"2": {ID: "2", Name: "general"},
+					},
+					Roles: map[string]*discordgo.Role{
+						"3": {ID: "3", Name: "stand-users"},
+					},
+				},
+			},
+		},
+	}
+
+	options := []*discordgo.ApplicationCommandInteractionDataOption{
+		{Name: "target", Type: discordgo.ApplicationCommandOptionUser, Value: "1"},
+		{Name: "channel", Type: discordgo.ApplicationCommandOptionChannel, Value: "2"},
+		{Name: "role", Type: discordgo.ApplicationCommandOptionRole, Value: "3"},
+		{Name: "ratio", Type: discordgo.ApplicationCommandOptionNumber, Value: 0.5},
+		{Name: "reason", Type: discordgo.ApplicationCommandOptionString, Value: "ora"},
+		{Name: "silent", Type: discordgo.ApplicationCommandOptionBoolean, Value: true},
+	}
+
+	result := optionBindingTestOptions{Ignored: "untouched"}
+	err := BindOptions(nil, i, options, &result)
+
+	suite.NoError(err)
+	suite.Equal("jotaro", result.Target.Username)
+	suite.Equal("general", result.Channel.Name)
+	suite.Equal("stand-users", result.Role.Name)
+	suite.Equal(6, result.Amount)
+	suite.Equal(0.5, result.Ratio)
+	suite.Equal("ora", result.Reason)
+	suite.True(result.Silent)
+	suite.Equal("untouched", result.Ignored)
+}
+
+func (suite *OptionBindingTestSuite) TestBindOptionsKeepsPrefilledValues() {
+	options := []*discordgo.ApplicationCommandInteractionDataOption{
+		{Name: "target", Type: discordgo.ApplicationCommandOptionUser, Value: "1"},
+	}
+
+	result := optionBindingTestOptions{Reason: "prefilled"}
+	err := BindOptions(nil, nil, options, &result)
+
+	suite.NoError(err)
+	suite.Equal("1", result.Target.ID)
+	suite.Equal("prefilled", result.Reason)
+}
+
+func (suite *OptionBindingTestSuite) TestBindOptionsCountsCharacters() {
+	options := []*discordgo.ApplicationCommandInteractionDataOption{
+		{Name: "target", Type: discordgo.ApplicationCommandOptionUser, Value: "1"},
+		{Name: "reason", Type: discordgo.ApplicationCommandOptionString, Value: "äöüßé"},
+	}
+
+	result := optionBindingTestOptions{}
+	err := BindOptions(nil, nil, options, &result)
+
+	suite.NoError(err)
+	suite.Equal("äöüßé", result.Reason)
+}
+
+func (suite *OptionBindingTestSuite) TestBindOptionsWithInvalidOptions() {
+	target := &discordgo.ApplicationCommandInteractionDataOption{
+		Name: "target", Type: discordgo.ApplicationCommandOptionUser, Value: "1",
+	}
+
+	tables := []struct {
+		options        []*discordgo.ApplicationCommandInteractionDataOption
+		expectedOption string
+	}{
+		{[]*discordgo.ApplicationCommandInteractionDataOption{}, "target"},
+		{[]*discordgo.ApplicationCommandInteractionDataOption{
+			target,
+			{Name: "amount", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(1)},
+		}, "amount"},
+		{[]*discordgo.ApplicationCommandInteractionDataOption{
+			target,
+			{Name: "amount", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(11)},
+		}, "amount"},
+		{[]*discordgo.ApplicationCommandInteractionDataOption{
+			target,
+			{Name: "ratio", Type: discordgo.ApplicationCommandOptionNumber, Value: 1.5},
+		}, "ratio"},
+		{[]*discordgo.ApplicationCommandInteractionDataOption{
+			target,
+			{Name: "reason", Type: discordgo.ApplicationCommandOptionString, Value: "too long"},
+		}, "reason"},
+		{[]*discordgo.ApplicationCommandInteractionDataOption{
+			target,
+			{Name: "reason", Type: discordgo.ApplicationCommandOptionString, Value: "äöüßéè"},
+		}, "reason"},
+		{[]*discordgo.ApplicationCommandInteractionDataOption{
+			target,
+			{Name: "silent", Type: discordgo.ApplicationCommandOptionString, Value: "true"},
+		}, "silent"},
+	}
+
+	for _, table := range tables {
+		result := optionBindingTestOptions{}
+		err := BindOptions(nil, nil, table.options, &result)
+
+		var bindingErr *OptionBindingError
+		suite.True(errors.As(err, &bindingErr), "Option: %s", table.expectedOption)
+		suite.Equal(table.expectedOption, bindingErr.Option)
+	}
+}
+
+func (suite *OptionBindingTestSuite) TestBindOptionsWithInvalidTarget() {
+	suite.Error(BindOptions(nil, nil, nil, optionBindingTestOptions{}))
+	suite.Error(BindOptions(nil, nil, nil, new(string)))
+	suite.Error(BindOptions(nil, nil, nil, &struct {
+		Invalid []string `option:"invalid"`
+	}{}))
+}
+
+func (suite *OptionBindingTestSuite) TestGenerateOptions() {
+	minAmount := float64(2)
+	minRatio := float64(0)
+
+	expected := []*discordgo.ApplicationCommandOption{
+		{
+			Name:        "target",
+			Description: "The target user",
+			Type:        discordgo.ApplicationCommandOptionUser,
+			Required:    true,
+		},
+		{
+			Name:        "channel",
+			Description: "The channel",
+			Type:        discordgo.ApplicationCommandOptionChannel,
+			ChannelTypes: []discordgo.ChannelType{
+				discordgo.ChannelTypeGuildText,
+				discordgo.ChannelTypeGuildNews,
+			},
+		},
+		{
+			Name:        "role",
+			Description: "The role",
+			Type:        discordgo.ApplicationCommandOptionRole,
+		},
+		{
+			Name:        "amount",
+			Description: "The amount",
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			MinValue:    &minAmount,
+			MaxValue:    10,
+		},
+		{
+			Name:        "ratio",
+			Description: "The ratio",
+			Type:        discordgo.ApplicationCommandOptionNumber,
+			MinValue:    &minRatio,
+			MaxValue:    1,
+		},
+		{
+			Name:         "reason",
+			Description:  "The reason",
+			Type:         discordgo.ApplicationCommandOptionString,
+			MaxLength:    5,
+			Autocomplete: true,
+		},
+		{
+			Name:        "silent",
+			Description: "Whether to be silent",
+			Type:        discordgo.ApplicationCommandOptionBoolean,
+		},
+	}
+
+	options, err := GenerateOptions(&optionBindingTestOptions{})
+
+	suite.NoError(err)
+	suite.Equal(expected, options)
+}
+
+func (suite *OptionBindingTestSuite) TestGenerateOptionsWithInvalidOrder() {
+	_, err := GenerateOptions(struct {
+		Optional string `option:"optional"`
+		Required string `option:"required" required:"true"`
+	}{})
+
+	suite.Error(err)
+	suite.Panics(func() {
+		MustGenerateOptions(struct {
+			Optional string `option:"optional"`
+			Required string `option:"required" required:"true"`
+		}{})
+	})
+}
+
+func (suite *OptionBindingTestSuite) TestGenerateOptionsWithInvalidTags() {
+	_, err := GenerateOptions(struct {
+		Amount int `option:"amount" min:"two"`
+	}{})
+	suite.Error(err)
+
+	_, err = GenerateOptions(struct {
+		Channel *discordgo.Channel `option:"channel" channel_types:"dm"`
+	}{})
+	suite.Error(err)
+
+	_, err = GenerateOptions("not a struct")
+	suite.Error(err)
+}
+
+func TestOptionBinding(t *testing.T) {
+	suite.Run(t, new(OptionBindingTestSuite))
+}
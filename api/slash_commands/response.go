@@ -25,8 +25,8 @@ import (
 )
 
 const (
-	GenericErrorResponseEmbedName  = ":x: Damn, something went wrong!"
-	GenericErrorResponseEmbedValue = "Something unexpected happened while processing the command!"
+	GenericErrorResponseEmbedName  = api.GenericErrorResponseEmbedName
+	GenericErrorResponseEmbedValue = api.GenericErrorResponseEmbedValue
 )
 
 // GenerateInteractionResponseTemplate creates a prefilled discordgo.InteractionResponseData
@@ -78,16 +78,16 @@ func GenerateEphemeralInteractionResponseTemplate(
 // Respond to the target interaction with the passed
 // discordgo.InteractionResponseData as a message in the channel
 // where the interaction has been triggered.
+//
+// When the interaction has been deferred, the deferred response is replaced.
+// See ResponseWriter for details.
 func Respond(
 	c *api.Component,
 	s *discordgo.Session,
 	i *discordgo.InteractionCreate,
 	resp *discordgo.InteractionResponseData,
 ) {
-	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: resp,
-	})
+	_, err := NewResponseWriter(c, s, i).Write(resp)
 
 	if nil != err {
 		c.Logger().Err(err, "Failed to deliver interaction response on slash-command!")
@@ -120,6 +120,7 @@ func RespondWithSimpleEmbedMessage(
 
 // RespondWithGenericErrorMessage fills the passed discordgo.InteractionResponseData
 // with a generic error message as content.
+// The message is translated to the locale of the interaction.
 //
 // The prepared interaction response will be sent.
 func RespondWithGenericErrorMessage(
@@ -130,8 +131,8 @@ func RespondWithGenericErrorMessage(
 ) {
 	embeds := []*discordgo.MessageEmbedField{
 		{
-			Name:  GenericErrorResponseEmbedName,
-			Value: GenericErrorResponseEmbedValue,
+			Name:  api.Translate(i, api.GenericErrorResponseEmbedNameKey),
+			Value: api.Translate(i, api.GenericErrorResponseEmbedValueKey),
 		},
 	}
 
@@ -164,7 +165,7 @@ func EditResponse(
 	i *discordgo.InteractionCreate,
 	editData *discordgo.WebhookEdit,
 ) *discordgo.Message {
-	message, err := s.InteractionResponseEdit(i.Interaction, editData)
+	message, err := NewResponseWriter(c, s, i).Edit(editData)
 
 	if nil != err {
 		c.Logger().Err(err, "Failed to deliver interaction response on slash-command!")
@@ -0,0 +1,67 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package slash_commands
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
+)
+
+// ResponseWriter is used to respond to an interaction without having to know
+// whether the interaction has already been deferred or responded to.
+//
+// Commands that do not respond within the configured threshold are deferred
+// automatically (see api.CommandAutoDeferMiddleware). The ResponseWriter
+// transparently replaces the deferred response or sends a follow-up message.
+type ResponseWriter struct {
+	c *api.Component
+	s *discordgo.Session
+	i *discordgo.InteractionCreate
+}
+
+// NewResponseWriter creates a new ResponseWriter for the passed interaction.
+func NewResponseWriter(
+	c *api.Component,
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+) *ResponseWriter {
+	return &ResponseWriter{
+		c: c,
+		s: s,
+		i: i,
+	}
+}
+
+// Write sends the passed discordgo.InteractionResponseData.
+//
+// The first response is sent as initial response or replaces the deferred response.
+// All consecutive responses are sent as follow-up messages.
+func (rw *ResponseWriter) Write(resp *discordgo.InteractionResponseData) (*discordgo.Message, error) {
+	return api.RespondToInteraction(rw.s, rw.i, resp)
+}
+
+// Edit edits the original response of the interaction with the passed discordgo.WebhookEdit.
+func (rw *ResponseWriter) Edit(editData *discordgo.WebhookEdit) (*discordgo.Message, error) {
+	return rw.s.InteractionResponseEdit(rw.i.Interaction, editData)
+}
+
+// Followup sends the passed discordgo.WebhookParams as follow-up message.
+func (rw *ResponseWriter) Followup(params *discordgo.WebhookParams) (*discordgo.Message, error) {
+	return rw.s.FollowupMessageCreate(rw.i.Interaction, true, params)
+}
@@ -0,0 +1,325 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"sync"
+	"time"
+)
+
+// CommandHandlerFunc is the signature of a function that handles
+// the execution of a command.
+type CommandHandlerFunc func(s *discordgo.Session, i *discordgo.InteractionCreate)
+
+// CommandMiddleware wraps the execution of a command.
+//
+// A middleware receives the command that is executed and the next
+// handler in the chain. It returns a handler that is able to run code
+// before and after the next handler. By not calling next, a middleware
+// can stop the execution of the command. In that case, the middleware is
+// responsible to respond to the interaction.
+//
+// Middlewares are executed in the following order:
+//  1. the default middlewares (auto defer, recovery, status check, permission overrides, logging and rate limit)
+//  2. the global middlewares registered using UseCommandMiddleware
+//  3. the middlewares registered by the owning component using CommonSlashCommandManager.Use
+//  4. the middlewares of the Command itself
+type CommandMiddleware func(command *Command, next CommandHandlerFunc) CommandHandlerFunc
+
+var (
+	// defaultCommandMiddlewares are the middlewares that wrap every command
+	// before any other middleware.
+	defaultCommandMiddlewares = []CommandMiddleware{
+		CommandAutoDeferMiddleware,
+		CommandRecoveryMiddleware,
+		CommandStatusMiddleware,
+		CommandPermissionOverrideMiddleware,
+		CommandLoggingMiddleware,
+		CommandRateLimitMiddleware,
+	}
+
+	// globalCommandMiddlewares holds the middlewares that have been
+	// registered using UseCommandMiddleware.
+	globalCommandMiddlewares []CommandMiddleware
+
+	// componentCommandMiddlewares holds the middlewares registered by components,
+	// with the code of the owning component as key.
+	componentCommandMiddlewares = make(map[entities.ComponentCode][]CommandMiddleware)
+
+	// commandMiddlewaresMu is used to synchronize access to the global
+	// and component middlewares, as they can be registered at runtime.
+	commandMiddlewaresMu sync.RWMutex
+)
+
+// UseCommandMiddleware registers the passed middlewares globally.
+// Global middlewares wrap the commands of all components.
+func UseCommandMiddleware(middlewares ...CommandMiddleware) {
+	commandMiddlewaresMu.Lock()
+	defer commandMiddlewaresMu.Unlock()
+
+	globalCommandMiddlewares = append(globalCommandMiddlewares, middlewares...)
+}
+
+// Use registers the passed middlewares for all commands owned by the component.
+//
+// The middlewares are removed when the component is unloaded.
+func (c *SlashCommandManager) Use(middlewares ...CommandMiddleware) {
+	commandMiddlewaresMu.Lock()
+	defer commandMiddlewaresMu.Unlock()
+
+	componentCommandMiddlewares[c.owner.Code] = append(componentCommandMiddlewares[c.owner.Code], middlewares...)
+}
+
+// removeComponentCommandMiddlewares removes all middlewares registered
+// by the component with the passed code.
+func removeComponentCommandMiddlewares(code entities.ComponentCode) {
+	commandMiddlewaresMu.Lock()
+	defer commandMiddlewaresMu.Unlock()
+
+	delete(componentCommandMiddlewares, code)
+}
+
+// buildCommandHandlerChain wraps the handler of the passed command
+// with all middlewares that apply to the command.
+func buildCommandHandlerChain(command *Command) CommandHandlerFunc {
+	commandMiddlewaresMu.RLock()
+	middlewares := make([]CommandMiddleware, 0, len(defaultCommandMiddlewares)+len(globalCommandMiddlewares))
+	middlewares = append(middlewares, defaultCommandMiddlewares...)
+	middlewares = append(middlewares, globalCommandMiddlewares...)
+	if nil != command.c {
+		middlewares = append(middlewares, componentCommandMiddlewares[command.c.Code]...)
+	}
+	commandMiddlewaresMu.RUnlock()
+
+	middlewares = append(middlewares, command.Middlewares...)
+
+	handler := CommandHandlerFunc(command.Handler)
+	for idx := len(middlewares) - 1; idx >= 0; idx-- {
+		handler = middlewares[idx](command, handler)
+	}
+
+	return handler
+}
+
+// CommandRecoveryMiddleware recovers from panics that occur during the execution
+// of a command. The panic is logged with an error reference and the user is informed
+// that something went wrong. Repeated panics result in the owning component
+// being disabled globally.
+func CommandRecoveryMiddleware(command *Command, next CommandHandlerFunc) CommandHandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		defer recoverFromInteractionPanic(s, i, command.c, fmt.Sprintf("command \"%s\" on guild \"%s\"",
+			command.Cmd.Name,
+			i.GuildID))
+
+		next(s, i)
+	}
+}
+
+// CommandStatusMiddleware ensures that disabled commands and the commands of disabled components
+// are not executed. It takes the global and the guild status of the component and the guild status
+// of the command in account to ensure that inconsistent command states do not end in prohibited
+// execution of a command.
+func CommandStatusMiddleware(command *Command, next CommandHandlerFunc) CommandHandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		if IsCommandEnabled(command, i.GuildID) {
+			next(s, i)
+
+			return
+		}
+
+		message := "An unexpected error happened while computing your error message!"
+
+		component := command.c
+		registeredComponent, err := component.EntityManager().RegisteredComponent().Get(component.Code)
+		if nil == err {
+			globalStatus, err := component.EntityManager().GlobalComponentStatus().Get(registeredComponent.ID)
+			if nil == err {
+				switch globalStatus.Enabled {
+				case true:
+					message = fmt.Sprintf("The command `/%s` is disabled on this "+
+						"guild! Ask your guilds administrator to enable the `%s` component to use this command!",
+						command.Cmd.Name,
+						component.Name)
+					if IsComponentEnabled(component, i.GuildID) {
+						message = fmt.Sprintf("The command `/%s` is disabled on this "+
+							"guild! Ask your guilds administrator to enable the command to use it!",
+							command.Cmd.Name)
+					}
+				case false:
+					message = fmt.Sprintf("The command `/%s` is globally disabled. "+
+						"This might be due to some maintenance on the `%s` module.",
+						command.Cmd.Name,
+						component.Name)
+				}
+			}
+		}
+
+		respondWithStopMessage(s, i, component, message)
+
+		user := getInteractionUser(i)
+		component.Logger().Info("The user \"%s#%s\" with id \"%s\" tried to execute the "+
+			"disabled command \"%s\" with options \"%s\" %s",
+			user.Username,
+			user.Discriminator,
+			user.ID,
+			component.SlashCommandManager().computeFullCommandStringFromInteractionData(i.ApplicationCommandData()),
+			component.SlashCommandManager().computeConfiguredOptionsString(i.ApplicationCommandData().Options),
+			getGuildOrGlobalLogPart(i.GuildID, "on"))
+	}
+}
+
+// CommandLoggingMiddleware logs the execution of commands
+// including the user that executed the command and the passed options.
+func CommandLoggingMiddleware(command *Command, next CommandHandlerFunc) CommandHandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		user := getInteractionUser(i)
+		command.c.Logger().Info("The user \"%s#%s\" with id \"%s\" executed the "+
+			"command \"%s\" with options \"%s\" %s",
+			user.Username,
+			user.Discriminator,
+			user.ID,
+			command.c.SlashCommandManager().computeFullCommandStringFromInteractionData(i.ApplicationCommandData()),
+			command.c.SlashCommandManager().computeConfiguredOptionsString(i.ApplicationCommandData().Options),
+			getGuildOrGlobalLogPart(i.GuildID, "on"))
+
+		next(s, i)
+	}
+}
+
+// CommandGuildOnlyMiddleware prevents the execution of commands
+// outside of guilds (e.g. in direct messages).
+func CommandGuildOnlyMiddleware(command *Command, next CommandHandlerFunc) CommandHandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		if "" == i.GuildID {
+			respondWithStopMessage(s, i, command.c, fmt.Sprintf("The command `/%s` "+
+				"can only be used on guilds!", command.Cmd.Name))
+
+			return
+		}
+
+		next(s, i)
+	}
+}
+
+// CommandPermissionMiddleware creates a middleware that only executes the command,
+// when the member executing the command has all the passed permissions.
+// Members with the administrator permission are always allowed to execute the command.
+//
+// Commands executed outside of guilds are rejected.
+func CommandPermissionMiddleware(permissions int64) CommandMiddleware {
+	return func(command *Command, next CommandHandlerFunc) CommandHandlerFunc {
+		return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+			if nil == i.Member {
+				respondWithStopMessage(s, i, command.c, fmt.Sprintf("The command `/%s` "+
+					"can only be used on guilds!", command.Cmd.Name))
+
+				return
+			}
+
+			memberPermissions := i.Member.Permissions
+			if memberPermissions&discordgo.PermissionAdministrator != discordgo.PermissionAdministrator &&
+				memberPermissions&permissions != permissions {
+				respondWithStopMessage(s, i, command.c, fmt.Sprintf("You do not have the "+
+					"permissions required to use the command `/%s`!", command.Cmd.Name))
+
+				return
+			}
+
+			next(s, i)
+		}
+	}
+}
+
+// CommandCooldownMiddleware creates a middleware that only allows a user
+// to execute a command once within the passed duration.
+//
+// The cooldown is a RateLimit with a fixed window, so it is shared
+// between instances that use the same cache.
+func CommandCooldownMiddleware(cooldown time.Duration) CommandMiddleware {
+	return func(command *Command, next CommandHandlerFunc) CommandHandlerFunc {
+		rateLimit := &RateLimit{
+			Name:     fmt.Sprintf("cooldown_%s", getCommandKey(command.Cmd.Type, command.Cmd.Name)),
+			Strategy: RateLimitStrategyFixedWindow,
+			Scope:    RateLimitScopeUser,
+			Limit:    1,
+			Window:   cooldown,
+		}
+
+		return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+			allowed, retryAfter := rateLimit.Take(i)
+			if !allowed {
+				respondWithRateLimited(s, i, command.c, retryAfter)
+
+				return
+			}
+
+			next(s, i)
+		}
+	}
+}
+
+// getInteractionUser returns the user that created the passed interaction.
+// When the interaction has been created on a guild, the user of the member is returned.
+//
+// An empty user is returned, if there is no user attached to the interaction.
+func getInteractionUser(i *discordgo.InteractionCreate) *discordgo.User {
+	if nil != i.User {
+		return i.User
+	}
+
+	if nil != i.Member && nil != i.Member.User {
+		return i.Member.User
+	}
+
+	return &discordgo.User{}
+}
+
+// respondWithStopMessage responds to the passed interaction with
+// an ephemeral embed that holds the passed message.
+// It is used whenever the processing of an interaction is stopped,
+// e.g. when a middleware prevents the execution of a command.
+func respondWithStopMessage(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	c *Component,
+	message string,
+) {
+	_, err := RespondToInteraction(s, i, &discordgo.InteractionResponseData{
+		Flags: discordgo.MessageFlagsEphemeral,
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title: "JOJO Discord Bot",
+				Color: DefaultEmbedColor,
+				Fields: []*discordgo.MessageEmbedField{
+					{
+						Name:  ":no_entry_sign: STOP :no_entry_sign:",
+						Value: message,
+					},
+				},
+			},
+		},
+	})
+
+	if nil != err {
+		c.Logger().Err(err, "Failed to deliver interaction response!")
+	}
+}
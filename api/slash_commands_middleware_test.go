@@ -0,0 +1,295 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"github.com/lazybytez/jojo-discord-bot/services/cache"
+	"github.com/lazybytez/jojo-discord-bot/test/discordgo_mock"
+	"github.com/lazybytez/jojo-discord-bot/test/logmock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type CommandMiddlewareTestSuite struct {
+	suite.Suite
+	owningComponent *Component
+	loggerMock      *logmock.LoggerMock
+}
+
+func (suite *CommandMiddlewareTestSuite) SetupTest() {
+	suite.loggerMock = &logmock.LoggerMock{}
+	suite.loggerMock.On("Info", mock.Anything, mock.Anything)
+	suite.loggerMock.On("Warn", mock.Anything, mock.Anything)
+	suite.loggerMock.On("Err", mock.Anything, mock.Anything, mock.Anything)
+
+	suite.owningComponent = &Component{
+		Code: "bot_test_component",
+		Name: "Test Component",
+	}
+	suite.owningComponent.SetLogger(suite.loggerMock)
+
+	globalCommandMiddlewares = nil
+	componentCommandMiddlewares = make(map[entities.ComponentCode][]CommandMiddleware)
+
+	err := cache.Init(cache.ModeMemory, 10*time.Minute, "")
+	suite.NoError(err)
+
+	// Prevent permission overrides from being loaded from the database
+	for _, guildId := range []uint{1, 2} {
+		suite.NoError(cache.Update(strconv.Itoa(int(guildId)), entities.Guild{Model: gorm.Model{ID: guildId}}))
+		suite.NoError(cache.Update(fmt.Sprintf("command_permission_override_%d_test", guildId),
+			[]entities.CommandPermissionOverride{}))
+	}
+}
+
+func (suite *CommandMiddlewareTestSuite) createCommand(
+	handler func(s *discordgo.Session, i *discordgo.InteractionCreate),
+	middlewares ...CommandMiddleware,
+) *Command {
+	return &Command{
+		Cmd: &discordgo.ApplicationCommand{
+			Name: "test",
+		},
+		Handler:     handler,
+		Middlewares: middlewares,
+		c:           suite.owningComponent,
+	}
+}
+
+func (suite *CommandMiddlewareTestSuite) createInteraction(guildId string, permissions int64) *discordgo.InteractionCreate {
+	user := &discordgo.User{ID: "42", Username: "jojo"}
+	interaction := &discordgo.Interaction{
+		Type:    discordgo.InteractionApplicationCommand,
+		GuildID: guildId,
+		Data: discordgo.ApplicationCommandInteractionData{
+			Name:        "test",
+			CommandType: discordgo.ChatApplicationCommand,
+		},
+	}
+
+	if "" == guildId {
+		interaction.User = user
+	} else {
+		interaction.Member = &discordgo.Member{User: user, Permissions: permissions}
+	}
+
+	return &discordgo.InteractionCreate{Interaction: interaction}
+}
+
+func (suite *CommandMiddlewareTestSuite) expectStopMessage(transport *discordgo_mock.RoundTripper) *json.RawMessage {
+	response := &json.RawMessage{}
+	transport.OnRequestCaptureResult(http.MethodPost, response).Once().Return(
+		&http.Response{
+			StatusCode: http.StatusNoContent,
+		}, nil)
+
+	return response
+}
+
+func createRecordingMiddleware(name string, calls *[]string) CommandMiddleware {
+	return func(_ *Command, next CommandHandlerFunc) CommandHandlerFunc {
+		return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+			*calls = append(*calls, name)
+			next(s, i)
+		}
+	}
+}
+
+func (suite *CommandMiddlewareTestSuite) TestBuildCommandHandlerChainOrder() {
+	calls := make([]string, 0)
+
+	UseCommandMiddleware(createRecordingMiddleware("global", &calls))
+	suite.owningComponent.SlashCommandManager().Use(createRecordingMiddleware("component", &calls))
+	foreignComponent := &Component{Code: "foreign_component"}
+	foreignComponent.SlashCommandManager().Use(createRecordingMiddleware("foreign", &calls))
+
+	command := suite.createCommand(func(_ *discordgo.Session, _ *discordgo.InteractionCreate) {
+		calls = append(calls, "handler")
+	}, createRecordingMiddleware("command", &calls))
+
+	buildCommandHandlerChain(command)(nil, suite.createInteraction("1", 0))
+
+	suite.Equal([]string{"global", "component", "command", "handler"}, calls)
+}
+
+func (suite *CommandMiddlewareTestSuite) TestRemoveComponentCommandMiddlewares() {
+	suite.owningComponent.SlashCommandManager().Use(CommandGuildOnlyMiddleware)
+	suite.Len(componentCommandMiddlewares[suite.owningComponent.Code], 1)
+
+	removeComponentCommandMiddlewares(suite.owningComponent.Code)
+
+	suite.NotContains(componentCommandMiddlewares, suite.owningComponent.Code)
+}
+
+func (suite *CommandMiddlewareTestSuite) TestCommandRecoveryMiddleware() {
+	session, transport := discordgo_mock.MockSession()
+	response := suite.expectStopMessage(transport)
+
+	command := suite.createCommand(func(_ *discordgo.Session, _ *discordgo.InteractionCreate) {
+		panic("something really bad happened")
+	})
+
+	suite.NotPanics(func() {
+		buildCommandHandlerChain(command)(session, suite.createInteraction("1", 0))
+	})
+
+	transport.AssertExpectations(suite.T())
+	suite.Contains(string(*response), GenericErrorResponseEmbedValue)
+	suite.Contains(string(*response), "Error Reference")
+}
+
+func (suite *CommandMiddlewareTestSuite) TestCommandGuildOnlyMiddleware() {
+	session, transport := discordgo_mock.MockSession()
+	response := suite.expectStopMessage(transport)
+	handlerCalled := false
+
+	command := suite.createCommand(func(_ *discordgo.Session, _ *discordgo.InteractionCreate) {
+		handlerCalled = true
+	}, CommandGuildOnlyMiddleware)
+
+	buildCommandHandlerChain(command)(session, suite.createInteraction("", 0))
+	suite.False(handlerCalled)
+	suite.Contains(string(*response), "can only be used on guilds")
+
+	buildCommandHandlerChain(command)(session, suite.createInteraction("1", 0))
+	suite.True(handlerCalled)
+}
+
+func (suite *CommandMiddlewareTestSuite) TestCommandPermissionMiddleware() {
+	tables := []struct {
+		permissions    int64
+		expectedCalled bool
+	}{
+		{0, false},
+		{discordgo.PermissionManageMessages, false},
+		{discordgo.PermissionManageMessages | discordgo.PermissionManageServer, true},
+		{discordgo.PermissionAdministrator, true},
+	}
+
+	for _, table := range tables {
+		session, transport := discordgo_mock.MockSession()
+		if !table.expectedCalled {
+			suite.expectStopMessage(transport)
+		}
+
+		handlerCalled := false
+		command := suite.createCommand(func(_ *discordgo.Session, _ *discordgo.InteractionCreate) {
+			handlerCalled = true
+		}, CommandPermissionMiddleware(discordgo.PermissionManageMessages|discordgo.PermissionManageServer))
+
+		buildCommandHandlerChain(command)(session, suite.createInteraction("1", table.permissions))
+
+		suite.Equal(table.expectedCalled, handlerCalled)
+		transport.AssertExpectations(suite.T())
+	}
+}
+
+func (suite *CommandMiddlewareTestSuite) TestCommandCooldownMiddleware() {
+	session, transport := discordgo_mock.MockSession()
+	response := suite.expectStopMessage(transport)
+	handlerCalls := 0
+
+	command := suite.createCommand(func(_ *discordgo.Session, _ *discordgo.InteractionCreate) {
+		handlerCalls++
+	}, CommandCooldownMiddleware(time.Hour))
+
+	buildCommandHandlerChain(command)(session, suite.createInteraction("1", 0))
+	buildCommandHandlerChain(command)(session, suite.createInteraction("1", 0))
+
+	suite.Equal(1, handlerCalls)
+	suite.Contains(string(*response), "Slow down my friend!")
+	transport.AssertExpectations(suite.T())
+}
+
+func (suite *CommandMiddlewareTestSuite) TestCommandCooldownMiddlewareExpired() {
+	handlerCalls := 0
+
+	command := suite.createCommand(func(_ *discordgo.Session, _ *discordgo.InteractionCreate) {
+		handlerCalls++
+	}, CommandCooldownMiddleware(0))
+
+	buildCommandHandlerChain(command)(nil, suite.createInteraction("1", 0))
+	buildCommandHandlerChain(command)(nil, suite.createInteraction("1", 0))
+
+	suite.Equal(2, handlerCalls)
+}
+
+func (suite *CommandMiddlewareTestSuite) TestCommandRateLimitMiddleware() {
+	session, transport := discordgo_mock.MockSession()
+	response := suite.expectStopMessage(transport)
+	handlerCalls := 0
+
+	command := suite.createCommand(func(_ *discordgo.Session, _ *discordgo.InteractionCreate) {
+		handlerCalls++
+	})
+	command.RateLimit = &RateLimit{
+		Strategy: RateLimitStrategyFixedWindow,
+		Scope:    RateLimitScopeGuild,
+		Limit:    2,
+		Window:   time.Hour,
+	}
+
+	for idx := 0; idx < 3; idx++ {
+		buildCommandHandlerChain(command)(session, suite.createInteraction("1", 0))
+	}
+	buildCommandHandlerChain(command)(session, suite.createInteraction("2", 0))
+
+	suite.Equal(3, handlerCalls)
+	suite.Contains(string(*response), "Slow down my friend!")
+	transport.AssertExpectations(suite.T())
+}
+
+func (suite *CommandMiddlewareTestSuite) TestCommandPermissionOverrideMiddleware() {
+	session, transport := discordgo_mock.MockSession()
+	response := suite.expectStopMessage(transport)
+	handlerCalled := false
+
+	suite.NoError(cache.Update("command_permission_override_1_test", []entities.CommandPermissionOverride{
+		{
+			TargetType: entities.CommandPermissionOverrideTargetUser,
+			TargetID:   42,
+			Allow:      false,
+		},
+	}))
+
+	command := suite.createCommand(func(_ *discordgo.Session, _ *discordgo.InteractionCreate) {
+		handlerCalled = true
+	})
+
+	buildCommandHandlerChain(command)(session, suite.createInteraction("1", 0))
+	suite.False(handlerCalled)
+	suite.Contains(string(*response), "You are not allowed to use")
+
+	buildCommandHandlerChain(command)(session, suite.createInteraction("2", 0))
+	suite.True(handlerCalled)
+	transport.AssertExpectations(suite.T())
+}
+
+func TestCommandMiddleware(t *testing.T) {
+	suite.Run(t, new(CommandMiddlewareTestSuite))
+}
@@ -0,0 +1,264 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"strings"
+)
+
+// Commands are synced by computing the set of commands that should be available
+// on a guild (or globally) and comparing it with the commands registered in Discord.
+// When they differ, the entire set is applied using a single bulk overwrite,
+// which keeps the IDs of unchanged commands and uses only a single request.
+
+// CommandSyncDiffEntry identifies a command that is part of a CommandSyncDiff.
+type CommandSyncDiffEntry struct {
+	Name string
+	Type discordgo.ApplicationCommandType
+}
+
+// CommandSyncDiff holds the differences between the commands registered
+// in Discord and the commands that should be available.
+type CommandSyncDiff struct {
+	// GuildId is the guild the diff has been computed for,
+	// it is empty for global commands.
+	GuildId string
+	// Added holds the commands that are missing in Discord.
+	Added []CommandSyncDiffEntry
+	// Removed holds the commands that are registered in Discord,
+	// but are either orphaned or disabled.
+	Removed []CommandSyncDiffEntry
+	// Changed holds the commands that are registered in Discord,
+	// but differ from the registered Command.
+	Changed []CommandSyncDiffEntry
+}
+
+// IsEmpty checks whether the CommandSyncDiff contains any changes.
+func (d *CommandSyncDiff) IsEmpty() bool {
+	return 0 == len(d.Added) && 0 == len(d.Removed) && 0 == len(d.Changed)
+}
+
+// String returns a human-readable summary of the CommandSyncDiff,
+// which is intended to be used for logging.
+func (d *CommandSyncDiff) String() string {
+	return fmt.Sprintf("added: [%s], removed: [%s], changed: [%s]",
+		joinCommandSyncDiffEntries(d.Added),
+		joinCommandSyncDiffEntries(d.Removed),
+		joinCommandSyncDiffEntries(d.Changed))
+}
+
+// joinCommandSyncDiffEntries joins the names of the passed entries.
+func joinCommandSyncDiffEntries(entries []CommandSyncDiffEntry) string {
+	names := make([]string, len(entries))
+	for key, entry := range entries {
+		names[key] = entry.Name
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// SyncApplicationComponentCommands ensures that the available discordgo.ApplicationCommand
+// are synced for the given component with the given guild.
+//
+// This means that disabled commands are enabled and enabled commands are disabled
+// depending on the component enable state.
+//
+// Also orphaned commands are cleaned up.
+// This is executed whenever a guild is joined or a component is toggled.
+//
+// The commands are only overwritten, when they differ from the commands
+// that should be available on the guild.
+func (c *SlashCommandManager) SyncApplicationComponentCommands(
+	session *discordgo.Session,
+	guildId string,
+) {
+	slashCommandManagerLogger.Info(
+		"Syncing slash-commands for guild \"%v\"...",
+		guildId)
+
+	c.syncApplicationCommands(session, guildId)
+
+	slashCommandManagerLogger.Info(
+		"Finished syncing slash-commands for guild \"%s\"...",
+		guildId)
+}
+
+// SyncApplicationComponentGlobalCommands ensures that the available discordgo.ApplicationCommand
+// are synced for the given component globally.
+//
+// This means that disabled commands are enabled and enabled commands are disabled
+// depending on their global enable state.
+//
+// Also orphaned commands are cleaned up.
+// This is executed whenever a guild is joined or a component is toggled.
+//
+// The commands are only overwritten, when they differ from the commands
+// that should be available globally.
+func (c *SlashCommandManager) SyncApplicationComponentGlobalCommands(
+	session *discordgo.Session,
+) {
+	slashCommandManagerLogger.Info("Syncing slash-commands globally...")
+
+	c.syncApplicationCommands(session, "")
+
+	slashCommandManagerLogger.Info(
+		"Finished syncing slash-commands globally...")
+}
+
+// syncCommandsOfAllGuilds syncs the slash commands globally and for all
+// guilds the bot is currently on, across all shards.
+func syncCommandsOfAllGuilds(session *discordgo.Session, slashCommandManager *SlashCommandManager) {
+	slashCommandManager.SyncApplicationComponentGlobalCommands(session)
+	for _, guildId := range getShardGuildIds(session) {
+		slashCommandManager.SyncApplicationComponentCommands(session, guildId)
+	}
+}
+
+// SyncApplicationComponentCommandsDryRun computes the changes a sync of the commands
+// of the given guild would apply, without applying them.
+// An empty guild id computes the changes of the global commands.
+func (c *SlashCommandManager) SyncApplicationComponentCommandsDryRun(
+	session *discordgo.Session,
+	guildId string,
+) (*CommandSyncDiff, error) {
+	registeredCommands, err := session.ApplicationCommands(session.State.User.ID, guildId)
+	if nil != err {
+		return nil, err
+	}
+
+	return c.computeCommandSyncDiff(guildId, registeredCommands, getDesiredCommands(guildId)), nil
+}
+
+// syncApplicationCommands computes the commands that should be available on the passed guild
+// (or globally for an empty guild id) and overwrites the registered commands with them,
+// when they differ.
+func (c *SlashCommandManager) syncApplicationCommands(session *discordgo.Session, guildId string) {
+	registeredCommands, err := session.ApplicationCommands(session.State.User.ID, guildId)
+	if nil != err {
+		slashCommandManagerLogger.Err(
+			err,
+			"Failed to handle slash-command sync %s!",
+			getGuildOrGlobalLogPart(guildId, "for"))
+
+		return
+	}
+
+	desiredCommands := getDesiredCommands(guildId)
+	diff := c.computeCommandSyncDiff(guildId, registeredCommands, desiredCommands)
+	if diff.IsEmpty() {
+		slashCommandManagerLogger.Info(
+			"Slash-commands %s are already up to date!",
+			getGuildOrGlobalLogPart(guildId, "of"))
+
+		return
+	}
+
+	_, err = session.ApplicationCommandBulkOverwrite(session.State.User.ID, guildId, desiredCommands)
+	if nil != err {
+		slashCommandManagerLogger.Err(
+			err,
+			"Failed to overwrite slash-commands %s (%s)!",
+			getGuildOrGlobalLogPart(guildId, "of"),
+			diff)
+
+		return
+	}
+
+	slashCommandManagerLogger.Info(
+		"Overwrote slash-commands %s (%s)!",
+		getGuildOrGlobalLogPart(guildId, "of"),
+		diff)
+
+	c.owner.EventBus().Publish(&CommandsSyncedEvent{
+		GuildId: guildId,
+		Diff:    diff,
+	})
+}
+
+// getDesiredCommands returns the localized commands that should be available
+// on the passed guild, or globally when an empty guild id is passed.
+//
+// Commands are available when they match the scope (guild or global)
+// and are enabled for the guild.
+func getDesiredCommands(guildId string) []*discordgo.ApplicationCommand {
+	desiredCommands := make([]*discordgo.ApplicationCommand, 0)
+
+	for _, componentCommand := range getComponentCommands() {
+		if componentCommand.Global && "" != guildId || !componentCommand.Global && "" == guildId {
+			continue
+		}
+
+		if !IsCommandEnabled(componentCommand, guildId) {
+			continue
+		}
+
+		desiredCommands = append(desiredCommands, localizeCommand(componentCommand.Cmd))
+	}
+
+	return desiredCommands
+}
+
+// computeCommandSyncDiff compares the registered commands with the desired commands
+// and returns the differences.
+func (c *SlashCommandManager) computeCommandSyncDiff(
+	guildId string,
+	registeredCommands []*discordgo.ApplicationCommand,
+	desiredCommands []*discordgo.ApplicationCommand,
+) *CommandSyncDiff {
+	diff := &CommandSyncDiff{
+		GuildId: guildId,
+		Added:   make([]CommandSyncDiffEntry, 0),
+		Removed: make([]CommandSyncDiffEntry, 0),
+		Changed: make([]CommandSyncDiffEntry, 0),
+	}
+
+	registeredCommandsByKey := make(map[string]*discordgo.ApplicationCommand, len(registeredCommands))
+	for _, registeredCommand := range registeredCommands {
+		registeredCommandsByKey[getCommandKey(registeredCommand.Type, registeredCommand.Name)] = registeredCommand
+
+		if !c.isCommandInApplicationCommandList(desiredCommands, registeredCommand) {
+			diff.Removed = append(diff.Removed, newCommandSyncDiffEntry(registeredCommand))
+		}
+	}
+
+	for _, desiredCommand := range desiredCommands {
+		registeredCommand, ok := registeredCommandsByKey[getCommandKey(desiredCommand.Type, desiredCommand.Name)]
+		if !ok {
+			diff.Added = append(diff.Added, newCommandSyncDiffEntry(desiredCommand))
+
+			continue
+		}
+
+		if !c.compareCommands(registeredCommand, desiredCommand) {
+			diff.Changed = append(diff.Changed, newCommandSyncDiffEntry(desiredCommand))
+		}
+	}
+
+	return diff
+}
+
+// newCommandSyncDiffEntry creates a CommandSyncDiffEntry for the passed command.
+func newCommandSyncDiffEntry(cmd *discordgo.ApplicationCommand) CommandSyncDiffEntry {
+	return CommandSyncDiffEntry{
+		Name: cmd.Name,
+		Type: cmd.Type,
+	}
+}
@@ -0,0 +1,181 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/test/discordgo_mock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type CommandSyncTestSuite struct {
+	suite.Suite
+	owningComponent     *Component
+	slashCommandManager SlashCommandManager
+}
+
+func (suite *CommandSyncTestSuite) SetupTest() {
+	suite.owningComponent = &Component{
+		Code: "bot_test_component",
+		Name: "Test Component",
+	}
+	suite.slashCommandManager = SlashCommandManager{owner: suite.owningComponent}
+
+	componentCommandMap = map[string]*Command{
+		getCommandKey(discordgo.ChatApplicationCommand, "quote"): {
+			Cmd: &discordgo.ApplicationCommand{
+				Name:        "quote",
+				Description: "Get a random quote",
+			},
+			c: suite.owningComponent,
+		},
+		getCommandKey(discordgo.UserApplicationCommand, "User info"): {
+			Cmd: &discordgo.ApplicationCommand{
+				Name: "User info",
+				Type: discordgo.UserApplicationCommand,
+			},
+			c: suite.owningComponent,
+		},
+		getCommandKey(discordgo.ChatApplicationCommand, "jojo"): {
+			Cmd: &discordgo.ApplicationCommand{
+				Name:        "jojo",
+				Description: "Manage the bot",
+			},
+			Global: true,
+			c:      suite.owningComponent,
+		},
+	}
+}
+
+func (suite *CommandSyncTestSuite) TearDownTest() {
+	componentCommandMap = make(map[string]*Command)
+}
+
+func (suite *CommandSyncTestSuite) TestGetDesiredCommands() {
+	guildCommands := getDesiredCommands("1234")
+	globalCommands := getDesiredCommands("")
+
+	suite.Len(guildCommands, 2)
+	suite.True(suite.slashCommandManager.isCommandInApplicationCommandList(guildCommands,
+		&discordgo.ApplicationCommand{Name: "quote"}))
+	suite.True(suite.slashCommandManager.isCommandInApplicationCommandList(guildCommands,
+		&discordgo.ApplicationCommand{Name: "User info", Type: discordgo.UserApplicationCommand}))
+
+	suite.Len(globalCommands, 1)
+	suite.Equal("jojo", globalCommands[0].Name)
+}
+
+func (suite *CommandSyncTestSuite) TestComputeCommandSyncDiff() {
+	registeredCommands := []*discordgo.ApplicationCommand{
+		{Name: "quote", Type: discordgo.ChatApplicationCommand, Description: "Get a quote"},
+		{Name: "User info", Type: discordgo.UserApplicationCommand},
+		{Name: "orphaned", Type: discordgo.ChatApplicationCommand, Description: "Orphaned command"},
+	}
+	desiredCommands := []*discordgo.ApplicationCommand{
+		{Name: "quote", Description: "Get a random quote"},
+		{Name: "User info", Type: discordgo.UserApplicationCommand},
+		{Name: "User info", Type: discordgo.MessageApplicationCommand},
+	}
+
+	diff := suite.slashCommandManager.computeCommandSyncDiff("1234", registeredCommands, desiredCommands)
+
+	suite.Equal("1234", diff.GuildId)
+	suite.False(diff.IsEmpty())
+	suite.Equal([]CommandSyncDiffEntry{{Name: "User info", Type: discordgo.MessageApplicationCommand}}, diff.Added)
+	suite.Equal([]CommandSyncDiffEntry{{Name: "orphaned", Type: discordgo.ChatApplicationCommand}}, diff.Removed)
+	suite.Equal([]CommandSyncDiffEntry{{Name: "quote"}}, diff.Changed)
+	suite.Equal("added: [User info], removed: [orphaned], changed: [quote]", diff.String())
+}
+
+func (suite *CommandSyncTestSuite) TestComputeCommandSyncDiffWithoutChanges() {
+	commands := []*discordgo.ApplicationCommand{
+		{Name: "quote", Type: discordgo.ChatApplicationCommand, Description: "Get a random quote"},
+	}
+
+	diff := suite.slashCommandManager.computeCommandSyncDiff("", commands, commands)
+
+	suite.True(diff.IsEmpty())
+}
+
+func (suite *CommandSyncTestSuite) TestSyncApplicationComponentCommandsDryRun() {
+	session, transport := suite.mockSessionWithRegisteredCommands([]*discordgo.ApplicationCommand{
+		{Name: "orphaned", Type: discordgo.ChatApplicationCommand, Description: "Orphaned command"},
+	})
+
+	diff, err := suite.slashCommandManager.SyncApplicationComponentCommandsDryRun(session, "")
+
+	suite.NoError(err)
+	transport.AssertExpectations(suite.T())
+	suite.Equal([]CommandSyncDiffEntry{{Name: "jojo"}}, diff.Added)
+	suite.Equal([]CommandSyncDiffEntry{{Name: "orphaned", Type: discordgo.ChatApplicationCommand}}, diff.Removed)
+	suite.Empty(diff.Changed)
+}
+
+func (suite *CommandSyncTestSuite) TestSyncApplicationComponentGlobalCommandsOverwritesOnChanges() {
+	session, transport := suite.mockSessionWithRegisteredCommands([]*discordgo.ApplicationCommand{})
+
+	overwrittenCommands := make([]*discordgo.ApplicationCommand, 0)
+	_, _ = transport.RespondWith(
+		transport.OnRequestCaptureResult(http.MethodPut, &overwrittenCommands).Once(),
+		[]*discordgo.ApplicationCommand{})
+
+	suite.slashCommandManager.SyncApplicationComponentGlobalCommands(session)
+
+	transport.AssertExpectations(suite.T())
+	suite.Len(overwrittenCommands, 1)
+	suite.Equal("jojo", overwrittenCommands[0].Name)
+}
+
+func (suite *CommandSyncTestSuite) TestSyncApplicationComponentGlobalCommandsSkipsWithoutChanges() {
+	session, transport := suite.mockSessionWithRegisteredCommands([]*discordgo.ApplicationCommand{
+		{Name: "jojo", Type: discordgo.ChatApplicationCommand, Description: "Manage the bot"},
+	})
+
+	suite.slashCommandManager.SyncApplicationComponentGlobalCommands(session)
+
+	transport.AssertExpectations(suite.T())
+	transport.AssertNotCalled(suite.T(), "RoundTrip", mock.MatchedBy(func(req *http.Request) bool {
+		return http.MethodPut == req.Method
+	}))
+}
+
+// mockSessionWithRegisteredCommands creates a mocked session that returns
+// the passed commands when the registered commands are requested.
+func (suite *CommandSyncTestSuite) mockSessionWithRegisteredCommands(
+	registeredCommands []*discordgo.ApplicationCommand,
+) (*discordgo.Session, *discordgo_mock.RoundTripper) {
+	session, transport := discordgo_mock.MockSession()
+	session.State = discordgo.NewState()
+	session.State.User = &discordgo.User{ID: "42"}
+
+	_, _ = transport.RespondWith(
+		transport.On("RoundTrip", mock.MatchedBy(func(req *http.Request) bool {
+			return http.MethodGet == req.Method
+		})).Once(),
+		registeredCommands)
+
+	return session, transport
+}
+
+func TestCommandSync(t *testing.T) {
+	suite.Run(t, new(CommandSyncTestSuite))
+}
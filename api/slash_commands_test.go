@@ -21,7 +21,10 @@ package api
 import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"github.com/lazybytez/jojo-discord-bot/test/discordgo_mock"
 	"github.com/stretchr/testify/suite"
+	"net/http"
+	"strconv"
 	"testing"
 )
 
@@ -301,7 +304,371 @@ func (suite *SlashCommandManagerTestSuite) TestGetCommandsForComponentWithComman
 	}
 
 	suite.Len(result, 2)
-	suite.Equal(expected, result)
+	suite.ElementsMatch(expected, result)
+}
+
+func (suite *SlashCommandManagerTestSuite) TestUnregisterWithOwnedCommand() {
+	componentCommandMap = map[string]*Command{
+		"a": {
+			Cmd: &discordgo.ApplicationCommand{Name: "a"},
+			c:   suite.owningComponent,
+		},
+	}
+
+	err := suite.slashCommandManager.Unregister("a")
+
+	suite.NoError(err)
+	suite.Len(componentCommandMap, 0)
+}
+
+func (suite *SlashCommandManagerTestSuite) TestUnregisterWithForeignCommand() {
+	componentCommandMap = map[string]*Command{
+		"a": {
+			Cmd: &discordgo.ApplicationCommand{Name: "a"},
+			c:   &Component{Code: "foreign_component"},
+		},
+	}
+
+	err := suite.slashCommandManager.Unregister("a")
+
+	suite.Error(err)
+	suite.Len(componentCommandMap, 1)
+}
+
+func (suite *SlashCommandManagerTestSuite) TestUnregisterWithMissingCommand() {
+	componentCommandMap = map[string]*Command{}
+
+	err := suite.slashCommandManager.Unregister("a")
+
+	suite.Error(err)
+}
+
+func (suite *SlashCommandManagerTestSuite) TestUnregisterAll() {
+	foreignCommand := &Command{
+		Cmd: &discordgo.ApplicationCommand{Name: "c"},
+		c:   &Component{Code: "foreign_component"},
+	}
+
+	componentCommandMap = map[string]*Command{
+		"a": {
+			Cmd: &discordgo.ApplicationCommand{Name: "a"},
+			c:   suite.owningComponent,
+		},
+		"b": {
+			Cmd: &discordgo.ApplicationCommand{Name: "b"},
+			c:   suite.owningComponent,
+		},
+		"c": foreignCommand,
+	}
+
+	suite.slashCommandManager.UnregisterAll()
+
+	suite.Equal(map[string]*Command{"c": foreignCommand}, componentCommandMap)
+}
+
+func (suite *SlashCommandManagerTestSuite) TestGetFocusedOption() {
+	focusedOption := &discordgo.ApplicationCommandInteractionDataOption{
+		Name:    "key",
+		Type:    discordgo.ApplicationCommandOptionString,
+		Value:   "dic",
+		Focused: true,
+	}
+	siblingOption := &discordgo.ApplicationCommandInteractionDataOption{
+		Name:  "module",
+		Type:  discordgo.ApplicationCommandOptionString,
+		Value: "dice",
+	}
+
+	options := []*discordgo.ApplicationCommandInteractionDataOption{
+		{
+			Name: "module",
+			Type: discordgo.ApplicationCommandOptionSubCommandGroup,
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{
+					Name: "config",
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Options: []*discordgo.ApplicationCommandInteractionDataOption{
+						siblingOption,
+						focusedOption,
+					},
+				},
+			},
+		},
+	}
+
+	focused, siblings := GetFocusedOption(options)
+
+	suite.Equal(focusedOption, focused)
+	suite.Equal([]*discordgo.ApplicationCommandInteractionDataOption{siblingOption, focusedOption}, siblings)
+}
+
+func (suite *SlashCommandManagerTestSuite) TestGetFocusedOptionWithoutFocusedOption() {
+	options := []*discordgo.ApplicationCommandInteractionDataOption{
+		{
+			Name:  "module",
+			Type:  discordgo.ApplicationCommandOptionString,
+			Value: "dice",
+		},
+	}
+
+	focused, siblings := GetFocusedOption(options)
+
+	suite.Nil(focused)
+	suite.Nil(siblings)
+}
+
+func (suite *SlashCommandManagerTestSuite) TestHandleCommandDispatchWithAutocomplete() {
+	autocompleteCalled := false
+	handlerCalled := false
+
+	componentCommandMap = map[string]*Command{
+		"a": {
+			Cmd: &discordgo.ApplicationCommand{Name: "a"},
+			Handler: func(_ *discordgo.Session, _ *discordgo.InteractionCreate) {
+				handlerCalled = true
+			},
+			Autocomplete: func(_ *discordgo.Session, _ *discordgo.InteractionCreate) {
+				autocompleteCalled = true
+			},
+			c: &Component{Code: "bot_test_component"},
+		},
+	}
+
+	handleCommandDispatch(nil, &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type: discordgo.InteractionApplicationCommandAutocomplete,
+			Data: discordgo.ApplicationCommandInteractionData{Name: "a"},
+		},
+	})
+
+	suite.True(autocompleteCalled)
+	suite.False(handlerCalled)
+}
+
+func (suite *SlashCommandManagerTestSuite) TestHandleCommandDispatchWithAutocompleteWithoutMember() {
+	autocompleteCalled := false
+
+	componentCommandMap = map[string]*Command{
+		"a": {
+			Cmd: &discordgo.ApplicationCommand{Name: "a"},
+			Autocomplete: func(_ *discordgo.Session, _ *discordgo.InteractionCreate) {
+				autocompleteCalled = true
+			},
+			c: &Component{Code: "bot_test_component"},
+		},
+	}
+
+	s, r := discordgo_mock.MockSession()
+	interactionResponse := &discordgo.InteractionResponse{}
+	r.OnRequestCaptureResult(http.MethodPost, interactionResponse).Once().Return(
+		&http.Response{
+			StatusCode: http.StatusNoContent,
+		}, nil)
+
+	handleCommandDispatch(s, &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:      "interaction.ID",
+			Token:   "interaction.Token",
+			GuildID: "1",
+			Type:    discordgo.InteractionApplicationCommandAutocomplete,
+			Data:    discordgo.ApplicationCommandInteractionData{Name: "a"},
+		},
+	})
+
+	suite.False(autocompleteCalled)
+	r.AssertExpectations(suite.T())
+	suite.Equal(discordgo.InteractionApplicationCommandAutocompleteResult, interactionResponse.Type)
+	suite.Empty(interactionResponse.Data.Choices)
+}
+
+func (suite *SlashCommandManagerTestSuite) TestHandleCommandDispatchWithOtherInteraction() {
+	handlerCalled := false
+
+	componentCommandMap = map[string]*Command{
+		"a": {
+			Cmd: &discordgo.ApplicationCommand{Name: "a"},
+			Handler: func(_ *discordgo.Session, _ *discordgo.InteractionCreate) {
+				handlerCalled = true
+			},
+			c: &Component{Code: "bot_test_component"},
+		},
+	}
+
+	handleCommandDispatch(nil, &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type: discordgo.InteractionPing,
+		},
+	})
+
+	suite.False(handlerCalled)
+}
+
+func (suite *SlashCommandManagerTestSuite) TestRespondWithAutocompleteChoices() {
+	s, r := discordgo_mock.MockSession()
+	i := &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:    "interaction.ID",
+			Token: "interaction.Token",
+			Type:  discordgo.InteractionApplicationCommandAutocomplete,
+		},
+	}
+
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0)
+	for c := 0; c < MaxAutocompleteChoices+5; c++ {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  strconv.Itoa(c),
+			Value: strconv.Itoa(c),
+		})
+	}
+
+	interactionResponse := &discordgo.InteractionResponse{}
+	r.OnRequestCaptureResult(http.MethodPost, interactionResponse).Once().Return(
+		&http.Response{
+			StatusCode: http.StatusNoContent,
+		}, nil)
+
+	err := RespondWithAutocompleteChoices(s, i, choices)
+
+	suite.NoError(err)
+	r.AssertExpectations(suite.T())
+	suite.Equal(discordgo.InteractionApplicationCommandAutocompleteResult, interactionResponse.Type)
+	suite.Len(interactionResponse.Data.Choices, MaxAutocompleteChoices)
+}
+
+func (suite *SlashCommandManagerTestSuite) TestGetCommandKey() {
+	suite.Equal("a", getCommandKey(0, "a"))
+	suite.Equal("a", getCommandKey(discordgo.ChatApplicationCommand, "a"))
+	suite.Equal("2:a", getCommandKey(discordgo.UserApplicationCommand, "a"))
+	suite.Equal("3:a", getCommandKey(discordgo.MessageApplicationCommand, "a"))
+}
+
+func (suite *SlashCommandManagerTestSuite) TestRegisterCommandsOfDifferentTypesWithSameName() {
+	componentCommandMap = map[string]*Command{}
+	handler := func(_ *discordgo.Session, _ *discordgo.InteractionCreate) {}
+
+	chatCommand := &Command{
+		Cmd:     &discordgo.ApplicationCommand{Name: "quote", Description: "Quote something"},
+		Handler: handler,
+	}
+	messageCommand := &Command{
+		Cmd:     &discordgo.ApplicationCommand{Name: "quote", Type: discordgo.MessageApplicationCommand},
+		Handler: handler,
+	}
+
+	suite.NoError(suite.slashCommandManager.Register(chatCommand))
+	suite.NoError(suite.slashCommandManager.Register(messageCommand))
+	suite.Equal(discordgo.ChatApplicationCommand, chatCommand.Cmd.Type)
+
+	foundCommand, ok := getComponentCommand(discordgo.MessageApplicationCommand, "quote")
+	suite.True(ok)
+	suite.Equal(messageCommand, foundCommand)
+
+	foundCommand, ok = getComponentCommand(discordgo.ChatApplicationCommand, "quote")
+	suite.True(ok)
+	suite.Equal(chatCommand, foundCommand)
+
+	suite.NoError(suite.slashCommandManager.Unregister("quote"))
+	suite.Len(componentCommandMap, 0)
+}
+
+func (suite *SlashCommandManagerTestSuite) TestValidateContextMenuCommand() {
+	handler := func(_ *discordgo.Session, _ *discordgo.InteractionCreate) {}
+
+	tables := []struct {
+		cmd     *Command
+		isValid bool
+	}{
+		{&Command{Cmd: &discordgo.ApplicationCommand{
+			Name: "User info",
+			Type: discordgo.UserApplicationCommand,
+		}}, true},
+		{&Command{Cmd: &discordgo.ApplicationCommand{
+			Name:        "User info",
+			Description: "Shows information about a user",
+			Type:        discordgo.UserApplicationCommand,
+		}}, false},
+		{&Command{Cmd: &discordgo.ApplicationCommand{
+			Name: "Quote message",
+			Type: discordgo.MessageApplicationCommand,
+			Options: []*discordgo.ApplicationCommandOption{
+				{Name: "channel", Type: discordgo.ApplicationCommandOptionChannel},
+			},
+		}}, false},
+		{&Command{Cmd: &discordgo.ApplicationCommand{
+			Name: "Quote message",
+			Type: discordgo.MessageApplicationCommand,
+		}, Autocomplete: handler}, false},
+		{&Command{Cmd: &discordgo.ApplicationCommand{
+			Name:        "quote",
+			Description: "Quote something",
+			Type:        discordgo.ChatApplicationCommand,
+		}, Autocomplete: handler}, true},
+	}
+
+	for _, table := range tables {
+		err := validateContextMenuCommand(table.cmd)
+
+		if table.isValid {
+			suite.NoError(err)
+
+			continue
+		}
+
+		suite.Error(err)
+	}
+}
+
+func (suite *SlashCommandManagerTestSuite) TestIsCommandInApplicationCommandList() {
+	commands := []*discordgo.ApplicationCommand{
+		{Name: "quote", Type: discordgo.ChatApplicationCommand},
+		{Name: "User info", Type: discordgo.UserApplicationCommand},
+	}
+
+	suite.True(suite.slashCommandManager.isCommandInApplicationCommandList(commands,
+		&discordgo.ApplicationCommand{Name: "quote", Type: discordgo.ChatApplicationCommand}))
+	suite.True(suite.slashCommandManager.isCommandInApplicationCommandList(commands,
+		&discordgo.ApplicationCommand{Name: "User info", Type: discordgo.UserApplicationCommand}))
+	suite.False(suite.slashCommandManager.isCommandInApplicationCommandList(commands,
+		&discordgo.ApplicationCommand{Name: "quote", Type: discordgo.MessageApplicationCommand}))
+	suite.False(suite.slashCommandManager.isCommandInApplicationCommandList(commands,
+		&discordgo.ApplicationCommand{Name: "User info", Type: discordgo.ChatApplicationCommand}))
+}
+
+func (suite *SlashCommandManagerTestSuite) TestHandleCommandDispatchWithContextMenuCommand() {
+	chatHandlerCalled := false
+	userHandlerCalled := false
+
+	componentCommandMap = map[string]*Command{
+		getCommandKey(discordgo.ChatApplicationCommand, "info"): {
+			Cmd: &discordgo.ApplicationCommand{Name: "info", Type: discordgo.ChatApplicationCommand},
+			Handler: func(_ *discordgo.Session, _ *discordgo.InteractionCreate) {
+				chatHandlerCalled = true
+			},
+			c: &Component{Code: "bot_test_component"},
+		},
+		getCommandKey(discordgo.UserApplicationCommand, "info"): {
+			Cmd: &discordgo.ApplicationCommand{Name: "info", Type: discordgo.UserApplicationCommand},
+			Handler: func(_ *discordgo.Session, _ *discordgo.InteractionCreate) {
+				userHandlerCalled = true
+			},
+			c: &Component{Code: "bot_test_component"},
+		},
+	}
+
+	handleCommandDispatch(nil, &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type: discordgo.InteractionApplicationCommand,
+			User: &discordgo.User{ID: "1", Username: "jotaro"},
+			Data: discordgo.ApplicationCommandInteractionData{
+				Name:        "info",
+				CommandType: discordgo.UserApplicationCommand,
+				TargetID:    "2",
+			},
+		},
+	})
+
+	suite.True(userHandlerCalled)
+	suite.False(chatHandlerCalled)
 }
 
 func TestSlashCommandManager(t *testing.T) {
@@ -19,6 +19,7 @@
 package api
 
 import (
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
 	"sync"
 )
 
@@ -30,9 +31,17 @@ var botStatusManager *DiscordGoStatusManager
 type DiscordGoStatusManager struct {
 	mu      sync.RWMutex
 	status  []SimpleBotStatus
+	owners  []entities.ComponentCode
 	current int
 }
 
+// ComponentStatusManager is a StatusManager that is bound
+// to a component. All status added through it are owned
+// by the component and are removed when the component is unloaded.
+type ComponentStatusManager struct {
+	owner *Component
+}
+
 // StatusManager manages the available status
 // which are set fopr the bot.
 type StatusManager interface {
@@ -46,7 +55,7 @@ type StatusManager interface {
 // BotStatusManager returns the current StatusManager which
 // allows to add additional status to the bot.
 func (c *Component) BotStatusManager() StatusManager {
-	return botStatusManager
+	return &ComponentStatusManager{owner: c}
 }
 
 func init() {
@@ -60,10 +69,38 @@ func init() {
 // AddStatusToRotation adds the given status to the list of
 // rotated status.
 func (dgsm *DiscordGoStatusManager) AddStatusToRotation(status SimpleBotStatus) {
+	dgsm.addOwnedStatusToRotation("", status)
+}
+
+// addOwnedStatusToRotation adds the given status to the list of
+// rotated status and remembers the component that owns the status.
+func (dgsm *DiscordGoStatusManager) addOwnedStatusToRotation(owner entities.ComponentCode, status SimpleBotStatus) {
 	dgsm.mu.Lock()
 	defer dgsm.mu.Unlock()
 
 	dgsm.status = append(dgsm.status, status)
+	dgsm.owners = append(dgsm.owners, owner)
+}
+
+// removeStatusOfComponent removes all status owned by the component
+// with the passed code from the rotation.
+func (dgsm *DiscordGoStatusManager) removeStatusOfComponent(owner entities.ComponentCode) {
+	dgsm.mu.Lock()
+	defer dgsm.mu.Unlock()
+
+	remainingStatus := make([]SimpleBotStatus, 0, len(dgsm.status))
+	remainingOwners := make([]entities.ComponentCode, 0, len(dgsm.owners))
+	for key, status := range dgsm.status {
+		if dgsm.owners[key] == owner {
+			continue
+		}
+
+		remainingStatus = append(remainingStatus, status)
+		remainingOwners = append(remainingOwners, dgsm.owners[key])
+	}
+
+	dgsm.status = remainingStatus
+	dgsm.owners = remainingOwners
 }
 
 // Next works like next on an iterator which self resets automatically.
@@ -86,3 +123,15 @@ func (dgsm *DiscordGoStatusManager) Next() *SimpleBotStatus {
 
 	return status
 }
+
+// AddStatusToRotation adds the given status to the list of
+// rotated status. The status is owned by the component of the
+// ComponentStatusManager.
+func (csm *ComponentStatusManager) AddStatusToRotation(status SimpleBotStatus) {
+	botStatusManager.addOwnedStatusToRotation(csm.owner.Code, status)
+}
+
+// Next works like next on an iterator which self resets automatically.
+func (csm *ComponentStatusManager) Next() *SimpleBotStatus {
+	return botStatusManager.Next()
+}
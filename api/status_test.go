@@ -95,6 +95,31 @@ func (suite *StatusManagerSuite) TestNextWithMultipleStatus() {
 	suite.Equal(thirdStatus, *botStatusManager.Next())
 }
 
+func (suite *StatusManagerSuite) TestRemoveStatusOfComponent() {
+	firstComponent := &Component{Code: "first_component"}
+	secondComponent := &Component{Code: "second_component"}
+
+	firstStatus := SimpleBotStatus{
+		ActivityType: discordgo.ActivityTypeGame,
+		Content:      "Test",
+	}
+
+	secondStatus := SimpleBotStatus{
+		ActivityType: discordgo.ActivityTypeListening,
+		Content:      "Roundabout",
+	}
+
+	firstComponent.BotStatusManager().AddStatusToRotation(firstStatus)
+	secondComponent.BotStatusManager().AddStatusToRotation(secondStatus)
+	firstComponent.BotStatusManager().AddStatusToRotation(firstStatus)
+
+	botStatusManager.removeStatusOfComponent(firstComponent.Code)
+
+	for i := 0; i < 3; i++ {
+		suite.Equal(secondStatus, *botStatusManager.Next())
+	}
+}
+
 func TestStatusManager(t *testing.T) {
 	suite.Run(t, new(StatusManagerSuite))
 }
@@ -3,37 +3,33 @@ package dice
 import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
 )
 
 var memberPermissions int64 = discordgo.PermissionSendMessages
 
-var minValueDieSites = float64(2)
-var maxValueDieSites = float64(1000)
-var minValueDice = float64(1)
-var maxValueDice = float64(100)
+// These are the keys of the configuration options of the dice component.
+const (
+	configKeyDiceSitesNumber = "dice-sites-number"
+	configKeyNumberDice      = "number-dice"
+)
+
+// diceOptions holds the options of the dice command.
+// The limits of the options are also used to validate the configured defaults.
+type diceOptions struct {
+	DiceSitesNumber int `option:"dice-sites-number" description:"The number of how many sites the die has, default is 6 unless configured otherwise" min:"2" max:"1000"`
+	NumberDice      int `option:"number-dice" description:"How many dice you want to throw, default is 1 unless configured otherwise" min:"1" max:"100"`
+}
+
+// diceCommandOptions holds the generated options of the dice command.
+var diceCommandOptions = slash_commands.MustGenerateOptions(diceOptions{})
+
 var diceCommand = &api.Command{
 	Cmd: &discordgo.ApplicationCommand{
 		Name:                     "dice",
 		Description:              "throw one or more dice of your wished type.",
 		DefaultMemberPermissions: &memberPermissions,
-		Options: []*discordgo.ApplicationCommandOption{
-			{
-				Name:        "dice-sites-number",
-				Description: "The number of how many sites the die has, default is 6",
-				Type:        discordgo.ApplicationCommandOptionInteger,
-				MinValue:    &minValueDieSites,
-				MaxValue:    maxValueDieSites,
-				Required:    false,
-			},
-			{
-				Name:        "number-dice",
-				Description: "How many dice you want to throw, default is 1",
-				Type:        discordgo.ApplicationCommandOptionInteger,
-				MinValue:    &minValueDice,
-				MaxValue:    maxValueDice,
-				Required:    false,
-			},
-		},
+		Options:                  diceCommandOptions,
 	},
 	Global:   true,
 	Category: api.CategoryFun,
@@ -42,9 +38,20 @@ var diceCommand = &api.Command{
 
 // handleDice handles the dice slash command
 func handleDice(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	options := getOptionsAsMap(i)
-	n := getIntOption(options, "number-dice", 1)
-	d := getIntOption(options, "dice-sites-number", 6)
+	options := diceOptions{
+		DiceSitesNumber: getConfiguredDefault(i.GuildID, configKeyDiceSitesNumber, 6),
+		NumberDice:      getConfiguredDefault(i.GuildID, configKeyNumberDice, 1),
+	}
+
+	err := slash_commands.BindOptions(s, i, i.ApplicationCommandData().Options, &options)
+	if nil != err {
+		slash_commands.RespondWithOptionBindingError(&C, s, i, err)
+
+		return
+	}
+
+	n := options.NumberDice
+	d := options.DiceSitesNumber
 
 	r := rollDice(d, n)
 	e := createAnswerEmbedMessage(n, d, r)
@@ -52,25 +59,15 @@ func handleDice(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	sendAnswer(s, i, eSlice[:])
 }
 
-// getOptionsAsMap create a map and insert the command options
-func getOptionsAsMap(i *discordgo.InteractionCreate) map[string]*discordgo.ApplicationCommandInteractionDataOption {
-	options := i.ApplicationCommandData().Options
-	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
-	for _, opt := range options {
-		optionMap[opt.Name] = opt
-	}
-
-	return optionMap
-}
-
-// getIntOption returns the option as int
-func getIntOption(options map[string]*discordgo.ApplicationCommandInteractionDataOption, name string, defaultValue int) int {
-	o := defaultValue
-	if opt, ok := options[name]; ok {
-		o = int(opt.IntValue())
+// getConfiguredDefault returns the value of the passed configuration option
+// for the guild or the passed fallback, if the configuration could not be read.
+func getConfiguredDefault(guildId string, key string, fallback int) int {
+	value, err := C.ComponentConfig().GetInteger(guildId, key)
+	if nil != err {
+		return fallback
 	}
 
-	return o
+	return int(value)
 }
 
 // sendAnser sends the Answer
@@ -78,8 +75,5 @@ func sendAnswer(s *discordgo.Session, i *discordgo.InteractionCreate, e []*disco
 	resp := &discordgo.InteractionResponseData{
 		Embeds: e,
 	}
-	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: resp,
-	})
+	slash_commands.Respond(&C, s, i, resp)
 }
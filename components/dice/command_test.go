@@ -6,7 +6,6 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/lazybytez/jojo-discord-bot/test/discordgo_mock"
-	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -41,71 +40,93 @@ func (suite *CommandTestSuite) TestHandleDice() {
 	r.AssertExpectations(suite.T())
 
 	checkResponseDataHasOneEmbedAndIsNotNil(suite, *requestInteractionResponse.Data)
-	suite.ObjectsAreEqualValues(getDiceEmbedMessage(), *(requestInteractionResponse.Data.Embeds[0]))
-}
-
-func getDiceTestArray() map[string]float64 {
-	t := make(map[string]float64)
-	t["number-dice"] = 3
-	t["dice-sites-number"] = 1
 
-	return t
+	embed := requestInteractionResponse.Data.Embeds[0]
+	suite.Equal(getDiceEmbedMessage().Title, embed.Title)
+	suite.Len(embed.Fields, 1)
+	suite.Equal(getDiceEmbedMessage().Fields[0].Name, embed.Fields[0].Name)
+	suite.Regexp("^[12], [12], [12]$", embed.Fields[0].Value)
 }
 
-func getDiceEmbedMessage() discordgo.MessageEmbed {
-	m := discordgo.MessageEmbed{
-		Title: "You rolled 3 d1",
-		Fields: []*discordgo.MessageEmbedField{
-			{
-				Name:   "The Results are",
-				Value:  "1, 1, 1",
-				Inline: false,
-			},
+func (suite *CommandTestSuite) TestHandleDiceWithoutOptions() {
+	i := &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:    "interaction.ID",
+			Token: "interaction.Token",
+			Data:  discordgo.ApplicationCommandInteractionData{},
+			Type:  discordgo.InteractionApplicationCommand,
 		},
 	}
 
-	return m
+	s, r := discordgo_mock.MockSession()
+
+	requestInteractionResponse := &discordgo.InteractionResponse{}
+	r.OnRequestCaptureResult(http.MethodPost, requestInteractionResponse).Once().Return(
+		&http.Response{
+			StatusCode: http.StatusCreated,
+		}, nil)
+
+	handleDice(s, i)
+
+	r.AssertExpectations(suite.T())
+
+	checkResponseDataHasOneEmbedAndIsNotNil(suite, *requestInteractionResponse.Data)
+	suite.Equal("You rolled 1 d6", requestInteractionResponse.Data.Embeds[0].Title)
 }
 
-func (suite *CommandTestSuite) TestGetOptionsAsMap() {
+func (suite *CommandTestSuite) TestHandleDiceWithInvalidOption() {
+	options := getDiceTestArray()
+	options["dice-sites-number"] = 1
+
 	i := &discordgo.InteractionCreate{
 		Interaction: &discordgo.Interaction{
+			ID:    "interaction.ID",
+			Token: "interaction.Token",
 			Data: discordgo.ApplicationCommandInteractionData{
-				Options: createOptionArray(getJoestarTestArray()),
+				Options: createOptionArray(options),
 			},
 			Type: discordgo.InteractionApplicationCommand,
 		},
 	}
-	expected := createOptionMap()
 
-	actual := getOptionsAsMap(i)
-
-	suite.Len(actual, len(expected))
-	for i, obj := range expected {
-		suite.ObjectsAreEqualValues(obj, actual[i])
-	}
-}
+	s, r := discordgo_mock.MockSession()
 
-func (suite *CommandTestSuite) ObjectsAreEqualValues(expected, actual interface{}) {
-	if !assert.ObjectsAreEqualValues(expected, actual) {
-		suite.T().Error("objects are not equal")
-	}
-}
+	requestInteractionResponse := &discordgo.InteractionResponse{}
+	r.OnRequestCaptureResult(http.MethodPost, requestInteractionResponse).Once().Return(
+		&http.Response{
+			StatusCode: http.StatusCreated,
+		}, nil)
 
-func (suite *CommandTestSuite) TestGetIntOptionGetValue() {
-	optionMap := createOptionMap()
+	handleDice(s, i)
 
-	intValue := getIntOption(optionMap, "Joseph Joestar", 3)
+	r.AssertExpectations(suite.T())
 
-	suite.Equal(2, intValue)
+	checkResponseDataHasOneEmbedAndIsNotNil(suite, *requestInteractionResponse.Data)
+	suite.Equal(discordgo.MessageFlagsEphemeral, requestInteractionResponse.Data.Flags)
+	suite.Contains(requestInteractionResponse.Data.Embeds[0].Fields[0].Value, "dice-sites-number")
 }
 
-func (suite *CommandTestSuite) TestGetIntOptionGetDefaultValue() {
-	optionMap := createOptionMap()
+func getDiceTestArray() map[string]float64 {
+	t := make(map[string]float64)
+	t["number-dice"] = 3
+	t["dice-sites-number"] = 2
+
+	return t
+}
 
-	intValue := getIntOption(optionMap, "Dio Brando", 3)
+func getDiceEmbedMessage() discordgo.MessageEmbed {
+	m := discordgo.MessageEmbed{
+		Title: "You rolled 3 d2",
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "The Results are",
+				Value:  "1, 1, 1",
+				Inline: false,
+			},
+		},
+	}
 
-	suite.Equal(3, intValue)
+	return m
 }
 
 func (suite *CommandTestSuite) TestSendAnswer() {
@@ -136,16 +157,6 @@ func checkResponseDataHasOneEmbedAndIsNotNil(s *CommandTestSuite, data discordgo
 	s.Len(data.Embeds, 1)
 }
 
-func createOptionMap() map[string]*discordgo.ApplicationCommandInteractionDataOption {
-	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, 3)
-
-	for name, value := range getJoestarTestArray() {
-		optionMap[name] = createDiscordOptionWithValue(name, value)
-	}
-
-	return optionMap
-}
-
 func createOptionArray(a map[string]float64) []*discordgo.ApplicationCommandInteractionDataOption {
 	i := 0
 	options := make([]*discordgo.ApplicationCommandInteractionDataOption, len(a))
@@ -157,15 +168,6 @@ func createOptionArray(a map[string]float64) []*discordgo.ApplicationCommandInte
 	return options
 }
 
-func getJoestarTestArray() map[string]float64 {
-	t := make(map[string]float64)
-	t["Jonathan Joestar"] = 1
-	t["Joseph Joestar"] = 2
-	t["Jotarou Joestar"] = 3
-
-	return t
-}
-
 func createDiscordOptionWithValue(name string, value float64) *discordgo.ApplicationCommandInteractionDataOption {
 	option := new(discordgo.ApplicationCommandInteractionDataOption)
 	option.Name = name
@@ -174,3 +176,12 @@ func createDiscordOptionWithValue(name string, value float64) *discordgo.Applica
 
 	return option
 }
+
+func (suite *CommandTestSuite) TestGetConfiguredDefaultWithoutGuild() {
+	suite.Equal(6, getConfiguredDefault("", configKeyDiceSitesNumber, 2))
+	suite.Equal(1, getConfiguredDefault("", configKeyNumberDice, 2))
+}
+
+func (suite *CommandTestSuite) TestGetConfiguredDefaultWithUnknownKey() {
+	suite.Equal(2, getConfiguredDefault("", "unknown", 2))
+}
@@ -16,6 +16,23 @@ var C = api.Component{
 	State: &api.State{
 		DefaultEnabled: true,
 	},
+
+	ConfigSchema: api.ConfigSchema{
+		{
+			Key:         configKeyDiceSitesNumber,
+			Description: "The number of sites of the dice, when no number is passed to the command",
+			Type:        api.ConfigOptionTypeInteger,
+			Default:     int64(6),
+			Validate:    api.IntegerOptionRangeValidator(diceCommandOptions, configKeyDiceSitesNumber),
+		},
+		{
+			Key:         configKeyNumberDice,
+			Description: "The number of dice to throw, when no number is passed to the command",
+			Type:        api.ConfigOptionTypeInteger,
+			Default:     int64(1),
+			Validate:    api.IntegerOptionRangeValidator(diceCommandOptions, configKeyNumberDice),
+		},
+	},
 }
 
 // init initializes the component with its metadata
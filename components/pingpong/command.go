@@ -21,6 +21,7 @@ package pingpong
 import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
 )
 
 var pingCommand = &api.Command{
@@ -33,12 +34,9 @@ var pingCommand = &api.Command{
 }
 
 func handlePing(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Flags:   discordgo.MessageFlagsEphemeral,
-			Content: "Pong!",
-		},
+	slash_commands.Respond(&C, s, i, &discordgo.InteractionResponseData{
+		Flags:   discordgo.MessageFlagsEphemeral,
+		Content: "Pong!",
 	})
 }
 
@@ -52,11 +50,8 @@ var pongCommand = &api.Command{
 }
 
 func handlePong(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Flags:   discordgo.MessageFlagsEphemeral,
-			Content: "Ping!",
-		},
+	slash_commands.Respond(&C, s, i, &discordgo.InteractionResponseData{
+		Flags:   discordgo.MessageFlagsEphemeral,
+		Content: "Ping!",
 	})
 }
@@ -19,40 +19,63 @@
 package statistics
 
 import (
+	"github.com/lazybytez/jojo-discord-bot/api"
 	"os"
+	"sync/atomic"
 	"time"
 )
 
+// guildCountUpdateInterval is the interval in which the cached guild count is updated.
+const guildCountUpdateInterval = 10 * time.Minute
+
 var (
-	lastGuildCountUpdate    time.Time
-	cachedGuildCount        int
-	cachedSlashCommandCount = -1
-	cachedClusterId         string
+	guildCountCollected atomic.Bool
+	cachedGuildCount    atomic.Int64
+	cachedClusterId     string
 )
 
-// collectGuildCount returns the currently cached guild count
-// or recomputes the guild count.
+// scheduleGuildCountUpdate schedules the job that periodically
+// updates the cached guild count.
+func scheduleGuildCountUpdate() error {
+	return C.Scheduler().Schedule(api.Job{
+		Name:     "guild_count_update",
+		Interval: guildCountUpdateInterval,
+		Jitter:   time.Minute,
+		Handler:  updateGuildCount,
+	})
+}
+
+// updateGuildCount recomputes the cached guild count.
+func updateGuildCount() error {
+	cachedGuildCount.Store(int64(C.DiscordApi().GuildCount()))
+	guildCountCollected.Store(true)
+
+	return nil
+}
+
+// collectGuildCount returns the currently cached guild count.
+// When the guild count has not been computed yet, it is computed immediately.
 //
-// The guild count is cached for 10 minutes.
+// The guild count is updated every 10 minutes.
 func collectGuildCount() int {
-	if time.Since(lastGuildCountUpdate) > 10*time.Minute {
-		cachedGuildCount = C.DiscordApi().GuildCount()
-
-		lastGuildCountUpdate = time.Now()
+	if !guildCountCollected.Load() {
+		_ = updateGuildCount()
 	}
 
-	return cachedGuildCount
+	return int(cachedGuildCount.Load())
+}
+
+// collectShardStatus returns the current status of all shards.
+// The value is not cached, as it should reflect the current health of the shards.
+func collectShardStatus() []api.ShardStatus {
+	return C.DiscordApi().ShardStatus()
 }
 
 // collectSlashCommandCount returns the current count of registered slash commands.
-// As slash-commands should be only registered during startup, we just cache
-// the resulting value infinite and return it on later calls.
+// The value is not cached, as components and their slash-commands
+// can be loaded and unloaded at runtime.
 func collectSlashCommandCount() int {
-	if -1 == cachedSlashCommandCount {
-		cachedSlashCommandCount = C.SlashCommandManager().GetCommandCount()
-	}
-
-	return cachedSlashCommandCount
+	return C.SlashCommandManager().GetCommandCount()
 }
 
 // collectClusterId returns the cluster id of the current instance.
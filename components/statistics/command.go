@@ -29,6 +29,7 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/dustin/go-humanize"
 	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
 	"github.com/lazybytez/jojo-discord-bot/build"
 )
 
@@ -54,6 +55,9 @@ var infoCommand = &api.Command{
 	Handler:  handleStats,
 }
 
+// maxListedShards is the maximum number of shards that are listed in detail
+const maxListedShards = 10
+
 // With the m variable the command can access memory runtime statistics
 var m runtime.MemStats
 
@@ -63,11 +67,8 @@ var botStartTime = time.Now()
 // handleStats gets called when executing the /stats or /info command
 func handleStats(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	runtime.ReadMemStats(&m)
-	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Embeds: buildInfoEmbed(s),
-		},
+	slash_commands.Respond(&C, s, i, &discordgo.InteractionResponseData{
+		Embeds: buildInfoEmbed(s),
 	})
 }
 
@@ -89,6 +90,10 @@ func buildInfoEmbed(s *discordgo.Session) []*discordgo.MessageEmbed {
 					Name:  "Stats",
 					Value: buildStatOutput(),
 				},
+				{
+					Name:  "Shards",
+					Value: buildShardOutput(),
+				},
 				{
 					Name:  "Links",
 					Value: "[GitHub / Source](https://github.com/lazybytez/jojo-discord-bot)",
@@ -144,6 +149,55 @@ func buildStatOutput() string {
 	return buf.String()
 }
 
+// buildShardOutput generates a string with the health of all shards.
+// To stay within the limits of embed fields, only the first shards are listed in detail.
+func buildShardOutput() string {
+	w := &tabwriter.Writer{}
+	buf := &bytes.Buffer{}
+
+	shardStatus := collectShardStatus()
+	connectedShards := 0
+	for _, status := range shardStatus {
+		if status.Connected {
+			connectedShards++
+		}
+	}
+
+	w.Init(buf, 0, 4, 0, ' ', 0)
+
+	appendStatLine(w, "Connected: **%d / %d**\n", connectedShards, len(shardStatus))
+	appendStatLine(w, "Average Latency: **%v**\n", C.DiscordApi().Latency().Round(time.Millisecond))
+	for key, status := range shardStatus {
+		if key >= maxListedShards {
+			appendStatLine(w, "*and %d more...*\n", len(shardStatus)-maxListedShards)
+
+			break
+		}
+
+		appendStatLine(w, "#%d: **%s**, %d servers, %v\n",
+			status.ID,
+			getShardStateString(status),
+			status.Guilds,
+			status.Latency.Round(time.Millisecond))
+	}
+
+	err := w.Flush()
+	if err != nil {
+		C.Logger().Err(err, "Could not flush statistics embed text write buffer.")
+	}
+
+	return buf.String()
+}
+
+// getShardStateString returns a readable representation of the connection state of a shard
+func getShardStateString(status api.ShardStatus) string {
+	if status.Connected {
+		return "Connected"
+	}
+
+	return "Disconnected"
+}
+
 // getDurationString transforms duration into a readable string
 func getDurationString(duration time.Duration) string {
 	return fmt.Sprintf(
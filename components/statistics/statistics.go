@@ -46,9 +46,13 @@ func LoadComponent(_ *discordgo.Session) error {
 	_ = C.SlashCommandManager().Register(infoCommand)
 
 	registerBotStatus()
-	registerRoutes()
 
-	return nil
+	err := scheduleGuildCountUpdate()
+	if nil != err {
+		return err
+	}
+
+	return registerRoutes()
 }
 
 // registerBotStatus registers the bot status for status rotation
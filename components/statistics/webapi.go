@@ -21,7 +21,6 @@ package statistics
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/lazybytez/jojo-discord-bot/build"
-	"github.com/lazybytez/jojo-discord-bot/webapi"
 	"net/http"
 )
 
@@ -31,6 +30,7 @@ import (
 // @Description Statistics holds statistics about the bot like the current version.
 type StatsDTO struct {
 	GuildCount        int    `json:"guild_count"`
+	ShardCount        int    `json:"shard_count"`
 	SlashCommandCount int    `json:"slash_command_count"`
 	Version           string `json:"version"`
 } //@Name Statistics
@@ -49,6 +49,7 @@ type StatsDTO struct {
 func StatsGet(g *gin.Context) {
 	statsDto := StatsDTO{
 		GuildCount:        collectGuildCount(),
+		ShardCount:        len(collectShardStatus()),
 		SlashCommandCount: collectSlashCommandCount(),
 		Version:           build.ComputeVersionString(),
 	}
@@ -58,7 +59,8 @@ func StatsGet(g *gin.Context) {
 
 // registerRoutes registers the web api routes
 // provided by the statistics component.
-func registerRoutes() {
-	eg := webapi.Router().Group("/stats")
-	eg.GET("/", StatsGet)
+func registerRoutes() error {
+	eg := C.WebApiRouter().Group("/stats")
+
+	return eg.GET("/", StatsGet)
 }
@@ -19,23 +19,31 @@
 package bot_core
 
 import (
+	"embed"
 	"github.com/bwmarrin/discordgo"
 	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/api/i18n"
 )
 
 var C = api.Component{
 	// Metadata
-	Code:         "bot_core",
-	Name:         "Bot Core",
-	Categories:   api.Categories{api.CategoryInternal},
-	Description:  "This component handles core routines and entity management.",
-	LoadPriority: 1000,
+	Code:        "bot_core",
+	Name:        "Bot Core",
+	Categories:  api.Categories{api.CategoryInternal},
+	Description: "This component handles core routines and entity management.",
+
+	Intents: discordgo.IntentsGuilds,
 
 	State: &api.State{
 		DefaultEnabled: true,
 	},
 }
 
+// catalogFiles holds the message catalogs of the bot core.
+//
+//go:embed locales/*.json
+var catalogFiles embed.FS
+
 func init() {
 	api.RegisterComponent(&C, LoadComponent)
 }
@@ -44,11 +52,27 @@ func init() {
 // and handles migration of core entities
 // and registration of important core event handlers.
 func LoadComponent(_ *discordgo.Session) error {
+	err := i18n.RegisterCatalogFiles(catalogFiles, "locales")
+	if nil != err {
+		return err
+	}
+
 	initializeComponentManagement()
 
 	_, _ = C.HandlerManager().Register("guild_join", onGuildJoin)
 	_, _ = C.HandlerManager().Register("update_registered_guilds", handleGuildUpdateOnUpdate)
 	_, _ = C.HandlerManager().Register("update_global_commands", handleGlobalCommandSyncOnReady)
+	_, _ = C.HandlerManager().Register("guild_leave", handleGuildLeave)
+
+	err = scheduleComponentStorageCleanup()
+	if nil != err {
+		return err
+	}
+
+	err = scheduleGuildDataPurge()
+	if nil != err {
+		return err
+	}
 
 	// We need to handle the JOJO command special as it needs access to the component list.
 	// This is only possible after the API has been properly initialized and the components.Components
@@ -65,6 +89,7 @@ func LoadComponent(_ *discordgo.Session) error {
 // by populating the database with necessary data and pre-warming the cache.
 func initializeComponentManagement() {
 	ensureGlobalComponentStatusExists()
+	warnAboutGloballyDisabledDependencies()
 }
 
 // onGuildJoin is an event handler called when to bot joins a guild.
@@ -19,8 +19,11 @@
 package bot_core
 
 import (
+	"encoding/json"
+	"github.com/lazybytez/jojo-discord-bot/api/i18n"
 	"github.com/lazybytez/jojo-discord-bot/test/helper"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
@@ -29,3 +32,24 @@ func TestBotCoreInit(t *testing.T) {
 
 	assert.True(t, result)
 }
+
+func TestBotCoreCatalogs(t *testing.T) {
+	err := i18n.RegisterCatalogFiles(catalogFiles, "locales")
+	assert.NoError(t, err)
+
+	data, err := catalogFiles.ReadFile("locales/de.json")
+	assert.NoError(t, err)
+
+	catalog := i18n.Catalog{}
+	assert.NoError(t, json.Unmarshal(data, &catalog))
+
+	// Every response message must also be available in the default locale,
+	// only command localizations are taken from the commands themselves.
+	for key := range catalog {
+		if strings.HasPrefix(key, "command.") {
+			continue
+		}
+
+		assert.True(t, i18n.HasTranslation(i18n.DefaultLocale, key), "missing message \"%s\"", key)
+	}
+}
@@ -20,17 +20,19 @@ package auditlog
 
 import (
 	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
 	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
 )
 
+// The keys of the messages used by the auditlog disable sub-command.
 const (
-	disableCommandResponseHeader                      = "Disable Bot Audit Log"
-	disableAuditLogNeverConfiguredBeforeResponseName  = ":x: Oh no, no configuration could be found!"
-	disableAuditLogNeverConfiguredBeforeResponseValue = "The bot audit log is already disabled, as it has not been configured before!"
-	disableAuditLogAlreadyDisabledResponseName        = ":x: Nothing to do here!"
-	disableAuditLogAlreadyDisabledResponseValue       = "The bot audit log is already disabled!"
-	disableAuditSuccessResponseName                   = ":white_check_mark: Done!"
-	disableAuditLogSuccessResponseValue               = "The bot audit log is now disabled! You can enable it again at any time!"
+	disableCommandResponseHeader                      = "bot_core.auditlog.disable.header"
+	disableAuditLogNeverConfiguredBeforeResponseName  = "bot_core.auditlog.disable.never_configured.name"
+	disableAuditLogNeverConfiguredBeforeResponseValue = "bot_core.auditlog.disable.never_configured.value"
+	disableAuditLogAlreadyDisabledResponseName        = "bot_core.auditlog.disable.already_disabled.name"
+	disableAuditLogAlreadyDisabledResponseValue       = "bot_core.auditlog.disable.already_disabled.value"
+	disableAuditSuccessResponseName                   = "bot_core.auditlog.disable.success.name"
+	disableAuditLogSuccessResponseValue               = "bot_core.auditlog.disable.success.value"
 )
 
 func handleAuditLogDisable(
@@ -52,7 +54,9 @@ func handleAuditLogDisable(
 		user = i.Member.User
 	}
 
-	resp := slash_commands.GenerateEphemeralInteractionResponseTemplate(disableCommandResponseHeader, "")
+	resp := slash_commands.GenerateEphemeralInteractionResponseTemplate(
+		api.Translate(i, disableCommandResponseHeader),
+		"")
 
 	guild, err := C.EntityManager().Guilds().Get(i.GuildID)
 	if nil != err {
@@ -67,8 +71,8 @@ func handleAuditLogDisable(
 			s,
 			i,
 			resp,
-			disableAuditLogNeverConfiguredBeforeResponseName,
-			disableAuditLogNeverConfiguredBeforeResponseValue)
+			api.Translate(i, disableAuditLogNeverConfiguredBeforeResponseName),
+			api.Translate(i, disableAuditLogNeverConfiguredBeforeResponseValue))
 
 		return
 	}
@@ -78,8 +82,8 @@ func handleAuditLogDisable(
 			s,
 			i,
 			resp,
-			disableAuditLogAlreadyDisabledResponseName,
-			disableAuditLogAlreadyDisabledResponseValue)
+			api.Translate(i, disableAuditLogAlreadyDisabledResponseName),
+			api.Translate(i, disableAuditLogAlreadyDisabledResponseValue))
 
 		return
 	}
@@ -94,12 +98,18 @@ func handleAuditLogDisable(
 		return
 	}
 
+	C.EventBus().Publish(&api.AuditLogConfigChangedEvent{
+		GuildId: i.GuildID,
+		Enabled: false,
+		UserId:  user.ID,
+	})
+
 	slash_commands.RespondWithSimpleEmbedMessage(C,
 		s,
 		i,
 		resp,
-		disableAuditSuccessResponseName,
-		disableAuditLogSuccessResponseValue)
+		api.Translate(i, disableAuditSuccessResponseName),
+		api.Translate(i, disableAuditLogSuccessResponseValue))
 
 	C.BotAuditLogger().Log(
 		dgoGuild,
@@ -21,20 +21,29 @@ package auditlog
 import (
 	"fmt"
 	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
 	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
 	"strconv"
 )
 
+// The keys of the messages used by the auditlog enable sub-command.
 const (
-	enableCommandResponseHeader                            = "Enable Bot Audit Log"
-	enableCannotConfigureWithoutChannelResponseName        = ":x: Whoops, no bot audit log without a channel!"
-	enableCannotConfigureWithoutChannelResponseValue       = "To enable the bot audit log for the first time, you must enter a valid channel to write the logs to!"
-	enableAlreadyConfiguredForChannelResponseName          = ":x: Nothing to do here!"
-	enableAlreadyConfiguredForChannelResponseValueTemplate = "The bot audit log is already enabled and configured to use the channel %s!"
-	enableSuccessResponseName                              = ":white_check_mark: Done!"
-	enableSuccessResponseValueTemplate                     = "The bot audit log is now enabled and configured to use the channel %s!"
+	enableCommandResponseHeader                            = "bot_core.auditlog.enable.header"
+	enableCannotConfigureWithoutChannelResponseName        = "bot_core.auditlog.enable.without_channel.name"
+	enableCannotConfigureWithoutChannelResponseValue       = "bot_core.auditlog.enable.without_channel.value"
+	enableAlreadyConfiguredForChannelResponseName          = "bot_core.auditlog.enable.already_configured.name"
+	enableAlreadyConfiguredForChannelResponseValueTemplate = "bot_core.auditlog.enable.already_configured.value"
+	enableSuccessResponseName                              = "bot_core.auditlog.enable.success.name"
+	enableSuccessResponseValueTemplate                     = "bot_core.auditlog.enable.success.value"
 )
 
+// EnableOptions holds the options of the auditlog enable sub-command.
+type EnableOptions struct {
+	Channel *discordgo.Channel `option:"channel" description:"The channel where audit log messages should be send to" required:"true" channel_types:"text"`
+}
+
+// handleAuditLogEnable enables the bot audit log and configures
+// the channel the audit log messages are sent to.
 func handleAuditLogEnable(
 	s *discordgo.Session,
 	i *discordgo.InteractionCreate,
@@ -54,7 +63,9 @@ func handleAuditLogEnable(
 		user = i.Member.User
 	}
 
-	resp := slash_commands.GenerateEphemeralInteractionResponseTemplate(enableCommandResponseHeader, "")
+	resp := slash_commands.GenerateEphemeralInteractionResponseTemplate(
+		api.Translate(i, enableCommandResponseHeader),
+		"")
 
 	guild, err := C.EntityManager().Guilds().Get(i.GuildID)
 	if nil != err {
@@ -63,14 +74,14 @@ func handleAuditLogEnable(
 		return
 	}
 
-	var channel *discordgo.Channel
-	for _, option := range options.Options {
-		if option.Name == "channel" {
-			channel = option.ChannelValue(s)
+	enableOptions := EnableOptions{}
+	err = slash_commands.BindOptions(s, i, options.Options, &enableOptions)
+	if nil != err {
+		slash_commands.RespondWithOptionBindingError(C, s, i, err)
 
-			break
-		}
+		return
 	}
+	channel := enableOptions.Channel
 
 	guildAuditLogConfig, err := C.EntityManager().AuditLogConfig().GetByGuildId(guild.ID)
 	if nil != err && nil == channel {
@@ -78,8 +89,8 @@ func handleAuditLogEnable(
 			s,
 			i,
 			resp,
-			enableCannotConfigureWithoutChannelResponseName,
-			enableCannotConfigureWithoutChannelResponseValue)
+			api.Translate(i, enableCannotConfigureWithoutChannelResponseName),
+			api.Translate(i, enableCannotConfigureWithoutChannelResponseValue))
 
 		return
 	}
@@ -104,8 +115,8 @@ func handleAuditLogEnable(
 			s,
 			i,
 			resp,
-			enableAlreadyConfiguredForChannelResponseName,
-			fmt.Sprintf(enableAlreadyConfiguredForChannelResponseValueTemplate, channel.Mention()))
+			api.Translate(i, enableAlreadyConfiguredForChannelResponseName),
+			api.Translate(i, enableAlreadyConfiguredForChannelResponseValueTemplate, channel.Mention()))
 
 		return
 	}
@@ -119,8 +130,8 @@ func handleAuditLogEnable(
 			s,
 			i,
 			resp,
-			enableCannotConfigureWithoutChannelResponseName,
-			enableCannotConfigureWithoutChannelResponseValue)
+			api.Translate(i, enableCannotConfigureWithoutChannelResponseName),
+			api.Translate(i, enableCannotConfigureWithoutChannelResponseValue))
 
 		return
 	}
@@ -134,13 +145,20 @@ func handleAuditLogEnable(
 		return
 	}
 
+	C.EventBus().Publish(&api.AuditLogConfigChangedEvent{
+		GuildId:   i.GuildID,
+		Enabled:   true,
+		ChannelId: strconv.FormatUint(*guildAuditLogConfig.ChannelId, 10),
+		UserId:    user.ID,
+	})
+
 	notifyAuditLogChannelConfigured(s, channel, i.Member)
 	slash_commands.RespondWithSimpleEmbedMessage(C,
 		s,
 		i,
 		resp,
-		enableSuccessResponseName,
-		fmt.Sprintf(enableSuccessResponseValueTemplate, channel.Mention()))
+		api.Translate(i, enableSuccessResponseName),
+		api.Translate(i, enableSuccessResponseValueTemplate, channel.Mention()))
 
 	C.BotAuditLogger().Log(
 		dgoGuild,
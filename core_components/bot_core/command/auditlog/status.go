@@ -21,17 +21,23 @@ package auditlog
 import (
 	"fmt"
 	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
 	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
 	"strconv"
 )
 
 const (
-	statusCommandResponseHeader                = "Bot Audit Log Status"
-	statusBotAuditLogEnabledName               = "Enabled"
-	statusBotAuditLogEnabledValue              = ":white_check_mark:"
-	statusBotAuditLogDisabledValue             = ":x:"
-	statusBotAuditLogChannelName               = "Channel"
-	statusBotAuditLogChannelNotConfiguredValue = "Not configured!"
+	statusBotAuditLogEnabledValue  = ":white_check_mark:"
+	statusBotAuditLogDisabledValue = ":x:"
+)
+
+// The keys of the messages used by the auditlog status sub-command.
+const (
+	statusCommandResponseHeader                = "bot_core.auditlog.status.header"
+	statusBotAuditLogEnabledName               = "bot_core.auditlog.status.enabled.name"
+	statusBotAuditLogChannelName               = "bot_core.auditlog.status.channel.name"
+	statusBotAuditLogChannelNotConfiguredValue = "bot_core.auditlog.status.channel.not_configured"
+	statusBotAuditLogChannelDeletedValue       = "bot_core.auditlog.status.channel.deleted"
 )
 
 // handleModuleDisable enables the targeted module.
@@ -40,7 +46,9 @@ func handleAuditLogStatus(
 	i *discordgo.InteractionCreate,
 	_ *discordgo.ApplicationCommandInteractionDataOption,
 ) {
-	resp := slash_commands.GenerateEphemeralInteractionResponseTemplate(statusCommandResponseHeader, "")
+	resp := slash_commands.GenerateEphemeralInteractionResponseTemplate(
+		api.Translate(i, statusCommandResponseHeader),
+		"")
 
 	guild, err := C.EntityManager().Guilds().Get(i.GuildID)
 	if nil != err {
@@ -55,7 +63,7 @@ func handleAuditLogStatus(
 			i,
 			resp,
 			statusBotAuditLogDisabledValue,
-			statusBotAuditLogChannelNotConfiguredValue)
+			api.Translate(i, statusBotAuditLogChannelNotConfiguredValue))
 
 		return
 	}
@@ -66,12 +74,12 @@ func handleAuditLogStatus(
 			i,
 			resp,
 			statusBotAuditLogDisabledValue,
-			statusBotAuditLogChannelNotConfiguredValue)
+			api.Translate(i, statusBotAuditLogChannelNotConfiguredValue))
 
 		return
 	}
 
-	channelStatus, channelFound := getConfiguredChannel(s, *currentChannelId)
+	channelStatus, channelFound := getConfiguredChannel(s, i, *currentChannelId)
 	statusBadge := getStatusDisplay(guildAuditLogConfig.Enabled, channelFound)
 
 	respondWithAuditLogConfigStatus(s,
@@ -94,7 +102,7 @@ func getStatusDisplay(auditLogEnabled bool, channelFound bool) string {
 // getConfiguredChannel returns the currently configured channel
 // for the bot audit log. It also returns a boolean indicating whether the configured channel could
 // be found on the guild.
-func getConfiguredChannel(session *discordgo.Session, channel uint64) (string, bool) {
+func getConfiguredChannel(session *discordgo.Session, i *discordgo.InteractionCreate, channel uint64) (string, bool) {
 	channelIdStr := strconv.FormatUint(channel, 10)
 
 	dgChannel, err := session.Channel(channelIdStr)
@@ -102,9 +110,7 @@ func getConfiguredChannel(session *discordgo.Session, channel uint64) (string, b
 		return fmt.Sprintf("<#%v>", dgChannel.ID), true
 	}
 
-	return fmt.Sprintf("Seems like the configured channel with id `%d` has been deleted. "+
-		"Please re-enable the bot auditlog to receive further bot audit log messages!",
-		channel), false
+	return api.Translate(i, statusBotAuditLogChannelDeletedValue, channel), false
 }
 
 // respondWithAuditLogConfigStatus cares about filling up the interaction
@@ -119,12 +125,12 @@ func respondWithAuditLogConfigStatus(
 ) {
 	resp.Embeds[0].Fields = []*discordgo.MessageEmbedField{
 		{
-			Name:   statusBotAuditLogEnabledName,
+			Name:   api.Translate(i, statusBotAuditLogEnabledName),
 			Value:  auditLogStatus,
 			Inline: false,
 		},
 		{
-			Name:   statusBotAuditLogChannelName,
+			Name:   api.Translate(i, statusBotAuditLogChannelName),
 			Value:  auditLogChannel,
 			Inline: false,
 		},
@@ -59,11 +59,8 @@ func HandleAuditLogCommandSubCommand(
 
 	if !success {
 		if !success {
-			_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-				Type: discordgo.InteractionResponseChannelMessageWithSource,
-				Data: &discordgo.InteractionResponseData{
-					Content: "The executed (sub)command is invalid or does not exist!",
-				},
+			slash_commands.Respond(C, s, i, &discordgo.InteractionResponseData{
+				Content: "The executed (sub)command is invalid or does not exist!",
 			})
 		}
 	}
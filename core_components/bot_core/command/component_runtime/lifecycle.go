@@ -0,0 +1,135 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package component_runtime
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
+)
+
+const (
+	runtimeInvalidModuleResponseName = ":x: Invalid module!"
+	runtimeInvalidModuleResponseText = "The passed module does not exist!"
+	runtimeProcessingResponseName    = ":alarm_clock: Processing..."
+	runtimeProcessingResponseValue   = "The module `%s` is being %s and slash-commands are synchronised, please wait..."
+	runtimeFailedResponseName        = ":x: Failed!"
+	runtimeFailedResponseValue       = "The module `%s` could not be %s: %s"
+	runtimeSuccessResponseName       = ":white_check_mark: Done!"
+	runtimeSuccessResponseValue      = "The module `%s` has been %s!"
+)
+
+// handleRuntimeLoad loads the passed module at runtime.
+func handleRuntimeLoad(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	option *discordgo.ApplicationCommandInteractionDataOption,
+) {
+	handleRuntimeOperation(s, i, option, "loaded", C.ComponentLifecycleManager().Load)
+}
+
+// handleRuntimeUnload unloads the passed module at runtime.
+func handleRuntimeUnload(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	option *discordgo.ApplicationCommandInteractionDataOption,
+) {
+	handleRuntimeOperation(s, i, option, "unloaded", C.ComponentLifecycleManager().Unload)
+}
+
+// handleRuntimeReload reloads the passed module at runtime.
+func handleRuntimeReload(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	option *discordgo.ApplicationCommandInteractionDataOption,
+) {
+	handleRuntimeOperation(s, i, option, "reloaded", C.ComponentLifecycleManager().Reload)
+}
+
+// handleRuntimeOperation executes the passed lifecycle operation for the module
+// passed in the options of the command and keeps the user informed about the progress.
+func handleRuntimeOperation(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	option *discordgo.ApplicationCommandInteractionDataOption,
+	operationName string,
+	operation func(code entities.ComponentCode) error,
+) {
+	resp := slash_commands.GenerateEphemeralInteractionResponseTemplate(runtimeCommandResponseHeader, "")
+
+	if len(option.Options) < 1 {
+		slash_commands.RespondWithSimpleEmbedMessage(C,
+			s,
+			i,
+			resp,
+			runtimeInvalidModuleResponseName,
+			runtimeInvalidModuleResponseText)
+
+		return
+	}
+
+	code, ok := option.Options[0].Value.(string)
+	if !ok {
+		slash_commands.RespondWithSimpleEmbedMessage(C,
+			s,
+			i,
+			resp,
+			runtimeInvalidModuleResponseName,
+			runtimeInvalidModuleResponseText)
+
+		return
+	}
+
+	slash_commands.RespondWithSimpleEmbedMessage(C,
+		s,
+		i,
+		resp,
+		runtimeProcessingResponseName,
+		fmt.Sprintf(runtimeProcessingResponseValue, code, operationName))
+
+	err := operation(entities.ComponentCode(code))
+	if nil != err {
+		C.Logger().Err(err, "Failed to execute runtime operation on module \"%s\"!", code)
+
+		resp.Embeds[0].Fields = []*discordgo.MessageEmbedField{
+			{
+				Name:  runtimeFailedResponseName,
+				Value: fmt.Sprintf(runtimeFailedResponseValue, code, operationName, err.Error()),
+			},
+		}
+
+		slash_commands.EditResponse(C, s, i, &discordgo.WebhookEdit{
+			Embeds: &resp.Embeds,
+		})
+
+		return
+	}
+
+	resp.Embeds[0].Fields = []*discordgo.MessageEmbedField{
+		{
+			Name:  runtimeSuccessResponseName,
+			Value: fmt.Sprintf(runtimeSuccessResponseValue, code, operationName),
+		},
+	}
+
+	slash_commands.EditResponse(C, s, i, &discordgo.WebhookEdit{
+		Embeds: &resp.Embeds,
+	})
+}
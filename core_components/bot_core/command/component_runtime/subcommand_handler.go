@@ -0,0 +1,83 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package component_runtime
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
+)
+
+var C *api.Component
+
+const (
+	runtimeCommandResponseHeader      = "Component Runtime Management"
+	runtimeNotABotOwnerResponseName   = ":no_entry_sign: Access denied!"
+	runtimeNotABotOwnerResponseValue  = "Only the owners of the bot are allowed to manage components at runtime!"
+	runtimeOwnerCheckFailedLogMessage = "Failed to check whether user \"%s\" is an owner of the bot!"
+)
+
+// HandleRuntimeSubCommand delegates the sub-commands of the runtime sub-command
+// to their dedicated handlers.
+//
+// The sub-commands can only be executed by the owners of the bot,
+// as they affect all guilds the bot is on.
+func HandleRuntimeSubCommand(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	option *discordgo.ApplicationCommandInteractionDataOption,
+) {
+	user := i.User
+	if nil == user {
+		user = i.Member.User
+	}
+
+	isBotOwner, err := C.DiscordApi().IsBotOwner(user.ID)
+	if nil != err {
+		C.Logger().Err(err, runtimeOwnerCheckFailedLogMessage, user.ID)
+	}
+
+	if !isBotOwner {
+		resp := slash_commands.GenerateEphemeralInteractionResponseTemplate(runtimeCommandResponseHeader, "")
+		slash_commands.RespondWithSimpleEmbedMessage(C,
+			s,
+			i,
+			resp,
+			runtimeNotABotOwnerResponseName,
+			runtimeNotABotOwnerResponseValue)
+
+		return
+	}
+
+	subCommands := map[string]func(
+		s *discordgo.Session,
+		i *discordgo.InteractionCreate,
+		option *discordgo.ApplicationCommandInteractionDataOption,
+	){
+		"load":   handleRuntimeLoad,
+		"unload": handleRuntimeUnload,
+		"reload": handleRuntimeReload,
+	}
+
+	api.ProcessSubCommands(
+		s,
+		i,
+		option,
+		subCommands)
+}
@@ -0,0 +1,146 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package export
+
+import (
+	"bytes"
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
+	"time"
+)
+
+var C *api.Component
+
+// The keys of the messages used by the export sub-command.
+const (
+	exportCommandResponseHeader = "bot_core.export.header"
+	exportSuccessResponseName   = "bot_core.export.success.name"
+	exportSuccessResponseValue  = "bot_core.export.success.value"
+	exportTooLargeResponseName  = "bot_core.export.too_large.name"
+	exportTooLargeResponseValue = "bot_core.export.too_large.value"
+	exportAuditLogMessage       = "The guild data has been exported"
+)
+
+// maxAttachmentSize is the maximum size of a file that can be
+// attached to an interaction response by the bot.
+const maxAttachmentSize = 10 * 1024 * 1024
+
+// exportRateLimit ensures that the data of a guild can only be
+// exported once every 5 minutes.
+var exportRateLimit = &api.RateLimit{
+	Name:     "guild_export",
+	Strategy: api.RateLimitStrategyFixedWindow,
+	Scope:    api.RateLimitScopeGuild,
+	Limit:    1,
+	Window:   5 * time.Minute,
+}
+
+// Options holds the options of the export sub-command.
+type Options struct {
+	Zip bool `option:"zip" description:"Whether the export should be compressed as zip archive"`
+}
+
+// HandleExportSubCommand handles the execution of the
+// "export" subcommand.
+//
+// The command exports all data the bot stores about the guild
+// and sends it back as an ephemeral attachment.
+func HandleExportSubCommand(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	option *discordgo.ApplicationCommandInteractionDataOption,
+) {
+	if nil == i.Member {
+		slash_commands.RespondWithCommandIsGuildOnly(C, s, i, "export")
+
+		return
+	}
+
+	resp := slash_commands.GenerateEphemeralInteractionResponseTemplate(
+		api.Translate(i, exportCommandResponseHeader),
+		"")
+
+	exportOptions := Options{}
+	err := slash_commands.BindOptions(s, i, option.Options, &exportOptions)
+	if nil != err {
+		slash_commands.RespondWithOptionBindingError(C, s, i, err)
+
+		return
+	}
+
+	if allowed, retryAfter := exportRateLimit.Take(i); !allowed {
+		resp.Embeds[0].Fields = []*discordgo.MessageEmbedField{
+			api.NewRateLimitedEmbedField(retryAfter),
+		}
+		slash_commands.Respond(C, s, i, resp)
+
+		return
+	}
+
+	export, err := api.ExportGuildData(i.GuildID)
+	if nil != err {
+		C.Logger().Err(err, "Failed to export the data of guild \"%s\"!", i.GuildID)
+		slash_commands.RespondWithGenericErrorMessage(C, s, i, resp)
+
+		return
+	}
+
+	file, err := export.ToFile(exportOptions.Zip)
+	if nil != err {
+		C.Logger().Err(err, "Failed to encode the data export of guild \"%s\"!", i.GuildID)
+		slash_commands.RespondWithGenericErrorMessage(C, s, i, resp)
+
+		return
+	}
+
+	if len(file.Data) > maxAttachmentSize {
+		slash_commands.RespondWithSimpleEmbedMessage(C, s, i, resp,
+			api.Translate(i, exportTooLargeResponseName),
+			api.Translate(i, exportTooLargeResponseValue))
+
+		return
+	}
+
+	resp.Embeds[0].Fields = []*discordgo.MessageEmbedField{
+		{
+			Name:  api.Translate(i, exportSuccessResponseName),
+			Value: api.Translate(i, exportSuccessResponseValue),
+		},
+	}
+	resp.Files = []*discordgo.File{
+		{
+			Name:        file.Name,
+			ContentType: file.ContentType,
+			Reader:      bytes.NewReader(file.Data),
+		},
+	}
+	slash_commands.Respond(C, s, i, resp)
+
+	dgoGuild, err := s.Guild(i.GuildID)
+	if nil != err {
+		C.Logger().Err(err, "Failed to get guild with id \"%s\" to create "+
+			"bot audit log when exporting the guild data!",
+			i.GuildID)
+
+		dgoGuild = &discordgo.Guild{ID: i.GuildID}
+	}
+
+	C.BotAuditLogger().Log(dgoGuild, i.Member.User, exportAuditLogMessage, true)
+}
@@ -0,0 +1,54 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package locale
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
+)
+
+// The keys of the messages used by the locale reset sub-command.
+const (
+	localeResetSuccessResponseName  = "bot_core.locale.reset.success.name"
+	localeResetSuccessResponseValue = "bot_core.locale.reset.success.value"
+	localeResetAuditLogMessage      = "The language of the bot has been reset to the language of each user!"
+)
+
+// handleLocaleReset removes the locale of the guild,
+// so that the bot responds in the locale of each user.
+func handleLocaleReset(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	_ *discordgo.ApplicationCommandInteractionDataOption,
+) {
+	resp := slash_commands.GenerateEphemeralInteractionResponseTemplate(
+		api.Translate(i, localeCommandResponseHeader),
+		"")
+
+	if !updateGuildLocale(s, i, resp, "") {
+		return
+	}
+
+	slash_commands.RespondWithSimpleEmbedMessage(C, s, i, resp,
+		api.Translate(i, localeResetSuccessResponseName),
+		api.Translate(i, localeResetSuccessResponseValue))
+
+	logToBotAuditLog(s, i, localeResetAuditLogMessage)
+}
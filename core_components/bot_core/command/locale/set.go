@@ -0,0 +1,128 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package locale
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"github.com/lazybytez/jojo-discord-bot/api/i18n"
+	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
+)
+
+// maxLocaleChoices is the maximum number of choices Discord allows for an option.
+const maxLocaleChoices = 25
+
+// The keys of the messages used by the locale set sub-command.
+const (
+	localeSetSuccessResponseName      = "bot_core.locale.set.success.name"
+	localeSetSuccessResponseValue     = "bot_core.locale.set.success.value"
+	localeUnsupportedResponseName     = "bot_core.locale.unsupported.name"
+	localeUnsupportedResponseValue    = "bot_core.locale.unsupported.value"
+	localeSetAuditLogMessage          = "The language of the bot has been set to `%s`!"
+	localeFailedToUpdateGuildLogEntry = "Failed to update the locale of guild \"%s\"!"
+)
+
+// SetOptions holds the options of the locale set sub-command.
+type SetOptions struct {
+	Locale string `option:"locale" description:"The language the bot should respond in" required:"true"`
+}
+
+// GetSetOptions returns the options of the locale set sub-command.
+// All locales that have a message catalog are offered as choices.
+func GetSetOptions() []*discordgo.ApplicationCommandOption {
+	options := slash_commands.MustGenerateOptions(SetOptions{})
+
+	for _, locale := range i18n.Locales() {
+		if len(options[0].Choices) >= maxLocaleChoices {
+			break
+		}
+
+		options[0].Choices = append(options[0].Choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  fmt.Sprintf("%s (%s)", discordgo.Locales[locale], locale),
+			Value: string(locale),
+		})
+	}
+
+	return options
+}
+
+// handleLocaleSet sets the locale the bot uses to respond on the guild.
+func handleLocaleSet(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	option *discordgo.ApplicationCommandInteractionDataOption,
+) {
+	resp := slash_commands.GenerateEphemeralInteractionResponseTemplate(
+		api.Translate(i, localeCommandResponseHeader),
+		"")
+
+	options := SetOptions{}
+	err := slash_commands.BindOptions(s, i, option.Options, &options)
+	if nil != err {
+		slash_commands.RespondWithOptionBindingError(C, s, i, err)
+
+		return
+	}
+
+	if !i18n.IsSupportedLocale(discordgo.Locale(options.Locale)) {
+		slash_commands.RespondWithSimpleEmbedMessage(C, s, i, resp,
+			api.Translate(i, localeUnsupportedResponseName),
+			api.Translate(i, localeUnsupportedResponseValue, options.Locale))
+
+		return
+	}
+
+	if !updateGuildLocale(s, i, resp, options.Locale) {
+		return
+	}
+
+	slash_commands.RespondWithSimpleEmbedMessage(C, s, i, resp,
+		api.Translate(i, localeSetSuccessResponseName),
+		api.Translate(i, localeSetSuccessResponseValue, options.Locale))
+
+	logToBotAuditLog(s, i, fmt.Sprintf(localeSetAuditLogMessage, options.Locale))
+}
+
+// updateGuildLocale stores the passed locale for the guild of the interaction.
+// When the locale cannot be stored, a generic error is sent and false is returned.
+func updateGuildLocale(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	resp *discordgo.InteractionResponseData,
+	locale string,
+) bool {
+	guild, err := C.EntityManager().Guilds().Get(i.GuildID)
+	if nil != err {
+		slash_commands.RespondWithGenericErrorMessage(C, s, i, resp)
+
+		return false
+	}
+
+	err = C.EntityManager().Guilds().Update(guild, entities.ColumnLocale, locale)
+	if nil != err {
+		C.Logger().Err(err, localeFailedToUpdateGuildLogEntry, i.GuildID)
+		slash_commands.RespondWithGenericErrorMessage(C, s, i, resp)
+
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,84 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package locale
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
+)
+
+var C *api.Component
+
+// The keys of the messages shared by the locale sub-commands.
+const (
+	localeCommandResponseHeader = "bot_core.locale.header"
+)
+
+const localeFailedToLoadGuildLogMessage = "Failed to get guild with id \"%s\" to create bot audit log entry!"
+
+// HandleLocaleSubCommand handles the execution of the
+// "locale" subcommand.
+//
+// The command allows to configure the locale the bot uses to respond on the guild.
+func HandleLocaleSubCommand(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	option *discordgo.ApplicationCommandInteractionDataOption,
+) {
+	if nil == i.Member {
+		slash_commands.RespondWithCommandIsGuildOnly(C, s, i, "locale")
+
+		return
+	}
+
+	subCommands := map[string]func(
+		s *discordgo.Session,
+		i *discordgo.InteractionCreate,
+		option *discordgo.ApplicationCommandInteractionDataOption,
+	){
+		"set":   handleLocaleSet,
+		"reset": handleLocaleReset,
+	}
+
+	success := api.ProcessSubCommands(
+		s,
+		i,
+		option,
+		subCommands)
+
+	if !success {
+		slash_commands.Respond(C, s, i, &discordgo.InteractionResponseData{
+			Content: "The executed (sub)command is invalid or does not exist!",
+		})
+	}
+}
+
+// logToBotAuditLog writes the passed message to the bot audit log of the guild
+// the passed interaction has been created on.
+func logToBotAuditLog(s *discordgo.Session, i *discordgo.InteractionCreate, message string) {
+	dgoGuild, err := s.Guild(i.GuildID)
+	if nil != err {
+		C.Logger().Err(err, localeFailedToLoadGuildLogMessage, i.GuildID)
+
+		return
+	}
+
+	C.BotAuditLogger().Log(dgoGuild, i.Member.User, message, false)
+}
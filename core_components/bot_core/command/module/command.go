@@ -0,0 +1,235 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package module
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
+)
+
+const (
+	commandResponseTitle           = "Module Commands"
+	commandErrorResponseName       = ":x: Error"
+	commandMissingResponse         = "The module `%s` has no command `/%s`!"
+	commandUnchangedResponseName   = ":x: Nothing to do here!"
+	commandUnchangedResponse       = "The command `/%s` is already %s!"
+	commandSuccessResponseName     = ":white_check_mark: Done!"
+	commandSuccessResponse         = "The command `/%s` has been %s!"
+	commandSaveFailedLogMessage    = "Could not save guild command status for command \"%v\" on guild \"%v\""
+	commandAuditLogMessage         = "The command `/%s` of the component `%s` has been %s"
+	commandFailedToLoadGuildLogMsg = "Failed to get guild with id \"%s\" to create bot audit log when toggling a command on guild!"
+)
+
+// CommandOptions holds the options of the module command sub-command.
+type CommandOptions struct {
+	Module  string `option:"module" description:"The name of the module the command belongs to" required:"true" autocomplete:"true"`
+	Command string `option:"command" description:"The name of the command" required:"true" autocomplete:"true"`
+	Enabled bool   `option:"enabled" description:"Whether the command should be enabled on the guild" required:"true"`
+}
+
+// handleModuleCommand enables or disables a single command of the targeted module.
+//
+// Disabled commands are removed from the guild on the next command sync,
+// which is triggered right away.
+func handleModuleCommand(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	option *discordgo.ApplicationCommandInteractionDataOption,
+) {
+	resp := slash_commands.GenerateEphemeralInteractionResponseTemplate(commandResponseTitle, "")
+
+	options := CommandOptions{}
+	err := slash_commands.BindOptions(s, i, option.Options, &options)
+	if nil != err {
+		slash_commands.RespondWithOptionBindingError(C, s, i, err)
+
+		return
+	}
+
+	regComp := findComponentByCode(entities.ComponentCode(options.Module))
+	if nil == regComp || regComp.IsCoreComponent() {
+		respondWithMissingComponent(s, i, resp, options.Module)
+
+		return
+	}
+
+	if !isCommandOfComponent(regComp.Code, options.Command) {
+		slash_commands.RespondWithSimpleEmbedMessage(C, s, i, resp,
+			commandErrorResponseName,
+			fmt.Sprintf(commandMissingResponse, regComp.Name, options.Command))
+
+		return
+	}
+
+	guild, err := C.EntityManager().Guilds().Get(i.GuildID)
+	if nil != err {
+		respondWithMissingComponent(s, i, resp, regComp.Name)
+
+		return
+	}
+
+	action := UserAction(UserActionDisable)
+	if options.Enabled {
+		action = UserActionEnable
+	}
+
+	changed, err := setCommandStatusForGuild(guild, regComp, options.Command, options.Enabled)
+	if nil != err {
+		C.Logger().Err(err, commandSaveFailedLogMessage, options.Command, i.GuildID)
+		slash_commands.RespondWithGenericErrorMessage(C, s, i, resp)
+
+		return
+	}
+
+	if !changed {
+		slash_commands.RespondWithSimpleEmbedMessage(C, s, i, resp,
+			commandUnchangedResponseName,
+			fmt.Sprintf(commandUnchangedResponse, options.Command, action))
+
+		return
+	}
+
+	respondWithTogglingComponent(s, i, resp, regComp.Name, action)
+	C.SlashCommandManager().SyncApplicationComponentCommands(s, i.GuildID)
+
+	resp.Embeds[0].Fields = []*discordgo.MessageEmbedField{
+		{
+			Name:  commandSuccessResponseName,
+			Value: fmt.Sprintf(commandSuccessResponse, options.Command, action),
+		},
+	}
+	slash_commands.EditResponse(C, s, i, &discordgo.WebhookEdit{
+		Embeds: &resp.Embeds,
+	})
+
+	dgoGuild, err := s.Guild(i.GuildID)
+	if nil != err {
+		C.Logger().Err(err, commandFailedToLoadGuildLogMsg, i.GuildID)
+
+		return
+	}
+
+	user := i.User
+	if nil == user {
+		user = i.Member.User
+	}
+
+	C.BotAuditLogger().Log(
+		dgoGuild,
+		user,
+		fmt.Sprintf(commandAuditLogMessage, options.Command, regComp.Name, action),
+		true)
+}
+
+// isCommandOfComponent checks whether the component with the passed code
+// owns a chat command with the passed name.
+//
+// Only chat commands can be toggled, as their name is the key the
+// status of the command is stored with.
+func isCommandOfComponent(code entities.ComponentCode, name string) bool {
+	for _, command := range C.SlashCommandManager().GetCommandsForComponent(code) {
+		if discordgo.ChatApplicationCommand != command.Cmd.Type && 0 != command.Cmd.Type {
+			continue
+		}
+
+		if command.Cmd.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setCommandStatusForGuild stores the passed status of the command
+// for the specified guild.
+//
+// Returns true if the status has been changed, or an error
+// if the status could not be loaded or saved.
+func setCommandStatusForGuild(
+	guild *entities.Guild,
+	regComp *entities.RegisteredComponent,
+	commandName string,
+	enabled bool,
+) (bool, error) {
+	em := C.EntityManager().GuildCommandStatus()
+
+	statuses, err := em.GetByGuildAndComponent(guild.ID, regComp.ID)
+	if nil != err {
+		return false, err
+	}
+
+	for idx := range statuses {
+		status := statuses[idx]
+		if status.Command != commandName {
+			continue
+		}
+
+		if status.Enabled == enabled {
+			return false, nil
+		}
+
+		status.Enabled = enabled
+		err = em.Save(&status)
+		if nil != err {
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	// Commands without status are enabled
+	if enabled {
+		return false, nil
+	}
+
+	err = em.Create(&entities.GuildCommandStatus{
+		GuildID:     guild.ID,
+		ComponentID: regComp.ID,
+		Command:     commandName,
+		Enabled:     enabled,
+	})
+	if nil != err {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// getCommandStatusDisplay returns a list of all commands of the passed component
+// together with their status on the passed guild.
+func getCommandStatusDisplay(code entities.ComponentCode, guildId string) string {
+	display := ""
+	for _, command := range C.SlashCommandManager().GetCommandsForComponent(code) {
+		status := entities.GuildComponentStatusEnabledDisplay
+		if !api.IsCommandEnabledOnGuild(command, guildId) {
+			status = entities.GuildComponentStatusDisabledDisplay
+		}
+
+		display += fmt.Sprintf("%s `/%s`\n", status, command.Cmd.Name)
+	}
+
+	if "" == display {
+		return "This module has no commands."
+	}
+
+	return display
+}
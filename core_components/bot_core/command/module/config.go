@@ -0,0 +1,222 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package module
+
+import (
+	"errors"
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
+)
+
+const (
+	configResponseTitle         = "Module Configuration"
+	configErrorResponseName     = ":x: Error"
+	configMissingOptionResponse = "The module `%s` has no configuration option `%v`!"
+	configInvalidValueResponse  = "The value `%s` is invalid for the option `%s`: %s"
+	configSaveFailedResponse    = "The option `%s` could not be saved, please try again later!"
+)
+
+// handleModuleConfig shows or changes the value of a configuration option
+// of the targeted module.
+//
+// When no value is passed, the current value of the option is shown.
+// When reset is passed, the configured value is removed and the default is used again.
+func handleModuleConfig(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	option *discordgo.ApplicationCommandInteractionDataOption,
+) {
+	resp := slash_commands.GenerateEphemeralInteractionResponseTemplate(configResponseTitle, "")
+
+	regComp := findComponent(option)
+	if nil == regComp || regComp.IsCoreComponent() {
+		respondWithMissingComponent(s, i, resp, option.Options[0].Value)
+
+		return
+	}
+
+	comp, ok := api.FindComponentByCode(regComp.Code)
+	if !ok {
+		respondWithMissingComponent(s, i, resp, regComp.Name)
+
+		return
+	}
+
+	options := getConfigCommandOptions(option)
+	key, _ := options["key"].(string)
+
+	configOption, err := comp.ComponentConfig().GetOption(key)
+	if nil != err {
+		slash_commands.RespondWithSimpleEmbedMessage(C,
+			s,
+			i,
+			resp,
+			configErrorResponseName,
+			fmt.Sprintf(configMissingOptionResponse, regComp.Name, options["key"]))
+
+		return
+	}
+
+	reset, _ := options["reset"].(bool)
+	rawValue, hasValue := options["value"].(string)
+
+	switch {
+	case reset:
+		err = comp.ComponentConfig().Reset(i.GuildID, configOption.Key)
+	case hasValue:
+		err = comp.ComponentConfig().Set(i.GuildID, configOption.Key, rawValue)
+
+		var valueErr *api.ConfigValueError
+		if errors.As(err, &valueErr) {
+			slash_commands.RespondWithSimpleEmbedMessage(C,
+				s,
+				i,
+				resp,
+				configErrorResponseName,
+				fmt.Sprintf(configInvalidValueResponse, rawValue, configOption.Key, valueErr.Err.Error()))
+
+			return
+		}
+	}
+
+	if nil != err {
+		C.Logger().Err(err, "Failed to save configuration option \"%s\" of component \"%s\" on guild \"%s\"!",
+			configOption.Key,
+			regComp.Code,
+			i.GuildID)
+
+		slash_commands.RespondWithSimpleEmbedMessage(C,
+			s,
+			i,
+			resp,
+			configErrorResponseName,
+			fmt.Sprintf(configSaveFailedResponse, configOption.Key))
+
+		return
+	}
+
+	value, err := comp.ComponentConfig().Get(i.GuildID, configOption.Key)
+	if nil != err {
+		C.Logger().Err(err, "Failed to get configuration option \"%s\" of component \"%s\" on guild \"%s\"!",
+			configOption.Key,
+			regComp.Code,
+			i.GuildID)
+	}
+
+	populateConfigOptionEmbedFields(resp, regComp, configOption, value)
+	slash_commands.Respond(C, s, i, resp)
+
+	if reset || hasValue {
+		logConfigChange(s, i, regComp, configOption, value, reset)
+	}
+}
+
+// getConfigCommandOptions maps the options of the config sub-command
+// by their name, as only the module option is required to be first.
+func getConfigCommandOptions(option *discordgo.ApplicationCommandInteractionDataOption) map[string]interface{} {
+	options := make(map[string]interface{})
+
+	for _, subOption := range option.Options {
+		options[subOption.Name] = subOption.Value
+	}
+
+	return options
+}
+
+// populateConfigOptionEmbedFields fills the interaction response template embed
+// with the information about the passed configuration option.
+func populateConfigOptionEmbedFields(
+	resp *discordgo.InteractionResponseData,
+	comp *entities.RegisteredComponent,
+	configOption *api.ConfigOption,
+	value interface{},
+) {
+	resp.Embeds[0].Fields = []*discordgo.MessageEmbedField{
+		{
+			Name:   "Module",
+			Value:  comp.Name,
+			Inline: true,
+		},
+		{
+			Name:   "Option",
+			Value:  configOption.Key,
+			Inline: true,
+		},
+		{
+			Name:   "Description",
+			Value:  configOption.Description,
+			Inline: false,
+		},
+		{
+			Name:   "Value",
+			Value:  fmt.Sprintf("`%s`", configOption.Format(value)),
+			Inline: true,
+		},
+		{
+			Name:   "Default",
+			Value:  fmt.Sprintf("`%s`", configOption.Format(configOption.Default)),
+			Inline: true,
+		},
+	}
+}
+
+// logConfigChange writes the changed configuration option to the bot audit log.
+// When the option has been reset, the default value is logged as new value.
+func logConfigChange(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	comp *entities.RegisteredComponent,
+	configOption *api.ConfigOption,
+	value interface{},
+	reset bool,
+) {
+	dgoGuild, err := s.Guild(i.GuildID)
+	if nil != err {
+		C.Logger().Err(err, "Failed to get guild with id \"%s\" to create "+
+			"bot audit log when configuring a module on guild!",
+			i.GuildID)
+
+		return
+	}
+
+	user := i.User
+	if nil == user {
+		user = i.Member.User
+	}
+
+	message := fmt.Sprintf("The option `%s` of component `%s` has been set to `%s`",
+		configOption.Key,
+		comp.Name,
+		configOption.Format(value))
+	if reset {
+		message = fmt.Sprintf("The option `%s` of component `%s` has been reset to its default `%s`",
+			configOption.Key,
+			comp.Name,
+			configOption.Format(value))
+	}
+
+	C.BotAuditLogger().Log(
+		dgoGuild,
+		user,
+		message,
+		true)
+}
@@ -21,6 +21,7 @@ package module
 import (
 	"fmt"
 	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
 	"github.com/lazybytez/jojo-discord-bot/api/entities"
 	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
 )
@@ -49,18 +50,18 @@ func handleModuleDisable(
 		return
 	}
 
-	if isModuleToggleRateLimited(guild) {
-		respondWithRateLimited(s, i, resp)
+	if allowed, retryAfter := moduleToggleRateLimit.Take(i); !allowed {
+		respondWithRateLimited(s, i, resp, retryAfter)
 
 		return
 	}
-	increaseRateLimitCount(guild)
 
 	if !disableComponentForGuild(guild, regComp) {
 		respondWithAlreadyDisabled(s, i, resp, regComp.Name)
 
 		return
 	}
+	disabledDependents := disableDependentComponentsForGuild(guild, regComp)
 
 	respondWithTogglingComponent(
 		s,
@@ -69,7 +70,8 @@ func handleModuleDisable(
 		regComp.Name,
 		UserActionDisable)
 	C.SlashCommandManager().SyncApplicationComponentCommands(s, i.GuildID)
-	finishWithModuleDisableSuccessfulEmbedField(s, i, resp, regComp)
+	finishWithModuleDisableSuccessfulEmbedField(s, i, resp, regComp, disabledDependents)
+	publishComponentToggledEvents(i, false, append([]*entities.RegisteredComponent{regComp}, disabledDependents...)...)
 
 	dgoGuild, err := s.Guild(i.GuildID)
 	if nil != err {
@@ -90,6 +92,48 @@ func handleModuleDisable(
 		user,
 		fmt.Sprintf("The component `%s` has been disabled", regComp.Name),
 		true)
+
+	for _, dependent := range disabledDependents {
+		C.BotAuditLogger().Log(
+			dgoGuild,
+			user,
+			fmt.Sprintf("The component `%s` has been disabled, as it depends on `%s`",
+				dependent.Name,
+				regComp.Name),
+			true)
+	}
+}
+
+// disableDependentComponentsForGuild disables all components that depend
+// on the passed component for the passed guild.
+//
+// Returns the components that have been enabled before and are now disabled.
+func disableDependentComponentsForGuild(
+	guild *entities.Guild,
+	regComp *entities.RegisteredComponent,
+) []*entities.RegisteredComponent {
+	disabledDependents := make([]*entities.RegisteredComponent, 0)
+
+	for _, dependent := range api.GetDependentComponents(regComp.Code) {
+		if api.IsCoreComponent(dependent) {
+			C.Logger().Warn("The core component \"%v\" depends on \"%v\" and cannot be disabled!",
+				dependent.Code,
+				regComp.Code)
+
+			continue
+		}
+
+		regDependent, err := C.EntityManager().RegisteredComponent().Get(dependent.Code)
+		if nil != err {
+			continue
+		}
+
+		if disableComponentForGuild(guild, regDependent) {
+			disabledDependents = append(disabledDependents, regDependent)
+		}
+	}
+
+	return disabledDependents
 }
 
 func disableComponentForGuild(
@@ -128,6 +172,7 @@ func finishWithModuleDisableSuccessfulEmbedField(
 	i *discordgo.InteractionCreate,
 	resp *discordgo.InteractionResponseData,
 	comp *entities.RegisteredComponent,
+	disabledDependents []*entities.RegisteredComponent,
 ) {
 	resp.Embeds[0].Fields = []*discordgo.MessageEmbedField{
 		{
@@ -142,6 +187,14 @@ func finishWithModuleDisableSuccessfulEmbedField(
 		},
 	}
 
+	if len(disabledDependents) > 0 {
+		resp.Embeds[0].Fields = append(resp.Embeds[0].Fields, &discordgo.MessageEmbedField{
+			Name:   "Dependent Modules",
+			Value:  ":warning: - The following modules depend on the module and have been disabled too: " + joinComponentNames(disabledDependents),
+			Inline: false,
+		})
+	}
+
 	slash_commands.EditResponse(C, s, i, &discordgo.WebhookEdit{
 		Embeds: &resp.Embeds,
 	})
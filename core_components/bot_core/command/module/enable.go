@@ -21,6 +21,7 @@ package module
 import (
 	"fmt"
 	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
 	"github.com/lazybytez/jojo-discord-bot/api/entities"
 	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
 )
@@ -47,19 +48,27 @@ func handleModuleEnable(
 		return
 	}
 
-	if isModuleToggleRateLimited(guild) {
-		respondWithRateLimited(s, i, resp)
+	if allowed, retryAfter := moduleToggleRateLimit.Take(i); !allowed {
+		respondWithRateLimited(s, i, resp, retryAfter)
 
 		return
 	}
-	increaseRateLimitCount(guild)
 
-	if !enableComponentForGuild(s, i, guild, regComp, resp) {
+	// Dependencies are enabled first, as the module is only effectively
+	// enabled when all modules it depends on are enabled too.
+	enabledDependencies := enableComponentDependenciesForGuild(s, i, guild, regComp, resp)
+	enabled := enableComponentForGuild(s, i, guild, regComp, resp)
+	if !enabled && 0 == len(enabledDependencies) {
 		respondWithAlreadyEnabled(s, i, resp, regComp.Name)
 
 		return
 	}
 
+	toggledComponents := enabledDependencies
+	if enabled {
+		toggledComponents = append([]*entities.RegisteredComponent{regComp}, enabledDependencies...)
+	}
+
 	respondWithTogglingComponent(
 		s,
 		i,
@@ -67,7 +76,8 @@ func handleModuleEnable(
 		regComp.Name,
 		UserActionEnable)
 	C.SlashCommandManager().SyncApplicationComponentCommands(s, i.GuildID)
-	finishWithModuleEnableSuccessfulEmbedField(s, i, resp, regComp)
+	finishWithModuleEnableSuccessfulEmbedField(s, i, resp, regComp, enabled, enabledDependencies)
+	publishComponentToggledEvents(i, true, toggledComponents...)
 
 	dgoGuild, err := s.Guild(i.GuildID)
 	if nil != err {
@@ -83,11 +93,54 @@ func handleModuleEnable(
 		user = i.Member.User
 	}
 
-	C.BotAuditLogger().Log(
-		dgoGuild,
-		user,
-		fmt.Sprintf("The component `%s` has been enabled", regComp.Name),
-		true)
+	if enabled {
+		C.BotAuditLogger().Log(
+			dgoGuild,
+			user,
+			fmt.Sprintf("The component `%s` has been enabled", regComp.Name),
+			true)
+	}
+
+	for _, dependency := range enabledDependencies {
+		C.BotAuditLogger().Log(
+			dgoGuild,
+			user,
+			fmt.Sprintf("The component `%s` has been enabled, as `%s` depends on it",
+				dependency.Name,
+				regComp.Name),
+			true)
+	}
+}
+
+// enableComponentDependenciesForGuild enables all components the passed
+// component depends on for the passed guild.
+//
+// Returns the components that have been disabled before and are now enabled.
+func enableComponentDependenciesForGuild(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	guild *entities.Guild,
+	regComp *entities.RegisteredComponent,
+	resp *discordgo.InteractionResponseData,
+) []*entities.RegisteredComponent {
+	enabledDependencies := make([]*entities.RegisteredComponent, 0)
+
+	for _, dependency := range api.GetComponentDependencies(regComp.Code) {
+		if api.IsCoreComponent(dependency) {
+			continue
+		}
+
+		regDependency, err := C.EntityManager().RegisteredComponent().Get(dependency.Code)
+		if nil != err {
+			continue
+		}
+
+		if enableComponentForGuild(s, i, guild, regDependency, resp) {
+			enabledDependencies = append(enabledDependencies, regDependency)
+		}
+	}
+
+	return enabledDependencies
 }
 
 // enableComponentForGuild enables the specified component
@@ -142,12 +195,24 @@ func enableComponentForGuild(
 // finishWithModuleEnableSuccessfulEmbedField updates the previously send
 // processing message with a success message, that indicates
 // that the module could be enabled properly.
+//
+// When the module has already been enabled before, the message tells
+// that only the modules it depends on have been enabled.
 func finishWithModuleEnableSuccessfulEmbedField(
 	s *discordgo.Session,
 	i *discordgo.InteractionCreate,
 	resp *discordgo.InteractionResponseData,
 	comp *entities.RegisteredComponent,
+	enabled bool,
+	enabledDependencies []*entities.RegisteredComponent,
 ) {
+	status := ":white_check_mark: - The module has been enabled!"
+	dependencies := ":warning: - The module depends on the following modules, which have been enabled too: "
+	if !enabled {
+		status = ":white_check_mark: - The module is already enabled, but some of the modules it depends on were disabled!"
+		dependencies = ":warning: - The following modules the module depends on have been enabled: "
+	}
+
 	resp.Embeds[0].Fields = []*discordgo.MessageEmbedField{
 		{
 			Name:   "Module",
@@ -156,11 +221,19 @@ func finishWithModuleEnableSuccessfulEmbedField(
 		},
 		{
 			Name:   "Status",
-			Value:  ":white_check_mark: - The module has been enabled!",
+			Value:  status,
 			Inline: false,
 		},
 	}
 
+	if len(enabledDependencies) > 0 {
+		resp.Embeds[0].Fields = append(resp.Embeds[0].Fields, &discordgo.MessageEmbedField{
+			Name:   "Required Modules",
+			Value:  dependencies + joinComponentNames(enabledDependencies),
+			Inline: false,
+		})
+	}
+
 	slash_commands.EditResponse(C, s, i, &discordgo.WebhookEdit{
 		Embeds: &resp.Embeds,
 	})
@@ -19,10 +19,10 @@
 package module
 
 import (
-	"fmt"
 	"github.com/bwmarrin/discordgo"
-	"github.com/lazybytez/jojo-discord-bot/api/entities"
-	"github.com/lazybytez/jojo-discord-bot/services/cache"
+	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
+	"time"
 )
 
 // ToggleModuleRateLimit is the maximum count how
@@ -30,45 +30,14 @@ import (
 // before the user has to wait for the rate limit to expire.
 const ToggleModuleRateLimit = 10
 
-// getComponentToggleCountCacheKey returns the cache key used to store
-// the count of how often the module toggle commands were used in the last
-// 10 minutes.
-func getComponentToggleCountCacheKey(guildID uint64) string {
-	return fmt.Sprintf("component_toggle_counter_%d", guildID)
-}
-
-// isModuleToggleRateLimited returns whether the current guild is
-// right now rate limited or not.
-func isModuleToggleRateLimited(guild *entities.Guild) bool {
-	cacheKey := getComponentToggleCountCacheKey(guild.GuildID)
-	toggleCount, ok := cache.Get(cacheKey, 0)
-	if ok && toggleCount > ToggleModuleRateLimit {
-		return true
-	}
-
-	return false
-}
-
-// increaseRateLimitCount increases the count of module
-// toggles in the cache by one.
-// The function returns whether increasing the rate limit count
-// worked or not.
-func increaseRateLimitCount(guild *entities.Guild) bool {
-	cacheKey := getComponentToggleCountCacheKey(guild.GuildID)
-	toggleCount, ok := cache.Get(cacheKey, 0)
-	if !ok {
-		return false
-	}
-
-	toggleCount += 1
-	err := cache.Update(cacheKey, toggleCount)
-	if nil != err {
-		C.Logger().Err(err, fmt.Sprintf(
-			"Failed to store incremented module toggle rate limit count in cache for guild %d",
-			guild.GuildID))
-	}
-
-	return true
+// moduleToggleRateLimit limits how often the module toggle
+// commands can be used per guild.
+var moduleToggleRateLimit = &api.RateLimit{
+	Name:     "component_toggle",
+	Strategy: api.RateLimitStrategyFixedWindow,
+	Scope:    api.RateLimitScopeGuild,
+	Limit:    ToggleModuleRateLimit,
+	Window:   10 * time.Minute,
 }
 
 // respondWithRateLimited responds with a message telling the user that the has to wait
@@ -77,20 +46,11 @@ func respondWithRateLimited(
 	s *discordgo.Session,
 	i *discordgo.InteractionCreate,
 	resp *discordgo.InteractionResponseData,
+	retryAfter time.Duration,
 ) {
-	embeds := []*discordgo.MessageEmbedField{
-		{
-			Name: ":x: Slow down my friend!",
-			Value: fmt.Sprintf("The `/jojo module enable` and `/jojo module disable` "+
-				"commands can only be used up to %d times in 10 minutes per guild!",
-				ToggleModuleRateLimit),
-		},
+	resp.Embeds[0].Fields = []*discordgo.MessageEmbedField{
+		api.NewRateLimitedEmbedField(retryAfter),
 	}
 
-	resp.Embeds[0].Fields = embeds
-
-	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: resp,
-	})
+	slash_commands.Respond(C, s, i, resp)
 }
@@ -19,9 +19,8 @@
 package module
 
 import (
-	"fmt"
 	"github.com/bwmarrin/discordgo"
-	"github.com/davecgh/go-spew/spew"
+	"github.com/lazybytez/jojo-discord-bot/api"
 	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
 )
 
@@ -35,6 +34,14 @@ const (
 	UserActionDisable = "disabled"
 )
 
+// The keys of the messages shared by the module sub-commands.
+const (
+	togglingComponentResponseName        = "bot_core.module.processing.name"
+	togglingComponentResponseValuePrefix = "bot_core.module.processing."
+	missingComponentResponseName         = "bot_core.module.missing.name"
+	missingComponentResponseValue        = "bot_core.module.missing.value"
+)
+
 // respondWithTogglingComponent responds with a message
 // telling the user the command is still processing.
 // This is necessary as the module enable/disable process
@@ -48,10 +55,8 @@ func respondWithTogglingComponent(
 ) {
 	embeds := []*discordgo.MessageEmbedField{
 		{
-			Name: ":alarm_clock: Processing...",
-			Value: spew.Sprintf("The module \"%s\" is being %s, please wait...",
-				componentName,
-				action),
+			Name:  api.Translate(i, togglingComponentResponseName),
+			Value: api.Translate(i, togglingComponentResponseValuePrefix+string(action), componentName),
 		},
 	}
 
@@ -70,15 +75,12 @@ func respondWithMissingComponent(
 ) {
 	embeds := []*discordgo.MessageEmbedField{
 		{
-			Name:  ":x: Error",
-			Value: fmt.Sprintf("No module with name \"%v\" could be found!", componentName),
+			Name:  api.Translate(i, missingComponentResponseName),
+			Value: api.Translate(i, missingComponentResponseValue, componentName),
 		},
 	}
 
 	resp.Embeds[0].Fields = embeds
 
-	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: resp,
-	})
+	slash_commands.Respond(C, s, i, resp)
 }
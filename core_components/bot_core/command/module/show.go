@@ -24,7 +24,8 @@ import (
 	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
 )
 
-// handleModuleShow prints out a list of all commands and their status.
+// handleModuleShow prints out information about the module, its status
+// and a list of all of its commands and their status.
 func handleModuleShow(
 	s *discordgo.Session,
 	i *discordgo.InteractionCreate,
@@ -52,6 +53,11 @@ func handleModuleShow(
 	guildSpecificStatusOutput, _ := em.GuildComponentStatus().GetDisplay(guild.ID, regComp.ID)
 
 	populateComponentStatusEmbedFields(resp, regComp, guildSpecificStatusOutput, globalStatusOutput)
+	resp.Embeds[0].Fields = append(resp.Embeds[0].Fields, &discordgo.MessageEmbedField{
+		Name:   "Commands",
+		Value:  getCommandStatusDisplay(regComp.Code, i.GuildID),
+		Inline: false,
+	})
 
 	slash_commands.Respond(C, s, i, resp)
 }
@@ -21,6 +21,7 @@ package module
 import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
 )
 
 var C *api.Component
@@ -41,6 +42,8 @@ func HandleModuleSubCommand(
 		"show":    handleModuleShow,
 		"enable":  handleModuleEnable,
 		"disable": handleModuleDisable,
+		"config":  handleModuleConfig,
+		"command": handleModuleCommand,
 	}
 
 	success := api.ProcessSubCommands(
@@ -51,11 +54,8 @@ func HandleModuleSubCommand(
 
 	if !success {
 		if !success {
-			_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-				Type: discordgo.InteractionResponseChannelMessageWithSource,
-				Data: &discordgo.InteractionResponseData{
-					Content: "The executed (sub)command is invalid or does not exist!",
-				},
+			slash_commands.Respond(C, s, i, &discordgo.InteractionResponseData{
+				Content: "The executed (sub)command is invalid or does not exist!",
 			})
 		}
 	}
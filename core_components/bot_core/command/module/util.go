@@ -19,8 +19,11 @@
 package module
 
 import (
+	"fmt"
 	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
 	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"strings"
 )
 
 // findComponent tries to find a specific component by its code.
@@ -34,6 +37,11 @@ func findComponent(option *discordgo.ApplicationCommandInteractionDataOption) *e
 		return nil
 	}
 
+	return findComponentByCode(componentCode)
+}
+
+// findComponentByCode tries to find a specific component by the passed code.
+func findComponentByCode(componentCode entities.ComponentCode) *entities.RegisteredComponent {
 	for _, c := range C.EntityManager().RegisteredComponent().GetAvailable() {
 		if c.Code == componentCode {
 			return c
@@ -42,3 +50,36 @@ func findComponent(option *discordgo.ApplicationCommandInteractionDataOption) *e
 
 	return nil
 }
+
+// joinComponentNames creates a comma separated list of the names
+// of the passed components.
+func joinComponentNames(components []*entities.RegisteredComponent) string {
+	names := make([]string, len(components))
+	for key, comp := range components {
+		names[key] = fmt.Sprintf("`%s`", comp.Name)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// publishComponentToggledEvents publishes an api.ComponentToggledEvent
+// for each of the passed components, that have been toggled through the passed interaction.
+func publishComponentToggledEvents(
+	i *discordgo.InteractionCreate,
+	enabled bool,
+	components ...*entities.RegisteredComponent,
+) {
+	user := i.User
+	if nil == user {
+		user = i.Member.User
+	}
+
+	for _, comp := range components {
+		C.EventBus().Publish(&api.ComponentToggledEvent{
+			GuildId:   i.GuildID,
+			Component: comp.Code,
+			Enabled:   enabled,
+			UserId:    user.ID,
+		})
+	}
+}
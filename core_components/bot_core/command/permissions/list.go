@@ -0,0 +1,109 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package permissions
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
+	"sort"
+	"strings"
+)
+
+const (
+	permissionsListNoRulesResponseName  = ":information_source: No rules"
+	permissionsListNoRulesResponseValue = "There are no permission rules configured. " +
+		"Commands can be used by every member with the required permissions."
+	permissionsListCommandFieldName = "/%s"
+)
+
+// handlePermissionsList lists the permission overrides of the guild.
+// When a command is passed, only the overrides of the command are listed.
+func handlePermissionsList(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	option *discordgo.ApplicationCommandInteractionDataOption,
+) {
+	resp := slash_commands.GenerateEphemeralInteractionResponseTemplate(permissionsCommandResponseHeader, "")
+
+	options := ListOptions{}
+	err := slash_commands.BindOptions(s, i, option.Options, &options)
+	if nil != err {
+		slash_commands.RespondWithOptionBindingError(C, s, i, err)
+
+		return
+	}
+
+	guild, err := C.EntityManager().Guilds().Get(i.GuildID)
+	if nil != err {
+		slash_commands.RespondWithGenericErrorMessage(C, s, i, resp)
+
+		return
+	}
+
+	var overrides []entities.CommandPermissionOverride
+	if "" == options.Command {
+		overrides, err = C.EntityManager().CommandPermissionOverride().GetByGuild(guild.ID)
+	} else {
+		overrides, err = C.EntityManager().CommandPermissionOverride().GetByGuildAndCommand(guild.ID, options.Command)
+	}
+
+	if nil != err {
+		slash_commands.RespondWithGenericErrorMessage(C, s, i, resp)
+
+		return
+	}
+
+	if 0 == len(overrides) {
+		slash_commands.RespondWithSimpleEmbedMessage(C, s, i, resp,
+			permissionsListNoRulesResponseName,
+			permissionsListNoRulesResponseValue)
+
+		return
+	}
+
+	resp.Embeds[0].Fields = buildRuleEmbedFields(overrides)
+	slash_commands.Respond(C, s, i, resp)
+}
+
+// buildRuleEmbedFields creates one embed field per command
+// that lists the rules of the command.
+func buildRuleEmbedFields(overrides []entities.CommandPermissionOverride) []*discordgo.MessageEmbedField {
+	rulesByCommand := make(map[string][]string)
+	for _, override := range overrides {
+		rulesByCommand[override.Command] = append(rulesByCommand[override.Command], formatRule(override))
+	}
+
+	commands := make([]string, 0, len(rulesByCommand))
+	for command := range rulesByCommand {
+		commands = append(commands, command)
+	}
+	sort.Strings(commands)
+
+	fields := make([]*discordgo.MessageEmbedField, 0, len(commands))
+	for _, command := range commands {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf(permissionsListCommandFieldName, command),
+			Value: strings.Join(rulesByCommand[command], "\n"),
+		})
+	}
+
+	return fields
+}
@@ -0,0 +1,121 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package permissions
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
+	"strconv"
+)
+
+const (
+	permissionsResetNoRulesResponseName     = ":x: Nothing to do here!"
+	permissionsResetNoRulesResponseValue    = "There are no matching rules for the command `/%s`!"
+	permissionsResetSuccessResponseName     = ":white_check_mark: Done!"
+	permissionsResetSuccessResponseValue    = "Removed %d rule(s) of the command `/%s`!"
+	permissionsResetAuditLogMessage         = "The rule of the command `/%s` for %s has been removed!"
+	permissionsFailedToDeleteOverrideLogMsg = "Failed to delete the permission override of command \"%s\" on guild \"%s\"!"
+)
+
+// handlePermissionsReset removes the permission override for the target selected in the options.
+// When no target is selected, all overrides of the command are removed.
+func handlePermissionsReset(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	option *discordgo.ApplicationCommandInteractionDataOption,
+) {
+	resp := slash_commands.GenerateEphemeralInteractionResponseTemplate(permissionsCommandResponseHeader, "")
+
+	options := OverrideOptions{}
+	err := slash_commands.BindOptions(s, i, option.Options, &options)
+	if nil != err {
+		slash_commands.RespondWithOptionBindingError(C, s, i, err)
+
+		return
+	}
+
+	targetType, targetId, targetCount := options.getTarget()
+	if targetCount > 1 {
+		slash_commands.RespondWithSimpleEmbedMessage(C, s, i, resp,
+			permissionsInvalidTargetResponseName,
+			permissionsInvalidTargetResponseValue)
+
+		return
+	}
+
+	guild, err := C.EntityManager().Guilds().Get(i.GuildID)
+	if nil != err {
+		slash_commands.RespondWithGenericErrorMessage(C, s, i, resp)
+
+		return
+	}
+
+	em := C.EntityManager().CommandPermissionOverride()
+	overrides, err := em.GetByGuildAndCommand(guild.ID, options.Command)
+	if nil != err {
+		slash_commands.RespondWithGenericErrorMessage(C, s, i, resp)
+
+		return
+	}
+
+	overridesToDelete := overrides
+	if 1 == targetCount {
+		targetIdInt, _ := strconv.ParseUint(targetId, 10, 64)
+		overridesToDelete = nil
+		if override, ok := findOverride(overrides, targetType, targetIdInt); ok {
+			overridesToDelete = []entities.CommandPermissionOverride{*override}
+		}
+	}
+
+	if 0 == len(overridesToDelete) {
+		slash_commands.RespondWithSimpleEmbedMessage(C, s, i, resp,
+			permissionsResetNoRulesResponseName,
+			fmt.Sprintf(permissionsResetNoRulesResponseValue, options.Command))
+
+		return
+	}
+
+	deleted := 0
+	for idx := range overridesToDelete {
+		override := overridesToDelete[idx]
+		err = em.Delete(&override)
+		if nil != err {
+			C.Logger().Err(err, permissionsFailedToDeleteOverrideLogMsg, options.Command, i.GuildID)
+
+			continue
+		}
+		deleted++
+
+		logToBotAuditLog(s, i, fmt.Sprintf(permissionsResetAuditLogMessage,
+			options.Command,
+			formatTarget(override)))
+	}
+
+	if 0 == deleted {
+		slash_commands.RespondWithGenericErrorMessage(C, s, i, resp)
+
+		return
+	}
+
+	slash_commands.RespondWithSimpleEmbedMessage(C, s, i, resp,
+		permissionsResetSuccessResponseName,
+		fmt.Sprintf(permissionsResetSuccessResponseValue, deleted, options.Command))
+}
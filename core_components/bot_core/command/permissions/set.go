@@ -0,0 +1,187 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package permissions
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
+	"strconv"
+)
+
+const (
+	permissionsCommandResponseHeader         = "Command Permissions"
+	permissionsUnknownCommandResponseName    = ":x: Unknown command!"
+	permissionsUnknownCommandResponseValue   = "There is no command with the name `/%s`!"
+	permissionsInvalidTargetResponseName     = ":x: Invalid target!"
+	permissionsInvalidTargetResponseValue    = "Select exactly one role, user or channel the rule should apply to!"
+	permissionsUnchangedResponseName         = ":x: Nothing to do here!"
+	permissionsUnchangedResponseValue        = "The command `/%s` is already %s for %s!"
+	permissionsSetSuccessResponseName        = ":white_check_mark: Done!"
+	permissionsSetSuccessResponseValue       = "The command `/%s` is now %s for %s!"
+	permissionsSetAuditLogMessage            = "The command `/%s` has been %s for %s!"
+	permissionsRuleAllowedName               = "allowed"
+	permissionsRuleDeniedName                = "denied"
+	permissionsFailedToLoadGuildLogMessage   = "Failed to get guild with id \"%s\" to create bot audit log when changing command permissions on guild!"
+	permissionsFailedToSaveOverrideLogFormat = "Failed to save the permission override of command \"%s\" on guild \"%s\"!"
+)
+
+// handlePermissionsAllow allows the usage of a command for a role, a user or in a channel.
+func handlePermissionsAllow(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	option *discordgo.ApplicationCommandInteractionDataOption,
+) {
+	handlePermissionsSet(s, i, option, true)
+}
+
+// handlePermissionsDeny denies the usage of a command for a role, a user or in a channel.
+func handlePermissionsDeny(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	option *discordgo.ApplicationCommandInteractionDataOption,
+) {
+	handlePermissionsSet(s, i, option, false)
+}
+
+// handlePermissionsSet creates or updates the permission override
+// for the target selected in the options.
+func handlePermissionsSet(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	option *discordgo.ApplicationCommandInteractionDataOption,
+	allow bool,
+) {
+	resp := slash_commands.GenerateEphemeralInteractionResponseTemplate(permissionsCommandResponseHeader, "")
+
+	options := OverrideOptions{}
+	err := slash_commands.BindOptions(s, i, option.Options, &options)
+	if nil != err {
+		slash_commands.RespondWithOptionBindingError(C, s, i, err)
+
+		return
+	}
+
+	if !isChatCommandRegistered(options.Command) {
+		slash_commands.RespondWithSimpleEmbedMessage(C, s, i, resp,
+			permissionsUnknownCommandResponseName,
+			fmt.Sprintf(permissionsUnknownCommandResponseValue, options.Command))
+
+		return
+	}
+
+	targetType, targetId, targetCount := options.getTarget()
+	if 1 != targetCount {
+		slash_commands.RespondWithSimpleEmbedMessage(C, s, i, resp,
+			permissionsInvalidTargetResponseName,
+			permissionsInvalidTargetResponseValue)
+
+		return
+	}
+
+	targetIdInt, err := strconv.ParseUint(targetId, 10, 64)
+	if nil != err {
+		slash_commands.RespondWithGenericErrorMessage(C, s, i, resp)
+
+		return
+	}
+
+	guild, err := C.EntityManager().Guilds().Get(i.GuildID)
+	if nil != err {
+		slash_commands.RespondWithGenericErrorMessage(C, s, i, resp)
+
+		return
+	}
+
+	em := C.EntityManager().CommandPermissionOverride()
+	overrides, err := em.GetByGuildAndCommand(guild.ID, options.Command)
+	if nil != err {
+		slash_commands.RespondWithGenericErrorMessage(C, s, i, resp)
+
+		return
+	}
+
+	override, ok := findOverride(overrides, targetType, targetIdInt)
+	if ok && override.Allow == allow {
+		slash_commands.RespondWithSimpleEmbedMessage(C, s, i, resp,
+			permissionsUnchangedResponseName,
+			fmt.Sprintf(permissionsUnchangedResponseValue,
+				options.Command,
+				getRuleName(allow),
+				formatTarget(*override)))
+
+		return
+	}
+
+	if ok {
+		override.Allow = allow
+		err = em.Save(override)
+	} else {
+		override = &entities.CommandPermissionOverride{
+			GuildID:    guild.ID,
+			Command:    options.Command,
+			TargetType: targetType,
+			TargetID:   targetIdInt,
+			Allow:      allow,
+		}
+		err = em.Create(override)
+	}
+
+	if nil != err {
+		C.Logger().Err(err, permissionsFailedToSaveOverrideLogFormat, options.Command, i.GuildID)
+		slash_commands.RespondWithGenericErrorMessage(C, s, i, resp)
+
+		return
+	}
+
+	slash_commands.RespondWithSimpleEmbedMessage(C, s, i, resp,
+		permissionsSetSuccessResponseName,
+		fmt.Sprintf(permissionsSetSuccessResponseValue,
+			options.Command,
+			getRuleName(allow),
+			formatTarget(*override)))
+
+	logToBotAuditLog(s, i, fmt.Sprintf(permissionsSetAuditLogMessage,
+		options.Command,
+		getRuleName(allow),
+		formatTarget(*override)))
+}
+
+// getRuleName returns the human-readable name of an allowing or denying rule.
+func getRuleName(allow bool) string {
+	if allow {
+		return permissionsRuleAllowedName
+	}
+
+	return permissionsRuleDeniedName
+}
+
+// logToBotAuditLog writes the passed message to the bot audit log of the guild
+// the passed interaction has been created on.
+func logToBotAuditLog(s *discordgo.Session, i *discordgo.InteractionCreate, message string) {
+	dgoGuild, err := s.Guild(i.GuildID)
+	if nil != err {
+		C.Logger().Err(err, permissionsFailedToLoadGuildLogMessage, i.GuildID)
+
+		return
+	}
+
+	C.BotAuditLogger().Log(dgoGuild, i.Member.User, message, true)
+}
@@ -0,0 +1,67 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package permissions
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
+)
+
+var C *api.Component
+
+// HandlePermissionsSubCommand handles the execution of the
+// "permissions" subcommand.
+//
+// The command allows to manage which roles and users are allowed to use
+// a command and in which channels the command can be used.
+func HandlePermissionsSubCommand(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	option *discordgo.ApplicationCommandInteractionDataOption,
+) {
+	if nil == i.Member {
+		slash_commands.RespondWithCommandIsGuildOnly(C, s, i, "permissions")
+
+		return
+	}
+
+	subCommands := map[string]func(
+		s *discordgo.Session,
+		i *discordgo.InteractionCreate,
+		option *discordgo.ApplicationCommandInteractionDataOption,
+	){
+		"allow": handlePermissionsAllow,
+		"deny":  handlePermissionsDeny,
+		"reset": handlePermissionsReset,
+		"list":  handlePermissionsList,
+	}
+
+	success := api.ProcessSubCommands(
+		s,
+		i,
+		option,
+		subCommands)
+
+	if !success {
+		slash_commands.Respond(C, s, i, &discordgo.InteractionResponseData{
+			Content: "The executed (sub)command is invalid or does not exist!",
+		})
+	}
+}
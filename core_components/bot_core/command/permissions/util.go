@@ -0,0 +1,118 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package permissions
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"strconv"
+)
+
+// OverrideOptions holds the options of the permissions sub-commands
+// that create or remove permission overrides.
+type OverrideOptions struct {
+	Command string             `option:"command" description:"The name of the command" required:"true" autocomplete:"true"`
+	Role    *discordgo.Role    `option:"role" description:"The role the rule applies to"`
+	User    *discordgo.User    `option:"user" description:"The user the rule applies to"`
+	Channel *discordgo.Channel `option:"channel" description:"The channel the rule applies to"`
+}
+
+// ListOptions holds the options of the permissions list sub-command.
+type ListOptions struct {
+	Command string `option:"command" description:"The name of the command, omit it to list all rules" autocomplete:"true"`
+}
+
+// getTarget returns the type and id of the target selected in the options.
+// The last return value is the number of selected targets.
+func (oo *OverrideOptions) getTarget() (entities.CommandPermissionOverrideTarget, string, int) {
+	targetType := entities.CommandPermissionOverrideTarget("")
+	targetId := ""
+	count := 0
+
+	if nil != oo.Role {
+		targetType, targetId = entities.CommandPermissionOverrideTargetRole, oo.Role.ID
+		count++
+	}
+
+	if nil != oo.User {
+		targetType, targetId = entities.CommandPermissionOverrideTargetUser, oo.User.ID
+		count++
+	}
+
+	if nil != oo.Channel {
+		targetType, targetId = entities.CommandPermissionOverrideTargetChannel, oo.Channel.ID
+		count++
+	}
+
+	return targetType, targetId, count
+}
+
+// isChatCommandRegistered checks whether there is a chat command with the passed name.
+func isChatCommandRegistered(name string) bool {
+	for _, command := range C.SlashCommandManager().GetCommands() {
+		if discordgo.ChatApplicationCommand != command.Cmd.Type && 0 != command.Cmd.Type {
+			continue
+		}
+
+		if command.Cmd.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findOverride returns the override for the passed target in the passed overrides.
+func findOverride(
+	overrides []entities.CommandPermissionOverride,
+	targetType entities.CommandPermissionOverrideTarget,
+	targetId uint64,
+) (*entities.CommandPermissionOverride, bool) {
+	for idx := range overrides {
+		if overrides[idx].TargetType == targetType && overrides[idx].TargetID == targetId {
+			return &overrides[idx], true
+		}
+	}
+
+	return nil, false
+}
+
+// formatTarget returns the mention of the target of the passed override.
+func formatTarget(override entities.CommandPermissionOverride) string {
+	targetId := strconv.FormatUint(override.TargetID, 10)
+
+	switch override.TargetType {
+	case entities.CommandPermissionOverrideTargetRole:
+		return fmt.Sprintf("role <@&%s>", targetId)
+	case entities.CommandPermissionOverrideTargetUser:
+		return fmt.Sprintf("user <@%s>", targetId)
+	default:
+		return fmt.Sprintf("channel <#%s>", targetId)
+	}
+}
+
+// formatRule returns a human-readable representation of the passed override.
+func formatRule(override entities.CommandPermissionOverride) string {
+	if override.Allow {
+		return fmt.Sprintf(":white_check_mark: allowed for %s", formatTarget(override))
+	}
+
+	return fmt.Sprintf(":no_entry_sign: denied for %s", formatTarget(override))
+}
@@ -19,20 +19,22 @@
 package sync_commands
 
 import (
-	"fmt"
 	"github.com/bwmarrin/discordgo"
 	"github.com/lazybytez/jojo-discord-bot/api"
 	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
-	"github.com/lazybytez/jojo-discord-bot/services/cache"
 	"time"
 )
 
 var C *api.Component
 
-// getLastGuildSyncCacheKey returns the cache key used to store
-// the last time a command sync has been run.
-func getLastGuildSyncCacheKey(guildID string) string {
-	return fmt.Sprintf("last_command_sync_%s", guildID)
+// syncCommandsRateLimit ensures that commands can only be
+// re-synced once every 10 minutes per guild.
+var syncCommandsRateLimit = &api.RateLimit{
+	Name:     "command_sync",
+	Strategy: api.RateLimitStrategyFixedWindow,
+	Scope:    api.RateLimitScopeGuild,
+	Limit:    1,
+	Window:   10 * time.Minute,
 }
 
 // HandleSyncCommandSubCommand handles the execution of the
@@ -61,16 +63,12 @@ func HandleSyncCommandSubCommand(
 
 	resp := slash_commands.GenerateEphemeralInteractionResponseTemplate("Slash Command Synchronisation", "")
 
-	cacheKey := getLastGuildSyncCacheKey(i.GuildID)
-	lastSync, ok := cache.Get(cacheKey, time.Time{})
-
-	if ok && time.Since(lastSync) < 10*time.Minute {
-		respondWithOnCoolDown(s, i, resp)
+	if allowed, retryAfter := syncCommandsRateLimit.Take(i); !allowed {
+		respondWithOnCoolDown(s, i, resp, retryAfter)
 
 		return
 	}
 
-	respondWithProcessing(s, i, resp)
 	C.BotAuditLogger().Log(
 		dgoGuild,
 		user,
@@ -82,14 +80,6 @@ func HandleSyncCommandSubCommand(
 		i.GuildID)
 	C.SlashCommandManager().SyncApplicationComponentCommands(s, i.GuildID)
 
-	currentTime := time.Now()
-	err = cache.Update(cacheKey, currentTime)
-	if nil != err {
-		C.Logger().Err(err, fmt.Sprintf(
-			"Failed to store time of last command sync for guild \"%s\" in the cache.",
-			dgoGuild.ID))
-	}
-
 	finishWitSuccess(s, i, resp)
 	C.BotAuditLogger().Log(
 		dgoGuild,
@@ -98,46 +88,26 @@ func HandleSyncCommandSubCommand(
 		true)
 }
 
-// respondWithProcessing responds with a message
-// telling the user the command is still processing.
-func respondWithProcessing(
-	s *discordgo.Session,
-	i *discordgo.InteractionCreate,
-	resp *discordgo.InteractionResponseData,
-) {
-	embeds := []*discordgo.MessageEmbedField{
-		{
-			Name:  ":alarm_clock: Processing...",
-			Value: "Synchronisation is in progress and can take up to a minute, please wait...",
-		},
-	}
-
-	resp.Embeds[0].Fields = embeds
-
-	slash_commands.Respond(C, s, i, resp)
-}
-
 // respondWithOnCoolDown responds with a message
 // telling the user the command is still on cool-down.
 func respondWithOnCoolDown(
 	s *discordgo.Session,
 	i *discordgo.InteractionCreate,
 	resp *discordgo.InteractionResponseData,
+	retryAfter time.Duration,
 ) {
-	embeds := []*discordgo.MessageEmbedField{
-		{
-			Name:  ":x: Too fast!",
-			Value: "This command can only be used once every 10 minutes!",
-		},
+	resp.Embeds[0].Fields = []*discordgo.MessageEmbedField{
+		api.NewRateLimitedEmbedField(retryAfter),
 	}
 
-	resp.Embeds[0].Fields = embeds
-
 	slash_commands.Respond(C, s, i, resp)
 }
 
-// respondWithOnCoolDown responds with a message
-// telling the user the command is still on cool-down.
+// finishWitSuccess responds with a message
+// telling the user the commands have been synced.
+//
+// The synchronisation can take some time, in that case the deferred
+// response that has been sent in the meantime is replaced.
 func finishWitSuccess(
 	s *discordgo.Session,
 	i *discordgo.InteractionCreate,
@@ -150,11 +120,7 @@ func finishWitSuccess(
 		},
 	}
 
-	// We have an already appropriate embed formed, therefore just
-	// edit and use it.
 	resp.Embeds[0].Fields = embeds
 
-	slash_commands.EditResponse(C, s, i, &discordgo.WebhookEdit{
-		Embeds: &resp.Embeds,
-	})
+	slash_commands.Respond(C, s, i, resp)
 }
@@ -18,6 +18,8 @@
 
 package bot_core
 
+import "github.com/lazybytez/jojo-discord-bot/api"
+
 // ensureGlobalComponentStatusExists ensures that for every component
 // a database entry in the global status table exists.
 //
@@ -45,3 +47,23 @@ func ensureGlobalComponentStatusExists() {
 		}
 	}
 }
+
+// warnAboutGloballyDisabledDependencies prints a warning for every
+// globally disabled component that other components depend on.
+//
+// The dependent components are implicitly disabled too,
+// as a component is only enabled when all of its dependencies are enabled.
+func warnAboutGloballyDisabledDependencies() {
+	for _, comp := range api.Components {
+		if api.IsComponentEnabled(comp, "") {
+			continue
+		}
+
+		for _, dependent := range api.GetDependentComponents(comp.Code) {
+			C.Logger().Warn(
+				"The component \"%v\" is implicitly disabled, as it depends on the globally disabled component \"%v\"!",
+				dependent.Code,
+				comp.Code)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package bot_core
+
+import (
+	"github.com/lazybytez/jojo-discord-bot/api"
+	"time"
+)
+
+// componentStorageCleanupInterval is the interval in which
+// expired values of the component storage are removed.
+const componentStorageCleanupInterval = time.Hour
+
+// scheduleComponentStorageCleanup schedules the job that periodically
+// removes expired values of the component storage.
+func scheduleComponentStorageCleanup() error {
+	return C.Scheduler().Schedule(api.Job{
+		Name:     "component_storage_cleanup",
+		Interval: componentStorageCleanupInterval,
+		Jitter:   time.Minute,
+		Handler:  purgeExpiredComponentStorage,
+	})
+}
+
+// purgeExpiredComponentStorage removes all expired values of the component storage.
+func purgeExpiredComponentStorage() error {
+	count, err := C.EntityManager().ComponentStorageEntry().DeleteExpired(time.Now())
+	if nil != err {
+		return err
+	}
+
+	if count > 0 {
+		C.Logger().Info("Removed %d expired values from the component storage", count)
+	}
+
+	return nil
+}
@@ -28,7 +28,8 @@ import (
 // handleGuildRegisterOnJoin is triggered when the bot joins a guild.
 //
 // It ensures that every guild that isn't already known is registered
-// in the database. It also keeps the name of the guild updated.
+// in the database. It also keeps the name of the guild updated and restores
+// the data of guilds that have been left within the retention period.
 func handleGuildRegisterOnJoin(_ *discordgo.Session, g *discordgo.GuildCreate) {
 	guildId, err := strconv.ParseUint(g.ID, 10, 64)
 	if nil != err {
@@ -47,11 +48,20 @@ func handleGuildRegisterOnJoin(_ *discordgo.Session, g *discordgo.GuildCreate) {
 		err = em.Guilds().Create(guild)
 		if nil != err {
 			C.Logger().Warn("Failed to create guild with ID \"%v\" in database!", g.ID)
+
+			return
 		}
 
+		C.EventBus().Publish(&api.GuildRegisteredEvent{
+			GuildId: g.ID,
+			Name:    g.Name,
+		})
+
 		return
 	}
 
+	restoreLeftGuild(guild)
+
 	if guild.Name != g.Name {
 		err = em.Guilds().Update(guild, entities.ColumnName, g.Name)
 		if nil != err {
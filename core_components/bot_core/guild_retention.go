@@ -0,0 +1,132 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package bot_core
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"time"
+)
+
+// guildDataPurgeInterval is the interval in which the data
+// of guilds with an expired retention period is purged.
+const guildDataPurgeInterval = time.Hour
+
+// handleGuildLeave is triggered when the bot leaves a guild.
+//
+// It marks the guild as left, so its data is purged after the retention period.
+// Guilds that became unavailable due to an outage are ignored.
+func handleGuildLeave(_ *discordgo.Session, g *discordgo.GuildDelete) {
+	if g.Unavailable {
+		return
+	}
+
+	em := C.EntityManager()
+	guild, err := em.Guilds().Get(g.ID)
+	if nil != err || nil != guild.LeftAt {
+		return
+	}
+
+	err = em.Guilds().Update(guild, entities.ColumnLeftAt, time.Now())
+	if nil != err {
+		C.Logger().Err(err, "Failed to mark guild with ID \"%v\" as left in database!", g.ID)
+
+		return
+	}
+
+	retention := api.GetGuildDataRetention()
+	if 0 == retention {
+		C.Logger().Info("Marked guild with ID \"%v\" as left, its data is kept", g.ID)
+
+		return
+	}
+
+	C.Logger().Info("Marked guild with ID \"%v\" as left, its data will be purged in %v", g.ID, retention)
+}
+
+// restoreLeftGuild removes the left mark of the passed guild,
+// which restores the data of the guild when the bot rejoins it.
+func restoreLeftGuild(guild *entities.Guild) {
+	if nil == guild.LeftAt {
+		return
+	}
+
+	err := C.EntityManager().Guilds().Update(guild, entities.ColumnLeftAt, nil)
+	if nil != err {
+		C.Logger().Err(err, "Failed to restore left guild with ID \"%v\" in database!", guild.GuildID)
+
+		return
+	}
+
+	C.Logger().Info("Restored data of rejoined guild with ID \"%v\"", guild.GuildID)
+}
+
+// scheduleGuildDataPurge schedules the job that periodically purges
+// the data of guilds with an expired retention period.
+func scheduleGuildDataPurge() error {
+	return C.Scheduler().Schedule(api.Job{
+		Name:     "guild_data_purge",
+		Interval: guildDataPurgeInterval,
+		Jitter:   time.Minute,
+		Handler:  purgeLeftGuilds,
+	})
+}
+
+// purgeLeftGuilds purges the data of all guilds that have been left
+// longer ago than the configured retention period and logs a summary.
+func purgeLeftGuilds() error {
+	retention := api.GetGuildDataRetention()
+	if 0 == retention {
+		return nil
+	}
+
+	em := C.EntityManager()
+	guilds, err := em.Guilds().GetLeftBefore(time.Now().Add(-retention))
+	if nil != err {
+		return err
+	}
+
+	if 0 == len(guilds) {
+		return nil
+	}
+
+	purgedGuilds := 0
+	var purgedRows int64
+	for i := range guilds {
+		rows, err := em.Guilds().Purge(&guilds[i])
+		if nil != err {
+			C.Logger().Err(err, "Failed to purge data of guild with ID \"%v\"!", guilds[i].GuildID)
+
+			continue
+		}
+
+		C.Logger().Info("Purged %d rows of guild \"%v\" with ID \"%v\"", rows, guilds[i].Name, guilds[i].GuildID)
+		purgedGuilds++
+		purgedRows += rows
+	}
+
+	C.Logger().Info("Purged data of %d/%d guilds (%d rows) that have been left more than %v ago",
+		purgedGuilds,
+		len(guilds),
+		purgedRows,
+		retention)
+
+	return nil
+}
@@ -0,0 +1,155 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package bot_core
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"strings"
+)
+
+// handleJojoAutocomplete provides suggestions for the options of the jojo command
+// that have autocomplete enabled.
+func handleJojoAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	focused, siblings := api.GetFocusedOption(i.ApplicationCommandData().Options)
+	if nil == focused {
+		err := api.RespondWithAutocompleteChoices(s, i, []*discordgo.ApplicationCommandOptionChoice{})
+		if nil != err {
+			C.Logger().Err(err, "Failed to deliver empty autocomplete suggestions!")
+		}
+
+		return
+	}
+
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	switch focused.Name {
+	case "module":
+		choices = getModuleAutocompleteChoices(focused.StringValue())
+	case "key":
+		choices = getModuleConfigKeyAutocompleteChoices(siblings, focused.StringValue())
+	case "command":
+		choices = getCommandAutocompleteChoices(siblings, focused.StringValue())
+	default:
+		choices = []*discordgo.ApplicationCommandOptionChoice{}
+	}
+
+	err := api.RespondWithAutocompleteChoices(s, i, choices)
+	if nil != err {
+		C.Logger().Err(err, "Failed to deliver autocomplete suggestions for option \"%s\"!", focused.Name)
+	}
+}
+
+// getModuleAutocompleteChoices builds a slice containing all available modules
+// whose name or code contains the passed input as command option choices.
+func getModuleAutocompleteChoices(input string) []*discordgo.ApplicationCommandOptionChoice {
+	availableModuleChoices := make([]*discordgo.ApplicationCommandOptionChoice, 0)
+	input = strings.ToLower(input)
+
+	for _, comp := range C.EntityManager().RegisteredComponent().GetAvailable() {
+		if comp.IsCoreComponent() {
+			continue
+		}
+
+		if !strings.Contains(strings.ToLower(comp.Name), input) &&
+			!strings.Contains(string(comp.Code), input) {
+			continue
+		}
+
+		availableModuleChoices = append(availableModuleChoices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  comp.Name,
+			Value: string(comp.Code),
+		})
+	}
+
+	return availableModuleChoices
+}
+
+// getModuleConfigKeyAutocompleteChoices builds a slice containing the configuration options
+// of the module selected in the passed options, whose key contains the passed input.
+func getModuleConfigKeyAutocompleteChoices(
+	options []*discordgo.ApplicationCommandInteractionDataOption,
+	input string,
+) []*discordgo.ApplicationCommandOptionChoice {
+	configKeyChoices := make([]*discordgo.ApplicationCommandOptionChoice, 0)
+
+	var moduleCode string
+	for _, option := range options {
+		if "module" == option.Name {
+			moduleCode = option.StringValue()
+		}
+	}
+
+	comp, ok := api.FindComponentByCode(entities.ComponentCode(moduleCode))
+	if !ok || api.IsCoreComponent(comp) {
+		return configKeyChoices
+	}
+
+	for _, option := range comp.ConfigSchema {
+		if !strings.Contains(option.Key, strings.ToLower(input)) {
+			continue
+		}
+
+		configKeyChoices = append(configKeyChoices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  option.Key,
+			Value: option.Key,
+		})
+	}
+
+	return configKeyChoices
+}
+
+// getCommandAutocompleteChoices builds a slice containing the names of all
+// chat commands that contain the passed input as command option choices.
+// When a module is selected in the passed options, only the commands of the module are suggested.
+func getCommandAutocompleteChoices(
+	options []*discordgo.ApplicationCommandInteractionDataOption,
+	input string,
+) []*discordgo.ApplicationCommandOptionChoice {
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0)
+	input = strings.ToLower(input)
+
+	commands := C.SlashCommandManager().GetCommands()
+	for _, option := range options {
+		if "module" == option.Name {
+			commands = C.SlashCommandManager().GetCommandsForComponent(entities.ComponentCode(option.StringValue()))
+		}
+	}
+
+	for _, command := range commands {
+		if discordgo.ChatApplicationCommand != command.Cmd.Type && 0 != command.Cmd.Type {
+			continue
+		}
+
+		if !strings.Contains(command.Cmd.Name, input) {
+			continue
+		}
+
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  "/" + command.Cmd.Name,
+			Value: command.Cmd.Name,
+		})
+
+		if len(choices) == api.MaxAutocompleteChoices {
+			break
+		}
+	}
+
+	return choices
+}
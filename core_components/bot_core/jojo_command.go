@@ -21,35 +21,30 @@ package bot_core
 import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/api/slash_commands"
 	"github.com/lazybytez/jojo-discord-bot/core_components/bot_core/command/auditlog"
+	"github.com/lazybytez/jojo-discord-bot/core_components/bot_core/command/component_runtime"
+	"github.com/lazybytez/jojo-discord-bot/core_components/bot_core/command/export"
+	"github.com/lazybytez/jojo-discord-bot/core_components/bot_core/command/locale"
 	"github.com/lazybytez/jojo-discord-bot/core_components/bot_core/command/module"
+	"github.com/lazybytez/jojo-discord-bot/core_components/bot_core/command/permissions"
 	"github.com/lazybytez/jojo-discord-bot/core_components/bot_core/command/sync_commands"
 )
 
 // jojoCommand holds the command configuration for the jojo command.
 var jojoCommand *api.Command
 
-// adminMemberPermissions is the default permission used for the jojo command.
-var adminMemberPermissions int64 = discordgo.PermissionAdministrator
-
-// getModuleCommandChoices builds a slice containing all available modules
-// as command option choices
-func getModuleCommandChoices() []*discordgo.ApplicationCommandOptionChoice {
-	availableModuleChoices := make([]*discordgo.ApplicationCommandOptionChoice, 0)
-
-	for _, comp := range C.EntityManager().RegisteredComponent().GetAvailable() {
-		if comp.IsCoreComponent() {
-			continue
-		}
-
-		availableModuleChoices = append(availableModuleChoices, &discordgo.ApplicationCommandOptionChoice{
-			Name:  comp.Name,
-			Value: string(comp.Code),
-		})
-	}
-
-	return availableModuleChoices
-}
+// adminMemberPermissions is the default permission required to use the jojo command.
+// It is checked by the bot instead of Discord, so that guilds are able to
+// delegate the bot management to other roles using permission overrides.
+//
+// As a consequence, the jojo command does not set DefaultMemberPermissions
+// and is visible in the command picker of every member. Members without the
+// permission or an allowing override are stopped by the bot when using it.
+// Setting DefaultMemberPermissions would hide the command from the roles
+// the management has been delegated to, unless guilds additionally grant
+// them access in the integration settings of Discord.
+const adminMemberPermissions int64 = discordgo.PermissionAdministrator
 
 // initAndRegisterJojoCommand initializes the jojo command variable and registers the command
 // in the command API
@@ -58,12 +53,15 @@ func initAndRegisterJojoCommand() {
 	module.C = &C
 	sync_commands.C = &C
 	auditlog.C = &C
+	component_runtime.C = &C
+	permissions.C = &C
+	locale.C = &C
+	export.C = &C
 
 	jojoCommand = &api.Command{
 		Cmd: &discordgo.ApplicationCommand{
-			Name:                     "jojo",
-			Description:              "Manage modules and core settings of the bot!",
-			DefaultMemberPermissions: &adminMemberPermissions,
+			Name:        "jojo",
+			Description: "Manage modules and core settings of the bot!",
 			Options: []*discordgo.ApplicationCommandOption{
 				{
 					Name:        "module",
@@ -81,11 +79,11 @@ func initAndRegisterJojoCommand() {
 							Type:        discordgo.ApplicationCommandOptionSubCommand,
 							Options: []*discordgo.ApplicationCommandOption{
 								{
-									Name:        "module",
-									Description: "The name of the module to show information about",
-									Required:    true,
-									Type:        discordgo.ApplicationCommandOptionString,
-									Choices:     getModuleCommandChoices(),
+									Name:         "module",
+									Description:  "The name of the module to show information about",
+									Required:     true,
+									Type:         discordgo.ApplicationCommandOptionString,
+									Autocomplete: true,
 								},
 							},
 						},
@@ -95,11 +93,11 @@ func initAndRegisterJojoCommand() {
 							Type:        discordgo.ApplicationCommandOptionSubCommand,
 							Options: []*discordgo.ApplicationCommandOption{
 								{
-									Name:        "module",
-									Description: "The name of the module to enable",
-									Required:    true,
-									Type:        discordgo.ApplicationCommandOptionString,
-									Choices:     getModuleCommandChoices(),
+									Name:         "module",
+									Description:  "The name of the module to enable",
+									Required:     true,
+									Type:         discordgo.ApplicationCommandOptionString,
+									Autocomplete: true,
 								},
 							},
 						},
@@ -109,11 +107,99 @@ func initAndRegisterJojoCommand() {
 							Type:        discordgo.ApplicationCommandOptionSubCommand,
 							Options: []*discordgo.ApplicationCommandOption{
 								{
-									Name:        "module",
-									Description: "The name of the module to disable",
-									Required:    true,
+									Name:         "module",
+									Description:  "The name of the module to disable",
+									Required:     true,
+									Type:         discordgo.ApplicationCommandOptionString,
+									Autocomplete: true,
+								},
+							},
+						},
+						{
+							Name:        "config",
+							Description: "Show or change a configuration option of a module for the guild",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Name:         "module",
+									Description:  "The name of the module to configure",
+									Required:     true,
+									Type:         discordgo.ApplicationCommandOptionString,
+									Autocomplete: true,
+								},
+								{
+									Name:         "key",
+									Description:  "The configuration option to show or change",
+									Required:     true,
+									Type:         discordgo.ApplicationCommandOptionString,
+									Autocomplete: true,
+								},
+								{
+									Name:        "value",
+									Description: "The new value of the option, omit it to show the current value",
+									Required:    false,
 									Type:        discordgo.ApplicationCommandOptionString,
-									Choices:     getModuleCommandChoices(),
+								},
+								{
+									Name:        "reset",
+									Description: "Reset the option to its default value",
+									Required:    false,
+									Type:        discordgo.ApplicationCommandOptionBoolean,
+								},
+							},
+						},
+						{
+							Name:        "command",
+							Description: "Enable or disable a single command of a module for the guild",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options:     slash_commands.MustGenerateOptions(module.CommandOptions{}),
+						},
+					},
+				},
+				{
+					Name:        "runtime",
+					Description: "Load, unload or reload modules while the bot is running (bot owners only)!",
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Name:        "load",
+							Description: "Load a module that is currently not loaded",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Name:         "module",
+									Description:  "The name of the module to load",
+									Required:     true,
+									Type:         discordgo.ApplicationCommandOptionString,
+									Autocomplete: true,
+								},
+							},
+						},
+						{
+							Name:        "unload",
+							Description: "Unload a module that is currently loaded",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Name:         "module",
+									Description:  "The name of the module to unload",
+									Required:     true,
+									Type:         discordgo.ApplicationCommandOptionString,
+									Autocomplete: true,
+								},
+							},
+						},
+						{
+							Name:        "reload",
+							Description: "Unload a module and load it again",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Name:         "module",
+									Description:  "The name of the module to reload",
+									Required:     true,
+									Type:         discordgo.ApplicationCommandOptionString,
+									Autocomplete: true,
 								},
 							},
 						},
@@ -139,17 +225,7 @@ func initAndRegisterJojoCommand() {
 							Name:        "enable",
 							Description: "Enable printing the bot audit log to the configured channel",
 							Type:        discordgo.ApplicationCommandOptionSubCommand,
-							Options: []*discordgo.ApplicationCommandOption{
-								{
-									Name:        "channel",
-									Description: "The channel where audit log messages should be send to",
-									Required:    true,
-									Type:        discordgo.ApplicationCommandOptionChannel,
-									ChannelTypes: []discordgo.ChannelType{
-										discordgo.ChannelTypeGuildText,
-									},
-								},
-							},
+							Options:     slash_commands.MustGenerateOptions(auditlog.EnableOptions{}),
 						},
 						{
 							Name:        "disable",
@@ -158,10 +234,68 @@ func initAndRegisterJojoCommand() {
 						},
 					},
 				},
+				{
+					Name:        "permissions",
+					Description: "Manage who can use commands and in which channels!",
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Name:        "allow",
+							Description: "Allow a role or user to use a command or allow a command in a channel",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options:     slash_commands.MustGenerateOptions(permissions.OverrideOptions{}),
+						},
+						{
+							Name:        "deny",
+							Description: "Deny a role or user to use a command or deny a command in a channel",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options:     slash_commands.MustGenerateOptions(permissions.OverrideOptions{}),
+						},
+						{
+							Name:        "reset",
+							Description: "Remove a rule of a command or all rules when no target is selected",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options:     slash_commands.MustGenerateOptions(permissions.OverrideOptions{}),
+						},
+						{
+							Name:        "list",
+							Description: "List the configured rules of all or a specific command",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options:     slash_commands.MustGenerateOptions(permissions.ListOptions{}),
+						},
+					},
+				},
+				{
+					Name:        "locale",
+					Description: "Set the language the bot responds in on your server!",
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Name:        "set",
+							Description: "Set the language of the bot for the guild",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options:     locale.GetSetOptions(),
+						},
+						{
+							Name:        "reset",
+							Description: "Respond in the language of each user again",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+						},
+					},
+				},
+				{
+					Name:        "export",
+					Description: "Export all data the bot stores about your server",
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Options:     slash_commands.MustGenerateOptions(export.Options{}),
+				},
 			},
 		},
-		Category: api.CategoryAdministration,
-		Handler:  handleJojoCommand,
+		Category:       api.CategoryAdministration,
+		Permissions:    adminMemberPermissions,
+		DeferEphemeral: true,
+		Handler:        handleJojoCommand,
+		Autocomplete:   handleJojoAutocomplete,
 	}
 
 	_ = C.SlashCommandManager().Register(jojoCommand)
@@ -178,6 +312,10 @@ func handleJojoCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		"module":        module.HandleModuleSubCommand,
 		"sync-commands": sync_commands.HandleSyncCommandSubCommand,
 		"auditlog":      auditlog.HandleAuditLogCommandSubCommand,
+		"runtime":       component_runtime.HandleRuntimeSubCommand,
+		"permissions":   permissions.HandlePermissionsSubCommand,
+		"locale":        locale.HandleLocaleSubCommand,
+		"export":        export.HandleExportSubCommand,
 	}
 
 	api.ProcessSubCommands(
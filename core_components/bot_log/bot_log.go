@@ -34,6 +34,8 @@ var C = api.Component{
 	Description: "This component prints out some basic information in the " +
 		"log when bot is ready or added to guilds.",
 
+	Intents: discordgo.IntentsGuilds,
+
 	State: &api.State{
 		DefaultEnabled: true,
 	},
@@ -19,8 +19,10 @@
 package bot_status
 
 import (
+	"fmt"
 	"github.com/bwmarrin/discordgo"
 	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
 	"time"
 )
 
@@ -30,21 +32,17 @@ const BotStatusRotationTime = 5 * time.Minute
 
 var C = api.Component{
 	// Metadata
-	Code:         "bot_status",
-	Name:         "Bot Status",
-	Categories:   api.Categories{api.CategoryInternal},
-	Description:  "This component handles automated rotation and setting of the bot status in Discord.",
-	LoadPriority: -1000, // Be the last core component, so others can register initial status
+	Code:        "bot_status",
+	Name:        "Bot Status",
+	Categories:  api.Categories{api.CategoryInternal},
+	Description: "This component handles automated rotation and setting of the bot status in Discord.",
+	DependsOn:   []entities.ComponentCode{"bot_core"},
 
 	State: &api.State{
 		DefaultEnabled: true,
 	},
 }
 
-// botStatusRotationTicker id the ticker used to periodically
-// change the bots' status.
-var botStatusRotationTicker *time.Ticker
-
 func init() {
 	api.RegisterComponent(&C, LoadComponent)
 }
@@ -64,42 +62,40 @@ func onBotReady(_ *discordgo.Session, _ *discordgo.Ready) {
 	startBotStatusRotation()
 }
 
-// startBotStatusRotation starts a routine that handles the automated rotation
-// of the bots' status.
+// startBotStatusRotation schedules a job that handles the automated rotation
+// of the bots' status. The job is cancelled automatically when the component is unloaded.
 func startBotStatusRotation() {
-	botStatusRotationTicker = time.NewTicker(BotStatusRotationTime)
-
-	// Initial status rotation
-	rotateStatus()
-
-	// Continues status rotation
-	go func() {
-		for range botStatusRotationTicker.C {
-			rotateStatus()
-		}
-	}()
+	err := C.Scheduler().Schedule(api.Job{
+		Name:           "status_rotation",
+		Interval:       BotStatusRotationTime,
+		RunImmediately: true,
+		Handler:        rotateStatus,
+	})
+	if nil != err {
+		C.Logger().Err(err, "Failed to schedule the bot status rotation!")
+	}
 }
 
 // rotateStatus updates the status of the bot by rotating it.
-func rotateStatus() {
+func rotateStatus() error {
 	status := C.BotStatusManager().Next()
 
 	if nil == status {
 		C.Logger().Info("Not updating status, as no status are registered!")
 
-		return
+		return nil
 	}
 
 	err := C.DiscordApi().SetBotStatus(*status)
 
 	if nil != err {
-		C.Logger().Err(err, "Could not update the status of the bot due to an unexpected error!")
-
-		return
+		return fmt.Errorf("could not update the status of the bot: %w", err)
 	}
 
 	C.Logger().Info("Updated bot status to content \"%s\" and url \"%s\" with activity type \"%d\"",
 		status.Content,
 		status.Url,
 		status.ActivityType)
+
+	return nil
 }
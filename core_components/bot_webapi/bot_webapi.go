@@ -19,19 +19,21 @@
 package bot_webapi
 
 import (
+	"errors"
 	"fmt"
 	"github.com/bwmarrin/discordgo"
 	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
 	"github.com/lazybytez/jojo-discord-bot/webapi"
 )
 
 var C = api.Component{
 	// Metadata
-	Code:         "bot_webapi",
-	Name:         "Bot WebAPI",
-	Categories:   api.Categories{api.CategoryInternal},
-	Description:  "This component handles setup of the web api for the bots core api endpoints.",
-	LoadPriority: 999,
+	Code:        "bot_webapi",
+	Name:        "Bot WebAPI",
+	Categories:  api.Categories{api.CategoryInternal},
+	Description: "This component handles setup of the web api for the bots core api endpoints.",
+	DependsOn:   []entities.ComponentCode{"bot_core"},
 
 	State: &api.State{
 		DefaultEnabled: true,
@@ -46,13 +48,36 @@ func init() {
 // and handles migration of core entities
 // and registration of important core event handlers.
 func LoadComponent(_ *discordgo.Session) error {
-	compGroup := webapi.Router().Group("/components")
-	compGroup.GET("/", ComponentsGet)
+	compGroup := C.WebApiRouter().Group("/components")
+	err := errors.Join(
+		compGroup.GET("/", ComponentsGet),
+		compGroup.POST(fmt.Sprintf("/:%s/load", ParamComponentCode), webapi.RequireAdminToken(), ComponentLoadPost),
+		compGroup.POST(fmt.Sprintf("/:%s/unload", ParamComponentCode), webapi.RequireAdminToken(), ComponentUnloadPost),
+		compGroup.POST(fmt.Sprintf("/:%s/reload", ParamComponentCode), webapi.RequireAdminToken(), ComponentReloadPost),
+	)
+	if nil != err {
+		return err
+	}
 
-	commandsGroup := webapi.Router().Group("/commands")
-	commandsGroup.GET("/", CommandsGet)
-	commandsGroup.GET(fmt.Sprintf("/:%s", ParamCommandID), CommandGet)
-	commandsGroup.GET(fmt.Sprintf("/:%s/options", ParamCommandID), CommandOptionsGet)
+	jobsGroup := C.WebApiRouter().Group("/jobs")
+	err = jobsGroup.GET("/", webapi.RequireAdminToken(), JobsGet)
+	if nil != err {
+		return err
+	}
 
-	return nil
+	guildsGroup := C.WebApiRouter().Group("/guilds")
+	err = guildsGroup.GET(fmt.Sprintf("/:%s/export", ParamGuildID), webapi.RequireAdminToken(), GuildExportGet)
+	if nil != err {
+		return err
+	}
+
+	commandsGroup := C.WebApiRouter().Group("/commands")
+	commandSyncGroup := C.WebApiRouter().Group("/command-sync")
+
+	return errors.Join(
+		commandsGroup.GET("/", CommandsGet),
+		commandsGroup.GET(fmt.Sprintf("/:%s", ParamCommandID), CommandGet),
+		commandsGroup.GET(fmt.Sprintf("/:%s/options", ParamCommandID), CommandOptionsGet),
+		commandSyncGroup.GET("/diff", webapi.RequireAdminToken(), CommandSyncDiffGet),
+	)
 }
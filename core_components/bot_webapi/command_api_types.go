@@ -35,6 +35,13 @@ const ParamCommandID = "id"
 // A command id consists of the entire path of command names until the target is reached.
 const CommandIdSeparator = "_"
 
+// These are the types of commands exposed through the WebAPI.
+const (
+	CommandTypeChatInput = "chat_input"
+	CommandTypeUser      = "user"
+	CommandTypeMessage   = "message"
+)
+
 // CommandDTOsWebApiCacheKey is the cache key used to store and retrieve all commands
 // as CommandDTO instances from the cache.
 const CommandDTOsWebApiCacheKey = "bot_web_api_commands_get_cache"
@@ -48,9 +55,12 @@ const CommandDTOsWebApiCacheKey = "bot_web_api_commands_get_cache"
 // @Description description and its options. Note that commands are always
 // @Description built from the deepest level commands. This means a command is either a sub command or the
 // @Description top-level command.
+// @Description The type is either chat_input for slash-commands or user or message
+// @Description for context-menu commands.
 type CommandDTO struct {
 	ID          string                 `json:"id"`
 	Name        string                 `json:"name"`
+	Type        string                 `json:"type"`
 	Component   entities.ComponentCode `json:"component"`
 	Category    api.Category           `json:"category"`
 	Description string                 `json:"description"`
@@ -75,6 +85,20 @@ func CommandDTOsFromCommands(cmds []*api.Command) []CommandDTO {
 func commandDTOsFromCommand(cmd *api.Command) []CommandDTO {
 	commandDTOs := make([]CommandDTO, 0)
 
+	commandType := getCommandTypeName(cmd.Cmd.Type)
+	if CommandTypeChatInput != commandType {
+		commandDTO := CommandDTO{
+			ID:          getCommandIDFromCommandDTOName(fmt.Sprintf("%s %s", commandType, cmd.Cmd.Name)),
+			Name:        cmd.Cmd.Name,
+			Type:        commandType,
+			Component:   cmd.GetComponentCode(),
+			Category:    cmd.Category,
+			Description: cmd.Cmd.Description,
+		}
+
+		return append(commandDTOs, commandDTO)
+	}
+
 	if nil != cmd.Cmd.Options {
 		cmdDTO := commandDTOsFromCommandOptions(cmd.Cmd.Name,
 			cmd.Category,
@@ -88,6 +112,7 @@ func commandDTOsFromCommand(cmd *api.Command) []CommandDTO {
 		commandDTO := CommandDTO{
 			ID:          getCommandIDFromCommandDTOName(cmd.Cmd.Name),
 			Name:        cmd.Cmd.Name,
+			Type:        commandType,
 			Component:   cmd.GetComponentCode(),
 			Category:    cmd.Category,
 			Description: cmd.Cmd.Description,
@@ -127,6 +152,7 @@ func commandDTOsFromCommandOptions(
 			cmdDTO := CommandDTO{
 				ID:          getCommandIDFromCommandDTOName(name),
 				Name:        name,
+				Type:        CommandTypeChatInput,
 				Component:   component,
 				Category:    category,
 				Description: cmdOption.Description,
@@ -163,3 +189,17 @@ func getCommandDTOs() []CommandDTO {
 func getCommandIDFromCommandDTOName(name string) string {
 	return strings.ToLower(strings.ReplaceAll(name, " ", CommandIdSeparator))
 }
+
+// getCommandTypeName returns the name of the passed command type
+// that is exposed through the WebAPI.
+// A missing type is treated as chat input command.
+func getCommandTypeName(cmdType discordgo.ApplicationCommandType) string {
+	switch cmdType {
+	case discordgo.UserApplicationCommand:
+		return CommandTypeUser
+	case discordgo.MessageApplicationCommand:
+		return CommandTypeMessage
+	default:
+		return CommandTypeChatInput
+	}
+}
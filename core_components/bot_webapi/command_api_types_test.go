@@ -0,0 +1,127 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package bot_webapi
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type CommandApiTypesTestSuite struct {
+	suite.Suite
+	component *api.Component
+	commands  []*api.Command
+}
+
+func (suite *CommandApiTypesTestSuite) SetupTest() {
+	handler := func(_ *discordgo.Session, _ *discordgo.InteractionCreate) {}
+
+	suite.component = &api.Component{Code: "test_component"}
+	suite.commands = []*api.Command{
+		{
+			Cmd: &discordgo.ApplicationCommand{
+				Name:        "quote",
+				Description: "Quote something",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Name:        "random",
+						Description: "Quote a random message",
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+					},
+				},
+			},
+			Category: api.CategoryFun,
+			Handler:  handler,
+		},
+		{
+			Cmd: &discordgo.ApplicationCommand{
+				Name: "Quote message",
+				Type: discordgo.MessageApplicationCommand,
+			},
+			Category: api.CategoryFun,
+			Handler:  handler,
+		},
+		{
+			Cmd: &discordgo.ApplicationCommand{
+				Name: "User info",
+				Type: discordgo.UserApplicationCommand,
+			},
+			Category: api.CategoryUtilities,
+			Handler:  handler,
+		},
+	}
+
+	for _, cmd := range suite.commands {
+		suite.NoError(suite.component.SlashCommandManager().Register(cmd))
+	}
+}
+
+func (suite *CommandApiTypesTestSuite) TearDownTest() {
+	suite.component.SlashCommandManager().UnregisterAll()
+}
+
+func (suite *CommandApiTypesTestSuite) TestCommandDTOsFromCommands() {
+	expected := []CommandDTO{
+		{
+			ID:          "quote_random",
+			Name:        "quote random",
+			Type:        CommandTypeChatInput,
+			Component:   "test_component",
+			Category:    api.CategoryFun,
+			Description: "Quote a random message",
+		},
+		{
+			ID:          "message_quote_message",
+			Name:        "Quote message",
+			Type:        CommandTypeMessage,
+			Component:   "test_component",
+			Category:    api.CategoryFun,
+			Description: "",
+		},
+		{
+			ID:          "user_user_info",
+			Name:        "User info",
+			Type:        CommandTypeUser,
+			Component:   "test_component",
+			Category:    api.CategoryUtilities,
+			Description: "",
+		},
+	}
+
+	suite.Equal(expected, CommandDTOsFromCommands(suite.commands))
+}
+
+func (suite *CommandApiTypesTestSuite) TestGetCommandTypeName() {
+	suite.Equal(CommandTypeChatInput, getCommandTypeName(0))
+	suite.Equal(CommandTypeChatInput, getCommandTypeName(discordgo.ChatApplicationCommand))
+	suite.Equal(CommandTypeUser, getCommandTypeName(discordgo.UserApplicationCommand))
+	suite.Equal(CommandTypeMessage, getCommandTypeName(discordgo.MessageApplicationCommand))
+}
+
+func (suite *CommandApiTypesTestSuite) TestComputeCommandOptionDTOsForContextMenuCommand() {
+	_, err := computeCommandOptionDTOsForCommand(suite.commands, "message_quote_message")
+
+	suite.Error(err)
+}
+
+func TestCommandApiTypes(t *testing.T) {
+	suite.Run(t, new(CommandApiTypesTestSuite))
+}
@@ -192,6 +192,11 @@ func findApplicationCommandByCommandPath(commandPath []string, commands []*api.C
 	}
 
 	for _, cmd := range commands {
+		// Context-menu commands have no options
+		if CommandTypeChatInput != getCommandTypeName(cmd.Cmd.Type) {
+			continue
+		}
+
 		if cmd.Cmd.Name == commandPath[0] {
 			return cmd.Cmd
 		}
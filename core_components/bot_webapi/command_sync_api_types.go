@@ -0,0 +1,74 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package bot_webapi
+
+import (
+	"github.com/lazybytez/jojo-discord-bot/api"
+)
+
+// QueryGuildID is the name of the query parameter that carries
+// the id of a guild.
+const QueryGuildID = "guild"
+
+// CommandSyncDiffEntryDTO is the data transfer object of a single
+// api.CommandSyncDiffEntry.
+//
+// @Description CommandSyncDiffEntry identifies a command that would be changed by a command sync.
+// @Description The type is either chat_input for slash-commands or user or message
+// @Description for context-menu commands.
+type CommandSyncDiffEntryDTO struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+} //@Name CommandSyncDiffEntry
+
+// CommandSyncDiffDTO is the data transfer object of an api.CommandSyncDiff.
+//
+// @Description CommandSyncDiff holds the changes a command sync would apply.
+// @Description The guild is empty when the diff has been computed for the global commands.
+type CommandSyncDiffDTO struct {
+	Guild   string                    `json:"guild"`
+	Added   []CommandSyncDiffEntryDTO `json:"added"`
+	Removed []CommandSyncDiffEntryDTO `json:"removed"`
+	Changed []CommandSyncDiffEntryDTO `json:"changed"`
+} //@Name CommandSyncDiff
+
+// CommandSyncDiffDTOFromCommandSyncDiff creates a CommandSyncDiffDTO
+// from the passed api.CommandSyncDiff.
+func CommandSyncDiffDTOFromCommandSyncDiff(diff *api.CommandSyncDiff) CommandSyncDiffDTO {
+	return CommandSyncDiffDTO{
+		Guild:   diff.GuildId,
+		Added:   commandSyncDiffEntryDTOsFromEntries(diff.Added),
+		Removed: commandSyncDiffEntryDTOsFromEntries(diff.Removed),
+		Changed: commandSyncDiffEntryDTOsFromEntries(diff.Changed),
+	}
+}
+
+// commandSyncDiffEntryDTOsFromEntries creates a CommandSyncDiffEntryDTO
+// for each of the passed api.CommandSyncDiffEntry.
+func commandSyncDiffEntryDTOsFromEntries(entries []api.CommandSyncDiffEntry) []CommandSyncDiffEntryDTO {
+	entryDTOs := make([]CommandSyncDiffEntryDTO, len(entries))
+	for key, entry := range entries {
+		entryDTOs[key] = CommandSyncDiffEntryDTO{
+			Name: entry.Name,
+			Type: getCommandTypeName(entry.Type),
+		}
+	}
+
+	return entryDTOs
+}
@@ -0,0 +1,62 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package bot_webapi
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type CommandSyncApiTypesTestSuite struct {
+	suite.Suite
+}
+
+func (suite *CommandSyncApiTypesTestSuite) TestCommandSyncDiffDTOFromCommandSyncDiff() {
+	diff := &api.CommandSyncDiff{
+		GuildId: "1234",
+		Added: []api.CommandSyncDiffEntry{
+			{Name: "quote"},
+			{Name: "Quote message", Type: discordgo.MessageApplicationCommand},
+		},
+		Removed: []api.CommandSyncDiffEntry{
+			{Name: "User info", Type: discordgo.UserApplicationCommand},
+		},
+		Changed: []api.CommandSyncDiffEntry{},
+	}
+
+	expectedDTO := CommandSyncDiffDTO{
+		Guild: "1234",
+		Added: []CommandSyncDiffEntryDTO{
+			{Name: "quote", Type: CommandTypeChatInput},
+			{Name: "Quote message", Type: CommandTypeMessage},
+		},
+		Removed: []CommandSyncDiffEntryDTO{
+			{Name: "User info", Type: CommandTypeUser},
+		},
+		Changed: []CommandSyncDiffEntryDTO{},
+	}
+
+	suite.Equal(expectedDTO, CommandSyncDiffDTOFromCommandSyncDiff(diff))
+}
+
+func TestCommandSyncApiTypes(t *testing.T) {
+	suite.Run(t, new(CommandSyncApiTypesTestSuite))
+}
@@ -0,0 +1,172 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package bot_webapi
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"github.com/lazybytez/jojo-discord-bot/services/cache"
+	"github.com/lazybytez/jojo-discord-bot/webapi"
+	"net/http"
+	"time"
+)
+
+// ParamComponentCode is the name of the parameter that carries a
+// requested component code.
+const ParamComponentCode = "code"
+
+// ComponentLoadPost endpoint
+//
+// @Summary     Load a component of the bot at runtime
+// @Description This endpoint loads a component that is currently not loaded.
+// @Description Core components cannot be managed using this endpoint.
+// @Description The endpoint requires the configured admin token to be passed as bearer token.
+// @Tags        Component System
+// @Param		code path string true "Code of the component to load"
+// @Produce     json
+// @Success     200 {object} ComponentDTO "The component that has been loaded"
+// @Failure		401 {object} webapi.ErrorResponse "An error indicating that no or an invalid admin token has been passed"
+// @Failure		403 {object} webapi.ErrorResponse "An error indicating that the component cannot be managed"
+// @Failure		404 {object} webapi.ErrorResponse "An error indicating that the requested resource could not be found"
+// @Failure		409 {object} webapi.ErrorResponse "An error indicating that the operation failed"
+// @Router      /components/{code}/load [post]
+func ComponentLoadPost(g *gin.Context) {
+	handleComponentLifecycleOperation(g, "load", C.ComponentLifecycleManager().Load)
+}
+
+// ComponentUnloadPost endpoint
+//
+// @Summary     Unload a component of the bot at runtime
+// @Description This endpoint unloads a component that is currently loaded.
+// @Description Core components cannot be managed using this endpoint.
+// @Description The endpoint requires the configured admin token to be passed as bearer token.
+// @Tags        Component System
+// @Param		code path string true "Code of the component to unload"
+// @Produce     json
+// @Success     200 {object} ComponentDTO "The component that has been unloaded"
+// @Failure		401 {object} webapi.ErrorResponse "An error indicating that no or an invalid admin token has been passed"
+// @Failure		403 {object} webapi.ErrorResponse "An error indicating that the component cannot be managed"
+// @Failure		404 {object} webapi.ErrorResponse "An error indicating that the requested resource could not be found"
+// @Failure		409 {object} webapi.ErrorResponse "An error indicating that the operation failed"
+// @Router      /components/{code}/unload [post]
+func ComponentUnloadPost(g *gin.Context) {
+	handleComponentLifecycleOperation(g, "unload", C.ComponentLifecycleManager().Unload)
+}
+
+// ComponentReloadPost endpoint
+//
+// @Summary     Reload a component of the bot at runtime
+// @Description This endpoint unloads a component and loads it again afterwards.
+// @Description Core components cannot be managed using this endpoint.
+// @Description The endpoint requires the configured admin token to be passed as bearer token.
+// @Tags        Component System
+// @Param		code path string true "Code of the component to reload"
+// @Produce     json
+// @Success     200 {object} ComponentDTO "The component that has been reloaded"
+// @Failure		401 {object} webapi.ErrorResponse "An error indicating that no or an invalid admin token has been passed"
+// @Failure		403 {object} webapi.ErrorResponse "An error indicating that the component cannot be managed"
+// @Failure		404 {object} webapi.ErrorResponse "An error indicating that the requested resource could not be found"
+// @Failure		409 {object} webapi.ErrorResponse "An error indicating that the operation failed"
+// @Router      /components/{code}/reload [post]
+func ComponentReloadPost(g *gin.Context) {
+	handleComponentLifecycleOperation(g, "reload", C.ComponentLifecycleManager().Reload)
+}
+
+// handleComponentLifecycleOperation executes the passed lifecycle operation
+// for the component specified in the path and responds with the resulting component.
+func handleComponentLifecycleOperation(
+	g *gin.Context,
+	operationName string,
+	operation func(code entities.ComponentCode) error,
+) {
+	code := entities.ComponentCode(g.Param(ParamComponentCode))
+
+	comp, ok := api.FindComponentByCode(code)
+	if !ok {
+		webapi.RespondWithError(g, webapi.ErrorResponse{
+			Status:    http.StatusNotFound,
+			Error:     "Component not found",
+			Message:   fmt.Sprintf("There is no component with code \"%s\"", code),
+			Timestamp: time.Now(),
+		})
+
+		return
+	}
+
+	if api.IsCoreComponent(comp) {
+		webapi.RespondWithError(g, webapi.ErrorResponse{
+			Status:    http.StatusForbidden,
+			Error:     "Core component cannot be managed",
+			Message:   fmt.Sprintf("The core component \"%s\" cannot be managed at runtime", code),
+			Timestamp: time.Now(),
+		})
+
+		return
+	}
+
+	staleCommandDTOs := getCommandDTOs()
+	err := operation(code)
+	invalidateComponentRelatedCaches(staleCommandDTOs)
+	if nil != err {
+		C.Logger().Err(err, "Failed to %s component \"%s\" through the web api!", operationName, code)
+
+		webapi.RespondWithError(g, webapi.ErrorResponse{
+			Status:    http.StatusConflict,
+			Error:     fmt.Sprintf("Failed to %s component", operationName),
+			Message:   err.Error(),
+			Timestamp: time.Now(),
+		})
+
+		return
+	}
+
+	C.Logger().Info("Component \"%s\" has been triggered to %s through the web api", code, operationName)
+
+	componentDTO, err := ComponentDTOFromComponent(comp, "")
+	if nil != err {
+		webapi.RespondWithError(g, webapi.ErrorResponse{
+			Status:    http.StatusInternalServerError,
+			Error:     "Failed to prepare component",
+			Message:   fmt.Sprintf("The server failed to prepare the component \"%s\"", comp.Name),
+			Timestamp: time.Now(),
+		})
+
+		return
+	}
+
+	g.JSON(http.StatusOK, componentDTO)
+}
+
+// invalidateComponentRelatedCaches invalidates all cached responses that
+// depend on the loaded components and their commands.
+//
+// The passed CommandDTO slice must contain the commands that were available
+// before the component has been loaded, unloaded or reloaded.
+func invalidateComponentRelatedCaches(staleCommandDTOs []CommandDTO) {
+	cache.Invalidate(ComponentDTOsResponseWebApiCacheKey, []ComponentDTO{})
+
+	for _, commandDTO := range staleCommandDTOs {
+		cache.Invalidate(getSpecificCommandDTOCacheKey(commandDTO.ID), CommandDTO{})
+		cache.Invalidate(getCommandOptionsDTOCacheKey(commandDTO.ID), []CommandOptionDTO{})
+	}
+
+	cache.Invalidate(CommandDTOsWebApiCacheKey, []CommandDTO{})
+}
@@ -0,0 +1,61 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package bot_webapi
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/lazybytez/jojo-discord-bot/webapi"
+	"net/http"
+	"time"
+)
+
+// CommandSyncDiffGet endpoint
+//
+// @Summary     Get the changes a command sync would apply
+// @Description This endpoint compares the commands registered in Discord with the commands
+// @Description that should be available and returns the differences, without applying them.
+// @Description When no guild is passed, the differences of the global commands are returned.
+// @Description The endpoint requires the configured admin token to be passed as bearer token.
+// @Tags        Command System
+// @Param		guild query string false "ID of the guild to compute the differences for"
+// @Produce     json
+// @Success     200 {object} CommandSyncDiffDTO "The changes a command sync would apply"
+// @Failure		401 {object} webapi.ErrorResponse "An error indicating that no or an invalid admin token has been passed"
+// @Failure		502 {object} webapi.ErrorResponse "An error indicating that the commands could not be fetched from Discord"
+// @Router      /command-sync/diff [get]
+func CommandSyncDiffGet(g *gin.Context) {
+	guildId := g.Query(QueryGuildID)
+
+	diff, err := C.DiscordApi().CommandSyncDiff(guildId)
+	if nil != err {
+		C.Logger().Err(err, "Failed to compute the command sync diff for guild \"%s\"!", guildId)
+
+		webapi.RespondWithError(g, webapi.ErrorResponse{
+			Status:    http.StatusBadGateway,
+			Error:     "Failed to compute command sync diff",
+			Message:   fmt.Sprintf("The registered commands of guild \"%s\" could not be fetched", guildId),
+			Timestamp: time.Now(),
+		})
+
+		return
+	}
+
+	g.JSON(http.StatusOK, CommandSyncDiffDTOFromCommandSyncDiff(diff))
+}
@@ -0,0 +1,121 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package bot_webapi
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/gin-gonic/gin"
+	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/webapi"
+	"net/http"
+	"time"
+)
+
+// ParamGuildID is the name of the parameter that carries a
+// requested guild id.
+const ParamGuildID = "guild"
+
+// QueryZip is the name of the query parameter that specifies
+// whether a response should be compressed as zip archive.
+const QueryZip = "zip"
+
+// guildExportAuditLogMessage is the message written to the bot audit log
+// of a guild, when its data has been exported using the web api.
+const guildExportAuditLogMessage = "The guild data has been exported using the web api"
+
+// GuildExportGet endpoint
+//
+// @Summary     Export all data stored about a guild
+// @Description This endpoint exports all data the bot stores about a guild as JSON file.
+// @Description The file can optionally be compressed as zip archive.
+// @Description Every export is recorded in the bot audit log of the guild.
+// @Description The endpoint requires the configured admin token to be passed as bearer token.
+// @Tags        Guild System
+// @Param		guild path string true "ID of the guild to export the data of"
+// @Param		zip query bool false "Whether the export should be compressed as zip archive"
+// @Produce     json
+// @Produce     application/zip
+// @Success     200 {object} api.GuildExport "The exported data of the guild"
+// @Failure		401 {object} webapi.ErrorResponse "An error indicating that no or an invalid admin token has been passed"
+// @Failure		404 {object} webapi.ErrorResponse "An error indicating that the requested resource could not be found"
+// @Failure		500 {object} webapi.ErrorResponse "An error indicating that the export failed"
+// @Router      /guilds/{guild}/export [get]
+func GuildExportGet(g *gin.Context) {
+	guildId := g.Param(ParamGuildID)
+
+	_, err := C.EntityManager().Guilds().Get(guildId)
+	if nil != err {
+		webapi.RespondWithError(g, webapi.ErrorResponse{
+			Status:    http.StatusNotFound,
+			Error:     "Guild not found",
+			Message:   fmt.Sprintf("There is no guild with id \"%s\"", guildId),
+			Timestamp: time.Now(),
+		})
+
+		return
+	}
+
+	export, err := api.ExportGuildData(guildId)
+	if nil != err {
+		C.Logger().Err(err, "Failed to export the data of guild \"%s\"!", guildId)
+		respondWithExportFailed(g, guildId)
+
+		return
+	}
+
+	file, err := export.ToFile("true" == g.Query(QueryZip))
+	if nil != err {
+		C.Logger().Err(err, "Failed to encode the data export of guild \"%s\"!", guildId)
+		respondWithExportFailed(g, guildId)
+
+		return
+	}
+
+	logGuildExport(guildId)
+
+	g.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", file.Name))
+	g.Data(http.StatusOK, file.ContentType, file.Data)
+}
+
+// respondWithExportFailed responds with an error telling
+// the data of the guild could not be exported.
+func respondWithExportFailed(g *gin.Context, guildId string) {
+	webapi.RespondWithError(g, webapi.ErrorResponse{
+		Status:    http.StatusInternalServerError,
+		Error:     "Failed to export guild data",
+		Message:   fmt.Sprintf("The data of guild \"%s\" could not be exported", guildId),
+		Timestamp: time.Now(),
+	})
+}
+
+// logGuildExport records the export of the guild data in the bot audit log.
+// As there is no Discord user behind web api requests, the bot user is used.
+func logGuildExport(guildId string) {
+	botUser := C.DiscordApi().BotUser()
+	if nil == botUser {
+		C.Logger().Warn("Could not record the data export of guild \"%s\" in the bot audit log, "+
+			"as the bot user is not known yet!",
+			guildId)
+
+		return
+	}
+
+	C.BotAuditLogger().Log(&discordgo.Guild{ID: guildId}, botUser, guildExportAuditLogMessage, true)
+}
@@ -0,0 +1,40 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package bot_webapi
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lazybytez/jojo-discord-bot/api"
+	"net/http"
+)
+
+// JobsGet endpoint
+//
+// @Summary     Get all scheduled jobs of the bot
+// @Description This endpoint returns the jobs that are currently scheduled by components,
+// @Description together with their status like the number of runs and the last error.
+// @Description The endpoint requires the configured admin token to be passed as bearer token.
+// @Tags        Component System
+// @Produce     json
+// @Success     200 {array} JobDTO "An array consisting of objects containing information about scheduled jobs"
+// @Failure		401 {object} webapi.ErrorResponse "An error indicating that no or an invalid admin token has been passed"
+// @Router      /jobs [get]
+func JobsGet(g *gin.Context) {
+	g.JSON(http.StatusOK, JobDTOsFromJobStatus(api.GetScheduledJobs()))
+}
@@ -0,0 +1,73 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package bot_webapi
+
+import (
+	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/lazybytez/jojo-discord-bot/api/entities"
+	"time"
+)
+
+// JobDTO is the data transfer object of an api.JobStatus.
+//
+// @Description Job holds information about a job scheduled by a component,
+// @Description like when it is executed and the result of its last execution.
+// @Description The last and next run are null, when the job has not been executed
+// @Description or is not scheduled to be executed yet.
+type JobDTO struct {
+	Component entities.ComponentCode `json:"component"`
+	Name      string                 `json:"name"`
+	Schedule  string                 `json:"schedule"`
+	Running   bool                   `json:"running"`
+	Runs      int                    `json:"runs"`
+	Skipped   int                    `json:"skipped"`
+	LastRun   *time.Time             `json:"last_run"`
+	NextRun   *time.Time             `json:"next_run"`
+	LastError string                 `json:"last_error"`
+} //@Name Job
+
+// JobDTOsFromJobStatus creates a JobDTO for each of the passed api.JobStatus.
+func JobDTOsFromJobStatus(jobs []api.JobStatus) []JobDTO {
+	jobDTOs := make([]JobDTO, len(jobs))
+	for key, job := range jobs {
+		jobDTOs[key] = JobDTO{
+			Component: job.Component,
+			Name:      job.Name,
+			Schedule:  job.Schedule,
+			Running:   job.Running,
+			Runs:      job.Runs,
+			Skipped:   job.Skipped,
+			LastRun:   getTimePointer(job.LastRun),
+			NextRun:   getTimePointer(job.NextRun),
+			LastError: job.LastError,
+		}
+	}
+
+	return jobDTOs
+}
+
+// getTimePointer returns a pointer to the passed time,
+// or nil if the passed time is the zero time.
+func getTimePointer(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
@@ -0,0 +1,75 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package bot_webapi
+
+import (
+	"github.com/lazybytez/jojo-discord-bot/api"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type JobApiTypesTestSuite struct {
+	suite.Suite
+}
+
+func (suite *JobApiTypesTestSuite) TestJobDTOsFromJobStatus() {
+	lastRun := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+	nextRun := lastRun.Add(5 * time.Minute)
+
+	jobs := []api.JobStatus{
+		{
+			Component: "bot_status",
+			Name:      "status_rotation",
+			Schedule:  "every 5m0s",
+			Runs:      3,
+			LastRun:   lastRun,
+			NextRun:   nextRun,
+			LastError: "could not update the status of the bot",
+		},
+		{
+			Component: "statistics",
+			Name:      "guild_count_update",
+			Schedule:  "every 10m0s",
+		},
+	}
+
+	expectedDTOs := []JobDTO{
+		{
+			Component: "bot_status",
+			Name:      "status_rotation",
+			Schedule:  "every 5m0s",
+			Runs:      3,
+			LastRun:   &lastRun,
+			NextRun:   &nextRun,
+			LastError: "could not update the status of the bot",
+		},
+		{
+			Component: "statistics",
+			Name:      "guild_count_update",
+			Schedule:  "every 10m0s",
+		},
+	}
+
+	suite.Equal(expectedDTOs, JobDTOsFromJobStatus(jobs))
+}
+
+func TestJobApiTypes(t *testing.T) {
+	suite.Run(t, new(JobApiTypesTestSuite))
+}
@@ -39,11 +39,12 @@ var coreLogger = logger.New(coreLoggerPrefix, nil)
 func Bootstrap() {
 	// Init config & db
 	initEnv()
+	resolveComponentDependencies()
 	initCache()
 	initGorm()
 
 	// Create DiscordGo session
-	createSession(Config.token)
+	createSession(Config.token, Config.shardCount)
 
 	// Init APIs
 	initApi()
@@ -51,6 +52,7 @@ func Bootstrap() {
 
 	// Load components
 	RegisterComponents()
+	updateIntents()
 	LoadComponents(discord)
 
 	// Start bot and finish API initialization
@@ -69,6 +71,16 @@ func initApi() {
 	if nil != err {
 		ExitFatalGracefully("Failed to initialize API!")
 	}
+
+	migrateDatabase()
+
+	err = api.InitShards(shardManager.Shards())
+	if nil != err {
+		ExitFatalGracefully("Failed to initialize shards!")
+	}
+
+	api.SetAutoDeferThreshold(Config.autoDefer)
+	api.SetGuildDataRetention(Config.guildRetention)
 }
 
 // waitForTerminate blocks the console and waits
@@ -19,6 +19,7 @@
 package internal
 
 import (
+	"fmt"
 	"github.com/bwmarrin/discordgo"
 	"github.com/lazybytez/jojo-discord-bot/api"
 	"github.com/lazybytez/jojo-discord-bot/services/logger"
@@ -32,6 +33,20 @@ const logComponentRegistry = "component_manager"
 // registration routines. They do not use the coreLogger
 var componentRegistryLogger = logger.New(logComponentRegistry, nil)
 
+// resolveComponentDependencies orders the registered components
+// by their dependencies.
+//
+// The application exits, when the dependencies of the
+// registered components cannot be resolved.
+func resolveComponentDependencies() {
+	componentRegistryLogger.Info("Resolving component dependencies...")
+	err := api.ResolveComponentDependencies()
+	if nil != err {
+		ExitFatal(fmt.Sprintf("Failed to resolve component dependencies: %v", err))
+	}
+	componentRegistryLogger.Info("Component dependencies have been successfully resolved...")
+}
+
 // RegisterComponents registers all available components in the database
 // and fills the available components in the database API, to provide
 // a unified API to get component information.
@@ -0,0 +1,103 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"flag"
+	"fmt"
+	"github.com/lazybytez/jojo-discord-bot/api"
+	serviceLogger "github.com/lazybytez/jojo-discord-bot/services/logger"
+	"os"
+	"text/tabwriter"
+)
+
+// CopyDatabaseCommand is the command line argument that runs the
+// database copy command instead of starting the bot.
+const CopyDatabaseCommand = "copy-database"
+
+// copyDatabaseLoggerPrefix is the prefix of the logger used while copying.
+const copyDatabaseLoggerPrefix = "database_copy"
+
+// CopyDatabase is the entrypoint of the database copy command, which copies all
+// entities of the bot from a source to an empty target database.
+// This allows to move an existing installation from SQLite to Postgres and vice versa.
+//
+// The source database defaults to the configured database.
+// The following arguments are supported:
+//   - -source-mode: the mode of the source database (SQLite or Postgres)
+//   - -source-url: the DSN of the source database
+//   - -target-mode: the mode of the target database (SQLite or Postgres)
+//   - -target-url: the DSN of the target database
+//   - -batch-size: the number of rows copied at once
+func CopyDatabase(args []string) {
+	initEnv()
+	resolveComponentDependencies()
+
+	flags := flag.NewFlagSet(CopyDatabaseCommand, flag.ExitOnError)
+	sourceMode := flags.String("source-mode", Config.sqlMode, "The mode of the source database (SQLite or Postgres)")
+	sourceUrl := flags.String("source-url", Config.sqlUrl, "The DSN of the source database")
+	targetMode := flags.String("target-mode", "", "The mode of the target database (SQLite or Postgres)")
+	targetUrl := flags.String("target-url", "", "The DSN of the target database")
+	batchSize := flags.Int("batch-size", api.DefaultDatabaseCopyBatchSize, "The number of rows copied at once")
+	_ = flags.Parse(args)
+
+	if "" == *targetMode || "" == *targetUrl {
+		flags.Usage()
+		ExitFatal("The mode and DSN of the target database must be passed!")
+	}
+
+	if *sourceMode == *targetMode && *sourceUrl == *targetUrl {
+		ExitFatal("The source and target database must not be the same!")
+	}
+
+	source, err := openDatabase(*sourceMode, *sourceUrl)
+	if nil != err {
+		ExitFatal(fmt.Sprintf("Failed to open the source database! Error: \"%v\"", err.Error()))
+	}
+
+	target, err := openDatabase(*targetMode, *targetUrl)
+	if nil != err {
+		ExitFatal(fmt.Sprintf("Failed to open the target database! Error: \"%v\"", err.Error()))
+	}
+
+	coreLogger.Info("Copying database from %s to %s...",
+		getDatabaseDriverName(*sourceMode),
+		getDatabaseDriverName(*targetMode))
+
+	copier := api.NewDatabaseCopier(source, target, serviceLogger.New(copyDatabaseLoggerPrefix, nil), *batchSize)
+	results, err := copier.Copy()
+	if nil != err {
+		ExitFatal(fmt.Sprintf("Failed to copy the database! Error: \"%v\"", err.Error()))
+	}
+
+	printDatabaseCopyResults(results)
+	coreLogger.Info("The database has been copied and the row counts of all tables match!")
+}
+
+// printDatabaseCopyResults prints the number of copied rows per table to stdout.
+func printDatabaseCopyResults(results []api.DatabaseCopyResult) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(writer, "TABLE\tROWS")
+	for _, result := range results {
+		_, _ = fmt.Fprintf(writer, "%s\t%d\n", result.Table, result.Rows)
+	}
+
+	_ = writer.Flush()
+}
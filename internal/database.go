@@ -38,26 +38,12 @@ var gormDB *gorm.DB
 
 // initGorm initializes GORM to allow the use of databases in the application
 func initGorm() {
-	var dial *gorm.Dialector
-
 	coreLogger.Info("Setting up database connection...")
-	switch Config.sqlMode {
-	case ModeSQLite:
-		coreLogger.Info("Using SQLite as database driver!")
-		dial = getSQLiteDialector()
-	case ModePostgres:
-		coreLogger.Info("Using PostgreSQL as database driver!")
-		dial = getPostgresDialector()
-	default:
-		ExitFatal(fmt.Sprintf("The database mode \"%v\" is not valid!", Config.sqlMode))
-	}
+	coreLogger.Info("Using %s as database driver!", getDatabaseDriverName(Config.sqlMode))
 
 	coreLogger.Info("Open GORM instance...")
 	var err error
-	gormDB, err = gorm.Open(*dial, &gorm.Config{
-		Logger:      logger.Default.LogMode(logger.Silent),
-		PrepareStmt: true,
-	})
+	gormDB, err = openDatabase(Config.sqlMode, Config.sqlUrl)
 	if nil != err {
 		ExitFatal(fmt.Sprintf("Failed to initialize database subsystem! Error: \"%v\"", err.Error()))
 	}
@@ -72,13 +58,52 @@ func CreateEntityManager() api.EntityManager {
 	return em
 }
 
+// openDatabase opens a new gorm.DB connection to the database
+// with the passed mode and DSN.
+func openDatabase(mode string, dsn string) (*gorm.DB, error) {
+	dial, err := getDialector(mode, dsn)
+	if nil != err {
+		return nil, err
+	}
+
+	return gorm.Open(*dial, &gorm.Config{
+		Logger:      logger.Default.LogMode(logger.Silent),
+		PrepareStmt: true,
+	})
+}
+
+// getDialector returns the gorm.Dialector for the passed database mode and DSN.
+func getDialector(mode string, dsn string) (*gorm.Dialector, error) {
+	switch mode {
+	case ModeSQLite:
+		return getSQLiteDialector(dsn), nil
+	case ModePostgres:
+		if "" == dsn {
+			return nil, fmt.Errorf("a DATABASE_URL must be defined when using DATABASE_MODE \"%v\"", ModePostgres)
+		}
+
+		return getPostgresDialector(dsn), nil
+	default:
+		return nil, fmt.Errorf("the database mode \"%v\" is not valid", mode)
+	}
+}
+
+// getDatabaseDriverName returns the human-readable name of the
+// database driver used for the passed database mode.
+func getDatabaseDriverName(mode string) string {
+	if ModePostgres == mode {
+		return "PostgreSQL"
+	}
+
+	return mode
+}
+
 // getSQLiteDialector creates a SQLite gormDB and returns the gorm.Dialector instance
 //
 // The gormDB is created either:
 //  1. when DB_DSN is present under the specified path
 //  2. when DB_DSN is empty under the current working directory
-func getSQLiteDialector() *gorm.Dialector {
-	dsn := Config.sqlUrl
+func getSQLiteDialector(dsn string) *gorm.Dialector {
 	if "" == dsn {
 		dsn = "jojo.db"
 	}
@@ -90,13 +115,7 @@ func getSQLiteDialector() *gorm.Dialector {
 // getPostgresDialector creates a gormDB connection and returns the gorm.Dialector instance
 //
 // The defined DB_DSN will be used to establish the connection.
-// If the DSN is empty, the application will crash!
-func getPostgresDialector() *gorm.Dialector {
-	dsn := Config.sqlUrl
-	if "" == dsn {
-		ExitFatal(fmt.Sprintf("A DATABASE_URL must be defined when using DATABASE_MODE \"%v\"", ModePostgres))
-	}
-
+func getPostgresDialector(dsn string) *gorm.Dialector {
 	sqlDialector := postgres.Open(dsn)
 
 	return &sqlDialector
@@ -21,58 +21,82 @@ package internal
 import (
 	"fmt"
 	"github.com/bwmarrin/discordgo"
+	"github.com/lazybytez/jojo-discord-bot/api"
 )
 
 const tokenPrefix = "Bot "
 
+// discord is the session of the first shard, which is used
+// for everything that is not bound to a specific shard.
 var discord *discordgo.Session
 
-// createSession creates a discordgo.Session,
-// but does not open the connection yet.
+// shardManager manages the sessions of all shards.
+var shardManager *ShardManager
+
+// createSession creates the discordgo.Session instances of all shards,
+// but does not open the connections yet.
 //
 // The token that is passed will be used to
-// configure the session.
-func createSession(token string) {
-	if nil != discord {
+// configure the sessions.
+func createSession(token string, shardCount int) {
+	if nil != shardManager {
 		ExitFatal("DiscordGo session can be created only once!")
 	}
 
 	var err error
-	discord, err = discordgo.New(tokenPrefix + token)
+	shardManager, err = NewShardManager(tokenPrefix+token, shardCount)
 	if nil != err {
 		ExitFatal(fmt.Sprintf("Failed to create discordgo session, error was: %v!", err.Error()))
 	}
+
+	discord = shardManager.Primary()
 }
 
-// startBot opens the connection and configures the bots intents.
+// startBot opens the connections of all shards.
 func startBot() {
-	if nil == discord {
+	if nil == shardManager {
 		ExitFatal("Session must be first created before the bot can be started")
 	}
 
-	err := discord.Open()
+	err := shardManager.Open()
 	if nil != err {
 		ExitFatal(fmt.Sprintf("Failed to open bot connection to Discord, error was: %v!", err.Error()))
 	}
-
-	updateIntents()
 }
 
 // stopBot tries to stop the bot.
-// The bot is stopped by closing the discordgo.Session.
+// The bot is stopped by closing the discordgo.Session of all shards.
 //
 // If the bot has not been initialized until this point,
 // the close function of the discordgo.Session won't be called.
 //
 // If closing the session throws an error, the error is ignored.
 func stopBot() {
-	if nil != discord {
-		_ = discord.Close()
+	if nil != shardManager {
+		shardManager.Close()
 	}
 }
 
-// updateIntents to receive all necessary permissions
-// for the bot
+// updateIntents computes the intents required by the registered components
+// and configures them for all shards. It must be called before the bot is started.
+//
+// Privileged intents are only requested, when they have been enabled in the configuration.
+// Components that require privileged intents that are not enabled cannot be loaded.
 func updateIntents() {
-	discord.Identify.Intents = discordgo.IntentsAllWithoutPrivileged
+	for _, comp := range api.Components {
+		missingIntents := comp.Intents & api.PrivilegedIntents &^ Config.privIntents
+		if 0 != missingIntents {
+			coreLogger.Warn("The component \"%s\" requires the privileged intents \"%s\", "+
+				"which are not enabled in the configuration! The component will not be loaded.",
+				comp.Code,
+				api.GetPrivilegedIntentNames(missingIntents))
+		}
+	}
+
+	intents := api.ComputeIntents(api.Components, Config.privIntents)
+	api.SetAvailableIntents(intents)
+
+	for _, shard := range shardManager.Shards() {
+		shard.Identify.Intents = intents
+	}
 }
@@ -20,10 +20,14 @@ package internal
 
 import (
 	"fmt"
+	"github.com/bwmarrin/discordgo"
 	"github.com/joho/godotenv"
+	"github.com/lazybytez/jojo-discord-bot/api"
 	"github.com/lazybytez/jojo-discord-bot/services/cache"
 	"os"
 	"path"
+	"strconv"
+	"time"
 )
 
 const envFile = ".env"
@@ -33,6 +37,7 @@ const (
 	token          = "TOKEN"
 	sqlMode        = "DATABASE_MODE"
 	sqlUrl         = "DATABASE_URL"
+	autoMigrate    = "DATABASE_AUTO_MIGRATE"
 	cacheMode      = "CACHE_MODE"
 	cacheDsn       = "CACHE_DSN"
 	redisUrl       = "REDIS_URL"
@@ -41,6 +46,11 @@ const (
 	webApiHost     = "WEBAPI_HOST"
 	webApiBasePath = "WEBAPI_BASE_PATH"
 	webApiSchemes  = "WEBAPI_SCHEMES"
+	webApiToken    = "WEBAPI_ADMIN_TOKEN"
+	autoDefer      = "COMMAND_AUTO_DEFER_THRESHOLD"
+	shardCount     = "SHARD_COUNT"
+	privIntents    = "PRIVILEGED_INTENTS"
+	guildRetention = "GUILD_DATA_RETENTION"
 )
 
 // JojoBotConfig represents the entire environment variable based configuration
@@ -54,6 +64,7 @@ type JojoBotConfig struct {
 	token          string
 	sqlMode        string
 	sqlUrl         string
+	autoMigrate    bool
 	cacheMode      cache.Mode
 	cacheDsn       cache.Dsn
 	webApiMode     string
@@ -61,6 +72,11 @@ type JojoBotConfig struct {
 	webApiHost     string
 	webApiBasePath string
 	webApiSchemes  string
+	webApiToken    string
+	autoDefer      time.Duration
+	shardCount     int
+	privIntents    discordgo.Intent
+	guildRetention time.Duration
 }
 
 // Config holds the currently loaded configuration
@@ -94,6 +110,69 @@ func getEnvOrFail(key string) string {
 	return val
 }
 
+// getDurationEnvOrDefault tries to get an environment variable holding a duration
+// (e.g. "2s") from the environment. If value could not be found, the passed default value will be used.
+// If the value is not a valid duration, the application will exit with a fatal crash.
+func getDurationEnvOrDefault(key string, defaultValue time.Duration) time.Duration {
+	val := os.Getenv(key)
+	if "" == val {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(val)
+	if nil != err {
+		ExitFatal(fmt.Sprintf("The environment variable \"%s\" does not contain a valid duration!", key))
+	}
+
+	return duration
+}
+
+// getIntEnvOrDefault tries to get an environment variable holding a non-negative integer
+// from the environment. If value could not be found, the passed default value will be used.
+// If the value is not a valid non-negative integer, the application will exit with a fatal crash.
+func getIntEnvOrDefault(key string, defaultValue int) int {
+	val := os.Getenv(key)
+	if "" == val {
+		return defaultValue
+	}
+
+	number, err := strconv.Atoi(val)
+	if nil != err || number < 0 {
+		ExitFatal(fmt.Sprintf("The environment variable \"%s\" does not contain a valid number!", key))
+	}
+
+	return number
+}
+
+// getBoolEnvOrDefault tries to get an environment variable holding a boolean
+// from the environment. If value could not be found, the passed default value will be used.
+// If the value is not a valid boolean, the application will exit with a fatal crash.
+func getBoolEnvOrDefault(key string, defaultValue bool) bool {
+	val := os.Getenv(key)
+	if "" == val {
+		return defaultValue
+	}
+
+	boolean, err := strconv.ParseBool(val)
+	if nil != err {
+		ExitFatal(fmt.Sprintf("The environment variable \"%s\" does not contain a valid boolean!", key))
+	}
+
+	return boolean
+}
+
+// getPrivilegedIntentsEnv reads the privileged intents that have been enabled
+// for the bot in the Discord developer portal from the environment.
+// If the value contains an unknown intent, the application will exit with a fatal crash.
+func getPrivilegedIntentsEnv() discordgo.Intent {
+	intents, err := api.ParsePrivilegedIntents(os.Getenv(privIntents))
+	if nil != err {
+		ExitFatal(fmt.Sprintf("The environment variable \"%s\" is invalid: %v!", privIntents, err.Error()))
+	}
+
+	return intents
+}
+
 // initEnv initializes environment with local .env file
 // This will load the environment variables defined in the specified
 // env file and merge them into os.Environ.
@@ -115,6 +194,7 @@ func initEnv() {
 		token:          getEnvOrFail(token),
 		sqlMode:        getEnvOrFail(sqlMode),
 		sqlUrl:         getEnvOrDefault(sqlUrl, ""),
+		autoMigrate:    getBoolEnvOrDefault(autoMigrate, true),
 		cacheMode:      cache.Mode(getEnvOrFail(cacheMode)),
 		cacheDsn:       findCacheDsn(),
 		webApiMode:     getEnvOrDefault(webApiMode, DefaultWebApiMode),
@@ -122,6 +202,11 @@ func initEnv() {
 		webApiHost:     getEnvOrDefault(webApiHost, DefaultWebApiHost),
 		webApiBasePath: getEnvOrDefault(webApiBasePath, DefaultWebApiBasePath),
 		webApiSchemes:  getEnvOrDefault(webApiSchemes, DefaultWebApiSchemes),
+		webApiToken:    getEnvOrDefault(webApiToken, ""),
+		autoDefer:      getDurationEnvOrDefault(autoDefer, api.DefaultAutoDeferThreshold),
+		shardCount:     getIntEnvOrDefault(shardCount, AutoShardCount),
+		privIntents:    getPrivilegedIntentsEnv(),
+		guildRetention: getDurationEnvOrDefault(guildRetention, api.DefaultGuildDataRetention),
 	}
 	coreLogger.Info("Successfully loaded environment configuration!")
 }
@@ -0,0 +1,172 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"fmt"
+	"github.com/lazybytez/jojo-discord-bot/api"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// MigrateCommand is the command line argument that runs the
+// migration command instead of starting the bot.
+const MigrateCommand = "migrate"
+
+// migrateUsage describes the usage of the migration command.
+const migrateUsage = "Usage: migrate up | down [steps] | status"
+
+// migrateDatabase applies all pending migrations on startup.
+//
+// When automated migrations are disabled, the application exits if
+// there are pending migrations, as the bot cannot work on an outdated schema.
+func migrateDatabase() {
+	migrator, err := api.GetEntityManager().Migrator()
+	if nil != err {
+		ExitFatal(fmt.Sprintf("Failed to prepare database migrations! Error: \"%v\"", err.Error()))
+	}
+
+	if !Config.autoMigrate {
+		pending := getPendingMigrationCount(migrator)
+		if pending > 0 {
+			ExitFatal(fmt.Sprintf("There are %d pending database migrations! "+
+				"Run the \"%s up\" command to apply them.", pending, MigrateCommand))
+		}
+
+		return
+	}
+
+	coreLogger.Info("Applying pending database migrations...")
+	count, err := migrator.Up()
+	if nil != err {
+		ExitFatal(fmt.Sprintf("Failed to apply database migrations! Error: \"%v\"", err.Error()))
+	}
+	coreLogger.Info("Applied %d database migrations!", count)
+}
+
+// Migrate is the entrypoint of the migration command, which allows to
+// apply, roll back and inspect the versioned database migrations
+// without starting the bot.
+//
+// The following arguments are supported:
+//   - up: applies all pending migrations
+//   - down [steps]: rolls back the passed number of migrations (default: 1)
+//   - status: prints the status of all migrations
+func Migrate(args []string) {
+	if 0 == len(args) {
+		ExitFatal(migrateUsage)
+	}
+
+	initEnv()
+	resolveComponentDependencies()
+	initGorm()
+
+	em := CreateEntityManager()
+	migrator, err := em.Migrator()
+	if nil != err {
+		ExitFatal(fmt.Sprintf("Failed to prepare database migrations! Error: \"%v\"", err.Error()))
+	}
+
+	switch args[0] {
+	case "up":
+		count, err := migrator.Up()
+		if nil != err {
+			ExitFatal(fmt.Sprintf("Failed to apply database migrations! Error: \"%v\"", err.Error()))
+		}
+		coreLogger.Info("Applied %d database migrations!", count)
+	case "down":
+		steps := getMigrationSteps(args[1:])
+		count, err := migrator.Down(steps)
+		if nil != err {
+			ExitFatal(fmt.Sprintf("Failed to roll back database migrations! Error: \"%v\"", err.Error()))
+		}
+		coreLogger.Info("Rolled back %d database migrations!", count)
+	case "status":
+		status, err := migrator.Status()
+		if nil != err {
+			ExitFatal(fmt.Sprintf("Failed to get status of database migrations! Error: \"%v\"", err.Error()))
+		}
+		printMigrationStatus(status)
+	default:
+		ExitFatal(migrateUsage)
+	}
+}
+
+// getMigrationSteps parses the optional number of migrations
+// that should be rolled back. Defaults to one migration.
+func getMigrationSteps(args []string) int {
+	if 0 == len(args) {
+		return 1
+	}
+
+	steps, err := strconv.Atoi(args[0])
+	if nil != err || steps < 1 {
+		ExitFatal(fmt.Sprintf("The number of steps \"%s\" is not a valid positive number!", args[0]))
+	}
+
+	return steps
+}
+
+// getPendingMigrationCount returns the number of migrations
+// that have not been applied yet.
+func getPendingMigrationCount(migrator *api.Migrator) int {
+	status, err := migrator.Status()
+	if nil != err {
+		ExitFatal(fmt.Sprintf("Failed to get status of database migrations! Error: \"%v\"", err.Error()))
+	}
+
+	pending := 0
+	for _, migration := range status {
+		if !migration.Applied {
+			pending++
+		}
+	}
+
+	return pending
+}
+
+// printMigrationStatus prints the passed migration status as table to stdout.
+func printMigrationStatus(status []api.MigrationStatus) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(writer, "OWNER\tVERSION\tDESCRIPTION\tSTATUS\tAPPLIED AT")
+	for _, migration := range status {
+		state := "pending"
+		appliedAt := "-"
+		if migration.Applied {
+			state = "applied"
+			appliedAt = migration.AppliedAt.Format(time.RFC3339)
+		}
+
+		if !migration.Registered {
+			state = "unknown"
+		}
+
+		_, _ = fmt.Fprintf(writer, "%s\t%d\t%s\t%s\t%s\n",
+			migration.Owner,
+			migration.Version,
+			migration.Description,
+			state,
+			appliedAt)
+	}
+
+	_ = writer.Flush()
+}
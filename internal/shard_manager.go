@@ -0,0 +1,117 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package internal
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"time"
+)
+
+// AutoShardCount is the shard count that lets the ShardManager
+// use the shard count recommended by Discord.
+const AutoShardCount = 0
+
+// shardIdentifyDelay is the delay between opening two shards.
+// Discord allows only one identify per five seconds.
+const shardIdentifyDelay = 5 * time.Second
+
+// ShardManager spawns and manages the discordgo.Session instances
+// of all shards of the bot.
+type ShardManager struct {
+	shards []*discordgo.Session
+}
+
+// NewShardManager creates a ShardManager with a discordgo.Session for each shard,
+// but does not open the connections yet.
+//
+// When the passed shard count is AutoShardCount, the shard count
+// recommended by Discord is used.
+func NewShardManager(token string, shardCount int) (*ShardManager, error) {
+	primary, err := discordgo.New(token)
+	if nil != err {
+		return nil, err
+	}
+
+	if AutoShardCount == shardCount {
+		gatewayBot, err := primary.GatewayBot()
+		if nil != err {
+			return nil, fmt.Errorf("failed to detect the recommended shard count: %w", err)
+		}
+
+		shardCount = max(gatewayBot.Shards, 1)
+		coreLogger.Info("Detected the recommended shard count of \"%d\"!", shardCount)
+	}
+
+	shards := make([]*discordgo.Session, shardCount)
+	shards[0] = primary
+	for shardId := 1; shardId < shardCount; shardId++ {
+		shards[shardId], err = discordgo.New(token)
+		if nil != err {
+			return nil, err
+		}
+	}
+
+	for shardId, shard := range shards {
+		shard.ShardID = shardId
+		shard.ShardCount = shardCount
+	}
+
+	return &ShardManager{
+		shards: shards,
+	}, nil
+}
+
+// Primary returns the session of the first shard.
+// It is used for everything that is not bound to a specific shard,
+// like REST calls.
+func (sm *ShardManager) Primary() *discordgo.Session {
+	return sm.shards[0]
+}
+
+// Shards returns the sessions of all shards, ordered by their shard id.
+func (sm *ShardManager) Shards() []*discordgo.Session {
+	return sm.shards
+}
+
+// Open opens the connections of all shards.
+// The shards are opened one after another, to respect the identify limits of Discord.
+func (sm *ShardManager) Open() error {
+	for shardId, shard := range sm.shards {
+		if shardId > 0 {
+			time.Sleep(shardIdentifyDelay)
+		}
+
+		coreLogger.Info("Opening shard \"%d\" of \"%d\"...", shardId+1, len(sm.shards))
+		err := shard.Open()
+		if nil != err {
+			return fmt.Errorf("failed to open shard \"%d\": %w", shardId, err)
+		}
+	}
+
+	return nil
+}
+
+// Close closes the connections of all shards.
+// Errors that occur while closing a shard are ignored.
+func (sm *ShardManager) Close() {
+	for _, shard := range sm.shards {
+		_ = shard.Close()
+	}
+}
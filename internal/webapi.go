@@ -183,7 +183,7 @@ func initWebApi() {
 	}()
 
 	initSwagger()
-	err := webapi.Init(v1ApiRouter)
+	err := webapi.Init(v1ApiRouter, Config.webApiToken)
 	if nil != err {
 		ExitFatal(fmt.Sprintf("Failed to initialize the api framework for the web api: %v", err))
 	}
@@ -23,10 +23,12 @@ import (
 	_ "github.com/lazybytez/jojo-discord-bot/core_components"
 
 	"github.com/lazybytez/jojo-discord-bot/internal"
+	"os"
 )
 
 // Entrypoint of Go
 // Call real internal.Bootstrap function of internal package
+// or run the migration or database copy command, when requested.
 // OpenAPI / Swagger data
 // @title         JoJo Bot API
 // @description   Documentation of the JoJo Discord Bot web API that allows to get information about the bot and control it from the web.
@@ -37,5 +39,17 @@ import (
 // @license.name GNU Affero General Public License v3.0
 // @license.url  https://www.gnu.org/licenses/agpl-3.0.html
 func main() {
+	if len(os.Args) > 1 && internal.MigrateCommand == os.Args[1] {
+		internal.Migrate(os.Args[2:])
+
+		return
+	}
+
+	if len(os.Args) > 1 && internal.CopyDatabaseCommand == os.Args[1] {
+		internal.CopyDatabase(os.Args[2:])
+
+		return
+	}
+
 	internal.Bootstrap()
 }
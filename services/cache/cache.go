@@ -41,10 +41,17 @@ const (
 type Dsn string
 
 // Provider specifies the interface that different cache implementations must provide.
+//
+// Counters and token buckets are updated atomically by the provider, so that
+// multiple instances sharing the same cache cannot overwrite each other.
+// Unlike cache items, they expire after their own ttl or window.
 type Provider interface {
 	Get(key string, t reflect.Type) (interface{}, bool)
 	Update(key string, t reflect.Type, value interface{}) error
 	Invalidate(key string, t reflect.Type) bool
+	Increment(key string, ttl time.Duration) (int64, error)
+	TakeToken(key string, capacity int64, window time.Duration, now time.Time) (bool, time.Duration, error)
+	Delete(key string)
 	Shutdown()
 }
 
@@ -109,6 +116,27 @@ func Invalidate[T any](key string, t T) bool {
 	return cache.Invalidate(key, reflect.TypeOf(t))
 }
 
+// Increment atomically increments the counter with the passed key and returns the new value.
+// The counter expires after the passed ttl, which starts when the counter is created.
+func Increment(key string, ttl time.Duration) (int64, error) {
+	return cache.Increment(key, ttl)
+}
+
+// TakeToken atomically takes a token from the token bucket with the passed key.
+// The bucket holds up to capacity tokens and is refilled continuously,
+// so that an empty bucket is full again after the passed window.
+//
+// Returns whether a token could be taken and, if not,
+// how long it takes until the next token is available.
+func TakeToken(key string, capacity int64, window time.Duration, now time.Time) (bool, time.Duration, error) {
+	return cache.TakeToken(key, capacity, window, now)
+}
+
+// Delete removes the counter or token bucket with the passed key.
+func Delete(key string) {
+	cache.Delete(key)
+}
+
 // Deinit stops the cache and ensures that all open connections
 // to external services are closed before the application exits.
 func Deinit() {
@@ -33,10 +33,10 @@ type CacheTestSuite struct {
 
 func (suite *CacheTestSuite) SetupTest() {
 	suite.cache = &InMemoryCacheProvider{
-		sync.RWMutex{},
-		CachePool{},
-		10 * time.Second,
-		nil,
+		mu:        sync.RWMutex{},
+		cachePool: CachePool{},
+		lifetime:  10 * time.Second,
+		counters:  Counters{},
 	}
 }
 
@@ -0,0 +1,111 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package memory
+
+import (
+	"math"
+	"time"
+)
+
+// Counter holds the state of a counter or token bucket
+// together with the time the state expires.
+type Counter struct {
+	value     float64
+	since     time.Time
+	expiresAt time.Time
+}
+
+// Counters is a map that holds counters by their key.
+type Counters map[string]*Counter
+
+// Increment atomically increments the counter with the passed key and returns the new value.
+// The counter expires after the passed ttl, which starts when the counter is created.
+func (provider *InMemoryCacheProvider) Increment(key string, ttl time.Duration) (int64, error) {
+	provider.countersMu.Lock()
+	defer provider.countersMu.Unlock()
+
+	now := time.Now()
+	counter, ok := provider.counters[key]
+	if !ok || !now.Before(counter.expiresAt) {
+		counter = &Counter{expiresAt: now.Add(ttl)}
+		provider.counters[key] = counter
+	}
+
+	counter.value++
+
+	return int64(counter.value), nil
+}
+
+// TakeToken atomically takes a token from the token bucket with the passed key.
+// The bucket holds up to capacity tokens and is refilled continuously,
+// so that an empty bucket is full again after the passed window.
+//
+// Returns whether a token could be taken and, if not,
+// how long it takes until the next token is available.
+func (provider *InMemoryCacheProvider) TakeToken(
+	key string,
+	capacity int64,
+	window time.Duration,
+	now time.Time,
+) (bool, time.Duration, error) {
+	provider.countersMu.Lock()
+	defer provider.countersMu.Unlock()
+
+	tokensPerSecond := float64(capacity) / window.Seconds()
+
+	bucket, ok := provider.counters[key]
+	if !ok || !now.Before(bucket.expiresAt) {
+		bucket = &Counter{value: float64(capacity), since: now}
+		provider.counters[key] = bucket
+	}
+
+	refilled := math.Max(0, now.Sub(bucket.since).Seconds()) * tokensPerSecond
+	bucket.value = math.Min(float64(capacity), bucket.value+refilled)
+	bucket.since = now
+	// An untouched bucket is full again after the window, so it can expire
+	bucket.expiresAt = now.Add(window)
+
+	if bucket.value < 1 {
+		return false, time.Duration((1 - bucket.value) / tokensPerSecond * float64(time.Second)), nil
+	}
+
+	bucket.value--
+
+	return true, 0, nil
+}
+
+// Delete removes the counter or token bucket with the passed key.
+func (provider *InMemoryCacheProvider) Delete(key string) {
+	provider.countersMu.Lock()
+	defer provider.countersMu.Unlock()
+
+	delete(provider.counters, key)
+}
+
+// cleanUpCounters removes all counters that are expired at the passed time.
+func (provider *InMemoryCacheProvider) cleanUpCounters(now time.Time) {
+	provider.countersMu.Lock()
+	defer provider.countersMu.Unlock()
+
+	for key, counter := range provider.counters {
+		if !now.Before(counter.expiresAt) {
+			delete(provider.counters, key)
+		}
+	}
+}
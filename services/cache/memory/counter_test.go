@@ -0,0 +1,117 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package memory
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CounterTestSuite struct {
+	suite.Suite
+	cache *InMemoryCacheProvider
+}
+
+func (suite *CounterTestSuite) SetupTest() {
+	suite.cache = New(10 * time.Second)
+}
+
+func (suite *CounterTestSuite) TestIncrement() {
+	count, err := suite.cache.Increment("counter", time.Minute)
+	suite.NoError(err)
+	suite.Equal(int64(1), count)
+
+	count, err = suite.cache.Increment("counter", time.Minute)
+	suite.NoError(err)
+	suite.Equal(int64(2), count)
+
+	count, err = suite.cache.Increment("other", time.Minute)
+	suite.NoError(err)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *CounterTestSuite) TestIncrementConcurrently() {
+	wg := sync.WaitGroup{}
+	for idx := 0; idx < 100; idx++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := suite.cache.Increment("counter", time.Minute)
+			suite.NoError(err)
+		}()
+	}
+	wg.Wait()
+
+	count, err := suite.cache.Increment("counter", time.Minute)
+	suite.NoError(err)
+	suite.Equal(int64(101), count)
+}
+
+func (suite *CounterTestSuite) TestIncrementExpires() {
+	_, err := suite.cache.Increment("counter", time.Millisecond)
+	suite.NoError(err)
+
+	time.Sleep(5 * time.Millisecond)
+
+	count, err := suite.cache.Increment("counter", time.Minute)
+	suite.NoError(err)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *CounterTestSuite) TestTakeToken() {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	for idx := 0; idx < 2; idx++ {
+		allowed, _, err := suite.cache.TakeToken("bucket", 2, 20*time.Second, now)
+		suite.NoError(err)
+		suite.True(allowed)
+	}
+
+	allowed, retryAfter, err := suite.cache.TakeToken("bucket", 2, 20*time.Second, now)
+	suite.NoError(err)
+	suite.False(allowed)
+	suite.Equal(10*time.Second, retryAfter)
+
+	allowed, _, err = suite.cache.TakeToken("bucket", 2, 20*time.Second, now.Add(10*time.Second))
+	suite.NoError(err)
+	suite.True(allowed)
+}
+
+func (suite *CounterTestSuite) TestDeleteAndCleanUp() {
+	now := time.Now()
+
+	_, err := suite.cache.Increment("counter", time.Minute)
+	suite.NoError(err)
+	_, _, err = suite.cache.TakeToken("bucket", 1, time.Second, now)
+	suite.NoError(err)
+
+	suite.cache.Delete("counter")
+	suite.NotContains(suite.cache.counters, "counter")
+
+	suite.cache.cleanUpCounters(now.Add(2 * time.Second))
+	suite.NotContains(suite.cache.counters, "bucket")
+}
+
+func TestCounter(t *testing.T) {
+	suite.Run(t, new(CounterTestSuite))
+}
@@ -56,15 +56,18 @@ type InMemoryCacheProvider struct {
 	cachePool  CachePool
 	lifetime   time.Duration
 	cleanUpJob *time.Ticker
+
+	countersMu sync.Mutex
+	counters   Counters
 }
 
 // New creates a new cache with the specified lifetime (in seconds).
 func New(lifetime time.Duration) *InMemoryCacheProvider {
 	return &InMemoryCacheProvider{
-		sync.RWMutex{},
-		CachePool{},
-		lifetime,
-		nil,
+		mu:        sync.RWMutex{},
+		cachePool: CachePool{},
+		lifetime:  lifetime,
+		counters:  Counters{},
 	}
 }
 
@@ -99,6 +102,8 @@ func cleanUpCache(provider *InMemoryCacheProvider) {
 		}()
 	}
 	provider.mu.RUnlock()
+
+	go provider.cleanUpCounters(time.Now())
 }
 
 // UseGarbageCollector configures a periodic job
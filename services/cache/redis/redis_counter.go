@@ -0,0 +1,118 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+// incrementScript increments a counter and sets its expiry when it is created.
+// Running both commands as script ensures the counter cannot be left without expiry.
+var incrementScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// takeTokenScript takes a token from a token bucket stored as hash.
+// The bucket is refilled continuously based on the passed time, so that
+// an empty bucket is full again after the window.
+//
+// Returns a pair of whether a token has been taken (1 or 0) and
+// the milliseconds until the next token is available.
+var takeTokenScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local rate = capacity / window
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'since')
+local tokens = tonumber(state[1])
+local since = tonumber(state[2])
+if tokens == nil or since == nil then
+	tokens = capacity
+else
+	tokens = math.min(capacity, tokens + math.max(0, now - since) * rate)
+end
+
+local allowed = 0
+local retry = 0
+if tokens < 1 then
+	retry = math.ceil((1 - tokens) / rate)
+else
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'since', now)
+redis.call('PEXPIRE', KEYS[1], window)
+
+return {allowed, retry}
+`)
+
+// Increment atomically increments the counter with the passed key and returns the new value.
+// The counter expires after the passed ttl, which starts when the counter is created.
+func (grc *GoRedisCacheProvider) Increment(key string, ttl time.Duration) (int64, error) {
+	return incrementScript.Run(context.TODO(), grc.client, []string{computeCounterKey(key)}, ttl.Milliseconds()).Int64()
+}
+
+// TakeToken atomically takes a token from the token bucket with the passed key.
+// The bucket holds up to capacity tokens and is refilled continuously,
+// so that an empty bucket is full again after the passed window.
+//
+// Returns whether a token could be taken and, if not,
+// how long it takes until the next token is available.
+func (grc *GoRedisCacheProvider) TakeToken(
+	key string,
+	capacity int64,
+	window time.Duration,
+	now time.Time,
+) (bool, time.Duration, error) {
+	result, err := takeTokenScript.Run(
+		context.TODO(),
+		grc.client,
+		[]string{computeCounterKey(key)},
+		capacity,
+		window.Milliseconds(),
+		now.UnixMilli()).Int64Slice()
+	if nil != err {
+		return false, 0, err
+	}
+
+	if 2 != len(result) {
+		return false, 0, fmt.Errorf("unexpected result of token bucket script: %v", result)
+	}
+
+	return 1 == result[0], time.Duration(result[1]) * time.Millisecond, nil
+}
+
+// Delete removes the counter or token bucket with the passed key.
+func (grc *GoRedisCacheProvider) Delete(key string) {
+	grc.client.Del(context.TODO(), computeCounterKey(key))
+}
+
+// computeCounterKey creates the key under which a counter is stored in Redis.
+func computeCounterKey(key string) string {
+	return fmt.Sprintf("counter_%s", key)
+}
@@ -27,14 +27,22 @@ import (
 // used to create API endpoints.
 var routerGroup *gin.RouterGroup
 
+// adminToken is the token that must be passed as bearer token
+// to access administrative endpoints.
+var adminToken string
+
 // Init initializes the webapi and makes
 // it ready to be used.
-func Init(apiRouterGroup *gin.RouterGroup) error {
+//
+// The passed token is used to protect administrative endpoints.
+// When the token is empty, administrative endpoints are disabled.
+func Init(apiRouterGroup *gin.RouterGroup, apiAdminToken string) error {
 	if nil != routerGroup {
 		return fmt.Errorf("cannot initialize the web api twice")
 	}
 
 	routerGroup = apiRouterGroup
+	adminToken = apiAdminToken
 
 	return nil
 }
@@ -0,0 +1,66 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package webapi
+
+import (
+	"crypto/subtle"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// bearerTokenPrefix is the prefix of the Authorization header
+// that carries the admin token.
+const bearerTokenPrefix = "Bearer "
+
+// RequireAdminToken creates a new gin.HandlerFunc that only allows requests
+// that carry the configured admin token as bearer token in the Authorization header.
+//
+// If no admin token has been configured, all requests are rejected.
+func RequireAdminToken() gin.HandlerFunc {
+	return func(g *gin.Context) {
+		if "" == adminToken {
+			RespondWithError(g, ErrorResponse{
+				Status:    http.StatusForbidden,
+				Error:     "Administrative endpoints are disabled",
+				Message:   "No admin token has been configured for this instance of the bot",
+				Timestamp: time.Now(),
+			})
+
+			return
+		}
+
+		authHeader := g.GetHeader("Authorization")
+		token := strings.TrimPrefix(authHeader, bearerTokenPrefix)
+		if !strings.HasPrefix(authHeader, bearerTokenPrefix) ||
+			1 != subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) {
+			RespondWithError(g, ErrorResponse{
+				Status:    http.StatusUnauthorized,
+				Error:     "Unauthorized",
+				Message:   "A valid admin token must be supplied as bearer token to access this resource",
+				Timestamp: time.Now(),
+			})
+
+			return
+		}
+
+		g.Next()
+	}
+}
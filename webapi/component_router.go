@@ -0,0 +1,223 @@
+/*
+ * JOJO Discord Bot - An advanced multi-purpose discord bot
+ * Copyright (C) 2022 Lazy Bytez (Elias Knodel, Pascal Zarrad)
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package webapi
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Gin does not support removing routes once they have been registered.
+// To allow components to be unloaded and loaded again at runtime,
+// routes registered through a ComponentRouter are registered only once in Gin.
+// The actual handlers are stored in an ownedRoute that can be
+// swapped or cleared when the owning component is (re-)loaded.
+
+// ComponentRouter is the interface that allows components to register
+// routes that are owned by them and can be removed again at runtime.
+type ComponentRouter interface {
+	// Group creates a new ComponentRouter with the passed relative path
+	// appended to the path of the current ComponentRouter.
+	Group(relativePath string) ComponentRouter
+	// Handle registers a new route with the given method and path.
+	// Registering a route that is already owned by another owner results in an error.
+	Handle(httpMethod string, relativePath string, handlers ...gin.HandlerFunc) error
+	// GET is a shortcut for Handle("GET", relativePath, handlers...)
+	GET(relativePath string, handlers ...gin.HandlerFunc) error
+	// POST is a shortcut for Handle("POST", relativePath, handlers...)
+	POST(relativePath string, handlers ...gin.HandlerFunc) error
+	// PUT is a shortcut for Handle("PUT", relativePath, handlers...)
+	PUT(relativePath string, handlers ...gin.HandlerFunc) error
+	// PATCH is a shortcut for Handle("PATCH", relativePath, handlers...)
+	PATCH(relativePath string, handlers ...gin.HandlerFunc) error
+	// DELETE is a shortcut for Handle("DELETE", relativePath, handlers...)
+	DELETE(relativePath string, handlers ...gin.HandlerFunc) error
+}
+
+// ownedRoute holds the handlers of a single route together with
+// the owner that registered them.
+type ownedRoute struct {
+	mu       sync.RWMutex
+	owner    string
+	handlers []gin.HandlerFunc
+}
+
+// ownedRouteMap holds all routes that have been registered through
+// a ComponentRouter. The key is the method and the absolute path of the route.
+type ownedRouteMap struct {
+	sync.Mutex
+	routes map[string]*ownedRoute
+}
+
+// ownedRoutes holds all currently known owned routes.
+var ownedRoutes = ownedRouteMap{
+	routes: make(map[string]*ownedRoute),
+}
+
+// OwnedRouterGroup is the default ComponentRouter implementation.
+// It wraps the gin.RouterGroup of the web api.
+type OwnedRouterGroup struct {
+	owner        string
+	relativePath string
+}
+
+// RouterFor returns a ComponentRouter for the passed owner.
+// All routes registered using the returned ComponentRouter can be removed
+// again using UnregisterRoutes.
+func RouterFor(owner string) ComponentRouter {
+	return &OwnedRouterGroup{
+		owner:        owner,
+		relativePath: "/",
+	}
+}
+
+// Group creates a new ComponentRouter with the passed relative path
+// appended to the path of the current ComponentRouter.
+func (org *OwnedRouterGroup) Group(relativePath string) ComponentRouter {
+	return &OwnedRouterGroup{
+		owner:        org.owner,
+		relativePath: joinPaths(org.relativePath, relativePath),
+	}
+}
+
+// Handle registers a new route with the given method and path.
+// Registering a route that is already owned by another owner results in an error.
+//
+// When the route has been registered before by the same owner
+// (e.g. before the owner has been reloaded), the existing route is reused
+// and the handlers are swapped.
+func (org *OwnedRouterGroup) Handle(httpMethod string, relativePath string, handlers ...gin.HandlerFunc) error {
+	if nil == routerGroup {
+		return fmt.Errorf("cannot register route \"%s\" before the web api has been initialized", relativePath)
+	}
+
+	fullPath := joinPaths(org.relativePath, relativePath)
+	routeKey := fmt.Sprintf("%s %s", httpMethod, fullPath)
+
+	ownedRoutes.Lock()
+	defer ownedRoutes.Unlock()
+
+	route, ok := ownedRoutes.routes[routeKey]
+	if !ok {
+		route = &ownedRoute{}
+		ownedRoutes.routes[routeKey] = route
+
+		routerGroup.Handle(httpMethod, fullPath, route.serve)
+	}
+
+	route.mu.Lock()
+	defer route.mu.Unlock()
+
+	if len(route.handlers) > 0 && route.owner != org.owner {
+		return fmt.Errorf("the route \"%s\" is already owned by \"%s\"", routeKey, route.owner)
+	}
+
+	route.owner = org.owner
+	route.handlers = handlers
+
+	return nil
+}
+
+// GET is a shortcut for Handle("GET", relativePath, handlers...)
+func (org *OwnedRouterGroup) GET(relativePath string, handlers ...gin.HandlerFunc) error {
+	return org.Handle(http.MethodGet, relativePath, handlers...)
+}
+
+// POST is a shortcut for Handle("POST", relativePath, handlers...)
+func (org *OwnedRouterGroup) POST(relativePath string, handlers ...gin.HandlerFunc) error {
+	return org.Handle(http.MethodPost, relativePath, handlers...)
+}
+
+// PUT is a shortcut for Handle("PUT", relativePath, handlers...)
+func (org *OwnedRouterGroup) PUT(relativePath string, handlers ...gin.HandlerFunc) error {
+	return org.Handle(http.MethodPut, relativePath, handlers...)
+}
+
+// PATCH is a shortcut for Handle("PATCH", relativePath, handlers...)
+func (org *OwnedRouterGroup) PATCH(relativePath string, handlers ...gin.HandlerFunc) error {
+	return org.Handle(http.MethodPatch, relativePath, handlers...)
+}
+
+// DELETE is a shortcut for Handle("DELETE", relativePath, handlers...)
+func (org *OwnedRouterGroup) DELETE(relativePath string, handlers ...gin.HandlerFunc) error {
+	return org.Handle(http.MethodDelete, relativePath, handlers...)
+}
+
+// UnregisterRoutes removes the handlers of all routes owned by the passed owner.
+// The routes will respond with a not found error until they are registered again.
+func UnregisterRoutes(owner string) {
+	ownedRoutes.Lock()
+	defer ownedRoutes.Unlock()
+
+	for _, route := range ownedRoutes.routes {
+		route.mu.Lock()
+		if route.owner == owner {
+			route.handlers = nil
+		}
+		route.mu.Unlock()
+	}
+}
+
+// serve executes the handlers of the route.
+// If the route has no handlers, because its owner has been unloaded,
+// a not found error is returned.
+func (or *ownedRoute) serve(g *gin.Context) {
+	or.mu.RLock()
+	handlers := or.handlers
+	or.mu.RUnlock()
+
+	if len(handlers) == 0 {
+		RespondWithError(g, ErrorResponse{
+			Status:    http.StatusNotFound,
+			Error:     "Resource not found",
+			Message:   "The requested resource is currently not available",
+			Timestamp: time.Now(),
+		})
+
+		return
+	}
+
+	for _, handler := range handlers {
+		handler(g)
+
+		if g.IsAborted() {
+			return
+		}
+	}
+}
+
+// joinPaths joins the passed paths and keeps a trailing slash
+// of the relative path, like Gin does.
+func joinPaths(absolutePath string, relativePath string) string {
+	if "" == relativePath {
+		return absolutePath
+	}
+
+	finalPath := path.Join(absolutePath, relativePath)
+	if strings.HasSuffix(relativePath, "/") && !strings.HasSuffix(finalPath, "/") {
+		return finalPath + "/"
+	}
+
+	return finalPath
+}